@@ -0,0 +1,410 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attrs
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+// ReservedRanges are the address ranges reserved for documentation by
+// RFC 5737 (IPv4) and RFC 3849 (IPv6), which test plans use for DUT and ATE
+// addresses.
+var ReservedRanges = []netip.Prefix{
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+// AllocatorOptions configure an Allocator. Zero values select the defaults
+// documented on each field.
+type AllocatorOptions struct {
+	// IPv4Pools are the prefixes link subnets are carved from, in order.
+	// Defaults to 192.0.2.0/24 and 198.51.100.0/24.
+	IPv4Pools []netip.Prefix
+	// IPv6Pools are the prefixes link subnets are carved from, in order.
+	// Defaults to 2001:db8::/64.
+	IPv6Pools []netip.Prefix
+	// IPv4LoopbackPool is used for /32 loopbacks. Defaults to 203.0.113.0/24.
+	IPv4LoopbackPool netip.Prefix
+	// IPv6LoopbackPool is used for /128 loopbacks. Defaults to
+	// 2001:db8:ffff::/64.
+	IPv6LoopbackPool netip.Prefix
+	// IPv4LinkLen is the link prefix length, 30 or 31. Defaults to 30.
+	IPv4LinkLen uint8
+	// IPv6LinkLen is the link prefix length, 126 or 127. Defaults to 126.
+	IPv6LinkLen uint8
+	// MACPrefix is the second octet of the locally administered MACs given
+	// to ATE interfaces, so that ATEs shared by several DUTs can use
+	// distinct MACs. The generated MACs are 02:<MACPrefix>:00:00:xx:xx.
+	MACPrefix uint8
+	// AllowUnreserved permits pools outside of ReservedRanges.
+	AllowUnreserved bool
+}
+
+// pool hands out consecutive subnets from a list of prefixes.
+type pool struct {
+	prefixes []netip.Prefix
+	idx      int
+	next     netip.Addr
+}
+
+func newPool(prefixes []netip.Prefix) *pool {
+	p := &pool{prefixes: prefixes}
+	if len(prefixes) > 0 {
+		p.next = prefixes[0].Masked().Addr()
+	}
+	return p
+}
+
+// subnet returns the next subnet of the given length.
+func (p *pool) subnet(bits uint8) (netip.Prefix, error) {
+	for p.idx < len(p.prefixes) {
+		cur := p.prefixes[p.idx]
+		s := netip.PrefixFrom(p.next, int(bits))
+		if int(bits) >= cur.Bits() && cur.Contains(p.next) && s.Masked() == s {
+			last := lastAddr(s)
+			if cur.Contains(last) {
+				if n := last.Next(); n.IsValid() {
+					p.next = n
+				} else {
+					p.idx = len(p.prefixes)
+				}
+				return s, nil
+			}
+		}
+		p.idx++
+		if p.idx < len(p.prefixes) {
+			p.next = p.prefixes[p.idx].Masked().Addr()
+		}
+	}
+	return netip.Prefix{}, fmt.Errorf("address pools %v exhausted", p.prefixes)
+}
+
+// lastAddr returns the last address of a prefix.
+func lastAddr(p netip.Prefix) netip.Addr {
+	b := p.Masked().Addr().AsSlice()
+	for i := p.Bits(); i < len(b)*8; i++ {
+		b[i/8] |= 1 << (7 - i%8)
+	}
+	a, _ := netip.AddrFromSlice(b)
+	return a
+}
+
+// nthAddr returns the n-th address of a prefix, counting from 0.
+func nthAddr(p netip.Prefix, n int) netip.Addr {
+	a := p.Masked().Addr()
+	for i := 0; i < n; i++ {
+		a = a.Next()
+	}
+	return a
+}
+
+// Allocator deterministically assigns addresses to DUT and ATE interfaces.
+// Two allocators with the same options assign the same addresses when asked
+// for the same sequence of links and loopbacks.
+type Allocator struct {
+	opts   AllocatorOptions
+	v4, v6 *pool
+	lo4    *pool
+	lo6    *pool
+	macs   int
+}
+
+// NewAllocator validates the options and returns an Allocator.
+func NewAllocator(opts *AllocatorOptions) (*Allocator, error) {
+	o := AllocatorOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if len(o.IPv4Pools) == 0 {
+		o.IPv4Pools = []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24"), netip.MustParsePrefix("198.51.100.0/24")}
+	}
+	if len(o.IPv6Pools) == 0 {
+		o.IPv6Pools = []netip.Prefix{netip.MustParsePrefix("2001:db8::/64")}
+	}
+	if !o.IPv4LoopbackPool.IsValid() {
+		o.IPv4LoopbackPool = netip.MustParsePrefix("203.0.113.0/24")
+	}
+	if !o.IPv6LoopbackPool.IsValid() {
+		o.IPv6LoopbackPool = netip.MustParsePrefix("2001:db8:ffff::/64")
+	}
+	if o.IPv4LinkLen == 0 {
+		o.IPv4LinkLen = 30
+	}
+	if o.IPv6LinkLen == 0 {
+		o.IPv6LinkLen = 126
+	}
+	if o.IPv4LinkLen != 30 && o.IPv4LinkLen != 31 {
+		return nil, fmt.Errorf("unsupported IPv4 link prefix length %d, want 30 or 31", o.IPv4LinkLen)
+	}
+	if o.IPv6LinkLen != 126 && o.IPv6LinkLen != 127 {
+		return nil, fmt.Errorf("unsupported IPv6 link prefix length %d, want 126 or 127", o.IPv6LinkLen)
+	}
+
+	v4 := append([]netip.Prefix{o.IPv4LoopbackPool}, o.IPv4Pools...)
+	v6 := append([]netip.Prefix{o.IPv6LoopbackPool}, o.IPv6Pools...)
+	for _, p := range v4 {
+		if !p.Addr().Is4() {
+			return nil, fmt.Errorf("pool %v is not an IPv4 prefix", p)
+		}
+	}
+	for _, p := range v6 {
+		if !p.Addr().Is6() || p.Addr().Is4In6() {
+			return nil, fmt.Errorf("pool %v is not an IPv6 prefix", p)
+		}
+	}
+	all := append(v4, v6...)
+	for i, p := range all {
+		if !o.AllowUnreserved && !inReserved(p) {
+			return nil, fmt.Errorf("pool %v is outside of the RFC 5737/3849 documentation ranges", p)
+		}
+		for _, q := range all[:i] {
+			if p.Overlaps(q) {
+				return nil, fmt.Errorf("pools %v and %v overlap", q, p)
+			}
+		}
+	}
+	return &Allocator{
+		opts: o,
+		v4:   newPool(o.IPv4Pools),
+		v6:   newPool(o.IPv6Pools),
+		lo4:  newPool([]netip.Prefix{o.IPv4LoopbackPool}),
+		lo6:  newPool([]netip.Prefix{o.IPv6LoopbackPool}),
+	}, nil
+}
+
+// inReserved reports whether the prefix is contained in ReservedRanges.
+func inReserved(p netip.Prefix) bool {
+	for _, r := range ReservedRanges {
+		if r.Bits() <= p.Bits() && r.Contains(p.Addr()) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextMAC returns the next locally administered unicast MAC.
+func (a *Allocator) nextMAC() string {
+	a.macs++
+	return fmt.Sprintf("02:%02x:00:00:%02x:%02x", a.opts.MACPrefix, a.macs>>8&0xff, a.macs&0xff)
+}
+
+// Link allocates a link subnet and returns matched DUT and ATE attributes.
+// The DUT gets the first usable address and the ATE the second one; for
+// /31 and /127 links these are the two addresses of the subnet.
+func (a *Allocator) Link(name string) (dut, ate *Attributes, err error) {
+	s4, err := a.v4.subnet(a.opts.IPv4LinkLen)
+	if err != nil {
+		return nil, nil, err
+	}
+	s6, err := a.v6.subnet(a.opts.IPv6LinkLen)
+	if err != nil {
+		return nil, nil, err
+	}
+	off4, off6 := 1, 1
+	if a.opts.IPv4LinkLen == 31 {
+		off4 = 0
+	}
+	if a.opts.IPv6LinkLen == 127 {
+		off6 = 0
+	}
+	dut = &Attributes{
+		Desc:    "dut" + name,
+		IPv4:    nthAddr(s4, off4).String(),
+		IPv4Len: a.opts.IPv4LinkLen,
+		IPv6:    nthAddr(s6, off6).String(),
+		IPv6Len: a.opts.IPv6LinkLen,
+	}
+	ate = &Attributes{
+		Name:    "ate" + name,
+		MAC:     a.nextMAC(),
+		IPv4:    nthAddr(s4, off4+1).String(),
+		IPv4Len: a.opts.IPv4LinkLen,
+		IPv6:    nthAddr(s6, off6+1).String(),
+		IPv6Len: a.opts.IPv6LinkLen,
+	}
+	return dut, ate, nil
+}
+
+// Loopback allocates a /32 and /128 loopback address pair.
+func (a *Allocator) Loopback(name string) (*Attributes, error) {
+	s4, err := a.lo4.subnet(32)
+	if err != nil {
+		return nil, err
+	}
+	s6, err := a.lo6.subnet(128)
+	if err != nil {
+		return nil, err
+	}
+	return &Attributes{
+		Desc:    name,
+		IPv4:    s4.Addr().String(),
+		IPv4Len: 32,
+		IPv6:    s6.Addr().String(),
+		IPv6Len: 128,
+	}, nil
+}
+
+// LAGSpec describes a LAG bundling several DUT<->ATE links.
+type LAGSpec struct {
+	Name    string
+	Members int
+}
+
+// Topology describes the links to allocate addresses for.
+type Topology struct {
+	// Ports is the number of individual DUT<->ATE links.
+	Ports int
+	// VLANs is the number of dot1q subinterfaces on each individual port.
+	// When non-zero, VLAN IDs start at VLANBase, which defaults to 10.
+	VLANs    int
+	VLANBase uint16
+	// LAGs are bundles of additional links. Each LAG gets one address pair
+	// configured on the aggregate interface.
+	LAGs []LAGSpec
+	// Loopbacks is the number of loopbacks to allocate.
+	Loopbacks int
+}
+
+// LinkPair is a matched pair of DUT and ATE attributes on a link.
+type LinkPair struct {
+	// Port is the 1-based index of the port for individual links, or 0 for
+	// LAGs.
+	Port int
+	// VLAN is the dot1q VLAN ID, 0 for untagged links.
+	VLAN uint16
+	// LAG is the name of the LAG, empty for individual links.
+	LAG string
+	// Members is the number of member ports of a LAG.
+	Members int
+	DUT     *Attributes
+	ATE     *Attributes
+}
+
+// Plan is the result of allocating addresses for a Topology.
+type Plan struct {
+	Links     []*LinkPair
+	Loopbacks []*Attributes
+}
+
+// Port returns the untagged link pair of a 1-based port index, or nil.
+func (p *Plan) Port(port int) *LinkPair {
+	for _, l := range p.Links {
+		if l.Port == port && l.VLAN == 0 && l.LAG == "" {
+			return l
+		}
+	}
+	return nil
+}
+
+// Allocate assigns addresses for every link of the topology. Ports are
+// allocated first in order, each followed by its VLAN subinterfaces, then
+// the LAGs and finally the loopbacks.
+func (a *Allocator) Allocate(top *Topology) (*Plan, error) {
+	plan := &Plan{}
+	base := top.VLANBase
+	if base == 0 {
+		base = 10
+	}
+	for port := 1; port <= top.Ports; port++ {
+		name := fmt.Sprintf("Port%d", port)
+		dut, ate, err := a.Link(name)
+		if err != nil {
+			return nil, err
+		}
+		plan.Links = append(plan.Links, &LinkPair{Port: port, DUT: dut, ATE: ate})
+		for v := 0; v < top.VLANs; v++ {
+			vlan := base + uint16(v)
+			dut, ate, err := a.Link(fmt.Sprintf("%s.%d", name, vlan))
+			if err != nil {
+				return nil, err
+			}
+			dut.Subinterface, dut.VLAN, ate.VLAN = uint32(vlan), vlan, vlan
+			plan.Links = append(plan.Links, &LinkPair{Port: port, VLAN: vlan, DUT: dut, ATE: ate})
+		}
+	}
+	for _, lag := range top.LAGs {
+		dut, ate, err := a.Link(lag.Name)
+		if err != nil {
+			return nil, err
+		}
+		dut.LAG, ate.LAG = &LAG{}, &LAG{}
+		for i := 0; i < lag.Members; i++ {
+			ate.LAG.MemberMACs = append(ate.LAG.MemberMACs, a.nextMAC())
+		}
+		plan.Links = append(plan.Links, &LinkPair{LAG: lag.Name, Members: lag.Members, DUT: dut, ATE: ate})
+	}
+	for i := 0; i < top.Loopbacks; i++ {
+		lo, err := a.Loopback(fmt.Sprintf("loopback%d", i))
+		if err != nil {
+			return nil, err
+		}
+		plan.Loopbacks = append(plan.Loopbacks, lo)
+	}
+	return plan, nil
+}
+
+// CheckAddresses verifies that hand-written attributes only use addresses
+// from ReservedRanges and that no address or MAC, including the MACs of LAG
+// member ports, is used twice.
+func CheckAddresses(as ...*Attributes) error {
+	seen := map[string]string{}
+	use := func(kind, v, owner string) error {
+		if prev, ok := seen[v]; ok {
+			return fmt.Errorf("%s %s is used by both %s and %s", kind, v, prev, owner)
+		}
+		seen[v] = owner
+		return nil
+	}
+	for i, a := range as {
+		owner := a.Name
+		if owner == "" {
+			owner = a.Desc
+		}
+		if owner == "" {
+			owner = fmt.Sprintf("attributes #%d", i)
+		}
+		for _, ip := range []string{a.IPv4, a.IPv6} {
+			if ip == "" {
+				continue
+			}
+			addr, err := netip.ParseAddr(ip)
+			if err != nil {
+				return fmt.Errorf("%s has invalid address %q: %v", owner, ip, err)
+			}
+			if !inReserved(netip.PrefixFrom(addr, addr.BitLen())) {
+				return fmt.Errorf("%s address %s is outside of the RFC 5737/3849 documentation ranges", owner, ip)
+			}
+			if err := use("address", addr.String(), owner); err != nil {
+				return err
+			}
+		}
+		if a.MAC != "" {
+			if err := use("MAC", a.MAC, owner); err != nil {
+				return err
+			}
+		}
+		if a.LAG != nil {
+			for j, mac := range a.LAG.MemberMACs {
+				if err := use("MAC", mac, fmt.Sprintf("%s member #%d", owner, j+1)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
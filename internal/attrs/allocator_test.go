@@ -0,0 +1,203 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attrs
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAllocatorLink(t *testing.T) {
+	tests := []struct {
+		desc     string
+		opts     *AllocatorOptions
+		wantDUT  *Attributes
+		wantATE  *Attributes
+		wantNext *Attributes
+	}{{
+		desc:     "defaults",
+		wantDUT:  &Attributes{Desc: "dutPort1", IPv4: "192.0.2.1", IPv4Len: 30, IPv6: "2001:db8::1", IPv6Len: 126},
+		wantATE:  &Attributes{Name: "atePort1", MAC: "02:00:00:00:00:01", IPv4: "192.0.2.2", IPv4Len: 30, IPv6: "2001:db8::2", IPv6Len: 126},
+		wantNext: &Attributes{Desc: "dutPort2", IPv4: "192.0.2.5", IPv4Len: 30, IPv6: "2001:db8::5", IPv6Len: 126},
+	}, {
+		desc:     "point to point",
+		opts:     &AllocatorOptions{IPv4LinkLen: 31, IPv6LinkLen: 127, MACPrefix: 7},
+		wantDUT:  &Attributes{Desc: "dutPort1", IPv4: "192.0.2.0", IPv4Len: 31, IPv6: "2001:db8::", IPv6Len: 127},
+		wantATE:  &Attributes{Name: "atePort1", MAC: "02:07:00:00:00:01", IPv4: "192.0.2.1", IPv4Len: 31, IPv6: "2001:db8::1", IPv6Len: 127},
+		wantNext: &Attributes{Desc: "dutPort2", IPv4: "192.0.2.2", IPv4Len: 31, IPv6: "2001:db8::2", IPv6Len: 127},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			a, err := NewAllocator(tt.opts)
+			if err != nil {
+				t.Fatalf("NewAllocator() got unexpected error: %v", err)
+			}
+			dut, ate, err := a.Link("Port1")
+			if err != nil {
+				t.Fatalf("Link() got unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantDUT, dut); diff != "" {
+				t.Errorf("Link() DUT diff (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantATE, ate); diff != "" {
+				t.Errorf("Link() ATE diff (-want +got):\n%s", diff)
+			}
+			next, _, err := a.Link("Port2")
+			if err != nil {
+				t.Fatalf("Link() got unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantNext, next); diff != "" {
+				t.Errorf("second Link() DUT diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAllocatorPoolRollover(t *testing.T) {
+	a, err := NewAllocator(&AllocatorOptions{IPv4Pools: []netip.Prefix{
+		netip.MustParsePrefix("192.0.2.248/29"),
+		netip.MustParsePrefix("198.51.100.0/30"),
+	}})
+	if err != nil {
+		t.Fatalf("NewAllocator() got unexpected error: %v", err)
+	}
+	var got []string
+	for i := 0; i < 3; i++ {
+		dut, _, err := a.Link("x")
+		if err != nil {
+			t.Fatalf("Link() #%d got unexpected error: %v", i, err)
+		}
+		got = append(got, dut.IPv4)
+	}
+	if want := []string{"192.0.2.249", "192.0.2.253", "198.51.100.1"}; !cmp.Equal(got, want) {
+		t.Errorf("Link() got DUT addresses %v, want %v", got, want)
+	}
+	if _, _, err := a.Link("x"); err == nil {
+		t.Errorf("Link() on exhausted pools got no error, want error")
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	a, err := NewAllocator(nil)
+	if err != nil {
+		t.Fatalf("NewAllocator() got unexpected error: %v", err)
+	}
+	plan, err := a.Allocate(&Topology{Ports: 2, VLANs: 2, LAGs: []LAGSpec{{Name: "LAG1", Members: 4}}, Loopbacks: 1})
+	if err != nil {
+		t.Fatalf("Allocate() got unexpected error: %v", err)
+	}
+	if got := len(plan.Links); got != 7 {
+		t.Errorf("Allocate() got %d links, want 7", got)
+	}
+	if got := plan.Port(2); got == nil || got.DUT.IPv4 != "192.0.2.13" {
+		t.Errorf("Plan.Port(2) got %+v, want DUT address 192.0.2.13", got)
+	}
+	if got := plan.Links[2]; got.Port != 1 || got.VLAN != 11 {
+		t.Errorf("Allocate() got third link port %d VLAN %d, want port 1 VLAN 11", got.Port, got.VLAN)
+	}
+	if got := plan.Links[6]; got.LAG != "LAG1" || got.Members != 4 {
+		t.Errorf("Allocate() got last link %+v, want LAG1 with 4 members", got)
+	}
+	if got := plan.Loopbacks[0]; got.IPv4 != "203.0.113.0" || got.IPv6 != "2001:db8:ffff::" {
+		t.Errorf("Allocate() got loopback %s, %s, want 203.0.113.0, 2001:db8:ffff::", got.IPv4, got.IPv6)
+	}
+
+	var all []*Attributes
+	for _, l := range plan.Links {
+		all = append(all, l.DUT, l.ATE)
+	}
+	all = append(all, plan.Loopbacks...)
+	if err := CheckAddresses(all...); err != nil {
+		t.Errorf("CheckAddresses() on allocated plan got error: %v", err)
+	}
+}
+
+func TestAllocateLAGMemberMACs(t *testing.T) {
+	a, err := NewAllocator(nil)
+	if err != nil {
+		t.Fatalf("NewAllocator() got unexpected error: %v", err)
+	}
+	plan, err := a.Allocate(&Topology{Ports: 1, LAGs: []LAGSpec{{Name: "LAG1", Members: 4}, {Name: "LAG2", Members: 4}}})
+	if err != nil {
+		t.Fatalf("Allocate() got unexpected error: %v", err)
+	}
+	var all []*Attributes
+	for _, l := range plan.Links {
+		all = append(all, l.DUT, l.ATE)
+		if l.LAG != "" {
+			if got := len(l.ATE.LAG.MemberMACs); got != l.Members {
+				t.Errorf("Allocate() got %d member MACs for %s, want %d", got, l.LAG, l.Members)
+			}
+		}
+	}
+	if err := CheckAddresses(all...); err != nil {
+		t.Errorf("CheckAddresses() on two allocated LAGs got error: %v", err)
+	}
+
+	// Member MACs derived from the device MAC stay clear of other devices.
+	lag1, lag2 := plan.Links[1].ATE, plan.Links[2].ATE
+	lag1.LAG.MemberMACs, lag2.LAG.MemberMACs = nil, nil
+	for _, l := range []*Attributes{lag1, lag2} {
+		for i := 0; i < 4; i++ {
+			l.LAG.MemberMACs = append(l.LAG.MemberMACs, memberMAC(l.MAC, i))
+		}
+	}
+	if err := CheckAddresses(all...); err != nil {
+		t.Errorf("CheckAddresses() with default member MACs got error: %v", err)
+	}
+
+	lag1.LAG.MemberMACs = []string{lag2.MAC}
+	if err := CheckAddresses(all...); err == nil {
+		t.Errorf("CheckAddresses() with member MAC of another device got no error, want error")
+	}
+}
+
+func TestNewAllocatorErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		opts *AllocatorOptions
+	}{
+		{"unreserved pool", &AllocatorOptions{IPv4Pools: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/24")}}},
+		{"overlapping pools", &AllocatorOptions{IPv4Pools: []netip.Prefix{netip.MustParsePrefix("203.0.113.0/25")}}},
+		{"wrong family", &AllocatorOptions{IPv6Pools: []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")}}},
+		{"bad link length", &AllocatorOptions{IPv4LinkLen: 24}},
+	}
+	for _, tt := range tests {
+		if _, err := NewAllocator(tt.opts); err == nil {
+			t.Errorf("%s: NewAllocator() got no error, want error", tt.desc)
+		}
+	}
+	if _, err := NewAllocator(&AllocatorOptions{IPv4Pools: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/24")}, AllowUnreserved: true}); err != nil {
+		t.Errorf("NewAllocator() with AllowUnreserved got unexpected error: %v", err)
+	}
+}
+
+func TestCheckAddresses(t *testing.T) {
+	dut := &Attributes{Desc: "dutPort1", IPv4: "192.0.2.1", IPv6: "2001:db8::1"}
+	ate := &Attributes{Name: "atePort1", IPv4: "192.0.2.2", IPv6: "2001:db8::2", MAC: "02:00:01:01:01:01"}
+	if err := CheckAddresses(dut, ate); err != nil {
+		t.Errorf("CheckAddresses() got unexpected error: %v", err)
+	}
+	dup := &Attributes{Name: "atePort2", IPv4: "192.0.2.2"}
+	if err := CheckAddresses(dut, ate, dup); err == nil {
+		t.Errorf("CheckAddresses() with duplicate address got no error, want error")
+	}
+	outside := &Attributes{Name: "atePort3", IPv4: "10.0.0.1"}
+	if err := CheckAddresses(outside); err == nil {
+		t.Errorf("CheckAddresses() with unreserved address got no error, want error")
+	}
+}
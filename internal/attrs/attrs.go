@@ -43,6 +43,15 @@ type Attributes struct {
 	IPv6Len uint8  // Prefix length for IPv6.
 	MTU     uint16
 	ID      uint32 // /interfaces/interface/state/id p4rt interface id
+
+	// Subinterface is the index of the subinterface holding the addresses.
+	Subinterface uint32
+	// VLAN is the dot1q VLAN ID of the subinterface, or the outer VLAN ID if
+	// InnerVLAN is also set. Zero leaves the subinterface untagged.
+	VLAN      uint16
+	InnerVLAN uint16 // Inner VLAN ID of a QinQ subinterface.
+	// LAG, if set, configures the interface as an aggregate.
+	LAG *LAG
 }
 
 // IPv4CIDR constructs the IPv4 CIDR notation with the given prefix
@@ -58,8 +67,10 @@ func (a *Attributes) IPv6CIDR() string {
 }
 
 // ConfigOCInterface configures an OpenConfig interface with these attributes.
+// The addresses are configured on subinterface a.Subinterface, tagged with
+// a.VLAN and a.InnerVLAN if set.
 func (a *Attributes) ConfigOCInterface(intf *oc.Interface, dut *ondatra.DUTDevice) *oc.Interface {
-	if a.Desc != "" {
+	if a.Desc != "" && a.Subinterface == 0 {
 		intf.Description = ygot.String(a.Desc)
 	}
 	intf.Type = oc.IETFInterfaces_InterfaceType_ethernetCsmacd
@@ -69,12 +80,31 @@ func (a *Attributes) ConfigOCInterface(intf *oc.Interface, dut *ondatra.DUTDevic
 	if a.MTU > 0 && !deviations.OmitL2MTU(dut) {
 		intf.Mtu = ygot.Uint16(a.MTU + 14)
 	}
-	e := intf.GetOrCreateEthernet()
-	if a.MAC != "" {
-		e.MacAddress = ygot.String(a.MAC)
+	if a.LAG != nil {
+		a.LAG.configOCAggregation(intf)
+		if a.MAC != "" {
+			intf.GetOrCreateEthernet().MacAddress = ygot.String(a.MAC)
+		}
+	} else {
+		e := intf.GetOrCreateEthernet()
+		if a.MAC != "" {
+			e.MacAddress = ygot.String(a.MAC)
+		}
 	}
 
-	s := intf.GetOrCreateSubinterface(0)
+	s := intf.GetOrCreateSubinterface(a.Subinterface)
+	if a.Subinterface != 0 {
+		if a.Desc != "" {
+			s.Description = ygot.String(a.Desc)
+		}
+		if deviations.InterfaceEnabled(dut) {
+			s.Enabled = ygot.Bool(true)
+		}
+		if deviations.RequireRoutedSubinterface0(dut) {
+			intf.GetOrCreateSubinterface(0).GetOrCreateIpv4().Enabled = ygot.Bool(true)
+		}
+	}
+	configOCVLAN(s, dut, a.VLAN, a.InnerVLAN)
 	if a.IPv4 != "" {
 		s4 := s.GetOrCreateIpv4()
 		if deviations.InterfaceEnabled(dut) && !deviations.IPv4MissingEnabled(dut) {
@@ -105,6 +135,22 @@ func (a *Attributes) ConfigOCInterface(intf *oc.Interface, dut *ondatra.DUTDevic
 	return intf
 }
 
+// configOCVLAN sets the VLAN match of a subinterface. Nothing is configured
+// for untagged subinterfaces.
+func configOCVLAN(s *oc.Interface_Subinterface, dut *ondatra.DUTDevice, outer, inner uint16) {
+	switch {
+	case outer == 0:
+	case inner != 0:
+		dt := s.GetOrCreateVlan().GetOrCreateMatch().GetOrCreateDoubleTagged()
+		dt.OuterVlanId = ygot.Uint16(outer)
+		dt.InnerVlanId = ygot.Uint16(inner)
+	case deviations.DeprecatedVlanID(dut):
+		s.GetOrCreateVlan().VlanId = oc.UnionUint16(outer)
+	default:
+		s.GetOrCreateVlan().GetOrCreateMatch().GetOrCreateSingleTagged().VlanId = ygot.Uint16(outer)
+	}
+}
+
 // NewOCInterface returns a new *oc.Interface configured with these attributes.
 func (a *Attributes) NewOCInterface(name string, dut *ondatra.DUTDevice) *oc.Interface {
 	return a.ConfigOCInterface(&oc.Interface{Name: ygot.String(name)}, dut)
@@ -129,16 +175,42 @@ func (a *Attributes) AddToATE(top *ondatra.ATETopology, ap *ondatra.Port, peer *
 	return i
 }
 
-// AddToOTG adds basic elements to a gosnappi configuration
+// AddToOTG adds basic elements to a gosnappi configuration. The port is only
+// added if the configuration does not have it yet, so that several tagged
+// attributes can share a port.
 func (a *Attributes) AddToOTG(top gosnappi.Config, ap *ondatra.Port, peer *Attributes) gosnappi.Device {
-	top.Ports().Add().SetName(ap.ID())
+	addOTGPort(top, ap.ID())
+	return a.addOTGDevice(top, peer, func(eth gosnappi.DeviceEthernet) {
+		eth.Connection().SetPortName(ap.ID())
+	})
+}
+
+// addOTGPort adds a port to the configuration unless it already exists.
+func addOTGPort(top gosnappi.Config, name string) {
+	for _, p := range top.Ports().Items() {
+		if p.Name() == name {
+			return
+		}
+	}
+	top.Ports().Add().SetName(name)
+}
+
+// addOTGDevice adds a device with these attributes, using connect to attach
+// its Ethernet to a port or a LAG.
+func (a *Attributes) addOTGDevice(top gosnappi.Config, peer *Attributes, connect func(gosnappi.DeviceEthernet)) gosnappi.Device {
 	dev := top.Devices().Add().SetName(a.Name)
 	eth := dev.Ethernets().Add().SetName(a.Name + ".Eth").SetMac(a.MAC)
-	eth.Connection().SetPortName(ap.ID())
+	connect(eth)
 
 	if a.MTU > 0 {
 		eth.SetMtu(uint32(a.MTU))
 	}
+	if a.VLAN != 0 {
+		eth.Vlans().Add().SetName(a.Name + ".VLAN").SetId(uint32(a.VLAN))
+		if a.InnerVLAN != 0 {
+			eth.Vlans().Add().SetName(a.Name + ".InnerVLAN").SetId(uint32(a.InnerVLAN))
+		}
+	}
 	if a.IPv4 != "" {
 		ip := eth.Ipv4Addresses().Add().SetName(dev.Name() + ".IPv4")
 		ip.SetAddress(a.IPv4).SetGateway(peer.IPv4).SetPrefix(uint32(a.IPv4Len))
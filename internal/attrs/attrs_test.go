@@ -0,0 +1,90 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attrs
+
+import (
+	"testing"
+
+	"github.com/open-traffic-generator/snappi/gosnappi"
+)
+
+func TestAddOTGDeviceVLANs(t *testing.T) {
+	top := gosnappi.NewConfig()
+	a := &Attributes{Name: "ate", MAC: "02:00:01:01:01:01", VLAN: 100, InnerVLAN: 200, IPv4: "192.0.2.2", IPv4Len: 30}
+	peer := &Attributes{IPv4: "192.0.2.1"}
+	dev := a.addOTGDevice(top, peer, func(eth gosnappi.DeviceEthernet) {
+		eth.Connection().SetPortName("port1")
+	})
+	vlans := dev.Ethernets().Items()[0].Vlans().Items()
+	if len(vlans) != 2 {
+		t.Fatalf("addOTGDevice() got %d VLAN headers, want 2", len(vlans))
+	}
+	if vlans[0].Id() != 100 || vlans[1].Id() != 200 {
+		t.Errorf("addOTGDevice() got VLAN IDs %d, %d, want 100, 200", vlans[0].Id(), vlans[1].Id())
+	}
+
+	addOTGPort(top, "port1")
+	addOTGPort(top, "port1")
+	if got := len(top.Ports().Items()); got != 1 {
+		t.Errorf("addOTGPort() twice got %d ports, want 1", got)
+	}
+}
+
+func TestWithNoMixVLAN(t *testing.T) {
+	untagged := &Attributes{Name: "untagged"}
+	tagged := &Attributes{Name: "tagged", VLAN: 10}
+
+	got := withNoMixVLAN([]*Attributes{untagged, tagged}, true)
+	if got[0].VLAN != NoMixVLAN || got[1].VLAN != 10 {
+		t.Errorf("withNoMixVLAN() got VLANs %d, %d, want %d, 10", got[0].VLAN, got[1].VLAN, NoMixVLAN)
+	}
+	if untagged.VLAN != 0 {
+		t.Errorf("withNoMixVLAN() modified its input: got VLAN %d, want 0", untagged.VLAN)
+	}
+	if got := withNoMixVLAN([]*Attributes{untagged, tagged}, false); got[0].VLAN != 0 {
+		t.Errorf("withNoMixVLAN() without deviation got VLAN %d, want 0", got[0].VLAN)
+	}
+	if got := withNoMixVLAN([]*Attributes{untagged}, true); got[0].VLAN != 0 {
+		t.Errorf("withNoMixVLAN() on untagged port got VLAN %d, want 0", got[0].VLAN)
+	}
+}
+
+func TestMemberMAC(t *testing.T) {
+	tests := []struct {
+		mac  string
+		i    int
+		want string
+	}{
+		{"02:00:01:01:01:01", 0, "06:00:01:01:01:01"},
+		{"02:00:01:01:01:01", 3, "12:00:01:01:01:01"},
+		{"02:00:01:01:01:01", 62, "fe:00:01:01:01:01"},
+		{"02:00:01:01:01:01", 63, "06:01:01:01:01:01"},
+		{"not a mac", 1, "not a mac"},
+	}
+	for _, tt := range tests {
+		if got := memberMAC(tt.mac, tt.i); got != tt.want {
+			t.Errorf("memberMAC(%q, %d) got %q, want %q", tt.mac, tt.i, got, tt.want)
+		}
+	}
+
+	seen := map[string]int{"02:00:01:01:01:01": -1}
+	for i := 0; i < 256; i++ {
+		mac := memberMAC("02:00:01:01:01:01", i)
+		if j, ok := seen[mac]; ok {
+			t.Fatalf("memberMAC() of members %d and %d got the same MAC %s", j, i, mac)
+		}
+		seen[mac] = i
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attrs
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/open-traffic-generator/snappi/gosnappi"
+	"github.com/openconfig/featureprofiles/internal/deviations"
+	"github.com/openconfig/ondatra"
+	"github.com/openconfig/ondatra/gnmi/oc"
+	"github.com/openconfig/ygot/ygot"
+)
+
+// LAG holds the aggregate attributes of a LAG interface.
+type LAG struct {
+	// Type is LACP or STATIC. Defaults to LACP.
+	Type oc.E_IfAggregate_AggregationType
+	// LACPMode is the LACP activity of the DUT. Defaults to ACTIVE.
+	LACPMode oc.E_Lacp_LacpActivityType
+	// MinLinks is the minimum number of member links for the LAG to be up.
+	MinLinks uint16
+	// MemberMACs are the MAC addresses of the ATE member ports, in order.
+	// Members without one get memberMAC of the device MAC.
+	MemberMACs []string
+}
+
+func (l *LAG) lagType() oc.E_IfAggregate_AggregationType {
+	if l.Type == oc.IfAggregate_AggregationType_UNSET {
+		return oc.IfAggregate_AggregationType_LACP
+	}
+	return l.Type
+}
+
+func (l *LAG) lacpMode() oc.E_Lacp_LacpActivityType {
+	if l.LACPMode == oc.Lacp_LacpActivityType_UNSET {
+		return oc.Lacp_LacpActivityType_ACTIVE
+	}
+	return l.LACPMode
+}
+
+func (l *LAG) configOCAggregation(intf *oc.Interface) {
+	intf.Type = oc.IETFInterfaces_InterfaceType_ieee8023adLag
+	agg := intf.GetOrCreateAggregation()
+	agg.LagType = l.lagType()
+	if l.MinLinks > 0 {
+		agg.MinLinks = ygot.Uint16(l.MinLinks)
+	}
+}
+
+// ConfigOCLAG configures the aggregate interface aggID with these attributes
+// on root, along with its member ports and, for LACP, the LACP interface.
+// a.LAG defaults to an active LACP bundle if unset.
+//
+// The aggregate and its members are built in the same root so that they can
+// be pushed in a single update, as required by devices with the
+// AggregateAtomicUpdate deviation.
+func (a *Attributes) ConfigOCLAG(root *oc.Root, dut *ondatra.DUTDevice, aggID string, members []*ondatra.Port) *oc.Interface {
+	c := *a
+	if c.LAG == nil {
+		c.LAG = &LAG{}
+	}
+	agg := c.ConfigOCInterface(root.GetOrCreateInterface(aggID), dut)
+
+	if c.LAG.lagType() == oc.IfAggregate_AggregationType_LACP {
+		lacp := root.GetOrCreateLacp().GetOrCreateInterface(aggID)
+		lacp.LacpMode = c.LAG.lacpMode()
+	}
+	for _, p := range members {
+		i := root.GetOrCreateInterface(p.Name())
+		i.Type = oc.IETFInterfaces_InterfaceType_ethernetCsmacd
+		if deviations.InterfaceEnabled(dut) {
+			i.Enabled = ygot.Bool(true)
+		}
+		i.GetOrCreateEthernet().AggregateId = ygot.String(aggID)
+	}
+	return agg
+}
+
+// AddLAGToOTG adds a LAG over the ATE ports and a device with these
+// attributes on top of it. lagID is used as the LACP actor key or the static
+// LAG ID. Member ports get the MAC addresses of a.LAG.MemberMACs, or
+// memberMAC of a.MAC when there are not enough of them. The ATE side of an
+// LACP bundle is always active so that it comes up with a passive DUT.
+func (a *Attributes) AddLAGToOTG(top gosnappi.Config, aps []*ondatra.Port, peer *Attributes, lagID uint32) gosnappi.Device {
+	l := a.LAG
+	if l == nil {
+		l = &LAG{}
+	}
+	agg := top.Lags().Add().SetName(a.Name + ".LAG")
+	if l.MinLinks > 0 {
+		agg.SetMinLinks(uint32(l.MinLinks))
+	}
+	lacp := l.lagType() == oc.IfAggregate_AggregationType_LACP
+	if lacp {
+		agg.Protocol().Lacp().SetActorKey(lagID).SetActorSystemPriority(1).SetActorSystemId(a.MAC)
+	} else {
+		agg.Protocol().Static().SetLagId(lagID)
+	}
+	for i, ap := range aps {
+		addOTGPort(top, ap.ID())
+		lp := agg.Ports().Add().SetPortName(ap.ID())
+		mac := memberMAC(a.MAC, i)
+		if i < len(l.MemberMACs) {
+			mac = l.MemberMACs[i]
+		}
+		lp.Ethernet().SetMac(mac).SetName(fmt.Sprintf("%s.%s.Eth", a.Name, ap.ID()))
+		if lacp {
+			lp.Lacp().SetActorActivity(gosnappi.LagPortLacpActorActivity.ACTIVE).
+				SetActorPortNumber(uint32(i + 1)).
+				SetActorPortPriority(1).
+				SetLacpduTimeout(0)
+		}
+	}
+	return a.addOTGDevice(top, peer, func(eth gosnappi.DeviceEthernet) {
+		eth.Connection().SetLagName(agg.Name())
+	})
+}
+
+// memberMAC returns the default MAC of the i-th member port of a LAG whose
+// device has MAC mac. The member index is encoded in the first octet, which
+// stays locally administered unicast but differs from the 02 of device MACs,
+// so member MACs collide neither with devices nor with each other. The first
+// octet holds 63 members; further members also flip bits of the second
+// octet.
+func memberMAC(mac string, i int) string {
+	hw, err := net.ParseMAC(mac)
+	if err != nil || len(hw) != 6 {
+		return mac
+	}
+	hw[0] = 0x02 | byte(i%63+1)<<2
+	hw[1] ^= byte(i / 63)
+	return hw.String()
+}
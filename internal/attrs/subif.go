@@ -0,0 +1,89 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attrs
+
+import (
+	"github.com/open-traffic-generator/snappi/gosnappi"
+	"github.com/openconfig/featureprofiles/internal/deviations"
+	"github.com/openconfig/ondatra"
+	"github.com/openconfig/ondatra/gnmi/oc"
+)
+
+// NoMixVLAN is the VLAN ID given to the untagged subinterface of a port that
+// also has tagged subinterfaces, on devices that do not support a mix of
+// tagged and untagged subinterfaces.
+const NoMixVLAN = 1
+
+// mixedTagging reports whether some of the attributes are tagged and some
+// are not.
+func mixedTagging(as []*Attributes) bool {
+	var tagged, untagged bool
+	for _, a := range as {
+		if a.VLAN == 0 {
+			untagged = true
+		} else {
+			tagged = true
+		}
+	}
+	return tagged && untagged
+}
+
+// withNoMixVLAN returns copies of the attributes where untagged ones are
+// tagged with NoMixVLAN if tagUntagged is set and the tagging is mixed.
+func withNoMixVLAN(as []*Attributes, tagUntagged bool) []*Attributes {
+	if !tagUntagged || !mixedTagging(as) {
+		return as
+	}
+	out := make([]*Attributes, len(as))
+	for i, a := range as {
+		c := *a
+		if c.VLAN == 0 {
+			c.VLAN = NoMixVLAN
+		}
+		out[i] = &c
+	}
+	return out
+}
+
+// ConfigOCSubinterfaces configures several subinterfaces of a single DUT
+// interface, one per attributes. The interface description is taken from the
+// attributes of subinterface 0.
+//
+// If the device does not support a mix of tagged and untagged subinterfaces,
+// untagged subinterfaces are tagged with NoMixVLAN. Use
+// AddSubinterfacesToOTG to configure the ATE port to match.
+func ConfigOCSubinterfaces(intf *oc.Interface, dut *ondatra.DUTDevice, as ...*Attributes) *oc.Interface {
+	for _, a := range withNoMixVLAN(as, deviations.NoMixOfTaggedAndUntaggedSubinterfaces(dut)) {
+		a.ConfigOCInterface(intf, dut)
+	}
+	return intf
+}
+
+// AddSubinterfacesToOTG adds a device per link pair on the ATE port, using
+// the ATE attributes of each pair with the DUT attributes as peer. Untagged
+// devices are tagged with NoMixVLAN when the DUT does not support a mix of
+// tagged and untagged subinterfaces, as done by ConfigOCSubinterfaces.
+func AddSubinterfacesToOTG(top gosnappi.Config, ap *ondatra.Port, dut *ondatra.DUTDevice, pairs ...*LinkPair) []gosnappi.Device {
+	ates := make([]*Attributes, len(pairs))
+	for i, p := range pairs {
+		ates[i] = p.ATE
+	}
+	ates = withNoMixVLAN(ates, deviations.NoMixOfTaggedAndUntaggedSubinterfaces(dut))
+	var devs []gosnappi.Device
+	for i, a := range ates {
+		devs = append(devs, a.AddToOTG(top, ap, pairs[i].DUT))
+	}
+	return devs
+}
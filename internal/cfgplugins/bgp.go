@@ -153,6 +153,8 @@ type BGPSession struct {
 	ATEPorts        []*attrs.Attributes
 	afiTypes        []oc.E_BgpTypes_AFI_SAFI_TYPE
 	networkInstance string
+	// loopback is the DUT loopback interface used by multihop neighbors.
+	loopback string
 }
 
 // NewBGPSession creates a new BGPSession using the default global config, and
@@ -288,6 +290,9 @@ func (bs *BGPSession) PushDUT(t testing.TB) error {
 		for i := 0; i < len(bs.DUTPorts); i++ {
 			fptest.AssignToNetworkInstance(t, bs.DUT, bs.OndatraDUTPorts[i].Name(), bs.networkInstance, 0)
 		}
+		if bs.loopback != "" {
+			fptest.AssignToNetworkInstance(t, bs.DUT, bs.loopback, bs.networkInstance, 0)
+		}
 	}
 	if deviations.ExplicitPortSpeed(bs.DUT) {
 		for i := 0; i < len(bs.DUTPorts); i++ {
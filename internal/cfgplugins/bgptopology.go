@@ -0,0 +1,513 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgplugins
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/open-traffic-generator/snappi/gosnappi"
+	"github.com/openconfig/featureprofiles/internal/attrs"
+	"github.com/openconfig/featureprofiles/internal/deviations"
+	"github.com/openconfig/featureprofiles/internal/fptest"
+	"github.com/openconfig/ondatra"
+	"github.com/openconfig/ondatra/gnmi/oc"
+	"github.com/openconfig/ondatra/netutil"
+	"github.com/openconfig/ygot/ygot"
+)
+
+// BGPRouteRange is a range of prefixes advertised by an ATE peer.
+type BGPRouteRange struct {
+	// Prefix is the first advertised prefix, e.g. "198.51.100.0/24". IPv4
+	// prefixes are advertised over the IPv4 session, IPv6 prefixes over the
+	// IPv6 session.
+	Prefix string
+	// Count is the number of consecutive prefixes. Defaults to 1.
+	Count uint32
+}
+
+// BGPNeighbor describes a BGP peering between the DUT and the ATE on a port.
+type BGPNeighbor struct {
+	// Port is the ID of the DUT and ATE ports the neighbor is on, e.g. "port1".
+	Port string
+	// AS is the AS of the ATE peer. Neighbors in the DUT AS are iBGP peers.
+	AS uint32
+	// PeerGroup defaults to "BGP-PEER-GROUP-<Port>".
+	PeerGroup string
+	// AfiSafis selects the sessions to establish: IPV4_UNICAST peers over
+	// IPv4 and IPV6_UNICAST over IPv6. Defaults to both.
+	AfiSafis []oc.E_BgpTypes_AFI_SAFI_TYPE
+	// RRClient configures the neighbor as a route reflector client.
+	RRClient bool
+	// Multihop peers between a DUT loopback and a loopback of the ATE
+	// device, reached through static routes, instead of the link addresses.
+	// MultihopTTL defaults to 255 and only applies to eBGP neighbors.
+	Multihop    bool
+	MultihopTTL uint8
+	// BFD enables BFD for the neighbor on the DUT. OTG does not support BFD,
+	// so the ATE side is not configured.
+	BFD          bool
+	AuthPassword string
+	// HoldTime and KeepaliveInterval are in seconds. Zero keeps the defaults.
+	HoldTime          uint16
+	KeepaliveInterval uint16
+	// GracefulRestart enables graceful restart with the given restart and
+	// stale routes times in seconds. Zero times keep the defaults.
+	GracefulRestart bool
+	RestartTime     uint16
+	StaleRoutesTime uint16
+	// Routes are advertised by the ATE peer.
+	Routes []*BGPRouteRange
+}
+
+func (n *BGPNeighbor) hasAfiSafi(afi oc.E_BgpTypes_AFI_SAFI_TYPE) bool {
+	return len(n.AfiSafis) == 0 || containsValue(n.AfiSafis, afi)
+}
+
+func (n *BGPNeighbor) peerGroup() string {
+	if n.PeerGroup != "" {
+		return n.PeerGroup
+	}
+	return "BGP-PEER-GROUP-" + n.Port
+}
+
+func (n *BGPNeighbor) multihopTTL() uint8 {
+	if n.MultihopTTL == 0 {
+		return 255
+	}
+	return n.MultihopTTL
+}
+
+// BGPTopology describes the ports and BGP neighbors of a test. It is used to
+// generate both the DUT configuration and the matching OTG configuration.
+//
+// Usage:
+//
+//	bs := cfgplugins.NewBGPSessionFromTopology(t, &cfgplugins.BGPTopology{
+//	  Ports: 3,
+//	  Neighbors: []*cfgplugins.BGPNeighbor{
+//	    {Port: "port1", AS: 65511, Routes: []*cfgplugins.BGPRouteRange{{Prefix: "198.51.100.0/24", Count: 10}}},
+//	    {Port: "port2", AS: cfgplugins.DutAS, RRClient: true},
+//	    {Port: "port3", AS: 65513, Multihop: true, BFD: true},
+//	  },
+//	})
+//	bs.PushAndStart(t)
+type BGPTopology struct {
+	// Ports is the number of DUT and ATE ports to configure, starting with
+	// "port1". Their addresses are allocated with attrs.Allocator.
+	Ports int
+	// AS is the AS of the DUT. Defaults to DutAS.
+	AS uint32
+	// NetworkInstance defaults to the default network instance.
+	NetworkInstance string
+	Neighbors       []*BGPNeighbor
+}
+
+func (bt *BGPTopology) as() uint32 {
+	if bt.AS == 0 {
+		return DutAS
+	}
+	return bt.AS
+}
+
+// Validate checks that the topology is consistent.
+func (bt *BGPTopology) Validate() error {
+	if bt.Ports < 1 {
+		return fmt.Errorf("topology must have at least one port, got %d", bt.Ports)
+	}
+	seen := map[string]bool{}
+	for _, n := range bt.Neighbors {
+		idx, err := portIndex(n.Port)
+		if err != nil {
+			return err
+		}
+		if idx > bt.Ports {
+			return fmt.Errorf("neighbor on %s is out of the %d topology ports", n.Port, bt.Ports)
+		}
+		if seen[n.Port] {
+			return fmt.Errorf("more than one neighbor on %s", n.Port)
+		}
+		seen[n.Port] = true
+		if n.AS == 0 {
+			return fmt.Errorf("neighbor on %s has no AS", n.Port)
+		}
+		for _, afi := range n.AfiSafis {
+			if afi != oc.BgpTypes_AFI_SAFI_TYPE_IPV4_UNICAST && afi != oc.BgpTypes_AFI_SAFI_TYPE_IPV6_UNICAST {
+				return fmt.Errorf("neighbor on %s has unsupported AFI-SAFI %v", n.Port, afi)
+			}
+		}
+		for _, r := range n.Routes {
+			p, err := netip.ParsePrefix(r.Prefix)
+			if err != nil {
+				return fmt.Errorf("neighbor on %s has invalid route prefix: %w", n.Port, err)
+			}
+			afi := oc.BgpTypes_AFI_SAFI_TYPE_IPV6_UNICAST
+			if p.Addr().Is4() {
+				afi = oc.BgpTypes_AFI_SAFI_TYPE_IPV4_UNICAST
+			}
+			if !n.hasAfiSafi(afi) {
+				return fmt.Errorf("neighbor on %s advertises %s without a %v session", n.Port, r.Prefix, afi)
+			}
+		}
+	}
+	return nil
+}
+
+// portIndex returns the 1-based index of a port ID of the form "portN".
+func portIndex(port string) (int, error) {
+	num, ok := strings.CutPrefix(port, "port")
+	idx, err := strconv.Atoi(num)
+	if !ok || err != nil || idx < 1 {
+		return 0, fmt.Errorf("invalid port %q, want portN", port)
+	}
+	return idx, nil
+}
+
+// bgpPeer holds the resolved addresses of a neighbor. The dut and ate
+// attributes are those used for peering, which are loopbacks for multihop
+// neighbors.
+type bgpPeer struct {
+	*BGPNeighbor
+	link     *attrs.LinkPair
+	dut, ate *attrs.Attributes
+}
+
+func (p *bgpPeer) ibgp(localAS uint32) bool {
+	return p.AS == localAS
+}
+
+// resolvePeers allocates the addresses of the topology and resolves the
+// peering addresses of every neighbor. The DUT loopback is only returned if
+// a neighbor uses multihop.
+func (bt *BGPTopology) resolvePeers() (*attrs.Plan, *attrs.Attributes, []*bgpPeer, error) {
+	loopbacks := 1
+	for _, n := range bt.Neighbors {
+		if n.Multihop {
+			loopbacks++
+		}
+	}
+	alloc, err := attrs.NewAllocator(nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	plan, err := alloc.Allocate(&attrs.Topology{Ports: bt.Ports, Loopbacks: loopbacks})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var dutLoopback *attrs.Attributes
+	next := 1
+	var peers []*bgpPeer
+	for _, n := range bt.Neighbors {
+		idx, err := portIndex(n.Port)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		link := plan.Port(idx)
+		p := &bgpPeer{BGPNeighbor: n, link: link, dut: link.DUT, ate: link.ATE}
+		if n.Multihop {
+			dutLoopback = plan.Loopbacks[0]
+			p.dut = dutLoopback
+			p.ate = plan.Loopbacks[next]
+			next++
+		}
+		peers = append(peers, p)
+	}
+	return plan, dutLoopback, peers, nil
+}
+
+// ocBGP builds the DUT BGP configuration of the peers. If policyUnderPG is
+// set, the permit-all policy is applied to peer groups rather than to their
+// AFI-SAFIs.
+func (bt *BGPTopology) ocBGP(peers []*bgpPeer, routerID string, policyUnderPG bool) *oc.NetworkInstance_Protocol_Bgp {
+	bgp := &oc.NetworkInstance_Protocol_Bgp{}
+	global := bgp.GetOrCreateGlobal()
+	global.As = ygot.Uint32(bt.as())
+	global.RouterId = ygot.String(routerID)
+
+	for _, p := range peers {
+		if p.GracefulRestart {
+			global.GetOrCreateGracefulRestart().Enabled = ygot.Bool(true)
+		}
+		for _, s := range []struct {
+			afi        oc.E_BgpTypes_AFI_SAFI_TYPE
+			local, nbr string
+		}{
+			{oc.BgpTypes_AFI_SAFI_TYPE_IPV4_UNICAST, p.dut.IPv4, p.ate.IPv4},
+			{oc.BgpTypes_AFI_SAFI_TYPE_IPV6_UNICAST, p.dut.IPv6, p.ate.IPv6},
+		} {
+			if !p.hasAfiSafi(s.afi) {
+				continue
+			}
+			global.GetOrCreateAfiSafi(s.afi).Enabled = ygot.Bool(true)
+
+			pg := bgp.GetOrCreatePeerGroup(p.peerGroup())
+			pg.PeerAs = ygot.Uint32(p.AS)
+			if policyUnderPG {
+				rpl := pg.GetOrCreateApplyPolicy()
+				rpl.SetExportPolicy([]string{RPLPermitAll})
+				rpl.SetImportPolicy([]string{RPLPermitAll})
+			} else {
+				pgAfi := pg.GetOrCreateAfiSafi(s.afi)
+				pgAfi.Enabled = ygot.Bool(true)
+				rpl := pgAfi.GetOrCreateApplyPolicy()
+				rpl.SetExportPolicy([]string{RPLPermitAll})
+				rpl.SetImportPolicy([]string{RPLPermitAll})
+			}
+
+			n := bgp.GetOrCreateNeighbor(s.nbr)
+			n.PeerAs = ygot.Uint32(p.AS)
+			n.PeerGroup = ygot.String(p.peerGroup())
+			n.Enabled = ygot.Bool(true)
+			n.GetOrCreateAfiSafi(s.afi).Enabled = ygot.Bool(true)
+			if p.AuthPassword != "" {
+				n.AuthPassword = ygot.String(p.AuthPassword)
+			}
+			if p.RRClient {
+				rr := n.GetOrCreateRouteReflector()
+				rr.RouteReflectorClient = ygot.Bool(true)
+				rr.RouteReflectorClusterId = oc.UnionString(routerID)
+			}
+			if p.Multihop {
+				n.GetOrCreateTransport().LocalAddress = ygot.String(s.local)
+				if !p.ibgp(bt.as()) {
+					mh := n.GetOrCreateEbgpMultihop()
+					mh.Enabled = ygot.Bool(true)
+					mh.MultihopTtl = ygot.Uint8(p.multihopTTL())
+				}
+			}
+			if p.BFD {
+				n.GetOrCreateEnableBfd().Enabled = ygot.Bool(true)
+			}
+			if p.HoldTime > 0 || p.KeepaliveInterval > 0 {
+				timers := n.GetOrCreateTimers()
+				if p.HoldTime > 0 {
+					timers.HoldTime = ygot.Uint16(p.HoldTime)
+				}
+				if p.KeepaliveInterval > 0 {
+					timers.KeepaliveInterval = ygot.Uint16(p.KeepaliveInterval)
+				}
+			}
+			if p.GracefulRestart {
+				gr := n.GetOrCreateGracefulRestart()
+				gr.Enabled = ygot.Bool(true)
+				if p.RestartTime > 0 {
+					gr.RestartTime = ygot.Uint16(p.RestartTime)
+				}
+				if p.StaleRoutesTime > 0 {
+					gr.StaleRoutesTime = ygot.Uint16(p.StaleRoutesTime)
+				}
+			}
+		}
+	}
+	return bgp
+}
+
+// otgPeer is implemented by both OTG BGPv4 and BGPv6 peers.
+type otgPeer interface {
+	Advanced() gosnappi.BgpAdvanced
+	GracefulRestart() gosnappi.BgpGracefulRestart
+}
+
+func (p *bgpPeer) configOTGPeerOptions(peer otgPeer) {
+	adv := peer.Advanced()
+	if p.HoldTime > 0 {
+		adv.SetHoldTimeInterval(uint32(p.HoldTime))
+	}
+	if p.KeepaliveInterval > 0 {
+		adv.SetKeepAliveInterval(uint32(p.KeepaliveInterval))
+	}
+	if p.AuthPassword != "" {
+		adv.SetMd5Key(p.AuthPassword)
+	}
+	if p.Multihop {
+		adv.SetTimeToLive(uint32(p.multihopTTL()))
+	}
+	if p.GracefulRestart {
+		gr := peer.GracefulRestart().SetEnableGr(true)
+		if p.RestartTime > 0 {
+			gr.SetRestartTime(uint32(p.RestartTime))
+		}
+		if p.StaleRoutesTime > 0 {
+			gr.SetStaleTime(uint32(p.StaleRoutesTime))
+		}
+	}
+}
+
+// configOTG adds the BGP peers of the neighbor to the ATE device of its
+// port, along with the ATE loopbacks of multihop neighbors.
+func (p *bgpPeer) configOTG(dev gosnappi.Device, localAS uint32) {
+	eth := dev.Ethernets().Items()[0]
+	bgp := dev.Bgp().SetRouterId(p.link.ATE.IPv4)
+
+	if p.hasAfiSafi(oc.BgpTypes_AFI_SAFI_TYPE_IPV4_UNICAST) {
+		ipName := eth.Ipv4Addresses().Items()[0].Name()
+		if p.Multihop {
+			ipName = dev.Ipv4Loopbacks().Add().SetName(dev.Name() + ".Loopback4").SetEthName(eth.Name()).SetAddress(p.ate.IPv4).Name()
+		}
+		peer := bgp.Ipv4Interfaces().Add().SetIpv4Name(ipName).Peers().Add().SetName(dev.Name() + ".BGP4.peer")
+		peer.SetPeerAddress(p.dut.IPv4).SetAsNumber(p.AS)
+		peer.SetAsType(gosnappi.BgpV4PeerAsType.EBGP)
+		if p.ibgp(localAS) {
+			peer.SetAsType(gosnappi.BgpV4PeerAsType.IBGP)
+		}
+		peer.Capability().SetIpv4UnicastAddPath(true).SetIpv6UnicastAddPath(true)
+		peer.LearnedInformationFilter().SetUnicastIpv4Prefix(true).SetUnicastIpv6Prefix(true)
+		p.configOTGPeerOptions(peer)
+		for i, r := range p.Routes {
+			pfx := netip.MustParsePrefix(r.Prefix)
+			if !pfx.Addr().Is4() {
+				continue
+			}
+			rr := peer.V4Routes().Add().SetName(fmt.Sprintf("%s.v4routes%d", peer.Name(), i))
+			rr.SetNextHopIpv4Address(p.ate.IPv4).
+				SetNextHopAddressType(gosnappi.BgpV4RouteRangeNextHopAddressType.IPV4).
+				SetNextHopMode(gosnappi.BgpV4RouteRangeNextHopMode.MANUAL)
+			rr.Addresses().Add().SetAddress(pfx.Addr().String()).SetPrefix(uint32(pfx.Bits())).SetCount(routeCount(r))
+		}
+	}
+
+	if p.hasAfiSafi(oc.BgpTypes_AFI_SAFI_TYPE_IPV6_UNICAST) {
+		ipName := eth.Ipv6Addresses().Items()[0].Name()
+		if p.Multihop {
+			ipName = dev.Ipv6Loopbacks().Add().SetName(dev.Name() + ".Loopback6").SetEthName(eth.Name()).SetAddress(p.ate.IPv6).Name()
+		}
+		peer := bgp.Ipv6Interfaces().Add().SetIpv6Name(ipName).Peers().Add().SetName(dev.Name() + ".BGP6.peer")
+		peer.SetPeerAddress(p.dut.IPv6).SetAsNumber(p.AS)
+		peer.SetAsType(gosnappi.BgpV6PeerAsType.EBGP)
+		if p.ibgp(localAS) {
+			peer.SetAsType(gosnappi.BgpV6PeerAsType.IBGP)
+		}
+		peer.Capability().SetIpv4UnicastAddPath(true).SetIpv6UnicastAddPath(true).SetExtendedNextHopEncoding(true)
+		peer.LearnedInformationFilter().SetUnicastIpv4Prefix(true).SetUnicastIpv6Prefix(true)
+		p.configOTGPeerOptions(peer)
+		for i, r := range p.Routes {
+			pfx := netip.MustParsePrefix(r.Prefix)
+			if pfx.Addr().Is4() {
+				continue
+			}
+			rr := peer.V6Routes().Add().SetName(fmt.Sprintf("%s.v6routes%d", peer.Name(), i))
+			rr.SetNextHopIpv6Address(p.ate.IPv6).
+				SetNextHopAddressType(gosnappi.BgpV6RouteRangeNextHopAddressType.IPV6).
+				SetNextHopMode(gosnappi.BgpV6RouteRangeNextHopMode.MANUAL)
+			rr.Addresses().Add().SetAddress(pfx.Addr().String()).SetPrefix(uint32(pfx.Bits())).SetCount(routeCount(r))
+		}
+	}
+}
+
+func routeCount(r *BGPRouteRange) uint32 {
+	if r.Count == 0 {
+		return 1
+	}
+	return r.Count
+}
+
+// configOCLoopback configures a loopback interface with the /32 and /128
+// addresses of a.
+func configOCLoopback(intf *oc.Interface, a *attrs.Attributes, dut *ondatra.DUTDevice) {
+	intf.Type = oc.IETFInterfaces_InterfaceType_softwareLoopback
+	if deviations.InterfaceEnabled(dut) {
+		intf.Enabled = ygot.Bool(true)
+	}
+	s := intf.GetOrCreateSubinterface(0)
+	s4 := s.GetOrCreateIpv4()
+	if deviations.InterfaceEnabled(dut) && !deviations.IPv4MissingEnabled(dut) {
+		s4.Enabled = ygot.Bool(true)
+	}
+	s4.GetOrCreateAddress(a.IPv4).PrefixLength = ygot.Uint8(a.IPv4Len)
+	s6 := s.GetOrCreateIpv6()
+	if deviations.InterfaceEnabled(dut) {
+		s6.Enabled = ygot.Bool(true)
+	}
+	s6.GetOrCreateAddress(a.IPv6).PrefixLength = ygot.Uint8(a.IPv6Len)
+}
+
+// NewBGPSessionFromTopology creates a BGPSession for any number of ports and
+// mix of iBGP and eBGP neighbors described by bt. The DUT interfaces, BGP,
+// loopback and static routes for multihop neighbors are added to DUTConf,
+// and the matching OTG devices, BGP peers and route ranges to ATETop.
+func NewBGPSessionFromTopology(t *testing.T, bt *BGPTopology) *BGPSession {
+	t.Helper()
+	if err := bt.Validate(); err != nil {
+		t.Fatalf("Invalid BGP topology: %v", err)
+	}
+	plan, dutLoopback, peers, err := bt.resolvePeers()
+	if err != nil {
+		t.Fatalf("Could not allocate BGP topology addresses: %v", err)
+	}
+
+	bs := &BGPSession{
+		DUT:             ondatra.DUT(t, "dut"),
+		DUTConf:         &oc.Root{},
+		OndatraDUTPorts: make([]*ondatra.Port, bt.Ports),
+		OndatraATEPorts: make([]*ondatra.Port, bt.Ports),
+		ATEIntfs:        make([]gosnappi.Device, bt.Ports),
+		networkInstance: bt.NetworkInstance,
+	}
+	for i := 0; i < bt.Ports; i++ {
+		link := plan.Port(i + 1)
+		bs.DUTPorts = append(bs.DUTPorts, link.DUT)
+		bs.ATEPorts = append(bs.ATEPorts, link.ATE)
+		bs.OndatraDUTPorts[i] = bs.DUT.Port(t, "port"+strconv.Itoa(i+1))
+		link.DUT.ConfigOCInterface(bs.DUTConf.GetOrCreateInterface(bs.OndatraDUTPorts[i].Name()), bs.DUT)
+	}
+	if bs.networkInstance == "" {
+		fptest.ConfigureDefaultNetworkInstance(t, bs.DUT)
+		bs.networkInstance = deviations.DefaultNetworkInstance(bs.DUT)
+	}
+	ni := bs.DUTConf.GetOrCreateNetworkInstance(bs.networkInstance)
+
+	if dutLoopback != nil {
+		bs.loopback = netutil.LoopbackInterface(t, bs.DUT, 0)
+		configOCLoopback(bs.DUTConf.GetOrCreateInterface(bs.loopback), dutLoopback, bs.DUT)
+		static := ni.GetOrCreateProtocol(oc.PolicyTypes_INSTALL_PROTOCOL_TYPE_STATIC, deviations.StaticProtocolName(bs.DUT))
+		for _, p := range peers {
+			if !p.Multihop {
+				continue
+			}
+			static.GetOrCreateStatic(p.ate.IPv4 + "/32").GetOrCreateNextHop("0").NextHop = oc.UnionString(p.link.ATE.IPv4)
+			static.GetOrCreateStatic(p.ate.IPv6 + "/128").GetOrCreateNextHop("0").NextHop = oc.UnionString(p.link.ATE.IPv6)
+		}
+	}
+
+	bs.afiTypes = nil
+	for _, p := range peers {
+		for _, afi := range []oc.E_BgpTypes_AFI_SAFI_TYPE{oc.BgpTypes_AFI_SAFI_TYPE_IPV4_UNICAST, oc.BgpTypes_AFI_SAFI_TYPE_IPV6_UNICAST} {
+			if p.hasAfiSafi(afi) && !containsValue(bs.afiTypes, afi) {
+				bs.afiTypes = append(bs.afiTypes, afi)
+			}
+		}
+	}
+	ni.GetOrCreateProtocol(PTBGP, bgpName).Bgp = bt.ocBGP(peers, plan.Port(1).DUT.IPv4, deviations.RoutePolicyUnderAFIUnsupported(bs.DUT))
+	if err := bs.configureRoutingPolicy(); err != nil {
+		t.Fatalf("Failed to configure routing policy: %v", err)
+	}
+
+	if ate, ok := ondatra.ATEs(t)["ate"]; ok {
+		bs.ATE = ate
+		bs.ATETop = gosnappi.NewConfig()
+		for i := 0; i < bt.Ports; i++ {
+			bs.OndatraATEPorts[i] = bs.ATE.Port(t, "port"+strconv.Itoa(i+1))
+			bs.ATEIntfs[i] = bs.ATEPorts[i].AddToOTG(bs.ATETop, bs.OndatraATEPorts[i], bs.DUTPorts[i])
+		}
+		for _, p := range peers {
+			idx, _ := portIndex(p.Port)
+			p.configOTG(bs.ATEIntfs[idx-1], bt.as())
+		}
+	}
+	return bs
+}
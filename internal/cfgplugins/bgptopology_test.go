@@ -0,0 +1,155 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgplugins
+
+import (
+	"testing"
+
+	"github.com/open-traffic-generator/snappi/gosnappi"
+	"github.com/openconfig/ondatra/gnmi/oc"
+)
+
+func testTopology() *BGPTopology {
+	return &BGPTopology{
+		Ports: 3,
+		Neighbors: []*BGPNeighbor{
+			{Port: "port1", AS: 65511, AuthPassword: "secret", Routes: []*BGPRouteRange{{Prefix: "198.51.100.0/24", Count: 10}, {Prefix: "2001:db8:100::/48"}}},
+			{Port: "port2", AS: DutAS, RRClient: true, AfiSafis: []oc.E_BgpTypes_AFI_SAFI_TYPE{oc.BgpTypes_AFI_SAFI_TYPE_IPV4_UNICAST}},
+			{Port: "port3", AS: 65513, Multihop: true, BFD: true, GracefulRestart: true, RestartTime: 120},
+		},
+	}
+}
+
+func TestBGPTopologyValidate(t *testing.T) {
+	if err := testTopology().Validate(); err != nil {
+		t.Fatalf("Validate() got unexpected error: %v", err)
+	}
+	tests := []struct {
+		desc string
+		mod  func(*BGPTopology)
+	}{
+		{"no ports", func(bt *BGPTopology) { bt.Ports = 0 }},
+		{"bad port", func(bt *BGPTopology) { bt.Neighbors[0].Port = "eth1" }},
+		{"port out of range", func(bt *BGPTopology) { bt.Neighbors[0].Port = "port4" }},
+		{"duplicate port", func(bt *BGPTopology) { bt.Neighbors[1].Port = "port1" }},
+		{"no AS", func(bt *BGPTopology) { bt.Neighbors[0].AS = 0 }},
+		{"unsupported AFI-SAFI", func(bt *BGPTopology) {
+			bt.Neighbors[0].AfiSafis = []oc.E_BgpTypes_AFI_SAFI_TYPE{oc.BgpTypes_AFI_SAFI_TYPE_L3VPN_IPV4_UNICAST}
+		}},
+		{"route without session", func(bt *BGPTopology) {
+			bt.Neighbors[1].Routes = []*BGPRouteRange{{Prefix: "2001:db8:200::/48"}}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			bt := testTopology()
+			tt.mod(bt)
+			if err := bt.Validate(); err == nil {
+				t.Errorf("Validate() got no error, want error")
+			}
+		})
+	}
+}
+
+func TestBGPTopologyOCBGP(t *testing.T) {
+	bt := testTopology()
+	plan, lo, peers, err := bt.resolvePeers()
+	if err != nil {
+		t.Fatalf("resolvePeers() got unexpected error: %v", err)
+	}
+	if lo == nil || peers[2].dut != lo {
+		t.Fatalf("resolvePeers() did not peer the multihop neighbor with the DUT loopback")
+	}
+	bgp := bt.ocBGP(peers, plan.Port(1).DUT.IPv4, false)
+
+	if got := bgp.GetGlobal().GetAs(); got != DutAS {
+		t.Errorf("global AS got %d, want %d", got, DutAS)
+	}
+	// 2 sessions for port1, 1 for port2 and 2 for port3.
+	if got := len(bgp.Neighbor); got != 5 {
+		t.Errorf("got %d neighbors, want 5", got)
+	}
+	n1 := bgp.GetNeighbor(peers[0].ate.IPv4)
+	if n1.GetAuthPassword() != "secret" || n1.GetPeerGroup() != "BGP-PEER-GROUP-port1" {
+		t.Errorf("port1 neighbor got password %q and peer group %q, want secret and BGP-PEER-GROUP-port1", n1.GetAuthPassword(), n1.GetPeerGroup())
+	}
+	if n2 := bgp.GetNeighbor(peers[1].ate.IPv4); !n2.GetRouteReflector().GetRouteReflectorClient() {
+		t.Errorf("port2 neighbor is not a route reflector client")
+	}
+	if n2v6 := bgp.GetNeighbor(peers[1].ate.IPv6); n2v6 != nil {
+		t.Errorf("port2 neighbor got an IPv6 session, want none")
+	}
+	n3 := bgp.GetNeighbor(peers[2].ate.IPv6)
+	if n3 == nil {
+		t.Fatalf("port3 neighbor is missing its IPv6 loopback session")
+	}
+	if got := n3.GetTransport().GetLocalAddress(); got != lo.IPv6 {
+		t.Errorf("port3 neighbor got local address %s, want %s", got, lo.IPv6)
+	}
+	if !n3.GetEbgpMultihop().GetEnabled() || n3.GetEbgpMultihop().GetMultihopTtl() != 255 {
+		t.Errorf("port3 neighbor got multihop %v, want enabled with TTL 255", n3.GetEbgpMultihop())
+	}
+	if !n3.GetEnableBfd().GetEnabled() || n3.GetGracefulRestart().GetRestartTime() != 120 {
+		t.Errorf("port3 neighbor got BFD %v and graceful restart %v, want BFD and restart time 120", n3.GetEnableBfd(), n3.GetGracefulRestart())
+	}
+	if pol := bgp.GetPeerGroup("BGP-PEER-GROUP-port1").GetAfiSafi(oc.BgpTypes_AFI_SAFI_TYPE_IPV6_UNICAST).GetApplyPolicy(); len(pol.GetImportPolicy()) != 1 {
+		t.Errorf("port1 peer group is missing its IPv6 import policy")
+	}
+}
+
+func TestBGPPeerConfigOTG(t *testing.T) {
+	bt := testTopology()
+	_, _, peers, err := bt.resolvePeers()
+	if err != nil {
+		t.Fatalf("resolvePeers() got unexpected error: %v", err)
+	}
+	top := gosnappi.NewConfig()
+	var devs []gosnappi.Device
+	for _, p := range peers {
+		dev := top.Devices().Add().SetName(p.link.ATE.Name)
+		eth := dev.Ethernets().Add().SetName(dev.Name() + ".Eth")
+		eth.Ipv4Addresses().Add().SetName(dev.Name() + ".IPv4").SetAddress(p.link.ATE.IPv4)
+		eth.Ipv6Addresses().Add().SetName(dev.Name() + ".IPv6").SetAddress(p.link.ATE.IPv6)
+		p.configOTG(dev, bt.as())
+		devs = append(devs, dev)
+	}
+
+	v4 := devs[0].Bgp().Ipv4Interfaces().Items()[0].Peers().Items()[0]
+	if v4.AsType() != gosnappi.BgpV4PeerAsType.EBGP || v4.Advanced().Md5Key() != "secret" {
+		t.Errorf("port1 peer got AS type %v and MD5 key %q, want EBGP and secret", v4.AsType(), v4.Advanced().Md5Key())
+	}
+	if got := v4.V4Routes().Items(); len(got) != 1 || got[0].Addresses().Items()[0].Count() != 10 {
+		t.Errorf("port1 peer got IPv4 routes %v, want one range of 10 prefixes", got)
+	}
+	if got := len(devs[0].Bgp().Ipv6Interfaces().Items()[0].Peers().Items()[0].V6Routes().Items()); got != 1 {
+		t.Errorf("port1 peer got %d IPv6 route ranges, want 1", got)
+	}
+
+	if got := devs[1].Bgp().Ipv4Interfaces().Items()[0].Peers().Items()[0].AsType(); got != gosnappi.BgpV4PeerAsType.IBGP {
+		t.Errorf("port2 peer got AS type %v, want IBGP", got)
+	}
+	if got := len(devs[1].Bgp().Ipv6Interfaces().Items()); got != 0 {
+		t.Errorf("port2 got %d IPv6 BGP interfaces, want 0", got)
+	}
+
+	lo := devs[2].Ipv4Loopbacks().Items()
+	if len(lo) != 1 || lo[0].Address() != peers[2].ate.IPv4 {
+		t.Fatalf("port3 got IPv4 loopbacks %v, want %s", lo, peers[2].ate.IPv4)
+	}
+	mh := devs[2].Bgp().Ipv4Interfaces().Items()[0]
+	if mh.Ipv4Name() != lo[0].Name() || mh.Peers().Items()[0].PeerAddress() != peers[2].dut.IPv4 {
+		t.Errorf("port3 peer got interface %s and peer %s, want loopback %s and DUT loopback %s", mh.Ipv4Name(), mh.Peers().Items()[0].PeerAddress(), lo[0].Name(), peers[2].dut.IPv4)
+	}
+}
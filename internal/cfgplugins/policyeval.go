@@ -0,0 +1,578 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgplugins
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/open-traffic-generator/snappi/gosnappi"
+	"github.com/openconfig/ondatra/gnmi/oc"
+)
+
+// Route is a BGP route as seen by a routing policy.
+type Route struct {
+	Prefix netip.Prefix
+	// Communities are standard communities such as "65000:1" or well-known
+	// community names such as "NO_EXPORT".
+	Communities []string
+	ASPath      []uint32
+	MED         uint32
+	LocalPref   uint32
+}
+
+func (r *Route) clone() *Route {
+	c := *r
+	c.Communities = append([]string(nil), r.Communities...)
+	c.ASPath = append([]uint32(nil), r.ASPath...)
+	return &c
+}
+
+func (r *Route) asPathString() string {
+	s := make([]string, len(r.ASPath))
+	for i, as := range r.ASPath {
+		s[i] = strconv.FormatUint(uint64(as), 10)
+	}
+	return strings.Join(s, " ")
+}
+
+// RoutesFromV4RouteRange expands an OTG IPv4 route range into the routes
+// received by the DUT. peerAS is prepended to the AS path of the routes, as
+// done over eBGP sessions; pass 0 for iBGP.
+func RoutesFromV4RouteRange(rr gosnappi.BgpV4RouteRange, peerAS uint32) ([]*Route, error) {
+	var routes []*Route
+	for _, a := range rr.Addresses().Items() {
+		pfxs, err := expandPrefixes(a.Address(), a.Prefix(), a.Count(), a.Step())
+		if err != nil {
+			return nil, fmt.Errorf("route range %s: %w", rr.Name(), err)
+		}
+		for _, p := range pfxs {
+			routes = append(routes, otgRoute(p, rr.Communities().Items(), rr.AsPath(), rr.Advanced(), peerAS))
+		}
+	}
+	return routes, nil
+}
+
+// RoutesFromV6RouteRange is the IPv6 equivalent of RoutesFromV4RouteRange.
+func RoutesFromV6RouteRange(rr gosnappi.BgpV6RouteRange, peerAS uint32) ([]*Route, error) {
+	var routes []*Route
+	for _, a := range rr.Addresses().Items() {
+		pfxs, err := expandPrefixes(a.Address(), a.Prefix(), a.Count(), a.Step())
+		if err != nil {
+			return nil, fmt.Errorf("route range %s: %w", rr.Name(), err)
+		}
+		for _, p := range pfxs {
+			routes = append(routes, otgRoute(p, rr.Communities().Items(), rr.AsPath(), rr.Advanced(), peerAS))
+		}
+	}
+	return routes, nil
+}
+
+// expandPrefixes returns count prefixes of the given length starting at
+// addr, each step prefixes apart.
+func expandPrefixes(addr string, bits, count, step uint32) ([]netip.Prefix, error) {
+	a, err := netip.ParseAddr(addr)
+	if err != nil {
+		return nil, err
+	}
+	if int(bits) > a.BitLen() {
+		return nil, fmt.Errorf("prefix length %d is too long for %s", bits, addr)
+	}
+	if count == 0 {
+		count = 1
+	}
+	if step == 0 {
+		step = 1
+	}
+	inc := new(big.Int).Lsh(big.NewInt(int64(step)), uint(a.BitLen()-int(bits)))
+	v := new(big.Int).SetBytes(a.AsSlice())
+	max := new(big.Int).Lsh(big.NewInt(1), uint(a.BitLen()))
+	var out []netip.Prefix
+	for i := uint32(0); i < count; i++ {
+		if v.Cmp(max) >= 0 {
+			return nil, fmt.Errorf("%d prefixes from %s/%d overflow the address space", count, addr, bits)
+		}
+		b := v.FillBytes(make([]byte, a.BitLen()/8))
+		next, _ := netip.AddrFromSlice(b)
+		p, err := next.Prefix(int(bits))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+		v.Add(v, inc)
+	}
+	return out, nil
+}
+
+func otgRoute(p netip.Prefix, comms []gosnappi.BgpCommunity, asPath gosnappi.BgpAsPath, adv gosnappi.BgpRouteAdvanced, peerAS uint32) *Route {
+	r := &Route{Prefix: p, LocalPref: 100}
+	for _, c := range comms {
+		switch c.Type() {
+		case gosnappi.BgpCommunityType.MANUAL_AS_NUMBER:
+			r.Communities = append(r.Communities, fmt.Sprintf("%d:%d", c.AsNumber(), c.AsCustom()))
+		case gosnappi.BgpCommunityType.NO_EXPORT:
+			r.Communities = append(r.Communities, "NO_EXPORT")
+		case gosnappi.BgpCommunityType.NO_ADVERTISED:
+			r.Communities = append(r.Communities, "NO_ADVERTISE")
+		case gosnappi.BgpCommunityType.NO_EXPORT_SUBCONFED:
+			r.Communities = append(r.Communities, "NO_EXPORT_SUBCONFED")
+		}
+	}
+	if peerAS != 0 {
+		r.ASPath = append(r.ASPath, peerAS)
+	}
+	for _, s := range asPath.Segments().Items() {
+		r.ASPath = append(r.ASPath, s.AsNumbers()...)
+	}
+	if adv.IncludeMultiExitDiscriminator() {
+		r.MED = adv.MultiExitDiscriminator()
+	}
+	if peerAS == 0 && adv.IncludeLocalPreference() {
+		r.LocalPref = adv.LocalPreference()
+	}
+	return r
+}
+
+// maxPolicyDepth bounds call-policy nesting, to detect loops.
+const maxPolicyDepth = 16
+
+// EvaluatePolicy evaluates the policy definition named policy of rp on a
+// route. It returns a copy of the route with the actions of the matching
+// statements applied and the policy result: ACCEPT_ROUTE or REJECT_ROUTE if
+// a matching statement ended the evaluation, or NEXT_STATEMENT if none did.
+func EvaluatePolicy(rp *oc.RoutingPolicy, policy string, r *Route) (*Route, oc.E_RoutingPolicy_PolicyResultType, error) {
+	return evaluatePolicy(rp, policy, r.clone(), 0)
+}
+
+// evaluatePolicy evaluates a policy on r, which it modifies in place.
+func evaluatePolicy(rp *oc.RoutingPolicy, policy string, r *Route, depth int) (*Route, oc.E_RoutingPolicy_PolicyResultType, error) {
+	if depth > maxPolicyDepth {
+		return nil, oc.RoutingPolicy_PolicyResultType_UNSET, fmt.Errorf("policy %s: call-policy nested more than %d times", policy, maxPolicyDepth)
+	}
+	pd := rp.GetPolicyDefinition(policy)
+	if pd == nil {
+		return nil, oc.RoutingPolicy_PolicyResultType_UNSET, fmt.Errorf("undefined policy %s", policy)
+	}
+	for _, st := range pd.Statement.Values() {
+		matched, err := matchStatement(rp, st.GetConditions(), r, depth)
+		if err != nil {
+			return nil, oc.RoutingPolicy_PolicyResultType_UNSET, fmt.Errorf("policy %s statement %s: %w", policy, st.GetName(), err)
+		}
+		if matched == nil {
+			continue
+		}
+		*r = *matched
+		if err := applyActions(rp, st.GetActions(), r); err != nil {
+			return nil, oc.RoutingPolicy_PolicyResultType_UNSET, fmt.Errorf("policy %s statement %s: %w", policy, st.GetName(), err)
+		}
+		switch res := st.GetActions().GetPolicyResult(); res {
+		case oc.RoutingPolicy_PolicyResultType_ACCEPT_ROUTE, oc.RoutingPolicy_PolicyResultType_REJECT_ROUTE:
+			return r, res, nil
+		}
+	}
+	return r, oc.RoutingPolicy_PolicyResultType_NEXT_STATEMENT, nil
+}
+
+// matchStatement evaluates the conditions of a statement. It returns nil if
+// they do not match, or the route to apply the actions to. The route differs
+// from r if the conditions call a policy that modified it.
+func matchStatement(rp *oc.RoutingPolicy, c *oc.RoutingPolicy_PolicyDefinition_Statement_Conditions, r *Route, depth int) (*Route, error) {
+	ok, err := matchConditions(rp, c, r)
+	if err != nil || !ok {
+		return nil, err
+	}
+	if c.GetCallPolicy() == "" {
+		return r, nil
+	}
+	// The called policy matches if it accepts the route, in which case its
+	// actions apply.
+	called, res, err := evaluatePolicy(rp, c.GetCallPolicy(), r.clone(), depth+1)
+	if err != nil || res != oc.RoutingPolicy_PolicyResultType_ACCEPT_ROUTE {
+		return nil, err
+	}
+	return called, nil
+}
+
+// applyPolicy is implemented by the apply-policy containers of BGP
+// neighbors, peer groups and their AFI-SAFIs.
+type applyPolicy interface {
+	GetImportPolicy() []string
+	GetDefaultImportPolicy() oc.E_RoutingPolicy_DefaultPolicyType
+	GetExportPolicy() []string
+	GetDefaultExportPolicy() oc.E_RoutingPolicy_DefaultPolicyType
+}
+
+// PolicyChain is an ordered list of policies applied to routes, with the
+// default action taken when no policy accepts or rejects a route.
+type PolicyChain struct {
+	Policies []string
+	// Default is the default policy. Unset means REJECT_ROUTE, as in
+	// OpenConfig.
+	Default oc.E_RoutingPolicy_DefaultPolicyType
+}
+
+// ImportChain returns the import policy chain of an apply-policy container.
+func ImportChain(ap applyPolicy) *PolicyChain {
+	return &PolicyChain{Policies: ap.GetImportPolicy(), Default: ap.GetDefaultImportPolicy()}
+}
+
+// ExportChain returns the export policy chain of an apply-policy container.
+func ExportChain(ap applyPolicy) *PolicyChain {
+	return &PolicyChain{Policies: ap.GetExportPolicy(), Default: ap.GetDefaultExportPolicy()}
+}
+
+// EvaluateChain evaluates a policy chain on a route. Policies are evaluated
+// in order until one accepts or rejects the route; if none does, the default
+// policy applies. It returns a copy of the route with the actions applied and
+// whether the route is accepted.
+func EvaluateChain(rp *oc.RoutingPolicy, chain *PolicyChain, r *Route) (*Route, bool, error) {
+	out := r.clone()
+	for _, p := range chain.Policies {
+		_, res, err := evaluatePolicy(rp, p, out, 0)
+		if err != nil {
+			return nil, false, err
+		}
+		switch res {
+		case oc.RoutingPolicy_PolicyResultType_ACCEPT_ROUTE:
+			return out, true, nil
+		case oc.RoutingPolicy_PolicyResultType_REJECT_ROUTE:
+			return out, false, nil
+		}
+	}
+	return out, chain.Default == oc.RoutingPolicy_DefaultPolicyType_ACCEPT_ROUTE, nil
+}
+
+// PolicyOutcome is the predicted result of applying a policy chain to a set
+// of candidate routes.
+type PolicyOutcome struct {
+	// Accepted holds the accepted routes with the policy actions applied.
+	Accepted []*Route
+	// Rejected holds the rejected routes as received.
+	Rejected []*Route
+}
+
+// Received returns the number of candidate routes, i.e. the expected
+// pre-policy received prefix count.
+func (o *PolicyOutcome) Received() int {
+	return len(o.Accepted) + len(o.Rejected)
+}
+
+// Installed returns the number of accepted routes, i.e. the expected
+// installed prefix count.
+func (o *PolicyOutcome) Installed() int {
+	return len(o.Accepted)
+}
+
+// Route returns the accepted route for a prefix, or nil if it was rejected
+// or is not a candidate.
+func (o *PolicyOutcome) Route(prefix string) *Route {
+	p, err := netip.ParsePrefix(prefix)
+	if err != nil {
+		return nil
+	}
+	for _, r := range o.Accepted {
+		if r.Prefix == p {
+			return r
+		}
+	}
+	return nil
+}
+
+// PredictRoutes applies a policy chain to candidate routes.
+//
+// Usage:
+//
+//	routes, _ := cfgplugins.RoutesFromV4RouteRange(rr, ateAS)
+//	ap := root.GetNetworkInstance(ni).GetProtocol(cfgplugins.PTBGP, "BGP").GetBgp().GetNeighbor(ateIP).GetAfiSafi(afi).GetApplyPolicy()
+//	out, err := cfgplugins.PredictRoutes(root.GetRoutingPolicy(), cfgplugins.ImportChain(ap), routes)
+//	...
+//	if got := gnmi.Get(t, dut, prefixes.Installed().State()); got != uint32(out.Installed()) {
+func PredictRoutes(rp *oc.RoutingPolicy, chain *PolicyChain, routes []*Route) (*PolicyOutcome, error) {
+	o := &PolicyOutcome{}
+	for _, r := range routes {
+		out, accepted, err := EvaluateChain(rp, chain, r)
+		if err != nil {
+			return nil, fmt.Errorf("route %s: %w", r.Prefix, err)
+		}
+		if accepted {
+			o.Accepted = append(o.Accepted, out)
+		} else {
+			o.Rejected = append(o.Rejected, r)
+		}
+	}
+	return o, nil
+}
+
+func matchConditions(rp *oc.RoutingPolicy, c *oc.RoutingPolicy_PolicyDefinition_Statement_Conditions, r *Route) (bool, error) {
+	if c == nil {
+		return true, nil
+	}
+	if m := c.GetMatchPrefixSet(); m.GetPrefixSet() != "" {
+		ps := rp.GetDefinedSets().GetPrefixSet(m.GetPrefixSet())
+		if ps == nil {
+			return false, fmt.Errorf("undefined prefix set %s", m.GetPrefixSet())
+		}
+		in, err := prefixSetContains(ps, r.Prefix)
+		if err != nil {
+			return false, err
+		}
+		if in == (m.GetMatchSetOptions() == oc.RoutingPolicy_MatchSetOptionsRestrictedType_INVERT) {
+			return false, nil
+		}
+	}
+	bc := c.GetBgpConditions()
+	if bc == nil {
+		return true, nil
+	}
+	bds := rp.GetDefinedSets().GetBgpDefinedSets()
+	if name, opt := communityCondition(bc, bds); name != "" {
+		cs := bds.GetCommunitySet(name)
+		if cs == nil {
+			return false, fmt.Errorf("undefined community set %s", name)
+		}
+		ok, err := matchSet(communityMembers(cs.CommunityMember), r.Communities, opt, matchCommunity)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	if m := bc.GetMatchAsPathSet(); m.GetAsPathSet() != "" {
+		as := bds.GetAsPathSet(m.GetAsPathSet())
+		if as == nil {
+			return false, fmt.Errorf("undefined AS path set %s", m.GetAsPathSet())
+		}
+		ok, err := matchSet(as.AsPathSetMember, []string{r.asPathString()}, m.GetMatchSetOptions(), regexMatch)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	if l := bc.GetAsPathLength(); l != nil && !compareAttribute(uint32(len(r.ASPath)), l.GetOperator(), l.GetValue()) {
+		return false, nil
+	}
+	if n := bc.GetCommunityCount(); n != nil && !compareAttribute(uint32(len(r.Communities)), n.GetOperator(), n.GetValue()) {
+		return false, nil
+	}
+	if bc.MedEq != nil && r.MED != bc.GetMedEq() {
+		return false, nil
+	}
+	if bc.LocalPrefEq != nil && r.LocalPref != bc.GetLocalPrefEq() {
+		return false, nil
+	}
+	return true, nil
+}
+
+// compareAttribute compares an attribute to a value with an OpenConfig
+// attribute comparison operator. An unset operator means equality.
+func compareAttribute(attr uint32, op oc.E_PolicyTypes_ATTRIBUTE_COMPARISON, v uint32) bool {
+	switch op {
+	case oc.PolicyTypes_ATTRIBUTE_COMPARISON_ATTRIBUTE_GE:
+		return attr >= v
+	case oc.PolicyTypes_ATTRIBUTE_COMPARISON_ATTRIBUTE_LE:
+		return attr <= v
+	}
+	return attr == v
+}
+
+// communityCondition returns the community set referenced by the conditions
+// and its match options, from either the match-community-set container or
+// the community-set leaf.
+func communityCondition(bc *oc.RoutingPolicy_PolicyDefinition_Statement_Conditions_BgpConditions, bds *oc.RoutingPolicy_DefinedSets_BgpDefinedSets) (string, oc.E_RoutingPolicy_MatchSetOptionsType) {
+	if m := bc.GetMatchCommunitySet(); m.GetCommunitySet() != "" {
+		return m.GetCommunitySet(), m.GetMatchSetOptions()
+	}
+	if bc.GetCommunitySet() == "" {
+		return "", oc.RoutingPolicy_MatchSetOptionsType_UNSET
+	}
+	switch bds.GetCommunitySet(bc.GetCommunitySet()).GetMatchSetOptions() {
+	case oc.BgpPolicy_MatchSetOptionsType_ALL:
+		return bc.GetCommunitySet(), oc.RoutingPolicy_MatchSetOptionsType_ALL
+	case oc.BgpPolicy_MatchSetOptionsType_INVERT:
+		return bc.GetCommunitySet(), oc.RoutingPolicy_MatchSetOptionsType_INVERT
+	}
+	return bc.GetCommunitySet(), oc.RoutingPolicy_MatchSetOptionsType_ANY
+}
+
+// matchSet matches values against the members of a defined set. With ANY
+// (the default) some member must match some value, with ALL every member
+// must match some value and with INVERT no member may match any value.
+func matchSet(members, values []string, opt oc.E_RoutingPolicy_MatchSetOptionsType, match func(member, value string) (bool, error)) (bool, error) {
+	matched := 0
+	for _, m := range members {
+		for _, v := range values {
+			ok, err := match(m, v)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				matched++
+				break
+			}
+		}
+	}
+	switch opt {
+	case oc.RoutingPolicy_MatchSetOptionsType_ALL:
+		return len(members) > 0 && matched == len(members), nil
+	case oc.RoutingPolicy_MatchSetOptionsType_INVERT:
+		return matched == 0, nil
+	}
+	return matched > 0, nil
+}
+
+func regexMatch(pattern, value string) (bool, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false, err
+	}
+	return re.MatchString(value), nil
+}
+
+// matchCommunity matches a community against a set member, which is either
+// a literal community or a regular expression matching the whole community.
+func matchCommunity(member, community string) (bool, error) {
+	if member == community {
+		return true, nil
+	}
+	return regexMatch("^(?:"+member+")$", community)
+}
+
+// communityString renders a community set member or inline community.
+func communityString(u any) string {
+	switch v := u.(type) {
+	case oc.UnionString:
+		return string(v)
+	case oc.UnionUint32:
+		return fmt.Sprintf("%d:%d", uint32(v)>>16, uint32(v)&0xffff)
+	case oc.E_BgpTypes_BGP_WELL_KNOWN_STD_COMMUNITY:
+		return v.String()
+	}
+	return fmt.Sprint(u)
+}
+
+func communityMembers(ms []oc.RoutingPolicy_DefinedSets_BgpDefinedSets_CommunitySet_CommunityMember_Union) []string {
+	out := make([]string, len(ms))
+	for i, m := range ms {
+		out[i] = communityString(m)
+	}
+	return out
+}
+
+// prefixSetContains reports whether p matches an entry of the prefix set.
+func prefixSetContains(ps *oc.RoutingPolicy_DefinedSets_PrefixSet, p netip.Prefix) (bool, error) {
+	for _, e := range ps.Prefix {
+		ep, err := netip.ParsePrefix(e.GetIpPrefix())
+		if err != nil {
+			return false, fmt.Errorf("prefix set %s: %w", ps.GetName(), err)
+		}
+		lo, hi, err := maskRange(e.GetMasklengthRange(), ep.Bits())
+		if err != nil {
+			return false, fmt.Errorf("prefix set %s: %w", ps.GetName(), err)
+		}
+		if ep.Addr().Is4() != p.Addr().Is4() || p.Bits() < ep.Bits() || p.Bits() < lo || p.Bits() > hi {
+			continue
+		}
+		if ep.Contains(p.Addr()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// maskRange parses a mask length range such as "exact" or "24..32".
+func maskRange(r string, bits int) (int, int, error) {
+	if r == "" || r == "exact" {
+		return bits, bits, nil
+	}
+	los, his, ok := strings.Cut(r, "..")
+	lo, err1 := strconv.Atoi(los)
+	hi, err2 := strconv.Atoi(his)
+	if !ok || err1 != nil || err2 != nil || lo > hi {
+		return 0, 0, fmt.Errorf("invalid mask length range %q", r)
+	}
+	return lo, hi, nil
+}
+
+func applyActions(rp *oc.RoutingPolicy, a *oc.RoutingPolicy_PolicyDefinition_Statement_Actions, r *Route) error {
+	ba := a.GetBgpActions()
+	if ba == nil {
+		return nil
+	}
+	if ba.SetLocalPref != nil {
+		r.LocalPref = ba.GetSetLocalPref()
+	}
+	if med, ok := ba.GetSetMed().(oc.UnionUint32); ok {
+		r.MED = uint32(med)
+	}
+	if pp := ba.GetSetAsPathPrepend(); pp != nil {
+		for i := 0; i < int(pp.GetRepeatN()); i++ {
+			r.ASPath = append([]uint32{pp.GetAsn()}, r.ASPath...)
+		}
+	}
+	if sc := ba.GetSetCommunity(); sc != nil {
+		comms, err := setCommunities(rp, sc)
+		if err != nil {
+			return err
+		}
+		switch sc.GetOptions() {
+		case oc.BgpPolicy_BgpSetCommunityOptionType_REPLACE:
+			r.Communities = comms
+		case oc.BgpPolicy_BgpSetCommunityOptionType_REMOVE:
+			var kept []string
+			for _, c := range r.Communities {
+				ok, err := matchSet(comms, []string{c}, oc.RoutingPolicy_MatchSetOptionsType_ANY, matchCommunity)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					kept = append(kept, c)
+				}
+			}
+			r.Communities = kept
+		default:
+			for _, c := range comms {
+				if !containsValue(r.Communities, c) {
+					r.Communities = append(r.Communities, c)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// setCommunities returns the communities of a set-community action.
+func setCommunities(rp *oc.RoutingPolicy, sc *oc.RoutingPolicy_PolicyDefinition_Statement_Actions_BgpActions_SetCommunity) ([]string, error) {
+	if sc.GetMethod() == oc.SetCommunity_Method_INLINE {
+		var out []string
+		for _, c := range sc.GetInline().Communities {
+			out = append(out, communityString(c))
+		}
+		return out, nil
+	}
+	refs := sc.GetReference().CommunitySetRefs
+	if ref := sc.GetReference().GetCommunitySetRef(); ref != "" {
+		refs = append(refs, ref)
+	}
+	var out []string
+	for _, ref := range refs {
+		cs := rp.GetDefinedSets().GetBgpDefinedSets().GetCommunitySet(ref)
+		if cs == nil {
+			return nil, fmt.Errorf("undefined community set %s", ref)
+		}
+		out = append(out, communityMembers(cs.CommunityMember)...)
+	}
+	return out, nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgplugins
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/openconfig/ondatra/gnmi/oc"
+	"github.com/openconfig/ygot/ygot"
+)
+
+func testPolicyChain(t *testing.T) *oc.RoutingPolicy {
+	t.Helper()
+	rp := NewRoutingPolicy().
+		PrefixSet("CUSTOMER", "198.51.100.0/24 24..32").
+		PrefixSet("BOGONS", "10.0.0.0/8 8..32").
+		CommunitySet("GOLD", "65000:1[0-9]")
+	rp.Policy("SET-GOLD").
+		Term("10").MatchCommunitySet("GOLD", oc.RoutingPolicy_MatchSetOptionsType_ANY).SetLocalPref(300).Accept()
+	rp.Policy("FILTER").
+		Term("10").MatchPrefixSet("BOGONS").Reject()
+	rp.Policy("CUSTOMER-IN").
+		Term("10").MatchPrefixSet("CUSTOMER").CallPolicy("SET-GOLD").SetMED(7).Accept().
+		Term("20").MatchPrefixSet("CUSTOMER").Accept()
+	out, err := rp.render(&policyRenderOptions{})
+	if err != nil {
+		t.Fatalf("render() got unexpected error: %v", err)
+	}
+	return out
+}
+
+func TestEvaluateChain(t *testing.T) {
+	rp := testPolicyChain(t)
+	chain := &PolicyChain{Policies: []string{"FILTER", "CUSTOMER-IN"}}
+	tests := []struct {
+		desc          string
+		route         *Route
+		wantAccepted  bool
+		wantLocalPref uint32
+		wantMED       uint32
+	}{
+		{"rejected by first policy", &Route{Prefix: netip.MustParsePrefix("10.1.0.0/16")}, false, 0, 0},
+		{"nested policy accepts", &Route{Prefix: netip.MustParsePrefix("198.51.100.0/25"), Communities: []string{"65000:12"}, LocalPref: 100}, true, 300, 7},
+		{"nested policy does not accept", &Route{Prefix: netip.MustParsePrefix("198.51.100.0/25"), Communities: []string{"65000:2"}, LocalPref: 100}, true, 100, 0},
+		{"default reject", &Route{Prefix: netip.MustParsePrefix("203.0.113.0/24")}, false, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, accepted, err := EvaluateChain(rp, chain, tt.route)
+			if err != nil {
+				t.Fatalf("EvaluateChain() got unexpected error: %v", err)
+			}
+			if accepted != tt.wantAccepted {
+				t.Fatalf("EvaluateChain() got accepted %v, want %v", accepted, tt.wantAccepted)
+			}
+			if accepted && (got.LocalPref != tt.wantLocalPref || got.MED != tt.wantMED) {
+				t.Errorf("EvaluateChain() got local-pref %d and MED %d, want %d and %d", got.LocalPref, got.MED, tt.wantLocalPref, tt.wantMED)
+			}
+		})
+	}
+
+	accept := &PolicyChain{Policies: []string{"FILTER"}, Default: oc.RoutingPolicy_DefaultPolicyType_ACCEPT_ROUTE}
+	if _, accepted, _ := EvaluateChain(rp, accept, &Route{Prefix: netip.MustParsePrefix("203.0.113.0/24")}); !accepted {
+		t.Errorf("EvaluateChain() with default accept got rejected route, want accepted")
+	}
+}
+
+func TestEvaluateChainErrors(t *testing.T) {
+	rp := testPolicyChain(t)
+	if _, _, err := EvaluateChain(rp, &PolicyChain{Policies: []string{"MISSING"}}, &Route{}); err == nil {
+		t.Errorf("EvaluateChain() with undefined policy got no error, want error")
+	}
+
+	loop := &oc.RoutingPolicy{}
+	st, err := loop.GetOrCreatePolicyDefinition("LOOP").AppendNewStatement("10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	st.GetOrCreateConditions().CallPolicy = ygot.String("LOOP")
+	if _, _, err := EvaluateChain(loop, &PolicyChain{Policies: []string{"LOOP"}}, &Route{}); err == nil {
+		t.Errorf("EvaluateChain() with call-policy loop got no error, want error")
+	}
+}
+
+func TestPredictRoutes(t *testing.T) {
+	rp := testPolicyChain(t)
+	ap := &oc.NetworkInstance_Protocol_Bgp_Neighbor_AfiSafi_ApplyPolicy{
+		ImportPolicy: []string{"FILTER", "CUSTOMER-IN"},
+	}
+	var routes []*Route
+	for _, p := range []string{"198.51.100.0/24", "198.51.100.128/25", "10.0.0.0/8", "192.0.2.0/24"} {
+		routes = append(routes, &Route{Prefix: netip.MustParsePrefix(p), Communities: []string{"65000:10"}})
+	}
+	out, err := PredictRoutes(rp, ImportChain(ap), routes)
+	if err != nil {
+		t.Fatalf("PredictRoutes() got unexpected error: %v", err)
+	}
+	if out.Received() != 4 || out.Installed() != 2 {
+		t.Errorf("PredictRoutes() got %d received and %d installed, want 4 and 2", out.Received(), out.Installed())
+	}
+	if r := out.Route("198.51.100.128/25"); r == nil || r.LocalPref != 300 {
+		t.Errorf("PredictRoutes() got route %+v for 198.51.100.128/25, want local-pref 300", r)
+	}
+	if r := out.Route("10.0.0.0/8"); r != nil {
+		t.Errorf("PredictRoutes() got accepted route %+v for 10.0.0.0/8, want rejected", r)
+	}
+}
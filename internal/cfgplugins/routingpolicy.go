@@ -0,0 +1,459 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgplugins
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/openconfig/featureprofiles/internal/deviations"
+	"github.com/openconfig/ondatra"
+	"github.com/openconfig/ondatra/gnmi/oc"
+	"github.com/openconfig/ygot/ygot"
+)
+
+// RoutingPolicy builds OpenConfig routing policy definitions and the defined
+// sets they reference.
+//
+// Usage:
+//
+//	rp := cfgplugins.NewRoutingPolicy().
+//	  PrefixSet("NETS", "198.51.100.0/24 24..32", "2001:db8:100::/48").
+//	  CommunitySet("NO-ADV", "65000:666")
+//	rp.Policy("IMPORT").
+//	  Term("10").MatchCommunitySet("NO-ADV", oc.RoutingPolicy_MatchSetOptionsType_ANY).Reject().
+//	  Term("20").MatchPrefixSet("NETS").SetLocalPref(200).Accept()
+//	gnmi.Replace(t, dut, gnmi.OC().RoutingPolicy().Config(), rp.Render(t, dut))
+type RoutingPolicy struct {
+	prefixSets    []*prefixSetDef
+	communitySets []*communitySetDef
+	asPathSets    []*asPathSetDef
+	policies      []*PolicyBuilder
+}
+
+type prefixSetDef struct {
+	name    string
+	entries []prefixSetEntry
+}
+
+type prefixSetEntry struct {
+	prefix    netip.Prefix
+	maskRange string
+}
+
+type communitySetDef struct {
+	name    string
+	members []string
+}
+
+type asPathSetDef struct {
+	name    string
+	members []string
+}
+
+// NewRoutingPolicy returns an empty routing policy builder.
+func NewRoutingPolicy() *RoutingPolicy {
+	return &RoutingPolicy{}
+}
+
+// PrefixSet defines a prefix set. Each entry is a prefix, optionally
+// followed by a space and a mask length range such as "24..32". Entries
+// without a range match the exact prefix.
+func (rp *RoutingPolicy) PrefixSet(name string, entries ...string) *RoutingPolicy {
+	ps := &prefixSetDef{name: name}
+	for _, e := range entries {
+		pfx, maskRange, _ := strings.Cut(e, " ")
+		entry := prefixSetEntry{maskRange: "exact"}
+		if maskRange = strings.TrimSpace(maskRange); maskRange != "" {
+			entry.maskRange = maskRange
+		}
+		// Invalid prefixes are reported by Render.
+		entry.prefix, _ = netip.ParsePrefix(pfx)
+		ps.entries = append(ps.entries, entry)
+	}
+	rp.prefixSets = append(rp.prefixSets, ps)
+	return rp
+}
+
+// CommunitySet defines a community set. Members are standard communities
+// such as "65000:1", regular expressions or well-known community names such
+// as "NO_EXPORT".
+func (rp *RoutingPolicy) CommunitySet(name string, members ...string) *RoutingPolicy {
+	rp.communitySets = append(rp.communitySets, &communitySetDef{name: name, members: members})
+	return rp
+}
+
+// ASPathSet defines an AS path set. Members are regular expressions matched
+// against the AS path, e.g. "^65511 ".
+func (rp *RoutingPolicy) ASPathSet(name string, members ...string) *RoutingPolicy {
+	rp.asPathSets = append(rp.asPathSets, &asPathSetDef{name: name, members: members})
+	return rp
+}
+
+// Policy returns the builder of the named policy definition, creating it if
+// needed.
+func (rp *RoutingPolicy) Policy(name string) *PolicyBuilder {
+	if p := rp.policy(name); p != nil {
+		return p
+	}
+	p := &PolicyBuilder{rp: rp, name: name}
+	rp.policies = append(rp.policies, p)
+	return p
+}
+
+func (rp *RoutingPolicy) policy(name string) *PolicyBuilder {
+	for _, p := range rp.policies {
+		if p.name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+func (rp *RoutingPolicy) communitySet(name string) *communitySetDef {
+	for _, cs := range rp.communitySets {
+		if cs.name == name {
+			return cs
+		}
+	}
+	return nil
+}
+
+// PolicyBuilder builds a policy definition as a sequence of terms, which
+// are rendered as OpenConfig statements in the order they were added.
+type PolicyBuilder struct {
+	rp    *RoutingPolicy
+	name  string
+	terms []*TermBuilder
+}
+
+// Term adds a term with the given statement name to the policy.
+func (p *PolicyBuilder) Term(name string) *TermBuilder {
+	t := &TermBuilder{policy: p, name: name}
+	p.terms = append(p.terms, t)
+	return t
+}
+
+// TermBuilder builds the conditions and actions of a policy statement. A
+// term without Accept or Reject continues to the next term.
+type TermBuilder struct {
+	policy *PolicyBuilder
+	name   string
+
+	prefixSet    string
+	prefixSetOpt oc.E_RoutingPolicy_MatchSetOptionsRestrictedType
+	commSet      string
+	commSetOpt   oc.E_RoutingPolicy_MatchSetOptionsType
+	asPathSet    string
+	asPathSetOpt oc.E_RoutingPolicy_MatchSetOptionsType
+	callPolicy   string
+	medEq        *uint32
+	localPrefEq  *uint32
+
+	localPref  *uint32
+	med        *uint32
+	commAction oc.E_BgpPolicy_BgpSetCommunityOptionType
+	commRefs   []string
+	prependASN uint32
+	prependN   uint8
+	result     oc.E_RoutingPolicy_PolicyResultType
+}
+
+// Term ends this term and adds the next one to the same policy.
+func (t *TermBuilder) Term(name string) *TermBuilder {
+	return t.policy.Term(name)
+}
+
+// MatchPrefixSet matches routes whose prefix is in the prefix set.
+func (t *TermBuilder) MatchPrefixSet(name string) *TermBuilder {
+	t.prefixSet, t.prefixSetOpt = name, oc.RoutingPolicy_MatchSetOptionsRestrictedType_ANY
+	return t
+}
+
+// MatchPrefixSetInvert matches routes whose prefix is not in the prefix set.
+func (t *TermBuilder) MatchPrefixSetInvert(name string) *TermBuilder {
+	t.prefixSet, t.prefixSetOpt = name, oc.RoutingPolicy_MatchSetOptionsRestrictedType_INVERT
+	return t
+}
+
+// MatchCommunitySet matches routes by their communities.
+func (t *TermBuilder) MatchCommunitySet(name string, opt oc.E_RoutingPolicy_MatchSetOptionsType) *TermBuilder {
+	t.commSet, t.commSetOpt = name, opt
+	return t
+}
+
+// MatchASPathSet matches routes by their AS path.
+func (t *TermBuilder) MatchASPathSet(name string, opt oc.E_RoutingPolicy_MatchSetOptionsType) *TermBuilder {
+	t.asPathSet, t.asPathSetOpt = name, opt
+	return t
+}
+
+// MatchMED matches routes with the given MED.
+func (t *TermBuilder) MatchMED(med uint32) *TermBuilder {
+	t.medEq = ygot.Uint32(med)
+	return t
+}
+
+// MatchLocalPref matches routes with the given local preference.
+func (t *TermBuilder) MatchLocalPref(lp uint32) *TermBuilder {
+	t.localPrefEq = ygot.Uint32(lp)
+	return t
+}
+
+// CallPolicy matches routes accepted by another policy, applying its
+// actions.
+func (t *TermBuilder) CallPolicy(name string) *TermBuilder {
+	t.callPolicy = name
+	return t
+}
+
+// SetLocalPref sets the local preference of matching routes.
+func (t *TermBuilder) SetLocalPref(lp uint32) *TermBuilder {
+	t.localPref = ygot.Uint32(lp)
+	return t
+}
+
+// SetMED sets the MED of matching routes.
+func (t *TermBuilder) SetMED(med uint32) *TermBuilder {
+	t.med = ygot.Uint32(med)
+	return t
+}
+
+// SetCommunity adds, removes or replaces the communities of matching routes
+// with those of the referenced community sets.
+func (t *TermBuilder) SetCommunity(opt oc.E_BgpPolicy_BgpSetCommunityOptionType, sets ...string) *TermBuilder {
+	t.commAction, t.commRefs = opt, sets
+	return t
+}
+
+// PrependASPath prepends asn n times to the AS path of matching routes.
+func (t *TermBuilder) PrependASPath(asn uint32, n uint8) *TermBuilder {
+	t.prependASN, t.prependN = asn, n
+	return t
+}
+
+// Accept accepts matching routes and ends the policy evaluation.
+func (t *TermBuilder) Accept() *TermBuilder {
+	t.result = oc.RoutingPolicy_PolicyResultType_ACCEPT_ROUTE
+	return t
+}
+
+// Reject rejects matching routes and ends the policy evaluation.
+func (t *TermBuilder) Reject() *TermBuilder {
+	t.result = oc.RoutingPolicy_PolicyResultType_REJECT_ROUTE
+	return t
+}
+
+// policyRenderOptions holds the deviations that affect rendering.
+type policyRenderOptions struct {
+	// communitySetLeaf references community sets from the bgp-conditions
+	// community-set leaf instead of the match-community-set container. The
+	// match options then go on the set, so terms matching the same set must
+	// use the same options.
+	communitySetLeaf bool
+	// ospfSetMetric mirrors set-med with an OSPF set-metric action.
+	ospfSetMetric bool
+	// skipMatchSetOptions omits the match-set-options leaf of
+	// match-prefix-set conditions matching ANY, the device default.
+	skipMatchSetOptions bool
+}
+
+// Render returns the OpenConfig routing policy, applying the deviations of
+// the DUT. It fails the test if the policy is inconsistent.
+func (rp *RoutingPolicy) Render(t testing.TB, dut *ondatra.DUTDevice) *oc.RoutingPolicy {
+	t.Helper()
+	out, err := rp.render(&policyRenderOptions{
+		communitySetLeaf:    deviations.BGPConditionsMatchCommunitySetUnsupported(dut),
+		ospfSetMetric:       deviations.BGPSetMedRequiresEqualOspfSetMetric(dut),
+		skipMatchSetOptions: deviations.SkipSetRpMatchSetOptions(dut),
+	})
+	if err != nil {
+		t.Fatalf("Could not render routing policy: %v", err)
+	}
+	return out
+}
+
+func (rp *RoutingPolicy) render(opts *policyRenderOptions) (*oc.RoutingPolicy, error) {
+	out := &oc.RoutingPolicy{}
+	ds := out.GetOrCreateDefinedSets()
+	for _, ps := range rp.prefixSets {
+		set := ds.GetOrCreatePrefixSet(ps.name)
+		var v4, v6 bool
+		for _, e := range ps.entries {
+			if !e.prefix.IsValid() {
+				return nil, fmt.Errorf("prefix set %s has an invalid prefix", ps.name)
+			}
+			if e.prefix.Addr().Is4() {
+				v4 = true
+			} else {
+				v6 = true
+			}
+			set.GetOrCreatePrefix(e.prefix.String(), e.maskRange)
+		}
+		switch {
+		case v4 && v6:
+			set.Mode = oc.PrefixSet_Mode_MIXED
+		case v4:
+			set.Mode = oc.PrefixSet_Mode_IPV4
+		case v6:
+			set.Mode = oc.PrefixSet_Mode_IPV6
+		}
+	}
+	bds := ds.GetOrCreateBgpDefinedSets()
+	for _, cs := range rp.communitySets {
+		set := bds.GetOrCreateCommunitySet(cs.name)
+		for _, m := range cs.members {
+			set.CommunityMember = append(set.CommunityMember, communityMember(m))
+		}
+	}
+	for _, as := range rp.asPathSets {
+		bds.GetOrCreateAsPathSet(as.name).AsPathSetMember = as.members
+	}
+
+	for _, p := range rp.policies {
+		pd := out.GetOrCreatePolicyDefinition(p.name)
+		for _, t := range p.terms {
+			st, err := pd.AppendNewStatement(t.name)
+			if err != nil {
+				return nil, fmt.Errorf("policy %s: %v", p.name, err)
+			}
+			if err := rp.renderTerm(st, t, out, opts); err != nil {
+				return nil, fmt.Errorf("policy %s term %s: %v", p.name, t.name, err)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (rp *RoutingPolicy) renderTerm(st *oc.RoutingPolicy_PolicyDefinition_Statement, t *TermBuilder, out *oc.RoutingPolicy, opts *policyRenderOptions) error {
+	bds := out.GetDefinedSets().GetBgpDefinedSets()
+	cond := st.GetOrCreateConditions()
+	if t.prefixSet != "" {
+		if out.GetDefinedSets().GetPrefixSet(t.prefixSet) == nil {
+			return fmt.Errorf("undefined prefix set %s", t.prefixSet)
+		}
+		m := cond.GetOrCreateMatchPrefixSet()
+		m.PrefixSet = ygot.String(t.prefixSet)
+		if !opts.skipMatchSetOptions || t.prefixSetOpt != oc.RoutingPolicy_MatchSetOptionsRestrictedType_ANY {
+			m.MatchSetOptions = t.prefixSetOpt
+		}
+	}
+	if t.callPolicy != "" {
+		if rp.policy(t.callPolicy) == nil {
+			return fmt.Errorf("undefined called policy %s", t.callPolicy)
+		}
+		cond.CallPolicy = ygot.String(t.callPolicy)
+	}
+	if t.commSet != "" {
+		cs := rp.communitySet(t.commSet)
+		if cs == nil {
+			return fmt.Errorf("undefined community set %s", t.commSet)
+		}
+		bc := cond.GetOrCreateBgpConditions()
+		if opts.communitySetLeaf {
+			// The match options can only be given on the set itself, so
+			// every term matching the set must use the same options.
+			bc.CommunitySet = ygot.String(t.commSet)
+			set := bds.GetCommunitySet(t.commSet)
+			opt := bgpMatchSetOptions(t.commSetOpt)
+			if set.MatchSetOptions != oc.BgpPolicy_MatchSetOptionsType_UNSET && set.MatchSetOptions != opt {
+				return fmt.Errorf("community set %s is matched with options %v and %v, which the device only supports on the set", t.commSet, set.MatchSetOptions, opt)
+			}
+			set.MatchSetOptions = opt
+		} else {
+			m := bc.GetOrCreateMatchCommunitySet()
+			m.CommunitySet = ygot.String(t.commSet)
+			m.MatchSetOptions = t.commSetOpt
+		}
+	}
+	if t.asPathSet != "" {
+		if bds.GetAsPathSet(t.asPathSet) == nil {
+			return fmt.Errorf("undefined AS path set %s", t.asPathSet)
+		}
+		m := cond.GetOrCreateBgpConditions().GetOrCreateMatchAsPathSet()
+		m.AsPathSet = ygot.String(t.asPathSet)
+		m.MatchSetOptions = t.asPathSetOpt
+	}
+	if t.medEq != nil {
+		cond.GetOrCreateBgpConditions().MedEq = t.medEq
+	}
+	if t.localPrefEq != nil {
+		cond.GetOrCreateBgpConditions().LocalPrefEq = t.localPrefEq
+	}
+
+	act := st.GetOrCreateActions()
+	if t.localPref != nil {
+		act.GetOrCreateBgpActions().SetLocalPref = t.localPref
+	}
+	if t.med != nil {
+		act.GetOrCreateBgpActions().SetMed = oc.UnionUint32(*t.med)
+		if opts.ospfSetMetric {
+			act.GetOrCreateOspfActions().GetOrCreateSetMetric().SetMetric(uint16(*t.med))
+		}
+	}
+	if len(t.commRefs) > 0 {
+		for _, ref := range t.commRefs {
+			if rp.communitySet(ref) == nil {
+				return fmt.Errorf("undefined community set %s", ref)
+			}
+		}
+		sc := act.GetOrCreateBgpActions().GetOrCreateSetCommunity()
+		sc.Method = oc.SetCommunity_Method_REFERENCE
+		sc.Options = t.commAction
+		sc.GetOrCreateReference().CommunitySetRefs = t.commRefs
+	}
+	if t.prependN > 0 {
+		pp := act.GetOrCreateBgpActions().GetOrCreateSetAsPathPrepend()
+		pp.Asn = ygot.Uint32(t.prependASN)
+		pp.RepeatN = ygot.Uint8(t.prependN)
+	}
+	act.PolicyResult = t.result
+	if t.result == oc.RoutingPolicy_PolicyResultType_UNSET {
+		act.PolicyResult = oc.RoutingPolicy_PolicyResultType_NEXT_STATEMENT
+	}
+	return nil
+}
+
+// communityMember returns the OpenConfig union of a community set member.
+func communityMember(m string) oc.RoutingPolicy_DefinedSets_BgpDefinedSets_CommunitySet_CommunityMember_Union {
+	for v, e := range oc.ΛEnum["E_BgpTypes_BGP_WELL_KNOWN_STD_COMMUNITY"] {
+		if e.Name == m {
+			return oc.E_BgpTypes_BGP_WELL_KNOWN_STD_COMMUNITY(v)
+		}
+	}
+	return oc.UnionString(m)
+}
+
+func bgpMatchSetOptions(opt oc.E_RoutingPolicy_MatchSetOptionsType) oc.E_BgpPolicy_MatchSetOptionsType {
+	switch opt {
+	case oc.RoutingPolicy_MatchSetOptionsType_ALL:
+		return oc.BgpPolicy_MatchSetOptionsType_ALL
+	case oc.RoutingPolicy_MatchSetOptionsType_INVERT:
+		return oc.BgpPolicy_MatchSetOptionsType_INVERT
+	}
+	return oc.BgpPolicy_MatchSetOptionsType_ANY
+}
+
+// Evaluate predicts the outcome of the named policy for a route, as
+// EvaluatePolicy does on the rendered policy.
+func (rp *RoutingPolicy) Evaluate(policy string, r *Route) (*Route, oc.E_RoutingPolicy_PolicyResultType, error) {
+	out, err := rp.render(&policyRenderOptions{})
+	if err != nil {
+		return nil, oc.RoutingPolicy_PolicyResultType_UNSET, err
+	}
+	return EvaluatePolicy(out, policy, r)
+}
@@ -0,0 +1,206 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfgplugins
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/open-traffic-generator/snappi/gosnappi"
+	"github.com/openconfig/ondatra/gnmi/oc"
+)
+
+func testRoutingPolicy() *RoutingPolicy {
+	rp := NewRoutingPolicy().
+		PrefixSet("NETS", "198.51.100.0/24 24..28", "2001:db8:100::/48").
+		CommunitySet("BLOCK", "65000:666").
+		CommunitySet("TAG", "65000:100", "NO_EXPORT").
+		ASPathSet("FROM-65511", "^65511 ")
+	rp.Policy("IMPORT").
+		Term("10").MatchCommunitySet("BLOCK", oc.RoutingPolicy_MatchSetOptionsType_ANY).Reject().
+		Term("20").MatchPrefixSet("NETS").MatchASPathSet("FROM-65511", oc.RoutingPolicy_MatchSetOptionsType_ANY).
+		SetLocalPref(200).SetMED(50).SetCommunity(oc.BgpPolicy_BgpSetCommunityOptionType_ADD, "TAG").Accept().
+		Term("30").MatchPrefixSetInvert("NETS").PrependASPath(65501, 2)
+	return rp
+}
+
+func TestRoutingPolicyRender(t *testing.T) {
+	rp, err := testRoutingPolicy().render(&policyRenderOptions{})
+	if err != nil {
+		t.Fatalf("render() got unexpected error: %v", err)
+	}
+	if got := rp.GetDefinedSets().GetPrefixSet("NETS").GetMode(); got != oc.PrefixSet_Mode_MIXED {
+		t.Errorf("prefix set mode got %v, want MIXED", got)
+	}
+	if got := rp.GetDefinedSets().GetPrefixSet("NETS").GetPrefix("198.51.100.0/24", "24..28"); got == nil {
+		t.Errorf("prefix set is missing 198.51.100.0/24 24..28")
+	}
+	if got := rp.GetDefinedSets().GetBgpDefinedSets().GetCommunitySet("TAG").CommunityMember[1]; got != oc.BgpTypes_BGP_WELL_KNOWN_STD_COMMUNITY_NO_EXPORT {
+		t.Errorf("community member got %v, want well-known NO_EXPORT", got)
+	}
+	var names []string
+	for _, st := range rp.GetPolicyDefinition("IMPORT").Statement.Values() {
+		names = append(names, st.GetName())
+	}
+	if want := []string{"10", "20", "30"}; !cmp.Equal(names, want) {
+		t.Errorf("statements got %v, want %v", names, want)
+	}
+	st30 := rp.GetPolicyDefinition("IMPORT").Statement.Get("30")
+	if got := st30.GetActions().GetPolicyResult(); got != oc.RoutingPolicy_PolicyResultType_NEXT_STATEMENT {
+		t.Errorf("term without result got policy result %v, want NEXT_STATEMENT", got)
+	}
+	st20 := rp.GetPolicyDefinition("IMPORT").Statement.Get("20")
+	if got := st20.GetActions().GetBgpActions().GetSetCommunity().GetMethod(); got != oc.SetCommunity_Method_REFERENCE {
+		t.Errorf("set-community method got %v, want REFERENCE", got)
+	}
+
+	dev, err := testRoutingPolicy().render(&policyRenderOptions{communitySetLeaf: true, ospfSetMetric: true})
+	if err != nil {
+		t.Fatalf("render() with deviations got unexpected error: %v", err)
+	}
+	bc := dev.GetPolicyDefinition("IMPORT").Statement.Get("10").GetConditions().GetBgpConditions()
+	if bc.GetCommunitySet() != "BLOCK" || bc.GetMatchCommunitySet() != nil {
+		t.Errorf("community condition with deviation got leaf %q and container %v, want leaf BLOCK only", bc.GetCommunitySet(), bc.GetMatchCommunitySet())
+	}
+	if got := dev.GetPolicyDefinition("IMPORT").Statement.Get("20").GetActions().GetOspfActions().GetSetMetric().GetMetric(); got != 50 {
+		t.Errorf("OSPF set-metric with deviation got %d, want 50", got)
+	}
+
+	bad := NewRoutingPolicy()
+	bad.Policy("P").Term("10").MatchPrefixSet("MISSING").Accept()
+	if _, err := bad.render(&policyRenderOptions{}); err == nil {
+		t.Errorf("render() with undefined prefix set got no error, want error")
+	}
+
+	conflict := NewRoutingPolicy().CommunitySet("BLOCK", "65000:666")
+	conflict.Policy("P").
+		Term("10").MatchCommunitySet("BLOCK", oc.RoutingPolicy_MatchSetOptionsType_ANY).Reject().
+		Term("20").MatchCommunitySet("BLOCK", oc.RoutingPolicy_MatchSetOptionsType_INVERT).Accept()
+	if _, err := conflict.render(&policyRenderOptions{}); err != nil {
+		t.Errorf("render() with per-term community match options got unexpected error: %v", err)
+	}
+	if _, err := conflict.render(&policyRenderOptions{communitySetLeaf: true}); err == nil {
+		t.Errorf("render() with conflicting community set options on the set got no error, want error")
+	}
+}
+
+func TestRoutingPolicyRenderSkipMatchSetOptions(t *testing.T) {
+	policy := NewRoutingPolicy().PrefixSet("NETS", "198.51.100.0/24")
+	policy.Policy("P").
+		Term("10").MatchPrefixSet("NETS").Accept().
+		Term("20").MatchPrefixSetInvert("NETS").Reject()
+	tests := []struct {
+		desc   string
+		opts   *policyRenderOptions
+		want10 oc.E_RoutingPolicy_MatchSetOptionsRestrictedType
+		want20 oc.E_RoutingPolicy_MatchSetOptionsRestrictedType
+	}{{
+		desc:   "default",
+		opts:   &policyRenderOptions{},
+		want10: oc.RoutingPolicy_MatchSetOptionsRestrictedType_ANY,
+		want20: oc.RoutingPolicy_MatchSetOptionsRestrictedType_INVERT,
+	}, {
+		desc:   "skip match set options",
+		opts:   &policyRenderOptions{skipMatchSetOptions: true},
+		want10: oc.RoutingPolicy_MatchSetOptionsRestrictedType_UNSET,
+		want20: oc.RoutingPolicy_MatchSetOptionsRestrictedType_INVERT,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			rp, err := policy.render(tt.opts)
+			if err != nil {
+				t.Fatalf("render() got unexpected error: %v", err)
+			}
+			pd := rp.GetPolicyDefinition("P")
+			m10 := pd.Statement.Get("10").GetConditions().GetMatchPrefixSet()
+			if m10.GetPrefixSet() != "NETS" || m10.MatchSetOptions != tt.want10 {
+				t.Errorf("term 10 match-prefix-set got %q with options %v, want NETS with %v", m10.GetPrefixSet(), m10.MatchSetOptions, tt.want10)
+			}
+			if got := pd.Statement.Get("20").GetConditions().GetMatchPrefixSet().MatchSetOptions; got != tt.want20 {
+				t.Errorf("term 20 match-set-options got %v, want %v", got, tt.want20)
+			}
+		})
+	}
+}
+
+func TestRoutingPolicyEvaluate(t *testing.T) {
+	tests := []struct {
+		desc       string
+		route      *Route
+		wantResult oc.E_RoutingPolicy_PolicyResultType
+		want       *Route
+	}{{
+		desc:       "blocked community",
+		route:      &Route{Prefix: netip.MustParsePrefix("198.51.100.0/24"), Communities: []string{"65000:666"}, ASPath: []uint32{65511}},
+		wantResult: oc.RoutingPolicy_PolicyResultType_REJECT_ROUTE,
+		want:       &Route{Prefix: netip.MustParsePrefix("198.51.100.0/24"), Communities: []string{"65000:666"}, ASPath: []uint32{65511}},
+	}, {
+		desc:       "accepted and modified",
+		route:      &Route{Prefix: netip.MustParsePrefix("198.51.100.16/28"), ASPath: []uint32{65511, 65000}, LocalPref: 100},
+		wantResult: oc.RoutingPolicy_PolicyResultType_ACCEPT_ROUTE,
+		want:       &Route{Prefix: netip.MustParsePrefix("198.51.100.16/28"), Communities: []string{"65000:100", "NO_EXPORT"}, ASPath: []uint32{65511, 65000}, MED: 50, LocalPref: 200},
+	}, {
+		desc:       "prefix longer than range",
+		route:      &Route{Prefix: netip.MustParsePrefix("198.51.100.0/30"), ASPath: []uint32{65511}, LocalPref: 100},
+		wantResult: oc.RoutingPolicy_PolicyResultType_NEXT_STATEMENT,
+		want:       &Route{Prefix: netip.MustParsePrefix("198.51.100.0/30"), ASPath: []uint32{65501, 65501, 65511}, LocalPref: 100},
+	}, {
+		desc:       "wrong AS path",
+		route:      &Route{Prefix: netip.MustParsePrefix("2001:db8:100::/48"), ASPath: []uint32{65512}, LocalPref: 100},
+		wantResult: oc.RoutingPolicy_PolicyResultType_NEXT_STATEMENT,
+		want:       &Route{Prefix: netip.MustParsePrefix("2001:db8:100::/48"), ASPath: []uint32{65512}, LocalPref: 100},
+	}}
+	rp := testRoutingPolicy()
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, res, err := rp.Evaluate("IMPORT", tt.route)
+			if err != nil {
+				t.Fatalf("Evaluate() got unexpected error: %v", err)
+			}
+			if res != tt.wantResult {
+				t.Errorf("Evaluate() got result %v, want %v", res, tt.wantResult)
+			}
+			if diff := cmp.Diff(tt.want, got, cmp.Comparer(func(a, b netip.Prefix) bool { return a == b })); diff != "" {
+				t.Errorf("Evaluate() got unexpected route diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRoutesFromV4RouteRange(t *testing.T) {
+	peer := gosnappi.NewBgpV4Peer()
+	rr := peer.V4Routes().Add().SetName("routes")
+	rr.Addresses().Add().SetAddress("198.51.100.0").SetPrefix(24).SetCount(3).SetStep(2)
+	rr.Communities().Add().SetType(gosnappi.BgpCommunityType.MANUAL_AS_NUMBER).SetAsNumber(65000).SetAsCustom(1)
+	rr.AsPath().Segments().Add().SetAsNumbers([]uint32{65000})
+	rr.Advanced().SetIncludeMultiExitDiscriminator(true).SetMultiExitDiscriminator(10)
+
+	routes, err := RoutesFromV4RouteRange(rr, 65511)
+	if err != nil {
+		t.Fatalf("RoutesFromV4RouteRange() got unexpected error: %v", err)
+	}
+	var got []string
+	for _, r := range routes {
+		got = append(got, r.Prefix.String())
+	}
+	if want := []string{"198.51.100.0/24", "198.51.102.0/24", "198.51.104.0/24"}; !cmp.Equal(got, want) {
+		t.Errorf("RoutesFromV4RouteRange() got prefixes %v, want %v", got, want)
+	}
+	r := routes[0]
+	if !cmp.Equal(r.ASPath, []uint32{65511, 65000}) || !cmp.Equal(r.Communities, []string{"65000:1"}) || r.MED != 10 {
+		t.Errorf("RoutesFromV4RouteRange() got route %+v, want AS path [65511 65000], community 65000:1 and MED 10", r)
+	}
+}
@@ -16,8 +16,11 @@
 
 // Binary cntrserver implements the Cntr (Container) service which can be used to test base
 // functionalities of a container hosting device. It implements a service that can:
-//   - dial a remote address as specified by the Dial RPC
-//   - respond to a ping request with a specified timestamp.
+//   - dial a remote address as specified by the Dial RPC, and run gNMI Capabilities, gNMI
+//     Subscribe, gRIBI Get, gNOI System.Time or gNSI authz Probe against it
+//   - probe the TCP or UDP reachability of a remote address and measure its latency
+//   - respond to a ping request with a specified timestamp
+//   - echo UDP datagrams sent to its port.
 //
 // By running the CNTR server on one machine, A, one can validate:
 //   - An external client can connect to a gRPC service running on A.
@@ -25,28 +28,42 @@
 // By running the CNTR server on two machines, A and B, one can:
 //   - Validate that A can dial B via gRPC by calling the Dial RPC on A with B's address.
 //   - Validate that A can send an RPC to B via gRPC by calling the Dial RPC on A with B's address and ping.
+//   - Validate that A can reach B over UDP by calling the Dial RPC on A with B's address and a
+//     UDP reachability request.
+//
+// The credentials and TLS material used to dial targets and to serve the CNTR service are set
+// through flags.
 package main
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"flag" // NOLINT
 	"fmt"
 	"net"
+	"os"
+	"time"
 
 	"github.com/openconfig/gnmi/testing/fake/testing/grpc/config"
-	"github.com/openconfig/ondatra/knebind/creds"
+	"github.com/openconfig/ygot/ygot"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"k8s.io/klog/v2"
 
 	cpb "github.com/openconfig/featureprofiles/internal/cntrsrv/proto/cntr"
 	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	syspb "github.com/openconfig/gnoi/system"
+	authzpb "github.com/openconfig/gnsi/authz"
 	spb "github.com/openconfig/gribi/v1/proto/service"
 )
 
@@ -57,6 +74,22 @@ var (
 
 	// port is the port that this CNTR server should listen on.
 	port = flag.Uint("port", 60061, "port for CNTR service to listen on.")
+
+	username   = flag.String("username", "admin", "username sent to dialled targets, no credentials are sent if empty.")
+	password   = flag.String("password", "admin", "password sent to dialled targets.")
+	caCert     = flag.String("ca_cert", "", "PEM file of the CA certificates that dialled targets are verified with, targets are not verified if empty.")
+	clientCert = flag.String("client_cert", "", "PEM file of the certificate presented to dialled targets.")
+	clientKey  = flag.String("client_key", "", "PEM file of the key of client_cert.")
+	serverCert = flag.String("server_cert", "", "PEM file of the certificate served by the CNTR service, a self-signed certificate is used if empty.")
+	serverKey  = flag.String("server_key", "", "PEM file of the key of server_cert.")
+	clientCA   = flag.String("client_ca", "", "PEM file of the CA certificates that clients of the CNTR service must present a certificate from, client certificates are not required if empty.")
+)
+
+const (
+	// defaultSampleInterval is the sample interval of gNMI subscriptions that do not set one.
+	defaultSampleInterval = time.Second
+	// defaultProbeTimeout is the timeout of reachability probes that do not set one.
+	defaultProbeTimeout = time.Second
 )
 
 // C is the container for the CNTR server implementation.
@@ -73,13 +106,13 @@ func (c *C) Ping(_ context.Context, _ *cpb.PingRequest) (*cpb.PingResponse, erro
 
 // rpcCredentials stores the per-RPC username and password used for authentication.
 type rpcCredentials struct {
-	*creds.UserPass
+	username, password string
 }
 
 func (r *rpcCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
 	return map[string]string{
-		"username": "admin",
-		"password": "admin",
+		"username": r.username,
+		"password": r.password,
 	}, nil
 }
 
@@ -87,14 +120,74 @@ func (r *rpcCredentials) RequireTransportSecurity() bool {
 	return true
 }
 
+// certPool returns the pool of the CA certificates in the PEM file path.
+func certPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates in %s", path)
+	}
+	return pool, nil
+}
+
+// dialOptions returns the options used to dial targets, as set by the flags.
+func dialOptions() ([]grpc.DialOption, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: true, // NOLINT
+	}
+	if *caCert != "" {
+		pool, err := certPool(*caCert)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig = &tls.Config{RootCAs: pool}
+	}
+	if *clientCert != "" {
+		cert, err := tls.LoadX509KeyPair(*clientCert, *clientKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	opts := []grpc.DialOption{
+		grpc.WithBlock(),
+		grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)),
+	}
+	if *username != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(&rpcCredentials{username: *username, password: *password}))
+	}
+	return opts, nil
+}
+
+// toAny marshals the message m, received from a target, to an Any.
+func toAny(m proto.Message) (*anypb.Any, error) {
+	a, err := anypb.New(m)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "can't marshal %s to any", prototext.Format(m))
+	}
+	return a, nil
+}
+
 // Dial connects to the remote gRPC CNTR server hosted at the address in the request proto.
 func (c *C) Dial(ctx context.Context, req *cpb.DialRequest) (*cpb.DialResponse, error) {
-	conn, err := grpc.DialContext(ctx, req.GetAddr(),
-		grpc.WithBlock(),
-		grpc.WithPerRPCCredentials(&rpcCredentials{}),
-		grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
-			InsecureSkipVerify: true, // NOLINT
-		})))
+	if r := req.GetReachability(); r != nil {
+		rr, err := probe(ctx, req.GetAddr(), r)
+		if err != nil {
+			return nil, err
+		}
+		return &cpb.DialResponse{
+			Response: &cpb.DialResponse_ReachabilityResponse{ReachabilityResponse: rr},
+		}, nil
+	}
+
+	opts, err := dialOptions()
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "invalid dial credentials, %v", err)
+	}
+	conn, err := grpc.DialContext(ctx, req.GetAddr(), opts...)
 	if err != nil {
 		klog.Infof("error dialling target at %s, %v", req.GetAddr(), err)
 		return nil, err
@@ -112,6 +205,31 @@ func (c *C) Dial(ctx context.Context, req *cpb.DialRequest) (*cpb.DialResponse,
 		}, nil
 	}
 
+	if s := req.GetGnmiSubscribe(); s != nil {
+		sr, err := gnmiSubscribe(ctx, conn, s)
+		if err != nil {
+			return nil, err
+		}
+		return &cpb.DialResponse{
+			Response: &cpb.DialResponse_GnmiSubscribeResponse{GnmiSubscribeResponse: sr},
+		}, nil
+	}
+
+	if p := req.GetAuthzProbe(); p != nil {
+		cl := authzpb.NewAuthzClient(conn)
+		pr, err := cl.Probe(ctx, &authzpb.ProbeRequest{User: p.GetUser(), Rpc: p.GetRpc()})
+		if err != nil {
+			return nil, err
+		}
+		a, err := toAny(pr)
+		if err != nil {
+			return nil, err
+		}
+		return &cpb.DialResponse{
+			Response: &cpb.DialResponse_AuthzProbeResponse{AuthzProbeResponse: a},
+		}, nil
+	}
+
 	switch req.GetSrv() {
 	case cpb.Service_ST_GNMI:
 		cl := gpb.NewGNMIClient(conn)
@@ -119,9 +237,9 @@ func (c *C) Dial(ctx context.Context, req *cpb.DialRequest) (*cpb.DialResponse,
 		if err != nil {
 			return nil, err
 		}
-		a, err := anypb.New(cr)
+		a, err := toAny(cr)
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, "can't marshal %s to any", prototext.Format(cr))
+			return nil, err
 		}
 		return &cpb.DialResponse{
 			Response: &cpb.DialResponse_GnmiResponse{
@@ -143,15 +261,30 @@ func (c *C) Dial(ctx context.Context, req *cpb.DialRequest) (*cpb.DialResponse,
 		if err != nil {
 			return nil, err
 		}
-		a, err := anypb.New(msg)
+		a, err := toAny(msg)
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, "can't marshal %s to any", prototext.Format(msg))
+			return nil, err
 		}
 		return &cpb.DialResponse{
 			Response: &cpb.DialResponse_GribiResponse{
 				GribiResponse: a,
 			},
 		}, nil
+	case cpb.Service_ST_GNOI:
+		cl := syspb.NewSystemClient(conn)
+		tr, err := cl.Time(ctx, &syspb.TimeRequest{})
+		if err != nil {
+			return nil, err
+		}
+		a, err := toAny(tr)
+		if err != nil {
+			return nil, err
+		}
+		return &cpb.DialResponse{
+			Response: &cpb.DialResponse_GnoiResponse{
+				GnoiResponse: a,
+			},
+		}, nil
 	default:
 		klog.Warningf("No action was specified in request, dial-only performed, %v", req)
 	}
@@ -159,14 +292,206 @@ func (c *C) Dial(ctx context.Context, req *cpb.DialRequest) (*cpb.DialResponse,
 	return &cpb.DialResponse{}, nil
 }
 
-// startServer starts a CNTR server listening on the specified port on localhost.
+// gnmiSubscribe runs the SAMPLE subscription described by req on conn and returns its first
+// responses.
+func gnmiSubscribe(ctx context.Context, conn *grpc.ClientConn, req *cpb.GNMISubscribeRequest) (*cpb.GNMISubscribeResponse, error) {
+	if len(req.GetPaths()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "no paths to subscribe to")
+	}
+	interval := defaultSampleInterval
+	if req.GetSampleInterval() != nil {
+		interval = req.GetSampleInterval().AsDuration()
+	}
+	samples := req.GetSamples()
+	if samples == 0 {
+		samples = 1
+	}
+	origin := req.GetOrigin()
+	if origin == "" {
+		origin = "openconfig"
+	}
+	var subs []*gpb.Subscription
+	for _, p := range req.GetPaths() {
+		path, err := ygot.StringToStructuredPath(p)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid path %q, %v", p, err)
+		}
+		subs = append(subs, &gpb.Subscription{
+			Path:           path,
+			Mode:           gpb.SubscriptionMode_SAMPLE,
+			SampleInterval: uint64(interval.Nanoseconds()),
+		})
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := gpb.NewGNMIClient(conn).Subscribe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(&gpb.SubscribeRequest{
+		Request: &gpb.SubscribeRequest_Subscribe{
+			Subscribe: &gpb.SubscriptionList{
+				Prefix:       &gpb.Path{Origin: origin},
+				Subscription: subs,
+				Mode:         gpb.SubscriptionList_STREAM,
+				Encoding:     gpb.Encoding_PROTO,
+			},
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	resp := &cpb.GNMISubscribeResponse{}
+	for uint32(len(resp.GetResponses())) < samples {
+		msg, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if msg.GetSyncResponse() {
+			continue
+		}
+		a, err := toAny(msg)
+		if err != nil {
+			return nil, err
+		}
+		resp.Responses = append(resp.Responses, a)
+	}
+	return resp, nil
+}
+
+// probe sends the reachability probes described by req to addr.
+func probe(ctx context.Context, addr string, req *cpb.ReachabilityRequest) (*cpb.ReachabilityResponse, error) {
+	var probeFn func(context.Context, string, time.Duration) (time.Duration, error)
+	switch req.GetProtocol() {
+	case cpb.Protocol_PROTOCOL_TCP:
+		probeFn = probeTCP
+	case cpb.Protocol_PROTOCOL_UDP:
+		probeFn = probeUDP
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported reachability protocol %v", req.GetProtocol())
+	}
+	count := req.GetCount()
+	if count == 0 {
+		count = 1
+	}
+	timeout := defaultProbeTimeout
+	if req.GetTimeout() != nil {
+		timeout = req.GetTimeout().AsDuration()
+	}
+
+	resp := &cpb.ReachabilityResponse{}
+	for i := uint32(0); i < count; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+		resp.Sent++
+		latency, err := probeFn(ctx, addr, timeout)
+		if err != nil {
+			klog.Infof("%v probe %d of %s failed, %v", req.GetProtocol(), i, addr, err)
+			continue
+		}
+		resp.Received++
+		resp.Latency = append(resp.Latency, durationpb.New(latency))
+	}
+	return resp, nil
+}
+
+// probeTCP returns the time taken to establish a TCP connection to addr.
+func probeTCP(ctx context.Context, addr string, timeout time.Duration) (time.Duration, error) {
+	d := net.Dialer{Timeout: timeout}
+	start := time.Now()
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return 0, err
+	}
+	latency := time.Since(start)
+	conn.Close()
+	return latency, nil
+}
+
+// probeUDP returns the round-trip time of a datagram sent to the UDP echo responder at addr.
+func probeUDP(ctx context.Context, addr string, timeout time.Duration) (time.Duration, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", addr)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	payload := []byte(fmt.Sprintf("cntr-probe-%d", time.Now().UnixNano()))
+	start := time.Now()
+	if err := conn.SetDeadline(start.Add(timeout)); err != nil {
+		return 0, err
+	}
+	if _, err := conn.Write(payload); err != nil {
+		return 0, err
+	}
+	buf := make([]byte, len(payload))
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return 0, err
+		}
+		// Ignore late echoes of earlier probes.
+		if bytes.Equal(buf[:n], payload) {
+			return time.Since(start), nil
+		}
+	}
+}
+
+// serveEcho echoes the datagrams received on pc back to their sender, until pc is closed.
+func serveEcho(pc net.PacketConn) {
+	buf := make([]byte, 1500)
+	for {
+		n, addr, err := pc.ReadFrom(buf)
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil {
+			klog.Warningf("error reading UDP echo request, %v", err)
+			continue
+		}
+		if _, err := pc.WriteTo(buf[:n], addr); err != nil {
+			klog.Warningf("error sending UDP echo response to %s, %v", addr, err)
+		}
+	}
+}
+
+// serverOptions returns the options of the CNTR service, as set by the flags.
+func serverOptions() ([]grpc.ServerOption, error) {
+	if *serverCert == "" {
+		opt, err := config.WithSelfTLSCert()
+		if err != nil {
+			return nil, fmt.Errorf("cannot generate self-signed cert, %v", err)
+		}
+		return []grpc.ServerOption{opt}, nil
+	}
+	cert, err := tls.LoadX509KeyPair(*serverCert, *serverKey)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}}
+	if *clientCA != "" {
+		pool, err := certPool(*clientCA)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsConfig))}, nil
+}
+
+// startServer starts a CNTR server listening on the specified port on localhost, and a UDP echo
+// responder on the same port.
 func startServer(port uint) func() {
-	tls, err := config.WithSelfTLSCert()
+	opts, err := serverOptions()
 	if err != nil {
-		klog.Fatalf("cannot generate self-signed cert, %v", err)
+		klog.Fatalf("cannot load server credentials, %v", err)
 	}
 
-	srv := grpc.NewServer(tls)
+	srv := grpc.NewServer(opts...)
 	s := &C{}
 	cpb.RegisterCntrServer(srv, s)
 
@@ -174,11 +499,19 @@ func startServer(port uint) func() {
 	if err != nil {
 		klog.Exitf("cannot start listening, got err: %v", err)
 	}
+	pc, err := net.ListenPacket("udp", fmt.Sprintf("[::]:%d", port))
+	if err != nil {
+		klog.Exitf("cannot start listening for UDP echo requests, got err: %v", err)
+	}
 
 	klog.Infof("cntr server listening on %s", lis.Addr().String())
 
 	go srv.Serve(lis)
-	return srv.Stop
+	go serveEcho(pc)
+	return func() {
+		srv.Stop()
+		pc.Close()
+	}
 }
 
 func main() {
@@ -30,12 +30,16 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/testing/protocmp"
 	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	cpb "github.com/openconfig/featureprofiles/internal/cntrsrv/proto/cntr"
 	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	syspb "github.com/openconfig/gnoi/system"
+	authzpb "github.com/openconfig/gnsi/authz"
 	spb "github.com/openconfig/gribi/v1/proto/service"
 )
 
@@ -133,6 +137,42 @@ func (g *gNMIServer) Capabilities(ctx context.Context, req *gpb.CapabilityReques
 	}, nil
 }
 
+// sample returns the i-th sample streamed by gNMIServer.
+func sample(i int64) *gpb.SubscribeResponse {
+	return &gpb.SubscribeResponse{
+		Response: &gpb.SubscribeResponse_Update{
+			Update: &gpb.Notification{
+				Timestamp: i,
+				Update: []*gpb.Update{{
+					Path: &gpb.Path{Elem: []*gpb.PathElem{{Name: "system"}, {Name: "state"}, {Name: "hostname"}}},
+					Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "demo"}},
+				}},
+			},
+		},
+	}
+}
+
+func (g *gNMIServer) Subscribe(stream gpb.GNMI_SubscribeServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if got := req.GetSubscribe().GetSubscription()[0].GetMode(); got != gpb.SubscriptionMode_SAMPLE {
+		return status.Errorf(codes.InvalidArgument, "got subscription mode %v, want SAMPLE", got)
+	}
+	if err := stream.Send(sample(1)); err != nil {
+		return err
+	}
+	if err := stream.Send(&gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_SyncResponse{SyncResponse: true}}); err != nil {
+		return err
+	}
+	for i := int64(2); ; i++ {
+		if err := stream.Send(sample(i)); err != nil {
+			return err
+		}
+	}
+}
+
 func buildGNMIServer(t *testing.T) func(uint) func() {
 	return func(port uint) func() {
 		tls, err := config.WithSelfTLSCert()
@@ -151,6 +191,53 @@ func buildGNMIServer(t *testing.T) func(uint) func() {
 	}
 }
 
+type gNOIServer struct {
+	*syspb.UnimplementedSystemServer
+}
+
+func (g *gNOIServer) Time(ctx context.Context, req *syspb.TimeRequest) (*syspb.TimeResponse, error) {
+	return &syspb.TimeResponse{Time: 42}, nil
+}
+
+type gNSIServer struct {
+	*authzpb.UnimplementedAuthzServer
+}
+
+func (g *gNSIServer) Probe(ctx context.Context, req *authzpb.ProbeRequest) (*authzpb.ProbeResponse, error) {
+	action := authzpb.ProbeResponse_ACTION_DENY
+	if req.GetUser() == "alice" {
+		action = authzpb.ProbeResponse_ACTION_PERMIT
+	}
+	return &authzpb.ProbeResponse{Action: action, Version: req.GetRpc()}, nil
+}
+
+func buildGNOIGNSIServer(t *testing.T) func(uint) func() {
+	return func(port uint) func() {
+		tls, err := config.WithSelfTLSCert()
+		if err != nil {
+			t.Fatalf("cannot create server, %v", err)
+		}
+		srv := grpc.NewServer(tls)
+		syspb.RegisterSystemServer(srv, &gNOIServer{})
+		authzpb.RegisterAuthzServer(srv, &gNSIServer{})
+		lis, err := net.Listen("tcp", fmt.Sprintf("[::]:%d", port))
+		if err != nil {
+			t.Fatalf("cannot listen on port %d, got err: %v", port, err)
+		}
+		go srv.Serve(lis)
+		return srv.Stop
+	}
+}
+
+func mustAny(t *testing.T, m proto.Message) *anypb.Any {
+	t.Helper()
+	a, err := anypb.New(m)
+	if err != nil {
+		t.Fatalf("cannot create any from %v, %v", m, err)
+	}
+	return a
+}
+
 func TestDial(t *testing.T) {
 	timeFn = func() *timestamppb.Timestamp {
 		return &timestamppb.Timestamp{
@@ -265,6 +352,90 @@ func TestDial(t *testing.T) {
 				}(),
 			},
 		},
+	}, {
+		desc:         "gnmi subscribe",
+		inServer:     startServer,
+		inServerPort: 60061,
+		inRemote:     buildGNMIServer(t),
+		inRemotePort: 9340,
+		inReq: &cpb.DialRequest{
+			Addr: "localhost:9340",
+			Request: &cpb.DialRequest_GnmiSubscribe{
+				GnmiSubscribe: &cpb.GNMISubscribeRequest{
+					Paths:          []string{"/system/state/hostname"},
+					SampleInterval: durationpb.New(10 * time.Millisecond),
+					Samples:        2,
+				},
+			},
+		},
+		wantResp: &cpb.DialResponse{
+			Response: &cpb.DialResponse_GnmiSubscribeResponse{
+				GnmiSubscribeResponse: &cpb.GNMISubscribeResponse{
+					Responses: []*anypb.Any{mustAny(t, sample(1)), mustAny(t, sample(2))},
+				},
+			},
+		},
+	}, {
+		desc:         "gnmi subscribe without paths",
+		inServer:     startServer,
+		inServerPort: 60061,
+		inRemote:     buildGNMIServer(t),
+		inRemotePort: 9340,
+		inReq: &cpb.DialRequest{
+			Addr: "localhost:9340",
+			Request: &cpb.DialRequest_GnmiSubscribe{
+				GnmiSubscribe: &cpb.GNMISubscribeRequest{},
+			},
+		},
+		wantErr: true,
+	}, {
+		desc:         "gnoi server",
+		inServer:     startServer,
+		inServerPort: 60061,
+		inRemote:     buildGNOIGNSIServer(t),
+		inRemotePort: 9341,
+		inReq: &cpb.DialRequest{
+			Addr: "localhost:9341",
+			Request: &cpb.DialRequest_Srv{
+				Srv: cpb.Service_ST_GNOI,
+			},
+		},
+		wantResp: &cpb.DialResponse{
+			Response: &cpb.DialResponse_GnoiResponse{
+				GnoiResponse: mustAny(t, &syspb.TimeResponse{Time: 42}),
+			},
+		},
+	}, {
+		desc:         "gnsi authz probe",
+		inServer:     startServer,
+		inServerPort: 60061,
+		inRemote:     buildGNOIGNSIServer(t),
+		inRemotePort: 9341,
+		inReq: &cpb.DialRequest{
+			Addr: "localhost:9341",
+			Request: &cpb.DialRequest_AuthzProbe{
+				AuthzProbe: &cpb.AuthzProbeRequest{User: "alice", Rpc: "/gnmi.gNMI/Get"},
+			},
+		},
+		wantResp: &cpb.DialResponse{
+			Response: &cpb.DialResponse_AuthzProbeResponse{
+				AuthzProbeResponse: mustAny(t, &authzpb.ProbeResponse{
+					Action:  authzpb.ProbeResponse_ACTION_PERMIT,
+					Version: "/gnmi.gNMI/Get",
+				}),
+			},
+		},
+	}, {
+		desc:         "unspecified reachability protocol",
+		inServer:     startServer,
+		inServerPort: 60061,
+		inReq: &cpb.DialRequest{
+			Addr: "localhost:60061",
+			Request: &cpb.DialRequest_Reachability{
+				Reachability: &cpb.ReachabilityRequest{},
+			},
+		},
+		wantErr: true,
 	}}
 
 	for _, tt := range tests {
@@ -292,3 +463,100 @@ func TestDial(t *testing.T) {
 		})
 	}
 }
+
+func TestReachability(t *testing.T) {
+	tests := []struct {
+		desc         string
+		inReq        *cpb.DialRequest
+		wantSent     uint32
+		wantReceived uint32
+	}{{
+		desc: "tcp",
+		inReq: &cpb.DialRequest{
+			Addr: "localhost:60061",
+			Request: &cpb.DialRequest_Reachability{
+				Reachability: &cpb.ReachabilityRequest{Protocol: cpb.Protocol_PROTOCOL_TCP, Count: 3},
+			},
+		},
+		wantSent:     3,
+		wantReceived: 3,
+	}, {
+		desc: "udp echo",
+		inReq: &cpb.DialRequest{
+			Addr: "localhost:60061",
+			Request: &cpb.DialRequest_Reachability{
+				Reachability: &cpb.ReachabilityRequest{Protocol: cpb.Protocol_PROTOCOL_UDP, Count: 2},
+			},
+		},
+		wantSent:     2,
+		wantReceived: 2,
+	}, {
+		desc: "tcp unreachable",
+		inReq: &cpb.DialRequest{
+			Addr: "localhost:6666",
+			Request: &cpb.DialRequest_Reachability{
+				Reachability: &cpb.ReachabilityRequest{Protocol: cpb.Protocol_PROTOCOL_TCP},
+			},
+		},
+		wantSent: 1,
+	}, {
+		desc: "udp unreachable",
+		inReq: &cpb.DialRequest{
+			Addr: "localhost:6666",
+			Request: &cpb.DialRequest_Reachability{
+				Reachability: &cpb.ReachabilityRequest{
+					Protocol: cpb.Protocol_PROTOCOL_UDP,
+					Timeout:  durationpb.New(100 * time.Millisecond),
+				},
+			},
+		},
+		wantSent: 1,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			stop := startServer(60061)
+			defer stop()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			client, stopC := newClient(ctx, t, 60061)
+			defer stopC()
+
+			got, err := client.Dial(ctx, tt.inReq)
+			if err != nil {
+				t.Fatalf("cannot probe reachability, %v", err)
+			}
+			r := got.GetReachabilityResponse()
+			if r.GetSent() != tt.wantSent || r.GetReceived() != tt.wantReceived {
+				t.Errorf("did not get expected probe counts, got sent %d, received %d, want sent %d, received %d", r.GetSent(), r.GetReceived(), tt.wantSent, tt.wantReceived)
+			}
+			if got := len(r.GetLatency()); got != int(tt.wantReceived) {
+				t.Errorf("did not get expected latencies, got %d, want %d", got, tt.wantReceived)
+			}
+			for _, l := range r.GetLatency() {
+				if l.AsDuration() <= 0 {
+					t.Errorf("did not get a positive latency, got %v", l.AsDuration())
+				}
+			}
+		})
+	}
+}
+
+func TestDialOptions(t *testing.T) {
+	defer func(u, ca string) { *username, *caCert = u, ca }(*username, *caCert)
+
+	*username, *caCert = "", ""
+	opts, err := dialOptions()
+	if err != nil {
+		t.Fatalf("cannot build dial options, %v", err)
+	}
+	if got, want := len(opts), 2; got != want {
+		t.Errorf("did not get expected dial options without credentials, got %d, want %d", got, want)
+	}
+
+	*caCert = "testdata/nonexistent.pem"
+	if _, err := dialOptions(); err == nil {
+		t.Errorf("did not get expected error for missing CA file")
+	}
+}
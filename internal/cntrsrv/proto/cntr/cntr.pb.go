@@ -10,6 +10,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	anypb "google.golang.org/protobuf/types/known/anypb"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -34,6 +35,9 @@ const (
 	// gRIBI indictes that gRIBI check should be initiated, particularly the
 	// Get RPC should be sent to the target.
 	Service_ST_GRIBI Service = 2
+	// gNOI indicates that a gNOI check should be initiated, particularly the
+	// System.Time RPC should be sent to the target.
+	Service_ST_GNOI Service = 3
 )
 
 // Enum value maps for Service.
@@ -42,11 +46,13 @@ var (
 		0: "ST_UNSPECIFIED",
 		1: "ST_GNMI",
 		2: "ST_GRIBI",
+		3: "ST_GNOI",
 	}
 	Service_value = map[string]int32{
 		"ST_UNSPECIFIED": 0,
 		"ST_GNMI":        1,
 		"ST_GRIBI":       2,
+		"ST_GNOI":        3,
 	}
 )
 
@@ -77,6 +83,59 @@ func (Service) EnumDescriptor() ([]byte, []int) {
 	return file_cntr_proto_rawDescGZIP(), []int{0}
 }
 
+// Protocol enumerates the transports used for reachability probes.
+type Protocol int32
+
+const (
+	Protocol_PROTOCOL_UNSPECIFIED Protocol = 0
+	// TCP probes measure the time to establish a TCP connection.
+	Protocol_PROTOCOL_TCP Protocol = 1
+	// UDP probes measure the round-trip time of a datagram to a UDP echo
+	// responder, such as the one run by the CNTR server on its own port.
+	Protocol_PROTOCOL_UDP Protocol = 2
+)
+
+// Enum value maps for Protocol.
+var (
+	Protocol_name = map[int32]string{
+		0: "PROTOCOL_UNSPECIFIED",
+		1: "PROTOCOL_TCP",
+		2: "PROTOCOL_UDP",
+	}
+	Protocol_value = map[string]int32{
+		"PROTOCOL_UNSPECIFIED": 0,
+		"PROTOCOL_TCP":         1,
+		"PROTOCOL_UDP":         2,
+	}
+)
+
+func (x Protocol) Enum() *Protocol {
+	p := new(Protocol)
+	*p = x
+	return p
+}
+
+func (x Protocol) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Protocol) Descriptor() protoreflect.EnumDescriptor {
+	return file_cntr_proto_enumTypes[1].Descriptor()
+}
+
+func (Protocol) Type() protoreflect.EnumType {
+	return &file_cntr_proto_enumTypes[1]
+}
+
+func (x Protocol) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Protocol.Descriptor instead.
+func (Protocol) EnumDescriptor() ([]byte, []int) {
+	return file_cntr_proto_rawDescGZIP(), []int{1}
+}
+
 type DialRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -87,6 +146,9 @@ type DialRequest struct {
 	// Types that are assignable to Request:
 	//	*DialRequest_Ping
 	//	*DialRequest_Srv
+	//	*DialRequest_GnmiSubscribe
+	//	*DialRequest_AuthzProbe
+	//	*DialRequest_Reachability
 	Request isDialRequest_Request `protobuf_oneof:"request"`
 }
 
@@ -150,6 +212,27 @@ func (x *DialRequest) GetSrv() Service {
 	return Service_ST_UNSPECIFIED
 }
 
+func (x *DialRequest) GetGnmiSubscribe() *GNMISubscribeRequest {
+	if x, ok := x.GetRequest().(*DialRequest_GnmiSubscribe); ok {
+		return x.GnmiSubscribe
+	}
+	return nil
+}
+
+func (x *DialRequest) GetAuthzProbe() *AuthzProbeRequest {
+	if x, ok := x.GetRequest().(*DialRequest_AuthzProbe); ok {
+		return x.AuthzProbe
+	}
+	return nil
+}
+
+func (x *DialRequest) GetReachability() *ReachabilityRequest {
+	if x, ok := x.GetRequest().(*DialRequest_Reachability); ok {
+		return x.Reachability
+	}
+	return nil
+}
+
 type isDialRequest_Request interface {
 	isDialRequest_Request()
 }
@@ -165,10 +248,351 @@ type DialRequest_Srv struct {
 	Srv Service `protobuf:"varint,3,opt,name=srv,proto3,enum=openconfig.featureprofiles.cntr.Service,oneof"`
 }
 
+type DialRequest_GnmiSubscribe struct {
+	// A gNMI subscription to be sampled from the target.
+	GnmiSubscribe *GNMISubscribeRequest `protobuf:"bytes,4,opt,name=gnmi_subscribe,json=gnmiSubscribe,proto3,oneof"`
+}
+
+type DialRequest_AuthzProbe struct {
+	// A gNSI authz Probe to be sent to the target.
+	AuthzProbe *AuthzProbeRequest `protobuf:"bytes,5,opt,name=authz_probe,json=authzProbe,proto3,oneof"`
+}
+
+type DialRequest_Reachability struct {
+	// A reachability probe of the address, which is sent without gRPC and
+	// so can target any TCP or UDP endpoint.
+	Reachability *ReachabilityRequest `protobuf:"bytes,6,opt,name=reachability,proto3,oneof"`
+}
+
 func (*DialRequest_Ping) isDialRequest_Request() {}
 
 func (*DialRequest_Srv) isDialRequest_Request() {}
 
+func (*DialRequest_GnmiSubscribe) isDialRequest_Request() {}
+
+func (*DialRequest_AuthzProbe) isDialRequest_Request() {}
+
+func (*DialRequest_Reachability) isDialRequest_Request() {}
+
+// GNMISubscribeRequest describes a SAMPLE subscription whose first updates
+// are returned.
+type GNMISubscribeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Paths to subscribe to, in the gNMI path string format, e.g.
+	// /interfaces/interface[name=eth0]/state/counters.
+	Paths []string `protobuf:"bytes,1,rep,name=paths,proto3" json:"paths,omitempty"`
+	// Origin of the paths, openconfig if unset.
+	Origin string `protobuf:"bytes,2,opt,name=origin,proto3" json:"origin,omitempty"`
+	// Sample interval of the subscription, 1 second if unset.
+	SampleInterval *durationpb.Duration `protobuf:"bytes,3,opt,name=sample_interval,json=sampleInterval,proto3" json:"sample_interval,omitempty"`
+	// Number of SubscribeResponse messages to return, 1 if unset. Sync
+	// responses are not counted.
+	Samples uint32 `protobuf:"varint,4,opt,name=samples,proto3" json:"samples,omitempty"`
+}
+
+func (x *GNMISubscribeRequest) Reset() {
+	*x = GNMISubscribeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cntr_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GNMISubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GNMISubscribeRequest) ProtoMessage() {}
+
+func (x *GNMISubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cntr_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GNMISubscribeRequest.ProtoReflect.Descriptor instead.
+func (*GNMISubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_cntr_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GNMISubscribeRequest) GetPaths() []string {
+	if x != nil {
+		return x.Paths
+	}
+	return nil
+}
+
+func (x *GNMISubscribeRequest) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *GNMISubscribeRequest) GetSampleInterval() *durationpb.Duration {
+	if x != nil {
+		return x.SampleInterval
+	}
+	return nil
+}
+
+func (x *GNMISubscribeRequest) GetSamples() uint32 {
+	if x != nil {
+		return x.Samples
+	}
+	return 0
+}
+
+// GNMISubscribeResponse contains the first updates of a gNMI subscription.
+type GNMISubscribeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The gNMI SubscribeResponse messages received, in order.
+	Responses []*anypb.Any `protobuf:"bytes,1,rep,name=responses,proto3" json:"responses,omitempty"`
+}
+
+func (x *GNMISubscribeResponse) Reset() {
+	*x = GNMISubscribeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cntr_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GNMISubscribeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GNMISubscribeResponse) ProtoMessage() {}
+
+func (x *GNMISubscribeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cntr_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GNMISubscribeResponse.ProtoReflect.Descriptor instead.
+func (*GNMISubscribeResponse) Descriptor() ([]byte, []int) {
+	return file_cntr_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GNMISubscribeResponse) GetResponses() []*anypb.Any {
+	if x != nil {
+		return x.Responses
+	}
+	return nil
+}
+
+// AuthzProbeRequest describes a gNSI authz Probe request.
+type AuthzProbeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// User whose access is probed.
+	User string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// RPC whose access is probed, e.g. /gnmi.gNMI/Get.
+	Rpc string `protobuf:"bytes,2,opt,name=rpc,proto3" json:"rpc,omitempty"`
+}
+
+func (x *AuthzProbeRequest) Reset() {
+	*x = AuthzProbeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cntr_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthzProbeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthzProbeRequest) ProtoMessage() {}
+
+func (x *AuthzProbeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cntr_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthzProbeRequest.ProtoReflect.Descriptor instead.
+func (*AuthzProbeRequest) Descriptor() ([]byte, []int) {
+	return file_cntr_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AuthzProbeRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *AuthzProbeRequest) GetRpc() string {
+	if x != nil {
+		return x.Rpc
+	}
+	return ""
+}
+
+// ReachabilityRequest describes a reachability probe.
+type ReachabilityRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Protocol Protocol `protobuf:"varint,1,opt,name=protocol,proto3,enum=openconfig.featureprofiles.cntr.Protocol" json:"protocol,omitempty"`
+	// Number of probes to send, 1 if unset.
+	Count uint32 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	// Timeout of each probe, 1 second if unset.
+	Timeout *durationpb.Duration `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+}
+
+func (x *ReachabilityRequest) Reset() {
+	*x = ReachabilityRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cntr_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReachabilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReachabilityRequest) ProtoMessage() {}
+
+func (x *ReachabilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cntr_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReachabilityRequest.ProtoReflect.Descriptor instead.
+func (*ReachabilityRequest) Descriptor() ([]byte, []int) {
+	return file_cntr_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ReachabilityRequest) GetProtocol() Protocol {
+	if x != nil {
+		return x.Protocol
+	}
+	return Protocol_PROTOCOL_UNSPECIFIED
+}
+
+func (x *ReachabilityRequest) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ReachabilityRequest) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+// ReachabilityResponse contains the results of a reachability probe.
+type ReachabilityResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Number of probes sent.
+	Sent uint32 `protobuf:"varint,1,opt,name=sent,proto3" json:"sent,omitempty"`
+	// Number of probes answered within the timeout.
+	Received uint32 `protobuf:"varint,2,opt,name=received,proto3" json:"received,omitempty"`
+	// Latency of each answered probe, in order.
+	Latency []*durationpb.Duration `protobuf:"bytes,3,rep,name=latency,proto3" json:"latency,omitempty"`
+}
+
+func (x *ReachabilityResponse) Reset() {
+	*x = ReachabilityResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cntr_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReachabilityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReachabilityResponse) ProtoMessage() {}
+
+func (x *ReachabilityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cntr_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReachabilityResponse.ProtoReflect.Descriptor instead.
+func (*ReachabilityResponse) Descriptor() ([]byte, []int) {
+	return file_cntr_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ReachabilityResponse) GetSent() uint32 {
+	if x != nil {
+		return x.Sent
+	}
+	return 0
+}
+
+func (x *ReachabilityResponse) GetReceived() uint32 {
+	if x != nil {
+		return x.Received
+	}
+	return 0
+}
+
+func (x *ReachabilityResponse) GetLatency() []*durationpb.Duration {
+	if x != nil {
+		return x.Latency
+	}
+	return nil
+}
+
 type DialResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -178,13 +602,17 @@ type DialResponse struct {
 	//	*DialResponse_Pong
 	//	*DialResponse_GribiResponse
 	//	*DialResponse_GnmiResponse
+	//	*DialResponse_GnoiResponse
+	//	*DialResponse_AuthzProbeResponse
+	//	*DialResponse_GnmiSubscribeResponse
+	//	*DialResponse_ReachabilityResponse
 	Response isDialResponse_Response `protobuf_oneof:"response"`
 }
 
 func (x *DialResponse) Reset() {
 	*x = DialResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cntr_proto_msgTypes[1]
+		mi := &file_cntr_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -197,7 +625,7 @@ func (x *DialResponse) String() string {
 func (*DialResponse) ProtoMessage() {}
 
 func (x *DialResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_cntr_proto_msgTypes[1]
+	mi := &file_cntr_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -210,7 +638,7 @@ func (x *DialResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DialResponse.ProtoReflect.Descriptor instead.
 func (*DialResponse) Descriptor() ([]byte, []int) {
-	return file_cntr_proto_rawDescGZIP(), []int{1}
+	return file_cntr_proto_rawDescGZIP(), []int{6}
 }
 
 func (m *DialResponse) GetResponse() isDialResponse_Response {
@@ -241,6 +669,34 @@ func (x *DialResponse) GetGnmiResponse() *anypb.Any {
 	return nil
 }
 
+func (x *DialResponse) GetGnoiResponse() *anypb.Any {
+	if x, ok := x.GetResponse().(*DialResponse_GnoiResponse); ok {
+		return x.GnoiResponse
+	}
+	return nil
+}
+
+func (x *DialResponse) GetAuthzProbeResponse() *anypb.Any {
+	if x, ok := x.GetResponse().(*DialResponse_AuthzProbeResponse); ok {
+		return x.AuthzProbeResponse
+	}
+	return nil
+}
+
+func (x *DialResponse) GetGnmiSubscribeResponse() *GNMISubscribeResponse {
+	if x, ok := x.GetResponse().(*DialResponse_GnmiSubscribeResponse); ok {
+		return x.GnmiSubscribeResponse
+	}
+	return nil
+}
+
+func (x *DialResponse) GetReachabilityResponse() *ReachabilityResponse {
+	if x, ok := x.GetResponse().(*DialResponse_ReachabilityResponse); ok {
+		return x.ReachabilityResponse
+	}
+	return nil
+}
+
 type isDialResponse_Response interface {
 	isDialResponse_Response()
 }
@@ -264,12 +720,44 @@ type DialResponse_GnmiResponse struct {
 	GnmiResponse *anypb.Any `protobuf:"bytes,4,opt,name=gnmi_response,json=gnmiResponse,proto3,oneof"`
 }
 
+type DialResponse_GnoiResponse struct {
+	// The gNOI message sent in response to the gNOI System.Time RPC. Populated
+	// only when the Service in the request is set to GNOI.
+	GnoiResponse *anypb.Any `protobuf:"bytes,5,opt,name=gnoi_response,json=gnoiResponse,proto3,oneof"`
+}
+
+type DialResponse_AuthzProbeResponse struct {
+	// The gNSI authz ProbeResponse, populated when the request specifies an
+	// AuthzProbeRequest.
+	AuthzProbeResponse *anypb.Any `protobuf:"bytes,6,opt,name=authz_probe_response,json=authzProbeResponse,proto3,oneof"`
+}
+
+type DialResponse_GnmiSubscribeResponse struct {
+	// The gNMI subscription updates, populated when the request specifies a
+	// GNMISubscribeRequest.
+	GnmiSubscribeResponse *GNMISubscribeResponse `protobuf:"bytes,7,opt,name=gnmi_subscribe_response,json=gnmiSubscribeResponse,proto3,oneof"`
+}
+
+type DialResponse_ReachabilityResponse struct {
+	// The reachability probe results, populated when the request specifies a
+	// ReachabilityRequest.
+	ReachabilityResponse *ReachabilityResponse `protobuf:"bytes,8,opt,name=reachability_response,json=reachabilityResponse,proto3,oneof"`
+}
+
 func (*DialResponse_Pong) isDialResponse_Response() {}
 
 func (*DialResponse_GribiResponse) isDialResponse_Response() {}
 
 func (*DialResponse_GnmiResponse) isDialResponse_Response() {}
 
+func (*DialResponse_GnoiResponse) isDialResponse_Response() {}
+
+func (*DialResponse_AuthzProbeResponse) isDialResponse_Response() {}
+
+func (*DialResponse_GnmiSubscribeResponse) isDialResponse_Response() {}
+
+func (*DialResponse_ReachabilityResponse) isDialResponse_Response() {}
+
 type PingRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -279,7 +767,7 @@ type PingRequest struct {
 func (x *PingRequest) Reset() {
 	*x = PingRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cntr_proto_msgTypes[2]
+		mi := &file_cntr_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -292,7 +780,7 @@ func (x *PingRequest) String() string {
 func (*PingRequest) ProtoMessage() {}
 
 func (x *PingRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_cntr_proto_msgTypes[2]
+	mi := &file_cntr_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -305,7 +793,7 @@ func (x *PingRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PingRequest.ProtoReflect.Descriptor instead.
 func (*PingRequest) Descriptor() ([]byte, []int) {
-	return file_cntr_proto_rawDescGZIP(), []int{2}
+	return file_cntr_proto_rawDescGZIP(), []int{7}
 }
 
 type PingResponse struct {
@@ -320,7 +808,7 @@ type PingResponse struct {
 func (x *PingResponse) Reset() {
 	*x = PingResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cntr_proto_msgTypes[3]
+		mi := &file_cntr_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -333,7 +821,7 @@ func (x *PingResponse) String() string {
 func (*PingResponse) ProtoMessage() {}
 
 func (x *PingResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_cntr_proto_msgTypes[3]
+	mi := &file_cntr_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -346,7 +834,7 @@ func (x *PingResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PingResponse.ProtoReflect.Descriptor instead.
 func (*PingResponse) Descriptor() ([]byte, []int) {
-	return file_cntr_proto_rawDescGZIP(), []int{3}
+	return file_cntr_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *PingResponse) GetTimestamp() *timestamppb.Timestamp {
@@ -363,9 +851,11 @@ var file_cntr_proto_rawDesc = []byte{
 	0x65, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65,
 	0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x2e, 0x63, 0x6e, 0x74, 0x72, 0x1a, 0x19, 0x67,
 	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x61,
-	0x6e, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x6e, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
 	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
-	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xae, 0x01, 0x0a, 0x0b, 0x44, 0x69,
+	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xc1, 0x03, 0x0a, 0x0b, 0x44, 0x69,
 	0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x64, 0x64,
 	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x61, 0x64, 0x64, 0x72, 0x12, 0x42, 0x0a,
 	0x04, 0x70, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x6f, 0x70,
@@ -375,50 +865,132 @@ var file_cntr_proto_rawDesc = []byte{
 	0x67, 0x12, 0x3c, 0x0a, 0x03, 0x73, 0x72, 0x76, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x28,
 	0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x66, 0x65, 0x61, 0x74,
 	0x75, 0x72, 0x65, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x2e, 0x63, 0x6e, 0x74, 0x72,
-	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x48, 0x00, 0x52, 0x03, 0x73, 0x72, 0x76, 0x42,
-	0x09, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xdb, 0x01, 0x0a, 0x0c, 0x44,
-	0x69, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x04, 0x70,
-	0x6f, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x6f, 0x70, 0x65, 0x6e,
-	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x70, 0x72,
-	0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x2e, 0x63, 0x6e, 0x74, 0x72, 0x2e, 0x50, 0x69, 0x6e, 0x67,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x04, 0x70, 0x6f, 0x6e, 0x67,
-	0x12, 0x3d, 0x0a, 0x0e, 0x67, 0x72, 0x69, 0x62, 0x69, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
-	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x48, 0x00,
-	0x52, 0x0d, 0x67, 0x72, 0x69, 0x62, 0x69, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x3b, 0x0a, 0x0d, 0x67, 0x6e, 0x6d, 0x69, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x48, 0x00, 0x52, 0x0c,
-	0x67, 0x6e, 0x6d, 0x69, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x0a, 0x0a, 0x08,
-	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x0d, 0x0a, 0x0b, 0x50, 0x69, 0x6e, 0x67,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x48, 0x0a, 0x0c, 0x50, 0x69, 0x6e, 0x67, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
-	0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
-	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
-	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
-	0x70, 0x2a, 0x38, 0x0a, 0x07, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x0e,
-	0x53, 0x54, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00,
-	0x12, 0x0b, 0x0a, 0x07, 0x53, 0x54, 0x5f, 0x47, 0x4e, 0x4d, 0x49, 0x10, 0x01, 0x12, 0x0c, 0x0a,
-	0x08, 0x53, 0x54, 0x5f, 0x47, 0x52, 0x49, 0x42, 0x49, 0x10, 0x02, 0x32, 0xd0, 0x01, 0x0a, 0x04,
-	0x43, 0x6e, 0x74, 0x72, 0x12, 0x63, 0x0a, 0x04, 0x44, 0x69, 0x61, 0x6c, 0x12, 0x2c, 0x2e, 0x6f,
+	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x48, 0x00, 0x52, 0x03, 0x73, 0x72, 0x76, 0x12,
+	0x5e, 0x0a, 0x0e, 0x67, 0x6e, 0x6d, 0x69, 0x5f, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x35, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x2e, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x70, 0x72, 0x6f, 0x66,
+	0x69, 0x6c, 0x65, 0x73, 0x2e, 0x63, 0x6e, 0x74, 0x72, 0x2e, 0x47, 0x4e, 0x4d, 0x49, 0x53, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00,
+	0x52, 0x0d, 0x67, 0x6e, 0x6d, 0x69, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12,
+	0x55, 0x0a, 0x0b, 0x61, 0x75, 0x74, 0x68, 0x7a, 0x5f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x2e, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65,
+	0x73, 0x2e, 0x63, 0x6e, 0x74, 0x72, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x7a, 0x50, 0x72, 0x6f, 0x62,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x0a, 0x61, 0x75, 0x74, 0x68,
+	0x7a, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x12, 0x5a, 0x0a, 0x0c, 0x72, 0x65, 0x61, 0x63, 0x68, 0x61,
+	0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x6f,
 	0x70, 0x65, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72,
-	0x65, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x2e, 0x63, 0x6e, 0x74, 0x72, 0x2e, 0x44,
-	0x69, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2d, 0x2e, 0x6f, 0x70, 0x65,
-	0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x70,
-	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x2e, 0x63, 0x6e, 0x74, 0x72, 0x2e, 0x44, 0x69, 0x61,
-	0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x63, 0x0a, 0x04, 0x50, 0x69, 0x6e,
-	0x67, 0x12, 0x2c, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x66,
+	0x65, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x2e, 0x63, 0x6e, 0x74, 0x72, 0x2e, 0x52,
+	0x65, 0x61, 0x63, 0x68, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x48, 0x00, 0x52, 0x0c, 0x72, 0x65, 0x61, 0x63, 0x68, 0x61, 0x62, 0x69, 0x6c, 0x69,
+	0x74, 0x79, 0x42, 0x09, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xa2, 0x01,
+	0x0a, 0x14, 0x47, 0x4e, 0x4d, 0x49, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x70, 0x61, 0x74, 0x68, 0x73, 0x12, 0x16, 0x0a, 0x06,
+	0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x72,
+	0x69, 0x67, 0x69, 0x6e, 0x12, 0x42, 0x0a, 0x0f, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x5f, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x61, 0x6d, 0x70,
+	0x6c, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x73, 0x61, 0x6d, 0x70, 0x6c,
+	0x65, 0x73, 0x22, 0x4b, 0x0a, 0x15, 0x47, 0x4e, 0x4d, 0x49, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
+	0x69, 0x62, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a, 0x09, 0x72,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x41, 0x6e, 0x79, 0x52, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x22,
+	0x39, 0x0a, 0x11, 0x41, 0x75, 0x74, 0x68, 0x7a, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x70, 0x63, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x72, 0x70, 0x63, 0x22, 0xa7, 0x01, 0x0a, 0x13, 0x52,
+	0x65, 0x61, 0x63, 0x68, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x45, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x29, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x2e, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65,
+	0x73, 0x2e, 0x63, 0x6e, 0x74, 0x72, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x52,
+	0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12,
+	0x33, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x74, 0x69, 0x6d,
+	0x65, 0x6f, 0x75, 0x74, 0x22, 0x7b, 0x0a, 0x14, 0x52, 0x65, 0x61, 0x63, 0x68, 0x61, 0x62, 0x69,
+	0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x73, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x73, 0x65, 0x6e, 0x74,
+	0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x08, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x33, 0x0a, 0x07,
+	0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63,
+	0x79, 0x22, 0xc2, 0x04, 0x0a, 0x0c, 0x44, 0x69, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x43, 0x0a, 0x04, 0x70, 0x6f, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x2d, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x66, 0x65,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x2e, 0x63, 0x6e,
+	0x74, 0x72, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48,
+	0x00, 0x52, 0x04, 0x70, 0x6f, 0x6e, 0x67, 0x12, 0x3d, 0x0a, 0x0e, 0x67, 0x72, 0x69, 0x62, 0x69,
+	0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x41, 0x6e, 0x79, 0x48, 0x00, 0x52, 0x0d, 0x67, 0x72, 0x69, 0x62, 0x69, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x0d, 0x67, 0x6e, 0x6d, 0x69, 0x5f, 0x72,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x41, 0x6e, 0x79, 0x48, 0x00, 0x52, 0x0c, 0x67, 0x6e, 0x6d, 0x69, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x0d, 0x67, 0x6e, 0x6f, 0x69, 0x5f, 0x72, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79,
+	0x48, 0x00, 0x52, 0x0c, 0x67, 0x6e, 0x6f, 0x69, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x48, 0x0a, 0x14, 0x61, 0x75, 0x74, 0x68, 0x7a, 0x5f, 0x70, 0x72, 0x6f, 0x62, 0x65, 0x5f,
+	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x41, 0x6e, 0x79, 0x48, 0x00, 0x52, 0x12, 0x61, 0x75, 0x74, 0x68, 0x7a, 0x50, 0x72, 0x6f,
+	0x62, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x70, 0x0a, 0x17, 0x67, 0x6e,
+	0x6d, 0x69, 0x5f, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x5f, 0x72, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x36, 0x2e, 0x6f, 0x70,
+	0x65, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x2e, 0x63, 0x6e, 0x74, 0x72, 0x2e, 0x47, 0x4e,
+	0x4d, 0x49, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x15, 0x67, 0x6e, 0x6d, 0x69, 0x53, 0x75, 0x62, 0x73, 0x63,
+	0x72, 0x69, 0x62, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6c, 0x0a, 0x15,
+	0x72, 0x65, 0x61, 0x63, 0x68, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x5f, 0x72, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x35, 0x2e, 0x6f, 0x70,
+	0x65, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x2e, 0x63, 0x6e, 0x74, 0x72, 0x2e, 0x52, 0x65,
+	0x61, 0x63, 0x68, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x48, 0x00, 0x52, 0x14, 0x72, 0x65, 0x61, 0x63, 0x68, 0x61, 0x62, 0x69, 0x6c, 0x69,
+	0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x0a, 0x0a, 0x08, 0x72, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x0d, 0x0a, 0x0b, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x48, 0x0a, 0x0c, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2a,
+	0x45, 0x0a, 0x07, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x0e, 0x53, 0x54,
+	0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0b,
+	0x0a, 0x07, 0x53, 0x54, 0x5f, 0x47, 0x4e, 0x4d, 0x49, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x53,
+	0x54, 0x5f, 0x47, 0x52, 0x49, 0x42, 0x49, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x54, 0x5f,
+	0x47, 0x4e, 0x4f, 0x49, 0x10, 0x03, 0x2a, 0x48, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63,
+	0x6f, 0x6c, 0x12, 0x18, 0x0a, 0x14, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x43, 0x4f, 0x4c, 0x5f, 0x55,
+	0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c,
+	0x50, 0x52, 0x4f, 0x54, 0x4f, 0x43, 0x4f, 0x4c, 0x5f, 0x54, 0x43, 0x50, 0x10, 0x01, 0x12, 0x10,
+	0x0a, 0x0c, 0x50, 0x52, 0x4f, 0x54, 0x4f, 0x43, 0x4f, 0x4c, 0x5f, 0x55, 0x44, 0x50, 0x10, 0x02,
+	0x32, 0xd0, 0x01, 0x0a, 0x04, 0x43, 0x6e, 0x74, 0x72, 0x12, 0x63, 0x0a, 0x04, 0x44, 0x69, 0x61,
+	0x6c, 0x12, 0x2c, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x66,
 	0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x2e, 0x63,
-	0x6e, 0x74, 0x72, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x6e, 0x74, 0x72, 0x2e, 0x44, 0x69, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
 	0x2d, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x66, 0x65, 0x61,
 	0x74, 0x75, 0x72, 0x65, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x2e, 0x63, 0x6e, 0x74,
-	0x72, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x49,
-	0x50, 0x01, 0x5a, 0x45, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x2f, 0x6f, 0x70,
-	0x65, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65,
-	0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61,
-	0x6c, 0x2f, 0x63, 0x6e, 0x74, 0x72, 0x73, 0x72, 0x76, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f,
-	0x63, 0x6e, 0x74, 0x72, 0x3b, 0x63, 0x6e, 0x74, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x72, 0x2e, 0x44, 0x69, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x63,
+	0x0a, 0x04, 0x50, 0x69, 0x6e, 0x67, 0x12, 0x2c, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x2e, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x70, 0x72, 0x6f, 0x66, 0x69,
+	0x6c, 0x65, 0x73, 0x2e, 0x63, 0x6e, 0x74, 0x72, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x2d, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x2e, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65,
+	0x73, 0x2e, 0x63, 0x6e, 0x74, 0x72, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x42, 0x49, 0x50, 0x01, 0x5a, 0x45, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x2f, 0x6f, 0x70, 0x65, 0x6e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2f, 0x66, 0x65,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x2f, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x63, 0x6e, 0x74, 0x72, 0x73, 0x72, 0x76, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6e, 0x74, 0x72, 0x3b, 0x63, 0x6e, 0x74, 0x72, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -433,33 +1005,52 @@ func file_cntr_proto_rawDescGZIP() []byte {
 	return file_cntr_proto_rawDescData
 }
 
-var file_cntr_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_cntr_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_cntr_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_cntr_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_cntr_proto_goTypes = []interface{}{
 	(Service)(0),                  // 0: openconfig.featureprofiles.cntr.Service
-	(*DialRequest)(nil),           // 1: openconfig.featureprofiles.cntr.DialRequest
-	(*DialResponse)(nil),          // 2: openconfig.featureprofiles.cntr.DialResponse
-	(*PingRequest)(nil),           // 3: openconfig.featureprofiles.cntr.PingRequest
-	(*PingResponse)(nil),          // 4: openconfig.featureprofiles.cntr.PingResponse
-	(*anypb.Any)(nil),             // 5: google.protobuf.Any
-	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
+	(Protocol)(0),                 // 1: openconfig.featureprofiles.cntr.Protocol
+	(*DialRequest)(nil),           // 2: openconfig.featureprofiles.cntr.DialRequest
+	(*GNMISubscribeRequest)(nil),  // 3: openconfig.featureprofiles.cntr.GNMISubscribeRequest
+	(*GNMISubscribeResponse)(nil), // 4: openconfig.featureprofiles.cntr.GNMISubscribeResponse
+	(*AuthzProbeRequest)(nil),     // 5: openconfig.featureprofiles.cntr.AuthzProbeRequest
+	(*ReachabilityRequest)(nil),   // 6: openconfig.featureprofiles.cntr.ReachabilityRequest
+	(*ReachabilityResponse)(nil),  // 7: openconfig.featureprofiles.cntr.ReachabilityResponse
+	(*DialResponse)(nil),          // 8: openconfig.featureprofiles.cntr.DialResponse
+	(*PingRequest)(nil),           // 9: openconfig.featureprofiles.cntr.PingRequest
+	(*PingResponse)(nil),          // 10: openconfig.featureprofiles.cntr.PingResponse
+	(*durationpb.Duration)(nil),   // 11: google.protobuf.Duration
+	(*anypb.Any)(nil),             // 12: google.protobuf.Any
+	(*timestamppb.Timestamp)(nil), // 13: google.protobuf.Timestamp
 }
 var file_cntr_proto_depIdxs = []int32{
-	3, // 0: openconfig.featureprofiles.cntr.DialRequest.ping:type_name -> openconfig.featureprofiles.cntr.PingRequest
-	0, // 1: openconfig.featureprofiles.cntr.DialRequest.srv:type_name -> openconfig.featureprofiles.cntr.Service
-	4, // 2: openconfig.featureprofiles.cntr.DialResponse.pong:type_name -> openconfig.featureprofiles.cntr.PingResponse
-	5, // 3: openconfig.featureprofiles.cntr.DialResponse.gribi_response:type_name -> google.protobuf.Any
-	5, // 4: openconfig.featureprofiles.cntr.DialResponse.gnmi_response:type_name -> google.protobuf.Any
-	6, // 5: openconfig.featureprofiles.cntr.PingResponse.timestamp:type_name -> google.protobuf.Timestamp
-	1, // 6: openconfig.featureprofiles.cntr.Cntr.Dial:input_type -> openconfig.featureprofiles.cntr.DialRequest
-	3, // 7: openconfig.featureprofiles.cntr.Cntr.Ping:input_type -> openconfig.featureprofiles.cntr.PingRequest
-	2, // 8: openconfig.featureprofiles.cntr.Cntr.Dial:output_type -> openconfig.featureprofiles.cntr.DialResponse
-	4, // 9: openconfig.featureprofiles.cntr.Cntr.Ping:output_type -> openconfig.featureprofiles.cntr.PingResponse
-	8, // [8:10] is the sub-list for method output_type
-	6, // [6:8] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	9,  // 0: openconfig.featureprofiles.cntr.DialRequest.ping:type_name -> openconfig.featureprofiles.cntr.PingRequest
+	0,  // 1: openconfig.featureprofiles.cntr.DialRequest.srv:type_name -> openconfig.featureprofiles.cntr.Service
+	3,  // 2: openconfig.featureprofiles.cntr.DialRequest.gnmi_subscribe:type_name -> openconfig.featureprofiles.cntr.GNMISubscribeRequest
+	5,  // 3: openconfig.featureprofiles.cntr.DialRequest.authz_probe:type_name -> openconfig.featureprofiles.cntr.AuthzProbeRequest
+	6,  // 4: openconfig.featureprofiles.cntr.DialRequest.reachability:type_name -> openconfig.featureprofiles.cntr.ReachabilityRequest
+	11, // 5: openconfig.featureprofiles.cntr.GNMISubscribeRequest.sample_interval:type_name -> google.protobuf.Duration
+	12, // 6: openconfig.featureprofiles.cntr.GNMISubscribeResponse.responses:type_name -> google.protobuf.Any
+	1,  // 7: openconfig.featureprofiles.cntr.ReachabilityRequest.protocol:type_name -> openconfig.featureprofiles.cntr.Protocol
+	11, // 8: openconfig.featureprofiles.cntr.ReachabilityRequest.timeout:type_name -> google.protobuf.Duration
+	11, // 9: openconfig.featureprofiles.cntr.ReachabilityResponse.latency:type_name -> google.protobuf.Duration
+	10, // 10: openconfig.featureprofiles.cntr.DialResponse.pong:type_name -> openconfig.featureprofiles.cntr.PingResponse
+	12, // 11: openconfig.featureprofiles.cntr.DialResponse.gribi_response:type_name -> google.protobuf.Any
+	12, // 12: openconfig.featureprofiles.cntr.DialResponse.gnmi_response:type_name -> google.protobuf.Any
+	12, // 13: openconfig.featureprofiles.cntr.DialResponse.gnoi_response:type_name -> google.protobuf.Any
+	12, // 14: openconfig.featureprofiles.cntr.DialResponse.authz_probe_response:type_name -> google.protobuf.Any
+	4,  // 15: openconfig.featureprofiles.cntr.DialResponse.gnmi_subscribe_response:type_name -> openconfig.featureprofiles.cntr.GNMISubscribeResponse
+	7,  // 16: openconfig.featureprofiles.cntr.DialResponse.reachability_response:type_name -> openconfig.featureprofiles.cntr.ReachabilityResponse
+	13, // 17: openconfig.featureprofiles.cntr.PingResponse.timestamp:type_name -> google.protobuf.Timestamp
+	2,  // 18: openconfig.featureprofiles.cntr.Cntr.Dial:input_type -> openconfig.featureprofiles.cntr.DialRequest
+	9,  // 19: openconfig.featureprofiles.cntr.Cntr.Ping:input_type -> openconfig.featureprofiles.cntr.PingRequest
+	8,  // 20: openconfig.featureprofiles.cntr.Cntr.Dial:output_type -> openconfig.featureprofiles.cntr.DialResponse
+	10, // 21: openconfig.featureprofiles.cntr.Cntr.Ping:output_type -> openconfig.featureprofiles.cntr.PingResponse
+	20, // [20:22] is the sub-list for method output_type
+	18, // [18:20] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
 }
 
 func init() { file_cntr_proto_init() }
@@ -481,7 +1072,7 @@ func file_cntr_proto_init() {
 			}
 		}
 		file_cntr_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DialResponse); i {
+			switch v := v.(*GNMISubscribeRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -493,7 +1084,7 @@ func file_cntr_proto_init() {
 			}
 		}
 		file_cntr_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PingRequest); i {
+			switch v := v.(*GNMISubscribeResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -505,6 +1096,66 @@ func file_cntr_proto_init() {
 			}
 		}
 		file_cntr_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuthzProbeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cntr_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReachabilityRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cntr_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReachabilityResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cntr_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DialResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cntr_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PingRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cntr_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*PingResponse); i {
 			case 0:
 				return &v.state
@@ -520,19 +1171,26 @@ func file_cntr_proto_init() {
 	file_cntr_proto_msgTypes[0].OneofWrappers = []interface{}{
 		(*DialRequest_Ping)(nil),
 		(*DialRequest_Srv)(nil),
+		(*DialRequest_GnmiSubscribe)(nil),
+		(*DialRequest_AuthzProbe)(nil),
+		(*DialRequest_Reachability)(nil),
 	}
-	file_cntr_proto_msgTypes[1].OneofWrappers = []interface{}{
+	file_cntr_proto_msgTypes[6].OneofWrappers = []interface{}{
 		(*DialResponse_Pong)(nil),
 		(*DialResponse_GribiResponse)(nil),
 		(*DialResponse_GnmiResponse)(nil),
+		(*DialResponse_GnoiResponse)(nil),
+		(*DialResponse_AuthzProbeResponse)(nil),
+		(*DialResponse_GnmiSubscribeResponse)(nil),
+		(*DialResponse_ReachabilityResponse)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_cntr_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   4,
+			NumEnums:      2,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -82,6 +82,8 @@ type Client struct {
 	DUT         *ondatra.DUTDevice
 	FIBACK      bool
 	Persistence bool
+	// Metrics records the ACK latency of programmed entries when set.
+	Metrics *Metrics
 
 	// Unexport fields below.
 	fluentC    *fluent.GRIBIClient
@@ -266,10 +268,12 @@ func NHGEntry(nhgIndex uint64, nhWeights map[uint64]uint64, instance string, exp
 // AddEntries adds the input gRIBI entries and checks the success of the input OperationResults.
 func (c *Client) AddEntries(t testing.TB, entries []fluent.GRIBIEntry, expectedResults []*client.OpResult) {
 	t.Helper()
+	start := c.resultCount(t)
 	c.fluentC.Modify().AddEntry(t, entries...)
 	if err := c.AwaitTimeout(context.Background(), t, timeout); err != nil {
 		t.Fatalf("Error waiting to add NHG: %v", err)
 	}
+	c.recordBatch(t, "AddEntries", start)
 	for _, result := range expectedResults {
 		chk.HasResult(t, c.fluentC.Results(t),
 			result,
@@ -287,10 +291,12 @@ func (c *Client) AddIPv4(t testing.TB, prefix string, nhgIndex uint64, instance,
 	if nhgInstance != "" && nhgInstance != instance {
 		ipv4Entry.WithNextHopGroupNetworkInstance(nhgInstance)
 	}
+	start := c.resultCount(t)
 	c.fluentC.Modify().AddEntry(t, ipv4Entry)
 	if err := c.AwaitTimeout(context.Background(), t, timeout); err != nil {
 		t.Fatalf("Error waiting to add IPv4: %v", err)
 	}
+	c.recordBatch(t, "AddIPv4", start)
 	chk.HasResult(t, c.fluentC.Results(t),
 		fluent.OperationResult().
 			WithIPv4Operation(prefix).
@@ -310,10 +316,12 @@ func (c *Client) AddIPv6(t testing.TB, prefix string, nhgIndex uint64, instance,
 	if nhgInstance != "" && nhgInstance != instance {
 		ipv6Entry.WithNextHopGroupNetworkInstance(nhgInstance)
 	}
+	start := c.resultCount(t)
 	c.fluentC.Modify().AddEntry(t, ipv6Entry)
 	if err := c.AwaitTimeout(context.Background(), t, timeout); err != nil {
 		t.Fatalf("Error waiting to add IPv6: %v", err)
 	}
+	c.recordBatch(t, "AddIPv6", start)
 	chk.HasResult(t, c.fluentC.Results(t),
 		fluent.OperationResult().
 			WithIPv6Operation(prefix).
@@ -328,10 +336,12 @@ func (c *Client) AddIPv6(t testing.TB, prefix string, nhgIndex uint64, instance,
 func (c *Client) DeleteIPv4(t testing.TB, prefix string, instance string, expectedResult fluent.ProgrammingResult) {
 	t.Helper()
 	ipv4Entry := fluent.IPv4Entry().WithPrefix(prefix).WithNetworkInstance(instance)
+	start := c.resultCount(t)
 	c.fluentC.Modify().DeleteEntry(t, ipv4Entry)
 	if err := c.AwaitTimeout(context.Background(), t, timeout); err != nil {
 		t.Fatalf("Error waiting to delete IPv4: %v", err)
 	}
+	c.recordBatch(t, "DeleteIPv4", start)
 	chk.HasResult(t, c.fluentC.Results(t),
 		fluent.OperationResult().
 			WithIPv4Operation(prefix).
@@ -346,10 +356,12 @@ func (c *Client) DeleteIPv4(t testing.TB, prefix string, instance string, expect
 func (c *Client) DeleteIPv6(t testing.TB, prefix string, instance string, expectedResult fluent.ProgrammingResult) {
 	t.Helper()
 	ipv6Entry := fluent.IPv6Entry().WithPrefix(prefix).WithNetworkInstance(instance)
+	start := c.resultCount(t)
 	c.fluentC.Modify().DeleteEntry(t, ipv6Entry)
 	if err := c.AwaitTimeout(context.Background(), t, timeout); err != nil {
 		t.Fatalf("Error waiting to delete IPv6: %v", err)
 	}
+	c.recordBatch(t, "DeleteIPv6", start)
 	chk.HasResult(t, c.fluentC.Results(t),
 		fluent.OperationResult().
 			WithIPv6Operation(prefix).
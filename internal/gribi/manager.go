@@ -0,0 +1,494 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gribi
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gribigo/client"
+	"github.com/openconfig/gribigo/fluent"
+	"github.com/openconfig/ondatra"
+
+	gpb "github.com/openconfig/gribi/v1/proto/service"
+)
+
+// EventKind identifies the type of an entry in a Manager timeline.
+type EventKind string
+
+const (
+	// EventStart is recorded when a client stream is established.
+	EventStart EventKind = "START"
+	// EventStop is recorded when a client stream is stopped.
+	EventStop EventKind = "STOP"
+	// EventKill is recorded when a client stream is torn down without cleanup.
+	EventKill EventKind = "KILL"
+	// EventElection is recorded when a client sends a new election ID.
+	EventElection EventKind = "ELECTION"
+	// EventModify is recorded when a client receives results for a Modify.
+	EventModify EventKind = "MODIFY"
+	// EventGet is recorded when a client snapshots the entries on the DUT.
+	EventGet EventKind = "GET"
+	// EventError is recorded when a client operation returns an error.
+	EventError EventKind = "ERROR"
+)
+
+// Event is a single entry in the Manager timeline, describing what a client
+// saw at a given point in time.
+type Event struct {
+	Time   time.Time
+	Client string
+	Kind   EventKind
+	// ElectionID is the election ID of the client when the event was recorded.
+	ElectionID Uint128
+	// Results are the operation results received since the previous event of
+	// this client.
+	Results []*client.OpResult
+	// Entries are the entries returned by a Get, only set for EventGet.
+	Entries []*gpb.AFTEntry
+	// Err is the error returned by the operation, if any.
+	Err error
+}
+
+// String returns a one-line summary of the event for logging.
+func (e *Event) String() string {
+	s := fmt.Sprintf("%s %-8s %-9s eid=%d:%d results=%d entries=%d", e.Time.Format("15:04:05.000"), e.Client, e.Kind, e.ElectionID.High, e.ElectionID.Low, len(e.Results), len(e.Entries))
+	if e.Err != nil {
+		s += fmt.Sprintf(" err=%v", e.Err)
+	}
+	return s
+}
+
+// ClientConfig describes a single named client run by a Manager.
+type ClientConfig struct {
+	// Name is used to refer to the client in Manager operations.
+	Name string
+	// ElectionID is the initial election ID of the client. It is ignored in
+	// the AllPrimaryClients redundancy mode.
+	ElectionID  Uint128
+	FIBACK      bool
+	Persistence bool
+}
+
+type managedClient struct {
+	cfg     ClientConfig
+	fluentC *fluent.GRIBIClient
+	// cancel cancels the context of the streams of fluentC.
+	cancel     context.CancelFunc
+	electionID Uint128
+	// seen is the number of results already copied into the timeline.
+	seen int
+}
+
+// Manager runs several named gRIBI clients against a single DUT and records
+// a timeline of what each of them observed.
+//
+// Usage:
+//
+//	m := gribi.NewManager(dut, fluent.ElectedPrimaryClient,
+//	  gribi.ClientConfig{Name: "A", ElectionID: gribi.Uint128{Low: 10}, Persistence: true},
+//	  gribi.ClientConfig{Name: "B", ElectionID: gribi.Uint128{Low: 9}, Persistence: true},
+//	)
+//	defer m.Close(t)
+//	m.StartAll(t)
+//	m.Promote(t, "B")
+//	m.Kill(t, "A")
+type Manager struct {
+	DUT            *ondatra.DUTDevice
+	RedundancyMode fluent.RedundancyMode
+
+	mu       sync.Mutex
+	clients  map[string]*managedClient
+	order    []string
+	timeline []*Event
+}
+
+// NewManager returns a Manager for the given clients. Clients are not
+// connected until Start or StartAll is called.
+func NewManager(dut *ondatra.DUTDevice, mode fluent.RedundancyMode, cfgs ...ClientConfig) *Manager {
+	m := &Manager{
+		DUT:            dut,
+		RedundancyMode: mode,
+		clients:        map[string]*managedClient{},
+	}
+	for _, cfg := range cfgs {
+		m.clients[cfg.Name] = &managedClient{cfg: cfg, electionID: cfg.ElectionID}
+		m.order = append(m.order, cfg.Name)
+	}
+	return m
+}
+
+func (m *Manager) client(t testing.TB, name string) *managedClient {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.clients[name]
+	if !ok {
+		t.Fatalf("gRIBI client %q is not managed", name)
+	}
+	return c
+}
+
+// record appends an event to the timeline, attaching any results received by
+// the client since its previous event.
+func (m *Manager) record(c *managedClient, kind EventKind, err error, entries []*gpb.AFTEntry) *Event {
+	e := &Event{
+		Time:       time.Now(),
+		Client:     c.cfg.Name,
+		Kind:       kind,
+		ElectionID: c.electionID,
+		Entries:    entries,
+		Err:        err,
+	}
+	if c.fluentC != nil {
+		// Status reports failures through the testing.TB and returns nil.
+		if s := c.fluentC.Status(nopTB{}); s != nil && len(s.Results) > c.seen {
+			e.Results = s.Results[c.seen:]
+			c.seen = len(s.Results)
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.timeline = append(m.timeline, e)
+	return e
+}
+
+// connected returns the named client, failing the test if it is not
+// connected.
+func (m *Manager) connected(t testing.TB, name string) *managedClient {
+	t.Helper()
+	c := m.client(t, name)
+	if c.fluentC == nil {
+		t.Fatalf("gRIBI client %q is not connected", name)
+	}
+	return c
+}
+
+// Fluent returns the underlying fluent client of the named client, or nil if
+// the client is not connected.
+func (m *Manager) Fluent(t testing.TB, name string) *fluent.GRIBIClient {
+	t.Helper()
+	return m.client(t, name).fluentC
+}
+
+// ElectionID returns the election ID currently used by the named client.
+func (m *Manager) ElectionID(t testing.TB, name string) Uint128 {
+	t.Helper()
+	return m.client(t, name).electionID
+}
+
+// Start connects the named client to the DUT using its current election ID.
+// Connection failures are returned rather than failing the test, so that
+// callers can retry.
+func (m *Manager) Start(t testing.TB, name string) error {
+	t.Helper()
+	c := m.client(t, name)
+	m.disconnect(c, t)
+	t.Logf("Starting gRIBI client %q for dut: %s", name, m.DUT.Name())
+	ctx, cancel := context.WithCancel(context.Background())
+	stub, err := m.DUT.RawAPIs().BindingDUT().DialGRIBI(ctx)
+	if err != nil {
+		cancel()
+		m.record(c, EventStart, err, nil)
+		return err
+	}
+	c.fluentC, c.cancel = fluent.NewClient(), cancel
+	c.seen = 0
+	conn := c.fluentC.Connection().WithStub(stub).WithRedundancyMode(m.RedundancyMode)
+	if m.RedundancyMode == fluent.ElectedPrimaryClient {
+		conn.WithInitialElectionID(c.electionID.Low, c.electionID.High)
+	}
+	if c.cfg.Persistence {
+		conn.WithPersistence()
+	}
+	if c.cfg.FIBACK {
+		conn.WithFIBACK()
+	}
+	err = startFluent(ctx, c.fluentC)
+	if err == nil {
+		err = awaitTimeout(ctx, t, c.fluentC, timeout)
+	}
+	m.record(c, EventStart, err, nil)
+	if err != nil {
+		m.disconnect(c, nopTB{})
+	}
+	return err
+}
+
+// startFluent starts the streams of c. The fluent client reports failures
+// with Fatalf, so it runs in its own goroutine with a testing.TB that turns
+// the first failure into the returned error.
+func startFluent(ctx context.Context, c *fluent.GRIBIClient) error {
+	tb := &errTB{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Start(ctx, tb)
+		c.StartSending(ctx, tb)
+	}()
+	<-done
+	return tb.err
+}
+
+// disconnect stops the streams of c, if any, and cancels their context.
+func (m *Manager) disconnect(c *managedClient, t testing.TB) {
+	if c.fluentC != nil {
+		c.fluentC.Stop(t)
+		c.fluentC = nil
+	}
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+}
+
+// StartAll connects all clients in the order they were given to NewManager.
+func (m *Manager) StartAll(t testing.TB) {
+	t.Helper()
+	for _, name := range m.order {
+		if err := m.Start(t, name); err != nil {
+			t.Fatalf("Could not start gRIBI client %q: %v", name, err)
+		}
+	}
+}
+
+// Stop gracefully stops the stream of the named client.
+func (m *Manager) Stop(t testing.TB, name string) {
+	t.Helper()
+	c := m.client(t, name)
+	if c.fluentC == nil {
+		return
+	}
+	t.Logf("Stopping gRIBI client %q", name)
+	m.record(c, EventStop, nil, nil)
+	m.disconnect(c, t)
+}
+
+// Kill aborts the streams of the named client by cancelling their context,
+// without stopping the sending of pending operations or closing the streams,
+// emulating a client that disappears. Entries remain on the DUT only if the
+// client was started with persistence.
+func (m *Manager) Kill(t testing.TB, name string) {
+	t.Helper()
+	c := m.connected(t, name)
+	t.Logf("Killing gRIBI client %q stream", name)
+	m.record(c, EventKill, nil, nil)
+	c.cancel()
+	c.cancel = nil
+	// The streams are already aborted, this only releases the client.
+	m.disconnect(c, nopTB{})
+}
+
+// SetElectionID sends the given election ID on the stream of the named client.
+func (m *Manager) SetElectionID(t testing.TB, name string, electionID Uint128) {
+	t.Helper()
+	c := m.connected(t, name)
+	UpdateElectionID(t, c.fluentC, electionID)
+	c.electionID = electionID
+	m.record(c, EventElection, nil, nil)
+}
+
+// Promote makes the named client the primary by sending an election ID that
+// is one higher than both the election IDs of all managed clients and the
+// latest server election ID they have seen. Unlike LearnElectionID, it never
+// sends a lower election ID first, which would demote the client if it is
+// already the primary.
+func (m *Manager) Promote(t testing.TB, name string) Uint128 {
+	t.Helper()
+	m.connected(t, name)
+	var next Uint128
+	for _, other := range m.order {
+		c := m.client(t, other)
+		if less(next, c.electionID) {
+			next = c.electionID
+		}
+		if c.fluentC == nil {
+			continue
+		}
+		if eID, ok := serverElectionID(c.fluentC.Results(t)); ok && less(next, eID) {
+			next = eID
+		}
+	}
+	next = next.Increment()
+	m.SetElectionID(t, name, next)
+	return next
+}
+
+// serverElectionID returns the server election ID reported by the latest
+// result that carries one.
+func serverElectionID(results []*client.OpResult) (Uint128, bool) {
+	for i := len(results) - 1; i >= 0; i-- {
+		if eID := results[i].CurrentServerElectionID; eID != nil {
+			return Uint128{Low: eID.GetLow(), High: eID.GetHigh()}, true
+		}
+	}
+	return Uint128{}, false
+}
+
+// Leader returns the name of the client with the highest election ID among
+// the connected clients, or an empty string if none is connected.
+func (m *Manager) Leader() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var leader string
+	var max Uint128
+	for _, name := range m.order {
+		c := m.clients[name]
+		if c.fluentC == nil {
+			continue
+		}
+		if leader == "" || less(max, c.electionID) {
+			leader, max = name, c.electionID
+		}
+	}
+	return leader
+}
+
+// Modify sends entries as additions on the stream of the named client and
+// records the results. Unlike Client.AddEntries it does not check the
+// results, so that tests can assert on rejected operations from non-primary
+// clients using the timeline.
+func (m *Manager) Modify(t testing.TB, name string, entries ...fluent.GRIBIEntry) *Event {
+	t.Helper()
+	c := m.connected(t, name)
+	c.fluentC.Modify().AddEntry(t, entries...)
+	err := awaitTimeout(context.Background(), t, c.fluentC, timeout)
+	return m.record(c, EventModify, err, nil)
+}
+
+// Snapshot records the entries visible to the named client in all network
+// instances through the gRIBI Get RPC.
+func (m *Manager) Snapshot(t testing.TB, name string) *Event {
+	t.Helper()
+	c := m.connected(t, name)
+	resp, err := c.fluentC.Get().AllNetworkInstances().WithAFT(fluent.AllAFTs).Send()
+	if err != nil {
+		return m.record(c, EventError, err, nil)
+	}
+	return m.record(c, EventGet, nil, resp.GetEntry())
+}
+
+// ReconnectAfterSwitchover restarts every client that was connected before a
+// DUT control processor switchover, retrying until the gRIBI server accepts
+// the connection or the timeout expires. Election IDs are preserved so that
+// the primary client remains the primary.
+func (m *Manager) ReconnectAfterSwitchover(t testing.TB, maxWait time.Duration) {
+	t.Helper()
+	var names []string
+	for _, name := range m.order {
+		if m.client(t, name).fluentC != nil {
+			names = append(names, name)
+		}
+	}
+	deadline := time.Now().Add(maxWait)
+	for _, name := range names {
+		for {
+			err := m.Start(t, name)
+			if err == nil {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("gRIBI client %q could not reconnect within %v: %v", name, maxWait, err)
+			}
+			t.Logf("gRIBI client %q could not reconnect: %v, retrying...", name, err)
+			time.Sleep(10 * time.Second)
+		}
+	}
+}
+
+// Timeline returns all recorded events ordered by time.
+func (m *Manager) Timeline() []*Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	events := append([]*Event(nil), m.timeline...)
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events
+}
+
+// ClientTimeline returns the events recorded for the named client.
+func (m *Manager) ClientTimeline(name string) []*Event {
+	var events []*Event
+	for _, e := range m.Timeline() {
+		if e.Client == name {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// LogTimeline logs every recorded event.
+func (m *Manager) LogTimeline(t testing.TB) {
+	t.Helper()
+	for _, e := range m.Timeline() {
+		t.Log(e.String())
+	}
+}
+
+// Close stops all connected clients.
+func (m *Manager) Close(t testing.TB) {
+	t.Helper()
+	for _, name := range m.order {
+		m.Stop(t, name)
+	}
+}
+
+func less(a, b Uint128) bool {
+	if a.High != b.High {
+		return a.High < b.High
+	}
+	return a.Low < b.Low
+}
+
+// errTB records the first failure reported by fluent client methods. Fatal
+// failures end the calling goroutine, as they do in a test.
+type errTB struct {
+	nopTB
+	err error
+}
+
+func (e *errTB) fail(err error) {
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+func (e *errTB) Error(args ...any)                 { e.fail(fmt.Errorf("%s", fmt.Sprint(args...))) }
+func (e *errTB) Errorf(format string, args ...any) { e.fail(fmt.Errorf(format, args...)) }
+func (e *errTB) Fatal(args ...any)                 { e.Error(args...); runtime.Goexit() }
+func (e *errTB) Fatalf(format string, args ...any) { e.Errorf(format, args...); runtime.Goexit() }
+func (e *errTB) Failed() bool                      { return e.err != nil }
+
+// nopTB is used to call fluent client methods that require a testing.TB where
+// failures must not stop the test, such as stopping a killed stream.
+type nopTB struct {
+	testing.TB
+}
+
+func (nopTB) Helper()               {}
+func (nopTB) Log(...any)            {}
+func (nopTB) Logf(string, ...any)   {}
+func (nopTB) Error(...any)          {}
+func (nopTB) Errorf(string, ...any) {}
+func (nopTB) Fatal(...any)          {}
+func (nopTB) Fatalf(string, ...any) {}
+func (nopTB) Fail()                 {}
+func (nopTB) FailNow()              {}
+func (nopTB) Failed() bool          { return false }
+func (nopTB) Name() string          { return "gribi-manager" }
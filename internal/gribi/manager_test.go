@@ -0,0 +1,151 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gribi
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gribigo/client"
+	"github.com/openconfig/gribigo/fluent"
+
+	gpb "github.com/openconfig/gribi/v1/proto/service"
+)
+
+func TestLess(t *testing.T) {
+	tests := []struct {
+		a, b Uint128
+		want bool
+	}{
+		{Uint128{Low: 1}, Uint128{Low: 2}, true},
+		{Uint128{Low: 2}, Uint128{Low: 1}, false},
+		{Uint128{Low: 1}, Uint128{Low: 1}, false},
+		{Uint128{Low: 10, High: 0}, Uint128{Low: 1, High: 1}, true},
+		{Uint128{Low: 1, High: 1}, Uint128{Low: 10, High: 0}, false},
+	}
+	for _, tt := range tests {
+		if got := less(tt.a, tt.b); got != tt.want {
+			t.Errorf("less(%v, %v): got %t, want %t", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestEventString(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	e := &Event{
+		Time:       at,
+		Client:     "A",
+		Kind:       EventModify,
+		ElectionID: Uint128{Low: 10, High: 1},
+		Results:    []*client.OpResult{{}, {}},
+	}
+	want := "03:04:05.006 A        MODIFY    eid=1:10 results=2 entries=0"
+	if got := e.String(); got != want {
+		t.Errorf("String(): got %q, want %q", got, want)
+	}
+	e.Err = errors.New("unavailable")
+	if got := e.String(); got != want+" err=unavailable" {
+		t.Errorf("String() with error: got %q, want %q", got, want+" err=unavailable")
+	}
+}
+
+func TestServerElectionID(t *testing.T) {
+	results := []*client.OpResult{
+		{CurrentServerElectionID: &gpb.Uint128{Low: 5}},
+		{CurrentServerElectionID: &gpb.Uint128{Low: 7, High: 1}},
+		{},
+	}
+	if got, ok := serverElectionID(results); !ok || got != (Uint128{Low: 7, High: 1}) {
+		t.Errorf("serverElectionID() got (%v, %v), want ({7 1}, true)", got, ok)
+	}
+	if _, ok := serverElectionID([]*client.OpResult{{}}); ok {
+		t.Errorf("serverElectionID() without election IDs got ok, want not ok")
+	}
+}
+
+func TestLeader(t *testing.T) {
+	m := NewManager(nil, fluent.ElectedPrimaryClient,
+		ClientConfig{Name: "A", ElectionID: Uint128{Low: 10}},
+		ClientConfig{Name: "B", ElectionID: Uint128{Low: 20}},
+		ClientConfig{Name: "C", ElectionID: Uint128{Low: 1, High: 1}},
+	)
+	if got := m.Leader(); got != "" {
+		t.Errorf("Leader() without connected clients: got %q, want none", got)
+	}
+	m.clients["A"].fluentC = fluent.NewClient()
+	m.clients["B"].fluentC = fluent.NewClient()
+	if got := m.Leader(); got != "B" {
+		t.Errorf("Leader(): got %q, want %q", got, "B")
+	}
+	m.clients["C"].fluentC = fluent.NewClient()
+	if got := m.Leader(); got != "C" {
+		t.Errorf("Leader() with high election ID: got %q, want %q", got, "C")
+	}
+	m.clients["C"].fluentC = nil
+	m.clients["A"].electionID = Uint128{Low: 30}
+	if got := m.Leader(); got != "A" {
+		t.Errorf("Leader() after promotion: got %q, want %q", got, "A")
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	m := NewManager(nil, fluent.ElectedPrimaryClient, ClientConfig{Name: "A"}, ClientConfig{Name: "B"})
+	at := time.Now()
+	m.timeline = []*Event{
+		{Time: at.Add(2 * time.Second), Client: "A", Kind: EventModify},
+		{Time: at, Client: "A", Kind: EventStart},
+		{Time: at.Add(time.Second), Client: "B", Kind: EventStart},
+		{Time: at.Add(3 * time.Second), Client: "B", Kind: EventKill},
+	}
+	kinds := func(events []*Event) []string {
+		var s []string
+		for _, e := range events {
+			s = append(s, e.Client+":"+string(e.Kind))
+		}
+		return s
+	}
+	want := []string{"A:START", "B:START", "A:MODIFY", "B:KILL"}
+	if diff := cmp.Diff(want, kinds(m.Timeline())); diff != "" {
+		t.Errorf("Timeline() returned diff (-want +got):\n%s", diff)
+	}
+	want = []string{"B:START", "B:KILL"}
+	if diff := cmp.Diff(want, kinds(m.ClientTimeline("B"))); diff != "" {
+		t.Errorf("ClientTimeline(B) returned diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestErrTB(t *testing.T) {
+	tb := &errTB{}
+	done := make(chan struct{})
+	reached := false
+	go func() {
+		defer close(done)
+		tb.Errorf("first %d", 1)
+		tb.Fatalf("second %d", 2)
+		reached = true
+	}()
+	<-done
+	if reached {
+		t.Errorf("Fatalf() did not end the goroutine")
+	}
+	if tb.err == nil || tb.err.Error() != "first 1" {
+		t.Errorf("errTB error: got %v, want %q", tb.err, "first 1")
+	}
+	if !tb.Failed() {
+		t.Errorf("Failed(): got false, want true")
+	}
+}
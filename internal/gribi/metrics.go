@@ -0,0 +1,356 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gribi
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openconfig/featureprofiles/internal/fptest"
+	"github.com/openconfig/gribigo/client"
+	"github.com/openconfig/ondatra"
+
+	gpb "github.com/openconfig/gribi/v1/proto/service"
+)
+
+// Entry types used to group operation timings.
+const (
+	EntryNH   = "NH"
+	EntryNHG  = "NHG"
+	EntryIPv4 = "IPv4"
+	EntryIPv6 = "IPv6"
+	EntryMPLS = "MPLS"
+)
+
+// OpTiming holds the timestamps of a single AFT operation.
+type OpTiming struct {
+	OperationID uint64
+	// Type is one of the Entry* constants.
+	Type string
+	// Key identifies the entry, e.g. its prefix or index.
+	Key string
+	// Sent is when the operation was queued by the client.
+	Sent time.Time
+	// RIBAck and FIBAck are when the corresponding ACKs were received. They
+	// are zero if the ACK was not received.
+	RIBAck time.Time
+	FIBAck time.Time
+	Failed bool
+}
+
+// RIBLatency returns the time between sending the operation and its RIB ACK.
+// A FIB ACK implies RIB programming, so it is used if no RIB ACK was seen.
+func (o *OpTiming) RIBLatency() (time.Duration, bool) {
+	switch {
+	case !o.RIBAck.IsZero():
+		return o.RIBAck.Sub(o.Sent), true
+	case !o.FIBAck.IsZero():
+		return o.FIBAck.Sub(o.Sent), true
+	}
+	return 0, false
+}
+
+// FIBLatency returns the time between sending the operation and its FIB ACK.
+func (o *OpTiming) FIBLatency() (time.Duration, bool) {
+	if o.FIBAck.IsZero() {
+		return 0, false
+	}
+	return o.FIBAck.Sub(o.Sent), true
+}
+
+// CollectTimings builds operation timings from fluent client results. The
+// send time of an operation is derived from the timestamp and latency of its
+// first result; results that are not AFT operation results are ignored.
+func CollectTimings(results []*client.OpResult) []*OpTiming {
+	ops := map[uint64]*OpTiming{}
+	var order []uint64
+	for _, r := range results {
+		if r.OperationID == 0 {
+			continue
+		}
+		op, ok := ops[r.OperationID]
+		if !ok {
+			op = &OpTiming{
+				OperationID: r.OperationID,
+				Sent:        time.Unix(0, r.Timestamp-r.Latency),
+			}
+			op.Type, op.Key = opDetails(r.Details)
+			ops[r.OperationID] = op
+			order = append(order, r.OperationID)
+		}
+		ts := time.Unix(0, r.Timestamp)
+		switch r.ProgrammingResult {
+		case gpb.AFTResult_RIB_PROGRAMMED:
+			op.RIBAck = ts
+		case gpb.AFTResult_FIB_PROGRAMMED:
+			op.FIBAck = ts
+		case gpb.AFTResult_FAILED, gpb.AFTResult_FIB_FAILED:
+			op.Failed = true
+		}
+	}
+	timings := make([]*OpTiming, 0, len(order))
+	for _, id := range order {
+		timings = append(timings, ops[id])
+	}
+	return timings
+}
+
+func opDetails(d *client.OpDetailsResults) (string, string) {
+	switch {
+	case d == nil:
+		return "", ""
+	case d.IPv4Prefix != "":
+		return EntryIPv4, d.IPv4Prefix
+	case d.IPv6Prefix != "":
+		return EntryIPv6, d.IPv6Prefix
+	case d.NextHopGroupID != 0:
+		return EntryNHG, fmt.Sprint(d.NextHopGroupID)
+	case d.NextHopIndex != 0:
+		return EntryNH, fmt.Sprint(d.NextHopIndex)
+	case d.MPLSLabel != 0:
+		return EntryMPLS, fmt.Sprint(d.MPLSLabel)
+	}
+	return "", ""
+}
+
+// Percentile returns the p-th percentile (0-100) of durations using the
+// nearest-rank method. It returns 0 for an empty slice.
+func Percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+// LatencyStats summarizes the ACK latencies of a set of operations.
+type LatencyStats struct {
+	Count  int
+	Failed int
+	P50    time.Duration
+	P90    time.Duration
+	P99    time.Duration
+	Max    time.Duration
+	// EntriesPerSecond is the number of ACKed operations divided by the time
+	// between the first send and the last ACK.
+	EntriesPerSecond float64
+}
+
+func computeStats(ops []*OpTiming, latency func(*OpTiming) (time.Duration, bool), ack func(*OpTiming) time.Time) *LatencyStats {
+	s := &LatencyStats{}
+	var lats []time.Duration
+	var first, last time.Time
+	for _, op := range ops {
+		if op.Failed {
+			s.Failed++
+		}
+		l, ok := latency(op)
+		if !ok {
+			continue
+		}
+		lats = append(lats, l)
+		if first.IsZero() || op.Sent.Before(first) {
+			first = op.Sent
+		}
+		if a := ack(op); a.After(last) {
+			last = a
+		}
+	}
+	s.Count = len(lats)
+	s.P50 = Percentile(lats, 50)
+	s.P90 = Percentile(lats, 90)
+	s.P99 = Percentile(lats, 99)
+	s.Max = Percentile(lats, 100)
+	if d := last.Sub(first); d > 0 {
+		s.EntriesPerSecond = float64(s.Count) / d.Seconds()
+	}
+	return s
+}
+
+// Batch is a named group of operations, for example all the entries sent
+// by one AddEntries call.
+type Batch struct {
+	Name string
+	Ops  []*OpTiming
+}
+
+// byType returns the operations of the batch grouped by entry type, plus an
+// "ALL" group with every operation.
+func (b *Batch) byType() map[string][]*OpTiming {
+	groups := map[string][]*OpTiming{"ALL": b.Ops}
+	for _, op := range b.Ops {
+		if op.Type != "" {
+			groups[op.Type] = append(groups[op.Type], op)
+		}
+	}
+	return groups
+}
+
+// RIBStats returns the RIB ACK statistics of the batch keyed by entry type.
+// The "ALL" key covers every operation of the batch.
+func (b *Batch) RIBStats() map[string]*LatencyStats {
+	stats := map[string]*LatencyStats{}
+	for typ, ops := range b.byType() {
+		stats[typ] = computeStats(ops, (*OpTiming).RIBLatency, func(o *OpTiming) time.Time {
+			if o.RIBAck.IsZero() {
+				return o.FIBAck
+			}
+			return o.RIBAck
+		})
+	}
+	return stats
+}
+
+// FIBStats returns the FIB ACK statistics of the batch keyed by entry type.
+// The "ALL" key covers every operation of the batch.
+func (b *Batch) FIBStats() map[string]*LatencyStats {
+	stats := map[string]*LatencyStats{}
+	for typ, ops := range b.byType() {
+		stats[typ] = computeStats(ops, (*OpTiming).FIBLatency, func(o *OpTiming) time.Time { return o.FIBAck })
+	}
+	return stats
+}
+
+// Metrics records operation timings of a Client. Set Client.Metrics to a
+// non-nil Metrics before programming entries to enable recording.
+//
+// Usage:
+//
+//	c := &gribi.Client{DUT: dut, FIBACK: true, Metrics: &gribi.Metrics{}}
+//	...
+//	c.AddEntries(t, entries, results)
+//	c.Metrics.Publish(t)
+type Metrics struct {
+	mu      sync.Mutex
+	batches []*Batch
+	names   map[string]int
+}
+
+// Add records a batch built from the given results. If a batch with the same
+// name was already recorded, a sequence number is appended to the name.
+func (m *Metrics) Add(name string, results []*client.OpResult) *Batch {
+	b := &Batch{Name: name, Ops: CollectTimings(results)}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.names == nil {
+		m.names = map[string]int{}
+	}
+	if n := m.names[name]; n > 0 {
+		b.Name = fmt.Sprintf("%s_%d", name, n+1)
+	}
+	m.names[name]++
+	m.batches = append(m.batches, b)
+	return b
+}
+
+// Batches returns the recorded batches in the order they were added.
+func (m *Metrics) Batches() []*Batch {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*Batch(nil), m.batches...)
+}
+
+// CSV renders a summary row per batch, ACK kind and entry type.
+func (m *Metrics) CSV() string {
+	var sb strings.Builder
+	sb.WriteString("batch,ack,type,count,failed,p50_us,p90_us,p99_us,max_us,entries_per_second\n")
+	for _, b := range m.Batches() {
+		for _, kind := range []struct {
+			name  string
+			stats map[string]*LatencyStats
+		}{{"RIB", b.RIBStats()}, {"FIB", b.FIBStats()}} {
+			for _, typ := range sortedKeys(kind.stats) {
+				s := kind.stats[typ]
+				if s.Count == 0 {
+					continue
+				}
+				fmt.Fprintf(&sb, "%s,%s,%s,%d,%d,%d,%d,%d,%d,%.1f\n", b.Name, kind.name, typ, s.Count, s.Failed,
+					s.P50.Microseconds(), s.P90.Microseconds(), s.P99.Microseconds(), s.Max.Microseconds(), s.EntriesPerSecond)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// Publish logs the recorded metrics, adds the overall RIB and FIB figures of
+// each batch as test properties and writes the full summary as a CSV file to
+// the test outputs directory.
+func (m *Metrics) Publish(t testing.TB) {
+	t.Helper()
+	for _, b := range m.Batches() {
+		for kind, stats := range map[string]*LatencyStats{"rib": b.RIBStats()["ALL"], "fib": b.FIBStats()["ALL"]} {
+			if stats.Count == 0 {
+				continue
+			}
+			prefix := fmt.Sprintf("gribi.%s.%s", b.Name, kind)
+			ondatra.Report().AddTestProperty(t, prefix+".p50_us", fmt.Sprint(stats.P50.Microseconds()))
+			ondatra.Report().AddTestProperty(t, prefix+".p99_us", fmt.Sprint(stats.P99.Microseconds()))
+			ondatra.Report().AddTestProperty(t, prefix+".entries_per_second", fmt.Sprintf("%.1f", stats.EntriesPerSecond))
+		}
+	}
+	text := m.CSV()
+	t.Logf("gRIBI programming metrics:\n%s", text)
+	filename, err := fptest.WriteOutput(t.Name()+" gribi metrics", ".csv", text)
+	if err != nil {
+		t.Logf("Could not write gRIBI metrics: %v", err)
+	}
+	if filename != "" {
+		ondatra.Report().AddTestProperty(t, "gribi_metrics_csv", filename)
+	}
+}
+
+func sortedKeys(m map[string]*LatencyStats) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// resultCount returns the number of results the fluent client currently
+// holds, used to mark the start of a batch. It is only called when metrics
+// are enabled.
+func (c *Client) resultCount(t testing.TB) int {
+	if c.Metrics == nil {
+		return 0
+	}
+	return len(c.fluentC.Results(t))
+}
+
+// recordBatch records the results received since start as a batch.
+func (c *Client) recordBatch(t testing.TB, name string, start int) {
+	if c.Metrics == nil {
+		return
+	}
+	results := c.fluentC.Results(t)
+	if start > len(results) {
+		start = len(results)
+	}
+	c.Metrics.Add(name, results[start:])
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gribi
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openconfig/gribigo/client"
+
+	gpb "github.com/openconfig/gribi/v1/proto/service"
+)
+
+func TestPercentile(t *testing.T) {
+	var durs []time.Duration
+	for i := 100; i >= 1; i-- {
+		durs = append(durs, time.Duration(i)*time.Millisecond)
+	}
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{0, time.Millisecond},
+		{50, 50 * time.Millisecond},
+		{99, 99 * time.Millisecond},
+		{100, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := Percentile(durs, tt.p); got != tt.want {
+			t.Errorf("Percentile(%v) got %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if got := Percentile(nil, 50); got != 0 {
+		t.Errorf("Percentile(nil) got %v, want 0", got)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	ms := int64(time.Millisecond)
+	results := []*client.OpResult{
+		{Timestamp: 0, SessionParameters: &gpb.SessionParametersResult{}},
+		{OperationID: 1, Timestamp: 10 * ms, Latency: 10 * ms, ProgrammingResult: gpb.AFTResult_RIB_PROGRAMMED, Details: &client.OpDetailsResults{NextHopIndex: 1}},
+		{OperationID: 2, Timestamp: 20 * ms, Latency: 20 * ms, ProgrammingResult: gpb.AFTResult_RIB_PROGRAMMED, Details: &client.OpDetailsResults{NextHopGroupID: 1}},
+		{OperationID: 1, Timestamp: 50 * ms, Latency: 50 * ms, ProgrammingResult: gpb.AFTResult_FIB_PROGRAMMED, Details: &client.OpDetailsResults{NextHopIndex: 1}},
+		{OperationID: 3, Timestamp: 40 * ms, Latency: 30 * ms, ProgrammingResult: gpb.AFTResult_FAILED, Details: &client.OpDetailsResults{IPv4Prefix: "192.0.2.0/24"}},
+	}
+	timings := CollectTimings(results)
+	if len(timings) != 3 {
+		t.Fatalf("CollectTimings() got %d operations, want 3", len(timings))
+	}
+	if got := timings[0]; got.Type != EntryNH || got.Key != "1" {
+		t.Errorf("CollectTimings() got first operation %s %s, want NH 1", got.Type, got.Key)
+	}
+	if l, ok := timings[0].FIBLatency(); !ok || l != 50*time.Millisecond {
+		t.Errorf("FIBLatency() got %v, %v, want 50ms, true", l, ok)
+	}
+	if !timings[2].Failed {
+		t.Errorf("CollectTimings() got operation 3 not failed, want failed")
+	}
+
+	m := &Metrics{}
+	m.Add("batch", results)
+	b := m.Add("batch", results)
+	if b.Name != "batch_2" {
+		t.Errorf("Add() with duplicate name got %q, want batch_2", b.Name)
+	}
+	rib := b.RIBStats()
+	if got := rib["ALL"]; got.Count != 2 || got.Failed != 1 || got.Max != 20*time.Millisecond {
+		t.Errorf("RIBStats()[ALL] got %+v, want 2 ACKed, 1 failed, max 20ms", got)
+	}
+	if got := rib["ALL"].EntriesPerSecond; got != 100 {
+		t.Errorf("RIBStats()[ALL] got %v entries per second, want 100", got)
+	}
+	if got := b.FIBStats()[EntryNH]; got.Count != 1 {
+		t.Errorf("FIBStats()[NH] got count %d, want 1", got.Count)
+	}
+	csv := m.CSV()
+	if !strings.Contains(csv, "batch_2,FIB,NH,1,0,50000,50000,50000,50000,20.0") {
+		t.Errorf("CSV() got:\n%s\nwant row for batch_2 FIB NH", csv)
+	}
+}
@@ -0,0 +1,547 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gribi
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/netip"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openconfig/featureprofiles/internal/args"
+	"github.com/openconfig/gribigo/fluent"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	gpb "github.com/openconfig/gribi/v1/proto/service"
+)
+
+// Distribution selects how prefixes are spread over next-hop-groups.
+type Distribution string
+
+const (
+	// Uniform assigns prefixes to next-hop-groups round-robin, so that every
+	// next-hop-group is referenced by the same number of prefixes (+/- 1).
+	Uniform Distribution = "UNIFORM"
+	// Skewed assigns prefixes to next-hop-groups following a power law, so
+	// that low next-hop-group IDs are reused by many more prefixes than high
+	// ones. The ScaleProfile.Skew exponent controls the steepness.
+	Skewed Distribution = "SKEWED"
+)
+
+// WeightMode selects how the weight sum of a next-hop-group is split over
+// its next-hops.
+type WeightMode string
+
+const (
+	// EvenWeights splits the weight sum as evenly as possible.
+	EvenWeights WeightMode = "EVEN"
+	// RandomWeights splits the weight sum randomly, with every next-hop
+	// getting a weight of at least 1.
+	RandomWeights WeightMode = "RANDOM"
+)
+
+// ScaleProfile describes a set of gRIBI entries to generate. A profile yields
+// NHCount next-hops, NHGCount next-hop-groups of NHSize next-hops each, and
+// IPv4Count IPv4 entries pointing at the next-hop-groups.
+type ScaleProfile struct {
+	// Seed makes the generated entries reproducible. Two generators built
+	// from the same profile produce identical entries.
+	Seed int64
+	// NetworkInstance is where the IPv4 entries are installed.
+	NetworkInstance string
+	// NHNetworkInstance is where next-hops and next-hop-groups are
+	// installed. It defaults to NetworkInstance.
+	NHNetworkInstance string
+
+	IPv4Count int
+	// IPv4Start is the first prefix, e.g. "198.18.0.0/32". Subsequent prefixes
+	// are consecutive blocks of the same length.
+	IPv4Start string
+
+	NHCount      int
+	NHStartIndex uint64
+	// NHAddressStart is the address of the first next-hop, subsequent
+	// next-hops use consecutive addresses.
+	NHAddressStart string
+	// NHAddressCount, if set, is the number of distinct next-hop addresses.
+	// Next-hop i uses the address NHAddressStart + i modulo NHAddressCount.
+	NHAddressCount int
+
+	NHGCount      int
+	NHGStartIndex uint64
+	// NHSize is the number of next-hops in each next-hop-group.
+	NHSize int
+	// WeightSum is the sum of the weights of the next-hops of each
+	// next-hop-group. It must be at least NHSize.
+	WeightSum int
+
+	Distribution Distribution
+	// Skew is the exponent used by the Skewed distribution. Values greater
+	// than 1 concentrate prefixes on fewer next-hop-groups. Defaults to 2.
+	Skew    float64
+	Weights WeightMode
+}
+
+// DefaultVRFScaleProfile returns the profile described by the default VRF
+// gRIBI scaling flags in the args package.
+func DefaultVRFScaleProfile(ni string) *ScaleProfile {
+	return &ScaleProfile{
+		Seed:            1,
+		NetworkInstance: ni,
+		IPv4Count:       *args.DefaultVRFIPv4Count,
+		IPv4Start:       "198.18.196.1/32",
+		NHCount:         *args.DefaultVRFIPv4NHCount,
+		NHStartIndex:    1,
+		NHAddressStart:  "192.0.2.1",
+		NHGCount:        *args.DefaultVRFIPv4Count,
+		NHGStartIndex:   1,
+		NHSize:          *args.DefaultVRFIPv4NHSize,
+		WeightSum:       *args.DefaultVRFIPv4NHGWeightSum,
+		Distribution:    Uniform,
+		Weights:         EvenWeights,
+	}
+}
+
+// NonDefaultVRFScaleProfile returns the profile described by the non-default
+// VRF gRIBI scaling flags in the args package. Next-hops and next-hop-groups
+// are installed in nhNI, and their indices start after the ones used by
+// DefaultVRFScaleProfile so that both profiles can be installed together.
+// Next-hop addresses cycle through the /32 prefixes installed by
+// DefaultVRFScaleProfile, so that every next-hop resolves.
+func NonDefaultVRFScaleProfile(vrf, nhNI string) *ScaleProfile {
+	return &ScaleProfile{
+		Seed:              1,
+		NetworkInstance:   vrf,
+		NHNetworkInstance: nhNI,
+		IPv4Count:         *args.NonDefaultVRFIPv4Count,
+		IPv4Start:         "198.18.0.1/32",
+		NHCount:           *args.NonDefaultVRFIPv4NHGCount * *args.NonDefaultVRFIPv4NHSize,
+		NHStartIndex:      uint64(*args.DefaultVRFIPv4NHCount) + 1,
+		NHAddressStart:    "198.18.196.1",
+		NHAddressCount:    *args.DefaultVRFIPv4Count,
+		NHGCount:          *args.NonDefaultVRFIPv4NHGCount,
+		NHGStartIndex:     uint64(*args.DefaultVRFIPv4Count) + 1,
+		NHSize:            *args.NonDefaultVRFIPv4NHSize,
+		WeightSum:         *args.NonDefaultVRFIPv4NHGWeightSum,
+		Distribution:      Uniform,
+		Weights:           EvenWeights,
+	}
+}
+
+// scaleProfileFields maps the textproto field names of a scale profile to
+// their kinds. The schema is built at runtime so that profiles can be parsed
+// with prototext without a generated Go package. It uses proto2 optional
+// fields, so that explicitly set zero values are distinguished from unset
+// fields and override the base profile.
+var scaleProfileFields = []struct {
+	name string
+	typ  descriptorpb.FieldDescriptorProto_Type
+}{
+	{"seed", descriptorpb.FieldDescriptorProto_TYPE_INT64},
+	{"network_instance", descriptorpb.FieldDescriptorProto_TYPE_STRING},
+	{"nh_network_instance", descriptorpb.FieldDescriptorProto_TYPE_STRING},
+	{"ipv4_count", descriptorpb.FieldDescriptorProto_TYPE_INT64},
+	{"ipv4_start", descriptorpb.FieldDescriptorProto_TYPE_STRING},
+	{"nh_count", descriptorpb.FieldDescriptorProto_TYPE_INT64},
+	{"nh_start_index", descriptorpb.FieldDescriptorProto_TYPE_UINT64},
+	{"nh_address_start", descriptorpb.FieldDescriptorProto_TYPE_STRING},
+	{"nh_address_count", descriptorpb.FieldDescriptorProto_TYPE_INT64},
+	{"nhg_count", descriptorpb.FieldDescriptorProto_TYPE_INT64},
+	{"nhg_start_index", descriptorpb.FieldDescriptorProto_TYPE_UINT64},
+	{"nh_size", descriptorpb.FieldDescriptorProto_TYPE_INT64},
+	{"weight_sum", descriptorpb.FieldDescriptorProto_TYPE_INT64},
+	{"distribution", descriptorpb.FieldDescriptorProto_TYPE_STRING},
+	{"skew", descriptorpb.FieldDescriptorProto_TYPE_DOUBLE},
+	{"weights", descriptorpb.FieldDescriptorProto_TYPE_STRING},
+}
+
+func scaleProfileDescriptor() (protoreflect.MessageDescriptor, error) {
+	msg := &descriptorpb.DescriptorProto{Name: proto.String("ScaleProfile")}
+	for i, f := range scaleProfileFields {
+		msg.Field = append(msg.Field, &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(f.name),
+			Number: proto.Int32(int32(i + 1)),
+			Type:   f.typ.Enum(),
+			Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		})
+	}
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:        proto.String("featureprofiles/gribi/scale_profile.proto"),
+		Package:     proto.String("featureprofiles.gribi"),
+		Syntax:      proto.String("proto2"),
+		MessageType: []*descriptorpb.DescriptorProto{msg},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return fd.Messages().Get(0), nil
+}
+
+// ParseScaleProfile parses a scale profile in textproto format. Field names
+// are the snake_case forms of the ScaleProfile fields, for example:
+//
+//	seed: 7
+//	network_instance: "VRF-A"
+//	ipv4_count: 100000
+//	ipv4_start: "198.18.0.0/32"
+//	distribution: "SKEWED"
+//
+// Fields that are not set keep the values of base, which may be nil.
+func ParseScaleProfile(text []byte, base *ScaleProfile) (*ScaleProfile, error) {
+	md, err := scaleProfileDescriptor()
+	if err != nil {
+		return nil, err
+	}
+	m := dynamicpb.NewMessage(md)
+	if err := prototext.Unmarshal(text, m); err != nil {
+		return nil, fmt.Errorf("cannot parse scale profile: %w", err)
+	}
+	p := &ScaleProfile{}
+	if base != nil {
+		*p = *base
+	}
+	var perr error
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch fd.Name() {
+		case "seed":
+			p.Seed = v.Int()
+		case "network_instance":
+			p.NetworkInstance = v.String()
+		case "nh_network_instance":
+			p.NHNetworkInstance = v.String()
+		case "ipv4_count":
+			p.IPv4Count = int(v.Int())
+		case "ipv4_start":
+			p.IPv4Start = v.String()
+		case "nh_count":
+			p.NHCount = int(v.Int())
+		case "nh_start_index":
+			p.NHStartIndex = v.Uint()
+		case "nh_address_start":
+			p.NHAddressStart = v.String()
+		case "nh_address_count":
+			p.NHAddressCount = int(v.Int())
+		case "nhg_count":
+			p.NHGCount = int(v.Int())
+		case "nhg_start_index":
+			p.NHGStartIndex = v.Uint()
+		case "nh_size":
+			p.NHSize = int(v.Int())
+		case "weight_sum":
+			p.WeightSum = int(v.Int())
+		case "distribution":
+			p.Distribution = Distribution(strings.ToUpper(v.String()))
+		case "skew":
+			p.Skew = v.Float()
+		case "weights":
+			p.Weights = WeightMode(strings.ToUpper(v.String()))
+		default:
+			perr = fmt.Errorf("unhandled scale profile field %q", fd.Name())
+			return false
+		}
+		return true
+	})
+	return p, perr
+}
+
+// LoadScaleProfile reads a textproto scale profile from a file, see
+// ParseScaleProfile.
+func LoadScaleProfile(path string, base *ScaleProfile) (*ScaleProfile, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseScaleProfile(text, base)
+}
+
+// ScaleGenerator produces the entries of a ScaleProfile on demand. Every
+// entry is derived from its index and the profile seed only, so entries can be
+// generated in any order and without holding the whole set in memory.
+type ScaleGenerator struct {
+	p      ScaleProfile
+	prefix netip.Prefix
+	nhAddr netip.Addr
+	nhNI   string
+	skew   float64
+}
+
+// NewScaleGenerator validates the profile and returns a generator for it.
+func NewScaleGenerator(p *ScaleProfile) (*ScaleGenerator, error) {
+	g := &ScaleGenerator{p: *p, nhNI: p.NHNetworkInstance, skew: p.Skew}
+	if g.nhNI == "" {
+		g.nhNI = p.NetworkInstance
+	}
+	if g.skew == 0 {
+		g.skew = 2
+	}
+	switch {
+	case p.NetworkInstance == "":
+		return nil, fmt.Errorf("scale profile has no network instance")
+	case p.NHSize <= 0 || p.NHSize > p.NHCount:
+		return nil, fmt.Errorf("scale profile next-hop-group size %d must be in [1, %d]", p.NHSize, p.NHCount)
+	case p.WeightSum < p.NHSize:
+		return nil, fmt.Errorf("scale profile weight sum %d is less than next-hop-group size %d", p.WeightSum, p.NHSize)
+	case p.NHAddressCount < 0:
+		return nil, fmt.Errorf("scale profile next-hop address count %d is negative", p.NHAddressCount)
+	case p.IPv4Count > 0 && p.NHGCount <= 0:
+		return nil, fmt.Errorf("scale profile has %d IPv4 entries but no next-hop-groups", p.IPv4Count)
+	}
+	switch p.Distribution {
+	case "", Uniform, Skewed:
+	default:
+		return nil, fmt.Errorf("unknown scale profile distribution %q", p.Distribution)
+	}
+	switch p.Weights {
+	case "", EvenWeights, RandomWeights:
+	default:
+		return nil, fmt.Errorf("unknown scale profile weight mode %q", p.Weights)
+	}
+	var err error
+	if g.nhAddr, err = netip.ParseAddr(p.NHAddressStart); err != nil || !g.nhAddr.Is4() {
+		return nil, fmt.Errorf("invalid next-hop start address %q", p.NHAddressStart)
+	}
+	if p.IPv4Count > 0 {
+		if g.prefix, err = netip.ParsePrefix(p.IPv4Start); err != nil || !g.prefix.Addr().Is4() {
+			return nil, fmt.Errorf("invalid IPv4 start prefix %q", p.IPv4Start)
+		}
+		g.prefix = g.prefix.Masked()
+		first := uint64(binary.BigEndian.Uint32(g.prefix.Addr().AsSlice())) >> (32 - g.prefix.Bits())
+		if first+uint64(p.IPv4Count) > 1<<g.prefix.Bits() {
+			return nil, fmt.Errorf("%d prefixes starting at %s overflow the IPv4 address space", p.IPv4Count, g.prefix)
+		}
+	}
+	return g, nil
+}
+
+// Profile returns the profile of the generator.
+func (g *ScaleGenerator) Profile() ScaleProfile {
+	return g.p
+}
+
+// random returns a deterministic pseudo-random number in [0, 1) for the
+// given stream and index, using a splitmix64 hash of the seed.
+func (g *ScaleGenerator) random(stream, i, j uint64) float64 {
+	x := uint64(g.p.Seed) ^ stream*0x9e3779b97f4a7c15 ^ i*0xbf58476d1ce4e5b9 ^ j*0x94d049bb133111eb
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	x ^= x >> 31
+	return float64(x>>11) / (1 << 53)
+}
+
+// NextHop returns the index and address of the i-th next-hop.
+func (g *ScaleGenerator) NextHop(i int) (uint64, string) {
+	n := i
+	if g.p.NHAddressCount > 0 {
+		n %= g.p.NHAddressCount
+	}
+	a := binary.BigEndian.Uint32(g.nhAddr.AsSlice()) + uint32(n)
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], a)
+	return g.p.NHStartIndex + uint64(i), netip.AddrFrom4(b).String()
+}
+
+// NextHopGroup returns the ID of the i-th next-hop-group and the weights of
+// its next-hops keyed by next-hop index. Next-hop-groups pick consecutive
+// next-hops, wrapping around NHCount, so every next-hop is used.
+func (g *ScaleGenerator) NextHopGroup(i int) (uint64, map[uint64]uint64) {
+	weights := g.weights(i)
+	nhs := map[uint64]uint64{}
+	for j := 0; j < g.p.NHSize; j++ {
+		idx, _ := g.NextHop((i*g.p.NHSize + j) % g.p.NHCount)
+		nhs[idx] = weights[j]
+	}
+	return g.p.NHGStartIndex + uint64(i), nhs
+}
+
+// weights splits WeightSum over the next-hops of the i-th next-hop-group.
+func (g *ScaleGenerator) weights(i int) []uint64 {
+	n, sum := g.p.NHSize, g.p.WeightSum
+	w := make([]uint64, n)
+	if g.p.Weights != RandomWeights {
+		for j := range w {
+			w[j] = uint64(sum / n)
+			if j < sum%n {
+				w[j]++
+			}
+		}
+		return w
+	}
+	for j := range w {
+		w[j] = 1
+	}
+	for k := 0; k < sum-n; k++ {
+		w[int(g.random(1, uint64(i), uint64(k))*float64(n))]++
+	}
+	return w
+}
+
+// IPv4 returns the i-th prefix and the ID of the next-hop-group it uses.
+func (g *ScaleGenerator) IPv4(i int) (string, uint64) {
+	step := uint32(1) << (32 - g.prefix.Bits())
+	a := binary.BigEndian.Uint32(g.prefix.Addr().AsSlice()) + uint32(i)*step
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], a)
+	pfx := netip.PrefixFrom(netip.AddrFrom4(b), g.prefix.Bits()).String()
+
+	nhg := i % g.p.NHGCount
+	if g.p.Distribution == Skewed {
+		u := g.random(2, uint64(i), 0)
+		nhg = int(math.Pow(u, g.skew) * float64(g.p.NHGCount))
+	}
+	return pfx, g.p.NHGStartIndex + uint64(nhg)
+}
+
+// EntryCount returns the total number of entries generated by the profile.
+func (g *ScaleGenerator) EntryCount() int {
+	return g.p.NHCount + g.p.NHGCount + g.p.IPv4Count
+}
+
+// entry returns the n-th entry of the generator. Next-hops come first,
+// followed by next-hop-groups and IPv4 entries, so that every entry only
+// references entries that precede it.
+func (g *ScaleGenerator) entry(n int) fluent.GRIBIEntry {
+	if n < g.p.NHCount {
+		idx, addr := g.NextHop(n)
+		return fluent.NextHopEntry().WithNetworkInstance(g.nhNI).WithIndex(idx).WithIPAddress(addr)
+	}
+	if n -= g.p.NHCount; n < g.p.NHGCount {
+		id, nhs := g.NextHopGroup(n)
+		nhg := fluent.NextHopGroupEntry().WithNetworkInstance(g.nhNI).WithID(id)
+		for j := 0; j < g.p.NHSize; j++ {
+			idx, _ := g.NextHop((n*g.p.NHSize + j) % g.p.NHCount)
+			nhg.AddNextHop(idx, nhs[idx])
+		}
+		return nhg
+	}
+	pfx, nhg := g.IPv4(n - g.p.NHGCount)
+	e := fluent.IPv4Entry().WithNetworkInstance(g.p.NetworkInstance).WithPrefix(pfx).WithNextHopGroup(nhg)
+	if g.nhNI != g.p.NetworkInstance {
+		e.WithNextHopGroupNetworkInstance(g.nhNI)
+	}
+	return e
+}
+
+// Chunks calls fn with consecutive chunks of at most size entries until all
+// entries are generated or fn returns an error.
+func (g *ScaleGenerator) Chunks(size int, fn func([]fluent.GRIBIEntry) error) error {
+	if size <= 0 {
+		return fmt.Errorf("invalid chunk size %d", size)
+	}
+	total := g.EntryCount()
+	chunk := make([]fluent.GRIBIEntry, 0, size)
+	for n := 0; n < total; n++ {
+		chunk = append(chunk, g.entry(n))
+		if len(chunk) == size || n == total-1 {
+			if err := fn(chunk); err != nil {
+				return err
+			}
+			chunk = make([]fluent.GRIBIEntry, 0, size)
+		}
+	}
+	return nil
+}
+
+// ScaleOptions control how PushScale sends entries to the DUT.
+type ScaleOptions struct {
+	// ChunkSize is the number of entries sent in each ModifyRequest batch.
+	// Defaults to 1000.
+	ChunkSize int
+	// MaxPending is the number of entries that may be waiting for an ACK
+	// before the next chunk is sent. Defaults to 4 * ChunkSize.
+	MaxPending int
+	// Timeout bounds the wait for outstanding ACKs to drain below MaxPending.
+	// Defaults to one minute.
+	Timeout time.Duration
+}
+
+// ScaleStats summarizes a PushScale run.
+type ScaleStats struct {
+	Entries  int
+	Failed   int
+	Duration time.Duration
+}
+
+// PushScale streams all entries of the generator to the DUT. Chunks are sent
+// as soon as fewer than MaxPending entries are waiting for an ACK, so that
+// hundreds of thousands of entries can be programmed without queuing all of
+// them in the client. Operations acknowledged with FAILED are counted in the
+// returned stats.
+func (c *Client) PushScale(t testing.TB, g *ScaleGenerator, opts *ScaleOptions) *ScaleStats {
+	t.Helper()
+	o := ScaleOptions{ChunkSize: 1000, Timeout: timeout}
+	if opts != nil {
+		if opts.ChunkSize > 0 {
+			o.ChunkSize = opts.ChunkSize
+		}
+		if opts.MaxPending > 0 {
+			o.MaxPending = opts.MaxPending
+		}
+		if opts.Timeout > 0 {
+			o.Timeout = opts.Timeout
+		}
+	}
+	if o.MaxPending == 0 {
+		o.MaxPending = 4 * o.ChunkSize
+	}
+	stats := &ScaleStats{}
+	seen := len(c.fluentC.Results(t))
+	first := seen
+	countFailed := func() {
+		results := c.fluentC.Results(t)
+		for _, r := range results[seen:] {
+			if r.OperationID != 0 && r.ProgrammingResult == gpb.AFTResult_FAILED {
+				stats.Failed++
+			}
+		}
+		seen = len(results)
+	}
+	start := time.Now()
+	t.Logf("Pushing %d gRIBI entries in chunks of %d", g.EntryCount(), o.ChunkSize)
+	err := g.Chunks(o.ChunkSize, func(chunk []fluent.GRIBIEntry) error {
+		c.fluentC.Modify().AddEntry(t, chunk...)
+		stats.Entries += len(chunk)
+		deadline := time.Now().Add(o.Timeout)
+		for len(c.fluentC.Status(t).PendingTransactions) > o.MaxPending {
+			if time.Now().After(deadline) {
+				return fmt.Errorf("more than %d entries still pending after %v", o.MaxPending, o.Timeout)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		countFailed()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error pushing gRIBI scale entries: %v", err)
+	}
+	if err := c.AwaitTimeout(context.Background(), t, o.Timeout); err != nil {
+		t.Fatalf("Error waiting for gRIBI scale entries: %v", err)
+	}
+	countFailed()
+	stats.Duration = time.Since(start)
+	c.recordBatch(t, "PushScale", first)
+	t.Logf("Pushed %d gRIBI entries in %v, %d failed", stats.Entries, stats.Duration, stats.Failed)
+	return stats
+}
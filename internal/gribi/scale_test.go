@@ -0,0 +1,225 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gribi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gribigo/fluent"
+)
+
+func testProfile() *ScaleProfile {
+	return &ScaleProfile{
+		Seed:            42,
+		NetworkInstance: "VRF-A",
+		IPv4Count:       1000,
+		IPv4Start:       "198.18.0.0/30",
+		NHCount:         16,
+		NHStartIndex:    100,
+		NHAddressStart:  "192.0.2.1",
+		NHGCount:        10,
+		NHGStartIndex:   200,
+		NHSize:          4,
+		WeightSum:       31,
+		Distribution:    Skewed,
+		Weights:         RandomWeights,
+	}
+}
+
+func TestScaleGeneratorDeterministic(t *testing.T) {
+	g1, err := NewScaleGenerator(testProfile())
+	if err != nil {
+		t.Fatalf("NewScaleGenerator() got unexpected error: %v", err)
+	}
+	g2, err := NewScaleGenerator(testProfile())
+	if err != nil {
+		t.Fatalf("NewScaleGenerator() got unexpected error: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		id1, nhs1 := g1.NextHopGroup(i)
+		id2, nhs2 := g2.NextHopGroup(i)
+		if id1 != id2 || !cmp.Equal(nhs1, nhs2) {
+			t.Errorf("NextHopGroup(%d) differs between generators with the same seed: %d %v, %d %v", i, id1, nhs1, id2, nhs2)
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		p1, nhg1 := g1.IPv4(i)
+		p2, nhg2 := g2.IPv4(i)
+		if p1 != p2 || nhg1 != nhg2 {
+			t.Errorf("IPv4(%d) differs between generators with the same seed: %s %d, %s %d", i, p1, nhg1, p2, nhg2)
+		}
+	}
+}
+
+func TestScaleGeneratorEntries(t *testing.T) {
+	g, err := NewScaleGenerator(testProfile())
+	if err != nil {
+		t.Fatalf("NewScaleGenerator() got unexpected error: %v", err)
+	}
+	if idx, addr := g.NextHop(3); idx != 103 || addr != "192.0.2.4" {
+		t.Errorf("NextHop(3) got %d, %s, want 103, 192.0.2.4", idx, addr)
+	}
+	if pfx, _ := g.IPv4(2); pfx != "198.18.0.8/30" {
+		t.Errorf("IPv4(2) got prefix %s, want 198.18.0.8/30", pfx)
+	}
+
+	for i := 0; i < 10; i++ {
+		id, nhs := g.NextHopGroup(i)
+		if id != uint64(200+i) {
+			t.Errorf("NextHopGroup(%d) got ID %d, want %d", i, id, 200+i)
+		}
+		if len(nhs) != 4 {
+			t.Errorf("NextHopGroup(%d) got %d next-hops, want 4", i, len(nhs))
+		}
+		var sum uint64
+		for _, w := range nhs {
+			if w == 0 {
+				t.Errorf("NextHopGroup(%d) got zero weight: %v", i, nhs)
+			}
+			sum += w
+		}
+		if sum != 31 {
+			t.Errorf("NextHopGroup(%d) got weight sum %d, want 31", i, sum)
+		}
+	}
+
+	uses := map[uint64]int{}
+	for i := 0; i < 1000; i++ {
+		_, nhg := g.IPv4(i)
+		if nhg < 200 || nhg >= 210 {
+			t.Fatalf("IPv4(%d) got NHG %d, want in [200, 210)", i, nhg)
+		}
+		uses[nhg]++
+	}
+	if uses[200] <= uses[209] {
+		t.Errorf("Skewed distribution got %d uses of first NHG and %d of last, want first > last", uses[200], uses[209])
+	}
+}
+
+func TestScaleGeneratorEvenWeights(t *testing.T) {
+	p := testProfile()
+	p.Weights = EvenWeights
+	p.Distribution = Uniform
+	g, err := NewScaleGenerator(p)
+	if err != nil {
+		t.Fatalf("NewScaleGenerator() got unexpected error: %v", err)
+	}
+	if got, want := g.weights(0), []uint64{8, 8, 8, 7}; !cmp.Equal(got, want) {
+		t.Errorf("weights(0) got %v, want %v", got, want)
+	}
+	if _, nhg := g.IPv4(13); nhg != 203 {
+		t.Errorf("IPv4(13) got NHG %d, want 203", nhg)
+	}
+}
+
+func TestScaleGeneratorChunks(t *testing.T) {
+	g, err := NewScaleGenerator(testProfile())
+	if err != nil {
+		t.Fatalf("NewScaleGenerator() got unexpected error: %v", err)
+	}
+	var sizes []int
+	var total int
+	if err := g.Chunks(400, func(chunk []fluent.GRIBIEntry) error {
+		sizes = append(sizes, len(chunk))
+		for _, e := range chunk {
+			if _, err := e.OpProto(); err != nil {
+				t.Errorf("entry %d cannot be converted to proto: %v", total, err)
+			}
+			total++
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("Chunks() got unexpected error: %v", err)
+	}
+	if want := []int{400, 400, 226}; !cmp.Equal(sizes, want) {
+		t.Errorf("Chunks() got chunk sizes %v, want %v", sizes, want)
+	}
+}
+
+func TestNonDefaultVRFScaleProfileNextHops(t *testing.T) {
+	def, err := NewScaleGenerator(DefaultVRFScaleProfile("DEFAULT"))
+	if err != nil {
+		t.Fatalf("NewScaleGenerator() of default VRF profile got unexpected error: %v", err)
+	}
+	installed := map[string]bool{}
+	for i := 0; i < def.Profile().IPv4Count; i++ {
+		pfx, _ := def.IPv4(i)
+		installed[strings.TrimSuffix(pfx, "/32")] = true
+	}
+	g, err := NewScaleGenerator(NonDefaultVRFScaleProfile("VRF-A", "DEFAULT"))
+	if err != nil {
+		t.Fatalf("NewScaleGenerator() of non-default VRF profile got unexpected error: %v", err)
+	}
+	for i := 0; i < g.Profile().NHCount; i++ {
+		if _, addr := g.NextHop(i); !installed[addr] {
+			t.Fatalf("NextHop(%d) got address %s, want an address installed by the default VRF profile", i, addr)
+		}
+	}
+}
+
+func TestNewScaleGeneratorErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		mod  func(*ScaleProfile)
+	}{
+		{"no network instance", func(p *ScaleProfile) { p.NetworkInstance = "" }},
+		{"weight sum too small", func(p *ScaleProfile) { p.WeightSum = 3 }},
+		{"group larger than next-hops", func(p *ScaleProfile) { p.NHSize = 17 }},
+		{"bad distribution", func(p *ScaleProfile) { p.Distribution = "ZIPF" }},
+		{"negative address count", func(p *ScaleProfile) { p.NHAddressCount = -1 }},
+		{"bad prefix", func(p *ScaleProfile) { p.IPv4Start = "2001:db8::/64" }},
+		{"prefix overflow", func(p *ScaleProfile) { p.IPv4Start = "255.255.255.0/30" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			p := testProfile()
+			tt.mod(p)
+			if _, err := NewScaleGenerator(p); err == nil {
+				t.Errorf("NewScaleGenerator() got no error, want error")
+			}
+		})
+	}
+}
+
+func TestParseScaleProfile(t *testing.T) {
+	text := `
+seed: 7
+network_instance: "VRF-B"
+ipv4_count: 100000
+distribution: "skewed"
+skew: 1.5
+nh_start_index: 0
+`
+	got, err := ParseScaleProfile([]byte(text), testProfile())
+	if err != nil {
+		t.Fatalf("ParseScaleProfile() got unexpected error: %v", err)
+	}
+	want := testProfile()
+	want.Seed = 7
+	want.NetworkInstance = "VRF-B"
+	want.IPv4Count = 100000
+	want.Distribution = Skewed
+	want.Skew = 1.5
+	want.NHStartIndex = 0
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ParseScaleProfile() got unexpected diff (-want +got):\n%s", diff)
+	}
+
+	if _, err := ParseScaleProfile([]byte(`unknown_field: 1`), nil); err == nil {
+		t.Errorf("ParseScaleProfile() with unknown field got no error, want error")
+	}
+}
@@ -111,6 +111,13 @@ func ProtocolPath(dut *ondatra.DUTDevice) *networkinstance.NetworkInstance_Proto
 
 // addISISOC configures basic IS-IS on a device.
 func addISISOC(dev *oc.Root, areaAddress, sysID, ifaceName string, dut *ondatra.DUTDevice) {
+	isis := configISISGlobal(dev, areaAddress, sysID, L2, dut)
+	configISISInterface(isis, ifaceName, L2, 0, dut)
+}
+
+// configISISGlobal configures the IS-IS instance of a device to run at the
+// given levels and returns it.
+func configISISGlobal(dev *oc.Root, areaAddress, sysID string, level Level, dut *ondatra.DUTDevice) *oc.NetworkInstance_Protocol_Isis {
 	inst := dev.GetOrCreateNetworkInstance(deviations.DefaultNetworkInstance(dut))
 	prot := inst.GetOrCreateProtocol(PTISIS, ISISName)
 	prot.Enabled = ygot.Bool(true)
@@ -122,22 +129,39 @@ func addISISOC(dev *oc.Root, areaAddress, sysID, ifaceName string, dut *ondatra.
 	glob.Net = []string{fmt.Sprintf("%v.%v.00", areaAddress, sysID)}
 	glob.GetOrCreateAf(oc.IsisTypes_AFI_TYPE_IPV4, oc.IsisTypes_SAFI_TYPE_UNICAST).Enabled = ygot.Bool(true)
 	glob.GetOrCreateAf(oc.IsisTypes_AFI_TYPE_IPV6, oc.IsisTypes_SAFI_TYPE_UNICAST).Enabled = ygot.Bool(true)
-	level := isis.GetOrCreateLevel(2)
-	level.MetricStyle = oc.Isis_MetricStyle_WIDE_METRIC
-	// Configure ISIS enabled flag at level
-	if deviations.ISISLevelEnabled(dut) {
-		level.Enabled = ygot.Bool(true)
+	for _, l := range level.levels() {
+		lvl := isis.GetOrCreateLevel(l)
+		lvl.MetricStyle = oc.Isis_MetricStyle_WIDE_METRIC
+		// Configure ISIS enabled flag at level
+		if deviations.ISISLevelEnabled(dut) {
+			lvl.Enabled = ygot.Bool(true)
+		}
 	}
+	glob.LevelCapability = level.ocLevelType()
+	return isis
+}
+
+// configISISInterface enables IS-IS on an interface at the given levels. A
+// zero metric leaves the interface metric to the device default.
+func configISISInterface(isis *oc.NetworkInstance_Protocol_Isis, ifaceName string, level Level, metric uint32, dut *ondatra.DUTDevice) {
 	intf := isis.GetOrCreateInterface(ifaceName)
 	intf.CircuitType = oc.Isis_CircuitType_POINT_TO_POINT
 	intf.Enabled = ygot.Bool(true)
 	// Configure ISIS level at global mode if true else at interface mode
-	if deviations.ISISInterfaceLevel1DisableRequired(dut) {
+	if level == L2 && deviations.ISISInterfaceLevel1DisableRequired(dut) {
 		intf.GetOrCreateLevel(1).Enabled = ygot.Bool(false)
 	} else {
-		intf.GetOrCreateLevel(2).Enabled = ygot.Bool(true)
+		for _, l := range level.levels() {
+			intf.GetOrCreateLevel(l).Enabled = ygot.Bool(true)
+		}
+	}
+	if metric != 0 {
+		for _, l := range level.levels() {
+			lvl := intf.GetOrCreateLevel(l)
+			lvl.GetOrCreateAf(oc.IsisTypes_AFI_TYPE_IPV4, oc.IsisTypes_SAFI_TYPE_UNICAST).Metric = ygot.Uint32(metric)
+			lvl.GetOrCreateAf(oc.IsisTypes_AFI_TYPE_IPV6, oc.IsisTypes_SAFI_TYPE_UNICAST).Metric = ygot.Uint32(metric)
+		}
 	}
-	glob.LevelCapability = oc.Isis_LevelType_LEVEL_2
 	// Configure ISIS enable flag at interface level
 	intf.GetOrCreateAf(oc.IsisTypes_AFI_TYPE_IPV4, oc.IsisTypes_SAFI_TYPE_UNICAST).Enabled = ygot.Bool(true)
 	intf.GetOrCreateAf(oc.IsisTypes_AFI_TYPE_IPV6, oc.IsisTypes_SAFI_TYPE_UNICAST).Enabled = ygot.Bool(true)
@@ -148,10 +172,16 @@ func addISISOC(dev *oc.Root, areaAddress, sysID, ifaceName string, dut *ondatra.
 
 // addISISTopo configures basic IS-IS on an ATETopology interface.
 func addISISTopo(dev gosnappi.Device, areaAddress, sysID string) {
+	addISISRouter(dev, "devIsis", areaAddress, sysID, L2, 10)
+}
+
+// addISISRouter adds an IS-IS router named name to an OTG device, with a
+// single point-to-point interface on the device's first ethernet.
+func addISISRouter(dev gosnappi.Device, name, areaAddress, sysID string, level Level, metric uint32) (gosnappi.DeviceIsisRouter, gosnappi.IsisInterface) {
 
 	devIsis := dev.Isis().
 		SetSystemId(sysID).
-		SetName("devIsis")
+		SetName(name)
 
 	devIsis.Basic().
 		SetHostname(devIsis.Name()).SetLearnedLspFilter(true)
@@ -162,14 +192,23 @@ func addISISTopo(dev gosnappi.Device, areaAddress, sysID string) {
 	devIsisInt := devIsis.Interfaces().
 		Add().
 		SetEthName(dev.Ethernets().Items()[0].Name()).
-		SetName("devIsisInt").
+		SetName(name + "Int").
 		SetNetworkType(gosnappi.IsisInterfaceNetworkType.POINT_TO_POINT).
-		SetLevelType(gosnappi.IsisInterfaceLevelType.LEVEL_2).
-		SetMetric(10)
+		SetLevelType(level.otgLevelType()).
+		SetMetric(metric)
 
 	devIsisInt.Advanced().
 		SetAutoAdjustMtu(true).SetAutoAdjustArea(true).SetAutoAdjustSupportedProtocols(true)
 
+	return devIsis, devIsisInt
+}
+
+// isisInterfaceName returns the name of the IS-IS interface of a port.
+func isisInterfaceName(dut *ondatra.DUTDevice, port *ondatra.Port) string {
+	if deviations.ExplicitInterfaceInDefaultVRF(dut) {
+		return port.Name() + ".0"
+	}
+	return port.Name()
 }
 
 // TestSession is a convenience wrapper around the dut, ate, ports, and topology we're using.
@@ -185,6 +224,11 @@ type TestSession struct {
 	// them to the dut and ate.
 	DUTConf *oc.Root
 	ATETop  gosnappi.Config
+	// Links and PeerDUTs are only populated by sessions created with
+	// NewTopology; the port fields above then refer to the first adjacency
+	// and the first traffic port.
+	Links    []*Link
+	PeerDUTs map[string]*PeerDUT
 }
 
 // New creates a new TestSession using the default global config, and
@@ -229,13 +273,14 @@ func MustNew(t testing.TB) *TestSession {
 	return v
 }
 
-// WithISIS adds ISIS to a test session.
+// WithISIS adds ISIS to a test session. For sessions created with
+// NewTopology, every adjacency of the topology is configured.
 func (s *TestSession) WithISIS() *TestSession {
-	if deviations.ExplicitInterfaceInDefaultVRF(s.DUT) {
-		addISISOC(s.DUTConf, DUTAreaAddress, DUTSysID, s.DUTPort1.Name()+".0", s.DUT)
-	} else {
-		addISISOC(s.DUTConf, DUTAreaAddress, DUTSysID, s.DUTPort1.Name(), s.DUT)
+	if s.Links != nil {
+		s.addTopologyISIS()
+		return s
 	}
+	addISISOC(s.DUTConf, DUTAreaAddress, DUTSysID, isisInterfaceName(s.DUT, s.DUTPort1), s.DUT)
 	if s.ATE != nil {
 		addISISTopo(s.ATEIntf1, ATEAreaAddress, ATESysID)
 	}
@@ -262,33 +307,62 @@ func (s *TestSession) PushAndStart(t testing.TB) error {
 }
 
 // PushDUT replaces DUT config with s.dutConf. Only interfaces and the ISIS
-// protocol are written.
+// protocol are written. The configuration of peer DUTs is pushed too.
 func (s *TestSession) PushDUT(ctx context.Context, t testing.TB) error {
+	if err := pushDUT(ctx, t, s.DUT, s.DUTClient, s.DUTConf, s.dutPorts()); err != nil {
+		return err
+	}
+	for _, id := range s.peerDUTIDs() {
+		p := s.PeerDUTs[id]
+		if err := pushDUT(ctx, t, p.DUT, p.Client, p.Conf, p.ports); err != nil {
+			return fmt.Errorf("on %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// dutPorts returns the DUT ports used by the session.
+func (s *TestSession) dutPorts() []*ondatra.Port {
+	if s.Links == nil {
+		return []*ondatra.Port{s.DUTPort1, s.DUTPort2}
+	}
+	var ports []*ondatra.Port
+	for _, l := range s.Links {
+		ports = append(ports, l.DUTPort)
+	}
+	return ports
+}
+
+// pushDUT replaces the interfaces and the ISIS protocol of dut with those in
+// conf.
+func pushDUT(ctx context.Context, t testing.TB, dut *ondatra.DUTDevice, client *ygnmi.Client, conf *oc.Root, ports []*ondatra.Port) error {
 	// Push the interfaces
-	for name, conf := range s.DUTConf.Interface {
-		_, err := ygnmi.Replace(ctx, s.DUTClient, ocpath.Root().Interface(name).Config(), conf)
+	for name, conf := range conf.Interface {
+		_, err := ygnmi.Replace(ctx, client, ocpath.Root().Interface(name).Config(), conf)
 		if err != nil {
 			return fmt.Errorf("configuring interface %s: %w", name, err)
 		}
 	}
-	if deviations.ExplicitInterfaceInDefaultVRF(s.DUT) {
-		fptest.AssignToNetworkInstance(t, s.DUT, s.DUTPort1.Name(), deviations.DefaultNetworkInstance(s.DUT), 0)
-		fptest.AssignToNetworkInstance(t, s.DUT, s.DUTPort2.Name(), deviations.DefaultNetworkInstance(s.DUT), 0)
+	if deviations.ExplicitInterfaceInDefaultVRF(dut) {
+		for _, p := range ports {
+			fptest.AssignToNetworkInstance(t, dut, p.Name(), deviations.DefaultNetworkInstance(dut), 0)
+		}
 	}
-	if deviations.ExplicitPortSpeed(s.DUT) {
-		fptest.SetPortSpeed(t, s.DUTPort1)
-		fptest.SetPortSpeed(t, s.DUTPort2)
+	if deviations.ExplicitPortSpeed(dut) {
+		for _, p := range ports {
+			fptest.SetPortSpeed(t, p)
+		}
 	}
 
 	// Push the ISIS protocol
-	if _, err := ygnmi.Update(ctx, s.DUTClient, ocpath.Root().NetworkInstance(deviations.DefaultNetworkInstance(s.DUT)).Config(), &oc.NetworkInstance{
-		Name: ygot.String(deviations.DefaultNetworkInstance(s.DUT)),
+	if _, err := ygnmi.Update(ctx, client, ocpath.Root().NetworkInstance(deviations.DefaultNetworkInstance(dut)).Config(), &oc.NetworkInstance{
+		Name: ygot.String(deviations.DefaultNetworkInstance(dut)),
 		Type: oc.NetworkInstanceTypes_NETWORK_INSTANCE_TYPE_DEFAULT_INSTANCE,
 	}); err != nil {
 		return fmt.Errorf("configuring network instance: %w", err)
 	}
-	dutConf := s.DUTConf.GetOrCreateNetworkInstance(deviations.DefaultNetworkInstance(s.DUT)).GetOrCreateProtocol(PTISIS, ISISName)
-	_, err := ygnmi.Replace(ctx, s.DUTClient, ProtocolPath(s.DUT).Config(), dutConf)
+	dutConf := conf.GetOrCreateNetworkInstance(deviations.DefaultNetworkInstance(dut)).GetOrCreateProtocol(PTISIS, ISISName)
+	_, err := ygnmi.Replace(ctx, client, ProtocolPath(dut).Config(), dutConf)
 	if err != nil {
 		return fmt.Errorf("configuring ISIS: %w", err)
 	}
@@ -305,16 +379,63 @@ func (s *TestSession) PushAndStartATE(t testing.TB) {
 
 // AwaitAdjacency waits up to a minute for the dut to report that the ISISIntf
 // link has formed any IS-IS adjacency, returning the adjacency ID or an error
-// if one doesn't form.
+// if one doesn't form. For sessions created with NewTopology, it waits for
+// every adjacency and returns the ID of the first one.
 func (s *TestSession) AwaitAdjacency() (string, error) {
-	intf := ISISPath(s.DUT).Interface(s.DUTPort1.Name())
-	if deviations.ExplicitInterfaceInDefaultVRF(s.DUT) {
-		intf = ISISPath(s.DUT).Interface(s.DUTPort1.Name() + ".0")
+	if s.Links != nil {
+		ids, err := s.AwaitAdjacencies()
+		if err != nil {
+			return "", err
+		}
+		return ids[s.DUTPort1.ID()], nil
 	}
-	query := intf.LevelAny().AdjacencyAny().AdjacencyState().State()
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 	defer cancel()
-	watcher := ygnmi.WatchAll(ctx, s.DUTClient, query, func(val *ygnmi.Value[oc.E_Isis_IsisInterfaceAdjState]) error {
+	return s.awaitAdjacency(ctx, s.DUT, s.DUTClient, s.DUTPort1)
+}
+
+// AwaitAdjacencies waits up to a minute for the dut to report an IS-IS
+// adjacency on the port of every adjacency of the session, returning the
+// adjacency IDs keyed by DUT port ID. For adjacencies with a peer DUT, it
+// also waits for the peer DUT to report the adjacency on its port.
+func (s *TestSession) AwaitAdjacencies() (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	ids := make(map[string]string)
+	if s.Links == nil {
+		id, err := s.awaitAdjacency(ctx, s.DUT, s.DUTClient, s.DUTPort1)
+		if err != nil {
+			return ids, fmt.Errorf("waiting for adjacency on %s: %w", s.DUTPort1.ID(), err)
+		}
+		ids[s.DUTPort1.ID()] = id
+		return ids, nil
+	}
+	for _, l := range s.Links {
+		if l.Adjacency == nil {
+			continue
+		}
+		id, err := s.awaitAdjacency(ctx, s.DUT, s.DUTClient, l.DUTPort)
+		if err != nil {
+			return ids, fmt.Errorf("waiting for adjacency on %s: %w", l.DUTPort.ID(), err)
+		}
+		ids[l.DUTPort.ID()] = id
+		if l.Adjacency.PeerDUT == "" {
+			continue
+		}
+		p := s.PeerDUTs[l.Adjacency.PeerDUT]
+		if _, err := s.awaitAdjacency(ctx, p.DUT, p.Client, l.PeerPort); err != nil {
+			return ids, fmt.Errorf("waiting for adjacency on %s port %s: %w", p.DUT.ID(), l.PeerPort.ID(), err)
+		}
+	}
+	return ids, nil
+}
+
+// awaitAdjacency waits for dut to report an IS-IS adjacency in the UP state
+// on port, returning its system ID.
+func (s *TestSession) awaitAdjacency(ctx context.Context, dut *ondatra.DUTDevice, client *ygnmi.Client, port *ondatra.Port) (string, error) {
+	intf := ISISPath(dut).Interface(isisInterfaceName(dut, port))
+	query := intf.LevelAny().AdjacencyAny().AdjacencyState().State()
+	watcher := ygnmi.WatchAll(ctx, client, query, func(val *ygnmi.Value[oc.E_Isis_IsisInterfaceAdjState]) error {
 		if val == nil || !val.IsPresent() {
 			return ygnmi.Continue
 		}
@@ -342,6 +463,18 @@ func (s *TestSession) MustAdjacency(t testing.TB) string {
 	return adjID
 }
 
+// MustAdjacencies waits up to a minute for all IS-IS adjacencies of the
+// session to form; it returns the adjacency IDs keyed by DUT port ID or calls
+// t.Fatal if any adjacency does not form.
+func (s *TestSession) MustAdjacencies(t testing.TB) map[string]string {
+	t.Helper()
+	ids, err := s.AwaitAdjacencies()
+	if err != nil {
+		t.Fatalf("Waiting for adjacencies to form: %v", err)
+	}
+	return ids
+}
+
 // MustATEInterface returns the ATE interface for the portID, or calls t.Fatal
 // if this fails.
 func (s *TestSession) MustATEInterface(t testing.TB, portID string) gosnappi.Device {
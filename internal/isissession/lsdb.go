@@ -0,0 +1,366 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package isissession
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"net/netip"
+	"regexp"
+
+	"github.com/open-traffic-generator/snappi/gosnappi"
+)
+
+// defaultSimMetric is the link metric of generated simulated topologies.
+const defaultSimMetric = 10
+
+var sysIDRE = regexp.MustCompile(`^[0-9a-fA-F]{12}$`)
+
+// TEAttributes are the traffic engineering attributes advertised for an
+// adjacency link.
+type TEAttributes struct {
+	// AdminGroup is the administrative group bit mask.
+	AdminGroup uint32
+	// Metric is the TE default metric.
+	Metric uint32
+	// MaxBandwidth and MaxReservableBandwidth are in bytes per second.
+	MaxBandwidth           uint32
+	MaxReservableBandwidth uint32
+}
+
+// addToOTG advertises the attributes on an OTG IS-IS interface.
+func (te *TEAttributes) addToOTG(intf gosnappi.IsisInterface) {
+	intf.TrafficEngineering().Add().
+		SetAdministrativeGroup(fmt.Sprintf("%08x", te.AdminGroup)).
+		SetMetricLevel(te.Metric).
+		SetMaxBandwith(te.MaxBandwidth).
+		SetMaxReservableBandwidth(te.MaxReservableBandwidth)
+}
+
+// SimNode is a router of a simulated topology.
+type SimNode struct {
+	// Name identifies the node within the topology and is used as the
+	// hostname.
+	Name string `json:"name"`
+	// SysID is the system ID as 12 hex digits, without dots.
+	SysID string `json:"sys_id"`
+	// Prefixes are the IPv4 and IPv6 prefixes the node advertises.
+	Prefixes []netip.Prefix `json:"prefixes,omitempty"`
+}
+
+// SimLink is a point-to-point link between two simulated nodes.
+type SimLink struct {
+	// A and B are the names of the nodes at either end.
+	A string `json:"a"`
+	B string `json:"b"`
+	// Metric is the link metric in both directions.
+	Metric uint32 `json:"metric"`
+}
+
+// SimTopology is a simulated IS-IS topology advertised by the ATE behind an
+// adjacency.
+//
+// OTG has no model for simulated routers, so the ATE router emulates the
+// Attach node and advertises every prefix of the other reachable nodes as
+// its own, with the shortest path metric from Attach as the prefix metric.
+// The DUT therefore installs the same routes with the same metrics as it
+// would with the full topology, but the topology is not a simulated LSDB:
+// the DUT LSDB holds a single LSP for it, from the Attach node, and the
+// links between simulated nodes are not advertised. Traffic engineering
+// attributes can only be advertised for the adjacency, see Adjacency.TE.
+type SimTopology struct {
+	Nodes []*SimNode `json:"nodes"`
+	Links []*SimLink `json:"links"`
+	// Attach is the name of the node that forms the adjacency with the DUT.
+	Attach string `json:"attach"`
+}
+
+// SimOptions configure generated simulated topologies. Zero values select
+// the defaults documented on each field.
+type SimOptions struct {
+	// Metric is the metric of every link. Defaults to 10.
+	Metric uint32
+	// PrefixesPerNode is the number of IPv4 and IPv6 prefixes advertised by
+	// each node. Defaults to 1.
+	PrefixesPerNode int
+	// IPv4Pool is carved into the /24s advertised by the nodes. Defaults to
+	// 198.18.0.0/15.
+	IPv4Pool netip.Prefix
+	// IPv6Pool is carved into the /64s advertised by the nodes. Defaults to
+	// 2001:db8:1000::/48.
+	IPv6Pool netip.Prefix
+	// SysIDBase is added to the node index, starting at 1, to build system
+	// IDs. Defaults to 0x650000000000.
+	SysIDBase uint64
+}
+
+func (o *SimOptions) withDefaults() SimOptions {
+	var opts SimOptions
+	if o != nil {
+		opts = *o
+	}
+	if opts.Metric == 0 {
+		opts.Metric = defaultSimMetric
+	}
+	if opts.PrefixesPerNode == 0 {
+		opts.PrefixesPerNode = 1
+	}
+	if !opts.IPv4Pool.IsValid() {
+		opts.IPv4Pool = netip.MustParsePrefix("198.18.0.0/15")
+	}
+	if !opts.IPv6Pool.IsValid() {
+		opts.IPv6Pool = netip.MustParsePrefix("2001:db8:1000::/48")
+	}
+	if opts.SysIDBase == 0 {
+		opts.SysIDBase = 0x650000000000
+	}
+	return opts
+}
+
+// nthSubnet returns the nth subnet of length bits within pool.
+func nthSubnet(pool netip.Prefix, bits, n int) (netip.Prefix, error) {
+	if bits < pool.Bits() || bits > pool.Addr().BitLen() {
+		return netip.Prefix{}, fmt.Errorf("cannot carve /%d subnets from %v", bits, pool)
+	}
+	if n >= 1<<min(bits-pool.Bits(), 62) {
+		return netip.Prefix{}, fmt.Errorf("pool %v has no room for %d /%d subnets", pool, n+1, bits)
+	}
+	base := pool.Masked().Addr()
+	v := new(big.Int).SetBytes(base.AsSlice())
+	v.Add(v, new(big.Int).Lsh(big.NewInt(int64(n)), uint(base.BitLen()-bits)))
+	b := make([]byte, base.BitLen()/8)
+	addr, _ := netip.AddrFromSlice(v.FillBytes(b))
+	return netip.PrefixFrom(addr, bits), nil
+}
+
+// newNode adds the ith generated node to st.
+func (st *SimTopology) newNode(name string, i int, opts *SimOptions) error {
+	n := &SimNode{
+		Name:  name,
+		SysID: fmt.Sprintf("%012x", opts.SysIDBase+uint64(i)+1),
+	}
+	for j := 0; j < opts.PrefixesPerNode; j++ {
+		k := i*opts.PrefixesPerNode + j
+		p4, err := nthSubnet(opts.IPv4Pool, 24, k)
+		if err != nil {
+			return err
+		}
+		p6, err := nthSubnet(opts.IPv6Pool, 64, k)
+		if err != nil {
+			return err
+		}
+		n.Prefixes = append(n.Prefixes, p4, p6)
+	}
+	st.Nodes = append(st.Nodes, n)
+	return nil
+}
+
+func (st *SimTopology) newLink(a, b string, opts *SimOptions) {
+	st.Links = append(st.Links, &SimLink{A: a, B: b, Metric: opts.Metric})
+}
+
+// GridTopology returns a rows x cols grid of simulated nodes named rRcC, in
+// which each node links to its right and lower neighbors. The DUT attaches
+// to r0c0.
+func GridTopology(rows, cols int, opts *SimOptions) (*SimTopology, error) {
+	if rows < 1 || cols < 1 || rows*cols < 2 {
+		return nil, fmt.Errorf("grid must have at least two nodes, got %dx%d", rows, cols)
+	}
+	o := opts.withDefaults()
+	name := func(r, c int) string { return fmt.Sprintf("r%dc%d", r, c) }
+	st := &SimTopology{Attach: name(0, 0)}
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			if err := st.newNode(name(r, c), r*cols+c, &o); err != nil {
+				return nil, err
+			}
+			if c > 0 {
+				st.newLink(name(r, c-1), name(r, c), &o)
+			}
+			if r > 0 {
+				st.newLink(name(r-1, c), name(r, c), &o)
+			}
+		}
+	}
+	return st, nil
+}
+
+// RingTopology returns a ring of n simulated nodes named ringN. The DUT
+// attaches to ring0.
+func RingTopology(n int, opts *SimOptions) (*SimTopology, error) {
+	if n < 3 {
+		return nil, fmt.Errorf("ring must have at least three nodes, got %d", n)
+	}
+	o := opts.withDefaults()
+	name := func(i int) string { return fmt.Sprintf("ring%d", i) }
+	st := &SimTopology{Attach: name(0)}
+	for i := 0; i < n; i++ {
+		if err := st.newNode(name(i), i, &o); err != nil {
+			return nil, err
+		}
+		st.newLink(name(i), name((i+1)%n), &o)
+	}
+	return st, nil
+}
+
+// ImportTopology reads a simulated topology from its JSON encoding, for
+// example one captured from a production network. Links without a metric
+// get a metric of 10. Unknown fields, such as traffic engineering attributes
+// that cannot be advertised, are rejected.
+func ImportTopology(r io.Reader) (*SimTopology, error) {
+	st := &SimTopology{}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(st); err != nil {
+		return nil, fmt.Errorf("decoding topology: %w", err)
+	}
+	for _, l := range st.Links {
+		if l.Metric == 0 {
+			l.Metric = defaultSimMetric
+		}
+	}
+	if err := st.Validate(); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// Validate checks that node names and system IDs are unique and valid and
+// that links and Attach refer to existing nodes.
+func (st *SimTopology) Validate() error {
+	if len(st.Nodes) == 0 {
+		return fmt.Errorf("topology has no nodes")
+	}
+	names := map[string]bool{}
+	sysIDs := map[string]string{}
+	for _, n := range st.Nodes {
+		if n.Name == "" || names[n.Name] {
+			return fmt.Errorf("node name %q is empty or duplicated", n.Name)
+		}
+		names[n.Name] = true
+		if !sysIDRE.MatchString(n.SysID) {
+			return fmt.Errorf("node %s has invalid system ID %q, want 12 hex digits", n.Name, n.SysID)
+		}
+		if other, ok := sysIDs[n.SysID]; ok {
+			return fmt.Errorf("nodes %s and %s share system ID %s", other, n.Name, n.SysID)
+		}
+		sysIDs[n.SysID] = n.Name
+	}
+	for _, l := range st.Links {
+		if !names[l.A] || !names[l.B] || l.A == l.B {
+			return fmt.Errorf("link %s-%s must connect two different known nodes", l.A, l.B)
+		}
+		if l.Metric == 0 {
+			return fmt.Errorf("link %s-%s has no metric", l.A, l.B)
+		}
+	}
+	if !names[st.Attach] {
+		return fmt.Errorf("attach node %q is not in the topology", st.Attach)
+	}
+	return nil
+}
+
+// Node returns the node with the given name, or nil if there is none.
+func (st *SimTopology) Node(name string) *SimNode {
+	for _, n := range st.Nodes {
+		if n.Name == name {
+			return n
+		}
+	}
+	return nil
+}
+
+// Distances returns the shortest path metric from Attach to every reachable
+// node, keyed by node name.
+func (st *SimTopology) Distances() map[string]uint32 {
+	adj := map[string][]*SimLink{}
+	for _, l := range st.Links {
+		adj[l.A] = append(adj[l.A], l)
+		adj[l.B] = append(adj[l.B], l)
+	}
+	dist := map[string]uint32{st.Attach: 0}
+	done := map[string]bool{}
+	for {
+		cur, best := "", uint32(math.MaxUint32)
+		for _, n := range st.Nodes {
+			if d, ok := dist[n.Name]; ok && !done[n.Name] && d < best {
+				cur, best = n.Name, d
+			}
+		}
+		if cur == "" {
+			return dist
+		}
+		done[cur] = true
+		for _, l := range adj[cur] {
+			next := l.B
+			if next == cur {
+				next = l.A
+			}
+			if d, ok := dist[next]; !ok || best+l.Metric < d {
+				dist[next] = best + l.Metric
+			}
+		}
+	}
+}
+
+// Routes returns the metric from Attach of every prefix advertised by a
+// reachable node. A prefix advertised by several nodes gets the lowest
+// metric.
+func (st *SimTopology) Routes() map[netip.Prefix]uint32 {
+	dist := st.Distances()
+	routes := map[netip.Prefix]uint32{}
+	for _, n := range st.Nodes {
+		d, ok := dist[n.Name]
+		if !ok {
+			continue
+		}
+		for _, p := range n.Prefixes {
+			if cur, ok := routes[p]; !ok || d < cur {
+				routes[p] = d
+			}
+		}
+	}
+	return routes
+}
+
+// addToOTG advertises the topology from the OTG IS-IS router emulating the
+// Attach node.
+func (st *SimTopology) addToOTG(isis gosnappi.DeviceIsisRouter) {
+	dist := st.Distances()
+	for _, n := range st.Nodes {
+		d, ok := dist[n.Name]
+		if !ok {
+			continue
+		}
+		var v4 gosnappi.IsisV4RouteRange
+		var v6 gosnappi.IsisV6RouteRange
+		for _, p := range n.Prefixes {
+			if p.Addr().Is4() {
+				if v4 == nil {
+					v4 = isis.V4Routes().Add().SetName(isis.Name() + "." + n.Name + ".IPv4").SetLinkMetric(d)
+				}
+				v4.Addresses().Add().SetAddress(p.Addr().String()).SetPrefix(uint32(p.Bits()))
+				continue
+			}
+			if v6 == nil {
+				v6 = isis.V6Routes().Add().SetName(isis.Name() + "." + n.Name + ".IPv6").SetLinkMetric(d)
+			}
+			v6.Addresses().Add().SetAddress(p.Addr().String()).SetPrefix(uint32(p.Bits()))
+		}
+	}
+}
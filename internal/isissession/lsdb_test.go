@@ -0,0 +1,189 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package isissession
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/open-traffic-generator/snappi/gosnappi"
+)
+
+func TestGridTopology(t *testing.T) {
+	st, err := GridTopology(3, 4, nil)
+	if err != nil {
+		t.Fatalf("GridTopology() got unexpected error: %v", err)
+	}
+	if err := st.Validate(); err != nil {
+		t.Fatalf("Validate() got unexpected error: %v", err)
+	}
+	if got, want := len(st.Nodes), 12; got != want {
+		t.Errorf("GridTopology() got %d nodes, want %d", got, want)
+	}
+	// 3 rows of 3 horizontal links and 4 columns of 2 vertical links.
+	if got, want := len(st.Links), 17; got != want {
+		t.Errorf("GridTopology() got %d links, want %d", got, want)
+	}
+	n := st.Node("r2c3")
+	if n == nil {
+		t.Fatalf("GridTopology() has no node r2c3")
+	}
+	wantPrefixes := []netip.Prefix{netip.MustParsePrefix("198.18.11.0/24"), netip.MustParsePrefix("2001:db8:1000:b::/64")}
+	if diff := cmp.Diff(wantPrefixes, n.Prefixes, cmp.Comparer(func(a, b netip.Prefix) bool { return a == b })); diff != "" {
+		t.Errorf("node r2c3 got unexpected prefixes (-want +got):\n%s", diff)
+	}
+	if n.SysID != "65000000000c" {
+		t.Errorf("node r2c3 got system ID %s, want 65000000000c", n.SysID)
+	}
+	if got := st.Distances()["r2c3"]; got != 50 {
+		t.Errorf("Distances() got %d for r2c3, want 50", got)
+	}
+
+	if _, err := GridTopology(1, 1, nil); err == nil {
+		t.Errorf("GridTopology(1, 1) got no error, want error")
+	}
+	if _, err := GridTopology(20, 20, &SimOptions{IPv4Pool: netip.MustParsePrefix("198.18.0.0/24")}); err == nil {
+		t.Errorf("GridTopology() with a small pool got no error, want error")
+	}
+}
+
+func TestRingTopologyRoutes(t *testing.T) {
+	st, err := RingTopology(5, &SimOptions{Metric: 20})
+	if err != nil {
+		t.Fatalf("RingTopology() got unexpected error: %v", err)
+	}
+	st.Links[0].Metric = 100 // ring0-ring1 is now longer than going the other way.
+	want := map[netip.Prefix]uint32{
+		netip.MustParsePrefix("198.18.0.0/24"):        0,
+		netip.MustParsePrefix("2001:db8:1000::/64"):   0,
+		netip.MustParsePrefix("198.18.1.0/24"):        80,
+		netip.MustParsePrefix("2001:db8:1000:1::/64"): 80,
+		netip.MustParsePrefix("198.18.2.0/24"):        60,
+		netip.MustParsePrefix("2001:db8:1000:2::/64"): 60,
+		netip.MustParsePrefix("198.18.3.0/24"):        40,
+		netip.MustParsePrefix("2001:db8:1000:3::/64"): 40,
+		netip.MustParsePrefix("198.18.4.0/24"):        20,
+		netip.MustParsePrefix("2001:db8:1000:4::/64"): 20,
+	}
+	if diff := cmp.Diff(want, st.Routes()); diff != "" {
+		t.Errorf("Routes() got unexpected diff (-want +got):\n%s", diff)
+	}
+
+	if _, err := RingTopology(2, nil); err == nil {
+		t.Errorf("RingTopology(2) got no error, want error")
+	}
+}
+
+func TestImportTopology(t *testing.T) {
+	const topo = `{
+		"nodes": [
+			{"name": "a", "sys_id": "650000000001", "prefixes": ["198.51.100.0/25"]},
+			{"name": "b", "sys_id": "650000000002", "prefixes": ["198.51.100.128/25", "2001:db8:2::/64"]},
+			{"name": "c", "sys_id": "650000000003", "prefixes": ["203.0.113.0/24"]}
+		],
+		"links": [{"a": "a", "b": "b"}],
+		"attach": "a"
+	}`
+	st, err := ImportTopology(strings.NewReader(topo))
+	if err != nil {
+		t.Fatalf("ImportTopology() got unexpected error: %v", err)
+	}
+	if got := st.Links[0]; got.Metric != 10 {
+		t.Errorf("ImportTopology() got link %+v, want metric 10", got)
+	}
+
+	isis := gosnappi.NewConfig().Devices().Add().SetName("dev").Isis().SetName("dev.ISIS")
+	st.addToOTG(isis)
+	// Node c is not reachable and is not advertised.
+	var got []string
+	for _, rr := range isis.V4Routes().Items() {
+		got = append(got, rr.Name())
+	}
+	if want := []string{"dev.ISIS.a.IPv4", "dev.ISIS.b.IPv4"}; !cmp.Equal(got, want) {
+		t.Errorf("addToOTG() got IPv4 route ranges %v, want %v", got, want)
+	}
+	if v6 := isis.V6Routes().Items(); len(v6) != 1 || v6[0].LinkMetric() != 10 {
+		t.Errorf("addToOTG() got IPv6 route ranges %v, want one with metric 10", v6)
+	}
+
+	for _, bad := range []string{
+		`{"nodes": [{"name": "a", "sys_id": "6500.0000.0001"}], "attach": "a"}`,
+		`{"nodes": [{"name": "a", "sys_id": "650000000001"}, {"name": "b", "sys_id": "650000000001"}], "attach": "a"}`,
+		`{"nodes": [{"name": "a", "sys_id": "650000000001"}], "links": [{"a": "a", "b": "z"}], "attach": "a"}`,
+		`{"nodes": [{"name": "a", "sys_id": "650000000001"}], "attach": "z"}`,
+		`{"nodes": [{"name": "a", "sys_id": "650000000001"}, {"name": "b", "sys_id": "650000000002"}], "links": [{"a": "a", "b": "b", "te": {"metric": 5}}], "attach": "a"}`,
+		`{"nodes": [`,
+	} {
+		if _, err := ImportTopology(strings.NewReader(bad)); err == nil {
+			t.Errorf("ImportTopology(%s) got no error, want error", bad)
+		}
+	}
+}
+
+func TestTopologyValidate(t *testing.T) {
+	grid, err := GridTopology(2, 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := func() *Topology {
+		return &Topology{
+			Adjacencies: []*Adjacency{
+				{Port: "port1", Level: L1L2, LSDB: grid},
+				{Port: "port2", PeerDUT: "dut2", Level: L1},
+			},
+			TrafficPorts: []string{"port3"},
+		}
+	}
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("Validate() got unexpected error: %v", err)
+	}
+	tests := []struct {
+		desc string
+		mod  func(*Topology)
+	}{
+		{"no adjacencies", func(tp *Topology) { tp.Adjacencies = nil }},
+		{"duplicate port", func(tp *Topology) { tp.TrafficPorts = []string{"port2"} }},
+		{"bad level", func(tp *Topology) { tp.Adjacencies[0].Level = 7 }},
+		{"LSDB on DUT peer", func(tp *Topology) { tp.Adjacencies[1].LSDB = &SimTopology{} }},
+		{"shared LSDB", func(tp *Topology) { tp.Adjacencies = append(tp.Adjacencies, &Adjacency{Port: "port4", LSDB: grid}) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			tp := valid()
+			tt.mod(tp)
+			if err := tp.Validate(); err == nil {
+				t.Errorf("Validate() got no error, want error")
+			}
+		})
+	}
+}
+
+func TestLevelUnion(t *testing.T) {
+	tests := []struct {
+		a, b, want Level
+	}{
+		{-1, L1, L1},
+		{L2, L2, L2},
+		{L1, L2, L1L2},
+		{L1L2, L1, L1L2},
+	}
+	for _, tt := range tests {
+		if got := tt.a.union(tt.b); got != tt.want {
+			t.Errorf("Level(%d).union(%d) got %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,400 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package isissession
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openconfig/ondatra"
+	"github.com/openconfig/ondatra/gnmi/oc"
+	"github.com/openconfig/ygnmi/ygnmi"
+)
+
+// lsdbPollInterval is how often AwaitLSDB reads the LSDB. Tests shorten it.
+var lsdbPollInterval = 2 * time.Second
+
+// LSPKey identifies an LSP within an LSDB.
+type LSPKey struct {
+	Level uint8
+	// ID is the normalized LSP ID, e.g. 1920.0000.2001.00-00.
+	ID string
+}
+
+func (k LSPKey) String() string {
+	return fmt.Sprintf("L%d %s", k.Level, k.ID)
+}
+
+// LSP is the normalized content of one LSP. Sequence numbers, lifetimes and
+// checksums are kept for reference but are not compared by DiffLSDB.
+type LSP struct {
+	LSPKey
+	Hostname   string
+	Sequence   uint32
+	Overload   bool
+	Attached   bool
+	TERouterID string
+	// Neighbors maps the normalized IDs of IS neighbors to the sorted
+	// metrics of the links to them, one per parallel link.
+	Neighbors map[string][]uint32
+	// Prefixes maps the IPv4 and IPv6 prefixes reachable through the LSP to
+	// their metric.
+	Prefixes map[netip.Prefix]uint32
+}
+
+// SysID returns the system ID of the originator of the LSP.
+func (l *LSP) SysID() string {
+	id, _, _ := strings.Cut(l.ID, "-")
+	if len(id) > len("0000.0000.0000") {
+		id = id[:len("0000.0000.0000")]
+	}
+	return id
+}
+
+// LSDB is a normalized snapshot of an IS-IS link state database.
+type LSDB struct {
+	LSPs map[LSPKey]*LSP
+}
+
+// NewLSDB returns an empty LSDB.
+func NewLSDB() *LSDB {
+	return &LSDB{LSPs: make(map[LSPKey]*LSP)}
+}
+
+// normalizeID formats a system ID, optionally followed by a pseudonode ID
+// and an LSP fragment number, in dotted form, regardless of whether the
+// device reports it with or without dots.
+func normalizeID(id string) string {
+	id, frag, hasFrag := strings.Cut(strings.ToLower(id), "-")
+	hex := strings.ReplaceAll(id, ".", "")
+	if len(hex) != 12 && len(hex) != 14 {
+		return strings.ToLower(id)
+	}
+	parts := []string{hex[0:4], hex[4:8], hex[8:12]}
+	if len(hex) == 14 {
+		parts = append(parts, hex[12:14])
+	}
+	out := strings.Join(parts, ".")
+	if hasFrag {
+		out += "-" + frag
+	}
+	return out
+}
+
+// Add normalizes an LSP read from the level of a device and adds it to db,
+// replacing any LSP with the same key.
+func (db *LSDB) Add(level uint8, lsp *oc.NetworkInstance_Protocol_Isis_Level_Lsp) *LSP {
+	l := &LSP{
+		LSPKey:    LSPKey{Level: level, ID: normalizeID(lsp.GetLspId())},
+		Sequence:  lsp.GetSequenceNumber(),
+		Neighbors: make(map[string][]uint32),
+		Prefixes:  make(map[netip.Prefix]uint32),
+	}
+	for _, f := range lsp.Flags {
+		switch f {
+		case oc.Lsp_Flags_OVERLOAD:
+			l.Overload = true
+		case oc.Lsp_Flags_ATTACHED_DEFAULT, oc.Lsp_Flags_ATTACHED_DELAY, oc.Lsp_Flags_ATTACHED_ERROR, oc.Lsp_Flags_ATTACHED_EXPENSE:
+			l.Attached = true
+		}
+	}
+	addPrefix := func(s string, metric uint32) {
+		if p, err := netip.ParsePrefix(s); err == nil {
+			l.Prefixes[p.Masked()] = metric
+		}
+	}
+	for _, tlv := range lsp.Tlv {
+		if h := tlv.GetHostname().GetHostname(); len(h) > 0 {
+			l.Hostname = h[0]
+		}
+		if id := tlv.GetIpv4TeRouterId().GetRouterId(); len(id) > 0 {
+			l.TERouterID = id[0]
+		}
+		if r := tlv.GetExtendedIsReachability(); r != nil {
+			for _, n := range r.Neighbor {
+				id := normalizeID(n.GetSystemId())
+				for _, inst := range n.Instance {
+					l.Neighbors[id] = append(l.Neighbors[id], inst.GetMetric())
+				}
+				slices.Sort(l.Neighbors[id])
+			}
+		}
+		if r := tlv.GetExtendedIpv4Reachability(); r != nil {
+			for _, p := range r.Prefix {
+				addPrefix(p.GetPrefix(), p.GetMetric())
+			}
+		}
+		if r := tlv.GetIpv4InternalReachability(); r != nil {
+			for _, p := range r.Prefix {
+				addPrefix(p.GetPrefix(), uint32(p.GetDefaultMetric().GetMetric()))
+			}
+		}
+		if r := tlv.GetIpv6Reachability(); r != nil {
+			for _, p := range r.Prefix {
+				addPrefix(p.GetPrefix(), p.GetMetric())
+			}
+		}
+	}
+	db.LSPs[l.LSPKey] = l
+	return l
+}
+
+// Keys returns the keys of the LSPs of db, sorted by level and ID.
+func (db *LSDB) Keys() []LSPKey {
+	var keys []LSPKey
+	for k := range db.LSPs {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Level != keys[j].Level {
+			return keys[i].Level < keys[j].Level
+		}
+		return keys[i].ID < keys[j].ID
+	})
+	return keys
+}
+
+// Originated returns the LSPs of all fragments and pseudonodes originated by
+// sysID at level, sorted by ID.
+func (db *LSDB) Originated(level uint8, sysID string) []*LSP {
+	sysID = normalizeID(sysID)
+	var lsps []*LSP
+	for _, k := range db.Keys() {
+		if l := db.LSPs[k]; k.Level == level && l.SysID() == sysID {
+			lsps = append(lsps, l)
+		}
+	}
+	return lsps
+}
+
+// Advertising returns the LSPs at level that advertise p, sorted by ID.
+func (db *LSDB) Advertising(level uint8, p netip.Prefix) []*LSP {
+	var lsps []*LSP
+	for _, k := range db.Keys() {
+		if l := db.LSPs[k]; k.Level == level {
+			if _, ok := l.Prefixes[p.Masked()]; ok {
+				lsps = append(lsps, l)
+			}
+		}
+	}
+	return lsps
+}
+
+// LSPDiff lists the changes between two versions of an LSP.
+type LSPDiff struct {
+	Key     LSPKey
+	Changes []string
+}
+
+// LSDBDiff lists the LSPs that were added, removed or changed between two
+// snapshots.
+type LSDBDiff struct {
+	Added   []*LSP
+	Removed []*LSP
+	Changed []*LSPDiff
+}
+
+// Empty reports whether the snapshots were equivalent.
+func (d *LSDBDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+func (d *LSDBDiff) String() string {
+	var b strings.Builder
+	for _, l := range d.Added {
+		fmt.Fprintf(&b, "+ %v\n", l.LSPKey)
+	}
+	for _, l := range d.Removed {
+		fmt.Fprintf(&b, "- %v\n", l.LSPKey)
+	}
+	for _, c := range d.Changed {
+		fmt.Fprintf(&b, "~ %v\n", c.Key)
+		for _, ch := range c.Changes {
+			fmt.Fprintf(&b, "    %s\n", ch)
+		}
+	}
+	return b.String()
+}
+
+// DiffLSDB compares two snapshots of an LSDB.
+func DiffLSDB(before, after *LSDB) *LSDBDiff {
+	d := &LSDBDiff{}
+	for _, k := range before.Keys() {
+		if _, ok := after.LSPs[k]; !ok {
+			d.Removed = append(d.Removed, before.LSPs[k])
+		}
+	}
+	for _, k := range after.Keys() {
+		old, ok := before.LSPs[k]
+		if !ok {
+			d.Added = append(d.Added, after.LSPs[k])
+			continue
+		}
+		if changes := diffLSP(old, after.LSPs[k]); len(changes) > 0 {
+			d.Changed = append(d.Changed, &LSPDiff{Key: k, Changes: changes})
+		}
+	}
+	return d
+}
+
+// diffLSP describes the TLV changes between two versions of an LSP.
+func diffLSP(before, after *LSP) []string {
+	var changes []string
+	if before.Hostname != after.Hostname {
+		changes = append(changes, fmt.Sprintf("hostname %q -> %q", before.Hostname, after.Hostname))
+	}
+	if before.Overload != after.Overload {
+		changes = append(changes, fmt.Sprintf("overload %v -> %v", before.Overload, after.Overload))
+	}
+	if before.Attached != after.Attached {
+		changes = append(changes, fmt.Sprintf("attached %v -> %v", before.Attached, after.Attached))
+	}
+	if before.TERouterID != after.TERouterID {
+		changes = append(changes, fmt.Sprintf("TE router ID %q -> %q", before.TERouterID, after.TERouterID))
+	}
+	for _, id := range sortedKeys(before.Neighbors, after.Neighbors, strings.Compare) {
+		b, inB := before.Neighbors[id]
+		a, inA := after.Neighbors[id]
+		switch {
+		case !inA:
+			changes = append(changes, fmt.Sprintf("neighbor %s removed (metrics %v)", id, b))
+		case !inB:
+			changes = append(changes, fmt.Sprintf("neighbor %s added (metrics %v)", id, a))
+		case !slices.Equal(a, b):
+			changes = append(changes, fmt.Sprintf("neighbor %s metrics %v -> %v", id, b, a))
+		}
+	}
+	for _, p := range sortedKeys(before.Prefixes, after.Prefixes, comparePrefix) {
+		b, inB := before.Prefixes[p]
+		a, inA := after.Prefixes[p]
+		switch {
+		case !inA:
+			changes = append(changes, fmt.Sprintf("prefix %v removed (metric %d)", p, b))
+		case !inB:
+			changes = append(changes, fmt.Sprintf("prefix %v added (metric %d)", p, a))
+		case a != b:
+			changes = append(changes, fmt.Sprintf("prefix %v metric %d -> %d", p, b, a))
+		}
+	}
+	return changes
+}
+
+func comparePrefix(a, b netip.Prefix) int {
+	if c := a.Addr().Compare(b.Addr()); c != 0 {
+		return c
+	}
+	return a.Bits() - b.Bits()
+}
+
+// sortedKeys returns the union of the keys of two maps, sorted by cmp.
+func sortedKeys[K comparable, V any](a, b map[K]V, cmp func(K, K) int) []K {
+	seen := make(map[K]bool)
+	var keys []K
+	for _, m := range []map[K]V{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	slices.SortFunc(keys, cmp)
+	return keys
+}
+
+// GetLSDB reads the level 1 and level 2 link state databases of the IS-IS
+// instance of dut.
+func GetLSDB(ctx context.Context, client *ygnmi.Client, dut *ondatra.DUTDevice) (*LSDB, error) {
+	db := NewLSDB()
+	for _, level := range []uint8{1, 2} {
+		lsps, err := ygnmi.GetAll(ctx, client, ISISPath(dut).Level(level).LspAny().State())
+		if errors.Is(err, ygnmi.ErrNotPresent) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading level %d LSDB: %w", level, err)
+		}
+		for _, lsp := range lsps {
+			db.Add(level, lsp)
+		}
+	}
+	return db, nil
+}
+
+// LSDB reads the link state database of the session DUT.
+func (s *TestSession) LSDB(ctx context.Context) (*LSDB, error) {
+	return GetLSDB(ctx, s.DUTClient, s.DUT)
+}
+
+// MustLSDB reads the link state database of the session DUT or calls
+// t.Fatal if this fails.
+func (s *TestSession) MustLSDB(t testing.TB) *LSDB {
+	t.Helper()
+	db, err := s.LSDB(context.Background())
+	if err != nil {
+		t.Fatalf("Reading LSDB: %v", err)
+	}
+	return db
+}
+
+// AwaitLSDB reads the link state database of the session DUT until pred
+// returns true for it or timeout expires. It returns the last snapshot read
+// successfully, with an error if pred was never satisfied.
+func (s *TestSession) AwaitLSDB(timeout time.Duration, pred func(*LSDB) bool) (*LSDB, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return awaitLSDB(ctx, s.LSDB, pred)
+}
+
+// awaitLSDB calls read until pred returns true for the snapshot or ctx is
+// done. Snapshots that fail to read do not replace the last one read.
+func awaitLSDB(ctx context.Context, read func(context.Context) (*LSDB, error), pred func(*LSDB) bool) (*LSDB, error) {
+	var last *LSDB
+	for {
+		db, err := read(ctx)
+		if err == nil {
+			if pred(db) {
+				return db, nil
+			}
+			last = db
+		}
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return last, fmt.Errorf("LSDB did not reach the expected state: %w", err)
+			}
+			return last, fmt.Errorf("LSDB did not reach the expected state: %w", ctx.Err())
+		case <-time.After(lsdbPollInterval):
+		}
+	}
+}
+
+// MustAwaitLSDB calls AwaitLSDB, calling t.Fatal if pred is not satisfied
+// before timeout.
+func (s *TestSession) MustAwaitLSDB(t testing.TB, timeout time.Duration, pred func(*LSDB) bool) *LSDB {
+	t.Helper()
+	db, err := s.AwaitLSDB(timeout, pred)
+	if err != nil {
+		t.Fatalf("Waiting for LSDB: %v", err)
+	}
+	return db
+}
@@ -0,0 +1,142 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package isissession
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ondatra/gnmi/oc"
+	"github.com/openconfig/ygot/ygot"
+)
+
+// testLSP builds an OC LSP with a hostname, one neighbor and the given
+// IPv4 prefix metrics.
+func testLSP(t *testing.T, id, neighbor string, metric uint32, overload bool, prefixes map[string]uint32) *oc.NetworkInstance_Protocol_Isis_Level_Lsp {
+	t.Helper()
+	lsp := &oc.NetworkInstance_Protocol_Isis_Level_Lsp{LspId: ygot.String(id), SequenceNumber: ygot.Uint32(1)}
+	if overload {
+		lsp.Flags = []oc.E_Lsp_Flags{oc.Lsp_Flags_OVERLOAD}
+	}
+	lsp.GetOrCreateTlv(oc.IsisLsdbTypes_ISIS_TLV_TYPE_DYNAMIC_NAME).GetOrCreateHostname().Hostname = []string{"dut"}
+	lsp.GetOrCreateTlv(oc.IsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetOrCreateExtendedIsReachability().
+		GetOrCreateNeighbor(neighbor).GetOrCreateInstance(0).Metric = ygot.Uint32(metric)
+	v4 := lsp.GetOrCreateTlv(oc.IsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY).GetOrCreateExtendedIpv4Reachability()
+	for p, m := range prefixes {
+		v4.GetOrCreatePrefix(p).Metric = ygot.Uint32(m)
+	}
+	return lsp
+}
+
+func TestLSDBAdd(t *testing.T) {
+	db := NewLSDB()
+	lsp := testLSP(t, "192000002001.00-00", "6400.0000.0001.00", 10, true, map[string]uint32{"198.51.100.0/24": 20})
+	par := lsp.GetTlv(oc.IsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetExtendedIsReachability().GetNeighbor("6400.0000.0001.00")
+	par.GetOrCreateInstance(1).Metric = ygot.Uint32(5)
+	lsp.GetOrCreateTlv(oc.IsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY).GetOrCreateIpv6Reachability().
+		GetOrCreatePrefix("2001:db8:1::/64").Metric = ygot.Uint32(30)
+	got := db.Add(2, lsp)
+
+	want := &LSP{
+		LSPKey:    LSPKey{Level: 2, ID: "1920.0000.2001.00-00"},
+		Hostname:  "dut",
+		Sequence:  1,
+		Overload:  true,
+		Neighbors: map[string][]uint32{"6400.0000.0001.00": {5, 10}},
+		Prefixes: map[netip.Prefix]uint32{
+			netip.MustParsePrefix("198.51.100.0/24"): 20,
+			netip.MustParsePrefix("2001:db8:1::/64"): 30,
+		},
+	}
+	if diff := cmp.Diff(want, got, cmp.Comparer(func(a, b netip.Prefix) bool { return a == b })); diff != "" {
+		t.Errorf("Add() got unexpected LSP diff (-want +got):\n%s", diff)
+	}
+	if got := got.SysID(); got != "1920.0000.2001" {
+		t.Errorf("SysID() got %s, want 1920.0000.2001", got)
+	}
+	if got := db.Originated(2, "192000002001"); len(got) != 1 {
+		t.Errorf("Originated() got %d LSPs, want 1", len(got))
+	}
+	if got := db.Advertising(2, netip.MustParsePrefix("198.51.100.0/24")); len(got) != 1 {
+		t.Errorf("Advertising() got %d LSPs, want 1", len(got))
+	}
+	if got := db.Advertising(1, netip.MustParsePrefix("198.51.100.0/24")); len(got) != 0 {
+		t.Errorf("Advertising() at level 1 got %d LSPs, want 0", len(got))
+	}
+}
+
+func TestDiffLSDB(t *testing.T) {
+	before, after := NewLSDB(), NewLSDB()
+	before.Add(2, testLSP(t, "1920.0000.2001.00-00", "6400.0000.0001.00", 10, false, map[string]uint32{"198.51.100.0/24": 10, "203.0.113.0/24": 10}))
+	before.Add(2, testLSP(t, "6400.0000.0001.00-00", "1920.0000.2001.00", 10, false, nil))
+	after.Add(2, testLSP(t, "1920.0000.2001.00-00", "6400.0000.0001.00", 20, true, map[string]uint32{"198.51.100.0/24": 30, "192.0.2.0/30": 10}))
+	after.Add(2, testLSP(t, "6400.0000.0002.00-00", "1920.0000.2001.00", 10, false, nil))
+
+	d := DiffLSDB(before, after)
+	if d.Empty() {
+		t.Fatalf("DiffLSDB() got empty diff")
+	}
+	if len(d.Added) != 1 || d.Added[0].ID != "6400.0000.0002.00-00" {
+		t.Errorf("DiffLSDB() got added %v, want 6400.0000.0002.00-00", d.Added)
+	}
+	if len(d.Removed) != 1 || d.Removed[0].ID != "6400.0000.0001.00-00" {
+		t.Errorf("DiffLSDB() got removed %v, want 6400.0000.0001.00-00", d.Removed)
+	}
+	want := []*LSPDiff{{
+		Key: LSPKey{Level: 2, ID: "1920.0000.2001.00-00"},
+		Changes: []string{
+			"overload false -> true",
+			"neighbor 6400.0000.0001.00 metrics [10] -> [20]",
+			"prefix 192.0.2.0/30 added (metric 10)",
+			"prefix 198.51.100.0/24 metric 10 -> 30",
+			"prefix 203.0.113.0/24 removed (metric 10)",
+		},
+	}}
+	if diff := cmp.Diff(want, d.Changed); diff != "" {
+		t.Errorf("DiffLSDB() got unexpected changes (-want +got):\n%s", diff)
+	}
+
+	if d := DiffLSDB(before, before); !d.Empty() {
+		t.Errorf("DiffLSDB() of identical snapshots got diff:\n%s", d)
+	}
+}
+
+func TestAwaitLSDBKeepsLastSnapshot(t *testing.T) {
+	defer func(d time.Duration) { lsdbPollInterval = d }(lsdbPollInterval)
+	lsdbPollInterval = time.Millisecond
+
+	first := NewLSDB()
+	reads := 0
+	read := func(context.Context) (*LSDB, error) {
+		reads++
+		if reads == 1 {
+			return first, nil
+		}
+		return nil, errors.New("transient error")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	got, err := awaitLSDB(ctx, read, func(*LSDB) bool { return false })
+	if err == nil {
+		t.Errorf("awaitLSDB() got no error, want error")
+	}
+	if got != first {
+		t.Errorf("awaitLSDB() got snapshot %v, want the last snapshot read successfully", got)
+	}
+}
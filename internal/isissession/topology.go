@@ -0,0 +1,374 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package isissession
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/open-traffic-generator/snappi/gosnappi"
+	"github.com/openconfig/featureprofiles/internal/attrs"
+	"github.com/openconfig/ondatra"
+	"github.com/openconfig/ondatra/gnmi/oc"
+	"github.com/openconfig/ygnmi/ygnmi"
+)
+
+// Level selects the IS-IS levels an adjacency runs at.
+type Level int
+
+const (
+	// L2 runs the adjacency at level 2 only, like the two port session.
+	L2 Level = iota
+	// L1 runs the adjacency at level 1 only.
+	L1
+	// L1L2 runs the adjacency at both levels.
+	L1L2
+)
+
+func (l Level) levels() []uint8 {
+	switch l {
+	case L1:
+		return []uint8{1}
+	case L1L2:
+		return []uint8{1, 2}
+	}
+	return []uint8{2}
+}
+
+func (l Level) ocLevelType() oc.E_Isis_LevelType {
+	switch l {
+	case L1:
+		return oc.Isis_LevelType_LEVEL_1
+	case L1L2:
+		return oc.Isis_LevelType_LEVEL_1_2
+	}
+	return oc.Isis_LevelType_LEVEL_2
+}
+
+func (l Level) otgLevelType() gosnappi.IsisInterfaceLevelTypeEnum {
+	switch l {
+	case L1:
+		return gosnappi.IsisInterfaceLevelType.LEVEL_1
+	case L1L2:
+		return gosnappi.IsisInterfaceLevelType.LEVEL_1_2
+	}
+	return gosnappi.IsisInterfaceLevelType.LEVEL_2
+}
+
+// union returns the level capability needed to run both l and o. A negative
+// l stands for no level yet.
+func (l Level) union(o Level) Level {
+	if l < 0 || l == o {
+		return o
+	}
+	return L1L2
+}
+
+// Adjacency describes one IS-IS adjacency of a topology session.
+type Adjacency struct {
+	// Port is the ID of the DUT port the adjacency forms over.
+	Port string
+	// PeerDUT is the ID of a second DUT to form the adjacency with. The
+	// adjacency is formed with the ATE when empty.
+	PeerDUT string
+	// PeerPort is the ID of the port on the ATE or peer DUT. Defaults to
+	// Port.
+	PeerPort string
+	// Level is the level of the adjacency, L2 by default.
+	Level Level
+	// Metric is the interface metric at both ends. The DUT keeps its
+	// default metric and the ATE uses 10 when zero.
+	Metric uint32
+	// LSDB is a simulated topology whose routes are advertised by the ATE
+	// behind the adjacency; the ATE router then takes the system ID of its
+	// Attach node. The DUT does not learn the simulated nodes and links as
+	// separate LSPs, see SimTopology. Only valid for ATE adjacencies.
+	LSDB *SimTopology
+	// TE holds traffic engineering attributes advertised by the ATE for the
+	// adjacency link. Only valid for ATE adjacencies.
+	TE *TEAttributes
+}
+
+func (a *Adjacency) peerPort() string {
+	if a.PeerPort != "" {
+		return a.PeerPort
+	}
+	return a.Port
+}
+
+// Topology describes the adjacencies and traffic ports of a topology session.
+type Topology struct {
+	Adjacencies []*Adjacency
+	// TrafficPorts are IDs of DUT ports connected to the same ATE port IDs
+	// without IS-IS, used to source and sink traffic.
+	TrafficPorts []string
+}
+
+// Validate checks that every DUT port is used once and that the simulated
+// topologies are valid and only attached to ATE adjacencies.
+func (tp *Topology) Validate() error {
+	if len(tp.Adjacencies) == 0 {
+		return fmt.Errorf("topology has no adjacencies")
+	}
+	ports := map[string]bool{}
+	usePort := func(port string) error {
+		if port == "" || ports[port] {
+			return fmt.Errorf("DUT port %q is empty or used more than once", port)
+		}
+		ports[port] = true
+		return nil
+	}
+	sysIDs := map[string]bool{}
+	for _, a := range tp.Adjacencies {
+		if err := usePort(a.Port); err != nil {
+			return err
+		}
+		if a.Level < L2 || a.Level > L1L2 {
+			return fmt.Errorf("adjacency on %s has invalid level %d", a.Port, a.Level)
+		}
+		if a.PeerDUT != "" && (a.LSDB != nil || a.TE != nil) {
+			return fmt.Errorf("adjacency on %s: simulated topologies and TE attributes need an ATE peer", a.Port)
+		}
+		if a.LSDB == nil {
+			continue
+		}
+		if err := a.LSDB.Validate(); err != nil {
+			return fmt.Errorf("adjacency on %s: %w", a.Port, err)
+		}
+		for _, n := range a.LSDB.Nodes {
+			if sysIDs[n.SysID] {
+				return fmt.Errorf("adjacency on %s: system ID %s is used by another simulated topology", a.Port, n.SysID)
+			}
+			sysIDs[n.SysID] = true
+		}
+	}
+	for _, p := range tp.TrafficPorts {
+		if err := usePort(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Link is a DUT port of a topology session with its addressing.
+type Link struct {
+	// Adjacency is the adjacency formed over the link, nil for traffic ports.
+	Adjacency *Adjacency
+	DUTPort   *ondatra.Port
+	DUTAttrs  *attrs.Attributes
+	// PeerPort and PeerAttrs describe the ATE or peer DUT end of the link.
+	// PeerPort is nil for ATE links when there is no ATE.
+	PeerPort  *ondatra.Port
+	PeerAttrs *attrs.Attributes
+	// ATEDevice is the OTG device of ATE links when there is an ATE.
+	ATEDevice gosnappi.Device
+	// ATEISIS is the OTG IS-IS router of ATE adjacencies, set by WithISIS.
+	ATEISIS gosnappi.DeviceIsisRouter
+	// SysID is the system ID of the peer of an adjacency.
+	SysID string
+}
+
+// PeerDUT is a second DUT that forms IS-IS adjacencies with the session DUT.
+type PeerDUT struct {
+	DUT    *ondatra.DUTDevice
+	Client *ygnmi.Client
+	// Conf is pushed by PushDUT, like TestSession.DUTConf.
+	Conf  *oc.Root
+	SysID string
+	level Level
+	ports []*ondatra.Port
+}
+
+// NewTopology creates a TestSession with the adjacencies and traffic ports
+// of tp. Addresses are allocated in order from attrs.NewAllocator defaults,
+// and the interfaces of the DUT, the peer DUTs and the ATE are configured.
+// IS-IS is configured by WithISIS.
+func NewTopology(t testing.TB, tp *Topology) (*TestSession, error) {
+	t.Helper()
+	if err := tp.Validate(); err != nil {
+		return nil, err
+	}
+	s := &TestSession{PeerDUTs: make(map[string]*PeerDUT)}
+	s.DUT = ondatra.DUT(t, "dut")
+	var err error
+	s.DUTClient, err = ygnmi.NewClient(s.DUT.RawAPIs().GNMI(t), ygnmi.WithTarget(s.DUT.ID()))
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to gNMI on %v: %w", s.DUT, err)
+	}
+	s.DUTConf = &oc.Root{}
+	if ate, ok := ondatra.ATEs(t)["ate"]; ok {
+		s.ATE = ate
+		s.ATETop = gosnappi.NewConfig()
+	}
+	alloc, err := attrs.NewAllocator(nil)
+	if err != nil {
+		return nil, err
+	}
+	ateSysIDs := 0
+	for _, a := range tp.Adjacencies {
+		l, err := s.addLink(t, alloc, a.Port, a)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case a.PeerDUT != "":
+			p, err := s.peerDUT(t, a.PeerDUT)
+			if err != nil {
+				return nil, err
+			}
+			p.level = p.level.union(a.Level)
+			l.PeerPort = p.DUT.Port(t, a.peerPort())
+			l.PeerAttrs.Name, l.PeerAttrs.MAC = "", ""
+			l.PeerAttrs.Desc = fmt.Sprintf("%s to %s with IS-IS", a.PeerDUT, s.DUT.ID())
+			l.PeerAttrs.ConfigOCInterface(p.Conf.GetOrCreateInterface(l.PeerPort.Name()), p.DUT)
+			p.ports = append(p.ports, l.PeerPort)
+			l.SysID = p.SysID
+		case a.LSDB != nil:
+			l.SysID = a.LSDB.Node(a.LSDB.Attach).SysID
+		default:
+			ateSysIDs++
+			l.SysID = fmt.Sprintf("64%010x", ateSysIDs)
+		}
+		if s.DUTPort1 == nil {
+			s.DUTPort1, s.ATEPort1, s.ATEIntf1 = l.DUTPort, l.PeerPort, l.ATEDevice
+		}
+	}
+	for _, port := range tp.TrafficPorts {
+		l, err := s.addLink(t, alloc, port, nil)
+		if err != nil {
+			return nil, err
+		}
+		if s.DUTPort2 == nil {
+			s.DUTPort2, s.ATEPort2, s.ATEIntf2 = l.DUTPort, l.PeerPort, l.ATEDevice
+		}
+	}
+	return s, nil
+}
+
+// MustNewTopology creates a new topology TestSession or Fatal()s if anything
+// goes wrong.
+func MustNewTopology(t testing.TB, tp *Topology) *TestSession {
+	t.Helper()
+	v, err := NewTopology(t, tp)
+	if err != nil {
+		t.Fatalf("Unable to initialize topology: %v", err)
+	}
+	return v
+}
+
+// addLink allocates addresses for a DUT port, configures its DUT interface
+// and, for ATE links, its OTG device.
+func (s *TestSession) addLink(t testing.TB, alloc *attrs.Allocator, port string, a *Adjacency) (*Link, error) {
+	t.Helper()
+	dutAttrs, peerAttrs, err := alloc.Link(port)
+	if err != nil {
+		return nil, fmt.Errorf("allocating addresses for %s: %w", port, err)
+	}
+	l := &Link{
+		Adjacency: a,
+		DUTPort:   s.DUT.Port(t, port),
+		DUTAttrs:  dutAttrs,
+		PeerAttrs: peerAttrs,
+	}
+	dutAttrs.ConfigOCInterface(s.DUTConf.GetOrCreateInterface(l.DUTPort.Name()), s.DUT)
+	if s.ATE != nil && (a == nil || a.PeerDUT == "") {
+		atePort := port
+		if a != nil {
+			atePort = a.peerPort()
+		}
+		l.PeerPort = s.ATE.Port(t, atePort)
+		l.ATEDevice = peerAttrs.AddToOTG(s.ATETop, l.PeerPort, dutAttrs)
+	}
+	s.Links = append(s.Links, l)
+	return l, nil
+}
+
+// peerDUT returns the peer DUT with the given ID, connecting to it on first
+// use.
+func (s *TestSession) peerDUT(t testing.TB, id string) (*PeerDUT, error) {
+	t.Helper()
+	if p, ok := s.PeerDUTs[id]; ok {
+		return p, nil
+	}
+	dut := ondatra.DUT(t, id)
+	client, err := ygnmi.NewClient(dut.RawAPIs().GNMI(t), ygnmi.WithTarget(dut.ID()))
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to gNMI on %v: %w", dut, err)
+	}
+	p := &PeerDUT{
+		DUT:    dut,
+		Client: client,
+		Conf:   &oc.Root{},
+		SysID:  fmt.Sprintf("1920.0000.%04x", 0x2001+len(s.PeerDUTs)+1),
+		level:  -1,
+	}
+	s.PeerDUTs[id] = p
+	return p, nil
+}
+
+func (s *TestSession) peerDUTIDs() []string {
+	var ids []string
+	for id := range s.PeerDUTs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// addTopologyISIS configures IS-IS for every adjacency of a topology
+// session.
+func (s *TestSession) addTopologyISIS() {
+	level := Level(-1)
+	for _, l := range s.Links {
+		if l.Adjacency != nil {
+			level = level.union(l.Adjacency.Level)
+		}
+	}
+	isis := configISISGlobal(s.DUTConf, DUTAreaAddress, DUTSysID, level, s.DUT)
+	for _, l := range s.Links {
+		a := l.Adjacency
+		if a == nil {
+			continue
+		}
+		configISISInterface(isis, isisInterfaceName(s.DUT, l.DUTPort), a.Level, a.Metric, s.DUT)
+		if a.PeerDUT != "" {
+			p := s.PeerDUTs[a.PeerDUT]
+			pisis := configISISGlobal(p.Conf, DUTAreaAddress, p.SysID, p.level, p.DUT)
+			configISISInterface(pisis, isisInterfaceName(p.DUT, l.PeerPort), a.Level, a.Metric, p.DUT)
+			continue
+		}
+		if l.ATEDevice == nil {
+			continue
+		}
+		// Level 1 adjacencies need a common area.
+		area := ATEAreaAddress
+		if a.Level != L2 {
+			area = DUTAreaAddress
+		}
+		metric := a.Metric
+		if metric == 0 {
+			metric = 10
+		}
+		var intf gosnappi.IsisInterface
+		l.ATEISIS, intf = addISISRouter(l.ATEDevice, l.ATEDevice.Name()+".ISIS", area, l.SysID, a.Level, metric)
+		if a.TE != nil {
+			a.TE.addToOTG(intf)
+		}
+		if a.LSDB != nil {
+			a.LSDB.addToOTG(l.ATEISIS)
+		}
+	}
+}
@@ -47,8 +47,9 @@ func GetFlowStats(t testing.TB, otg *otg.OTG, flowName string, timeout time.Dura
 }
 
 // GetFlowLossPct checks to see if all the flows are completely stopped and
-// returns the loss percentage for the given flow
+// returns the loss percentage for the given flow. A flow that did not
+// transmit any packet is reported as 100% loss.
 func GetFlowLossPct(t testing.TB, otg *otg.OTG, flowName string, timeout time.Duration) (lossPct float64) {
 	tx, rx := GetFlowStats(t, otg, flowName, timeout)
-	return (float64(tx) - float64(rx)) * 100 / float64(tx)
+	return (&FlowCounters{TxPkts: tx, RxPkts: rx}).LossPct()
 }
@@ -0,0 +1,271 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otgutils
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-traffic-generator/snappi/gosnappi"
+	"github.com/openconfig/featureprofiles/internal/args"
+	"github.com/openconfig/ondatra/otg"
+)
+
+// TimestampSource provides the first and last receive timestamps of a flow,
+// for ATEs that support timestamp metrics. Flows must be configured with
+// Metrics().SetEnable(true).SetTimestamps(true).
+type TimestampSource interface {
+	// FlowTimestamps returns the first and last receive timestamps of the
+	// flow in nanoseconds. ok is false if the ATE did not report them.
+	FlowTimestamps(flow string) (first, last float64, ok bool, err error)
+}
+
+// SnappiTimestamps is a TimestampSource that queries flow metrics through a
+// gosnappi API client connected to the ATE.
+type SnappiTimestamps struct {
+	API gosnappi.Api
+}
+
+// FlowTimestamps implements TimestampSource.
+func (s *SnappiTimestamps) FlowTimestamps(flow string) (float64, float64, bool, error) {
+	req := gosnappi.NewMetricsRequest()
+	req.Flow().SetFlowNames([]string{flow})
+	resp, err := s.API.GetMetrics(req)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	for _, m := range resp.FlowMetrics().Items() {
+		if m.Name() != flow || !m.HasTimestamps() {
+			continue
+		}
+		ts := m.Timestamps()
+		if !ts.HasFirstTimestampNs() || !ts.HasLastTimestampNs() {
+			continue
+		}
+		return ts.FirstTimestampNs(), ts.LastTimestampNs(), true, nil
+	}
+	return 0, 0, false, nil
+}
+
+// ConvergenceOptions control MeasureConvergence. Zero values select the
+// defaults documented on each field.
+type ConvergenceOptions struct {
+	// Flows to measure. Defaults to all flows of the configuration.
+	Flows []string
+	// Threshold is the maximum tolerated outage per flow. Defaults to the
+	// arg_convergence_path_change flag.
+	Threshold time.Duration
+	// PreEvent is how long traffic runs before the event. Defaults to 10s.
+	PreEvent time.Duration
+	// PostEvent is how long traffic runs after the event returns. Defaults
+	// to 30s.
+	PostEvent time.Duration
+	// Timeout bounds the wait for flows to stop and counters to settle.
+	// Defaults to one minute.
+	Timeout time.Duration
+	// Timestamps, if set, is used to compute outages from receive
+	// timestamps. The outage of a flow is the larger of that and the outage
+	// from packet loss, which alone is used for flows without timestamps.
+	Timestamps TimestampSource
+}
+
+// FlowConvergence is the convergence measured for a single flow.
+type FlowConvergence struct {
+	Flow    string
+	TxPkts  uint64
+	RxPkts  uint64
+	RatePPS float64
+	// Outage is the estimated time during which the flow was not received.
+	Outage time.Duration
+	// Method is "timestamps" or "loss", depending on which estimate gave
+	// the outage.
+	Method string
+	Passed bool
+}
+
+// ConvergenceResult holds the result of MeasureConvergence.
+type ConvergenceResult struct {
+	Threshold time.Duration
+	// EventDuration is how long the event function took.
+	EventDuration time.Duration
+	Flows         []*FlowConvergence
+}
+
+// Passed reports whether every flow converged within the threshold.
+func (r *ConvergenceResult) Passed() bool {
+	for _, f := range r.Flows {
+		if !f.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// String renders the result as a table.
+func (r *ConvergenceResult) String() string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "Convergence threshold %v, event took %v\n", r.Threshold, r.EventDuration)
+	fmt.Fprintln(&out, strings.Repeat("-", 110))
+	fmt.Fprintf(&out, "%-25s%-15s%-15s%-15s%-15s%-15s%-10s\n", "Flow", "Frames Tx", "Frames Rx", "Rate (pps)", "Outage", "Method", "Result")
+	for _, f := range r.Flows {
+		result := "PASS"
+		if !f.Passed {
+			result = "FAIL"
+		}
+		fmt.Fprintf(&out, "%-25s%-15d%-15d%-15.0f%-15v%-15s%-10s\n", f.Flow, f.TxPkts, f.RxPkts, f.RatePPS, f.Outage, f.Method, result)
+	}
+	fmt.Fprintln(&out, strings.Repeat("-", 110))
+	return out.String()
+}
+
+// OutageFromLoss returns the outage implied by losing tx-rx packets of a
+// flow sent at a constant rate in packets per second.
+func OutageFromLoss(tx, rx uint64, pps float64) time.Duration {
+	if pps <= 0 || rx >= tx {
+		return 0
+	}
+	return time.Duration(float64(tx-rx) / pps * float64(time.Second))
+}
+
+// OutageFromTimestamps returns the outage of a flow sent at a constant rate,
+// given the first and last receive timestamps in nanoseconds and the number
+// of packets received. It is the part of the receive window that is not
+// accounted for by received packets.
+func OutageFromTimestamps(first, last float64, rx uint64, pps float64) time.Duration {
+	if pps <= 0 || rx < 2 || last <= first {
+		return 0
+	}
+	expected := float64(rx-1) / pps * float64(time.Second)
+	if gap := (last - first) - expected; gap > 0 {
+		return time.Duration(gap)
+	}
+	return 0
+}
+
+// flowOutage returns the outage of a flow and the method it was computed
+// with. Receive timestamps only see gaps between the first and last received
+// packet, so a flow that starts late or never recovers has no outage by
+// timestamps; the larger of both estimates is used.
+func flowOutage(tx, rx uint64, pps float64, first, last float64, timestamps bool) (time.Duration, string) {
+	outage := OutageFromLoss(tx, rx, pps)
+	if !timestamps {
+		return outage, "loss"
+	}
+	if ts := OutageFromTimestamps(first, last, rx, pps); ts >= outage {
+		return ts, "timestamps"
+	}
+	return outage, "loss"
+}
+
+// flowRate returns the configured rate of the flow in packets per second, or
+// 0 if the rate is not configured in packets per second.
+func flowRate(f gosnappi.Flow) float64 {
+	if f.Rate().Choice() == gosnappi.FlowRateChoice.PPS {
+		return float64(f.Rate().Pps())
+	}
+	return 0
+}
+
+// MeasureConvergence runs the flows of the configuration at their constant
+// rate, calls event once PreEvent has elapsed, keeps traffic running for
+// PostEvent and reports the outage of each flow against the threshold. The
+// configuration must already be pushed and protocols started.
+//
+// Usage:
+//
+//	res := otgutils.MeasureConvergence(t, ate.OTG(), top, func(t testing.TB) {
+//	  gnmi.Replace(t, dut, gnmi.OC().Interface(p1).Enabled().Config(), false)
+//	}, nil)
+//	if !res.Passed() {
+//	  t.Errorf("Convergence failed:\n%s", res)
+//	}
+func MeasureConvergence(t testing.TB, otg *otg.OTG, c gosnappi.Config, event func(t testing.TB), opts *ConvergenceOptions) *ConvergenceResult {
+	t.Helper()
+	o := ConvergenceOptions{
+		Threshold: time.Duration(*args.ConvergencePathChange) * time.Millisecond,
+		PreEvent:  10 * time.Second,
+		PostEvent: 30 * time.Second,
+		Timeout:   time.Minute,
+	}
+	if opts != nil {
+		o.Flows = opts.Flows
+		o.Timestamps = opts.Timestamps
+		if opts.Threshold > 0 {
+			o.Threshold = opts.Threshold
+		}
+		if opts.PreEvent > 0 {
+			o.PreEvent = opts.PreEvent
+		}
+		if opts.PostEvent > 0 {
+			o.PostEvent = opts.PostEvent
+		}
+		if opts.Timeout > 0 {
+			o.Timeout = opts.Timeout
+		}
+	}
+	flows := map[string]gosnappi.Flow{}
+	for _, f := range c.Flows().Items() {
+		flows[f.Name()] = f
+	}
+	if len(o.Flows) == 0 {
+		for _, f := range c.Flows().Items() {
+			o.Flows = append(o.Flows, f.Name())
+		}
+	}
+
+	res := &ConvergenceResult{Threshold: o.Threshold}
+	t.Logf("Starting traffic for convergence measurement of %d flows", len(o.Flows))
+	start := time.Now()
+	otg.StartTraffic(t)
+	time.Sleep(o.PreEvent)
+	eventStart := time.Now()
+	event(t)
+	res.EventDuration = time.Since(eventStart)
+	t.Logf("Convergence event completed in %v", res.EventDuration)
+	time.Sleep(o.PostEvent)
+	otg.StopTraffic(t)
+	sent := time.Since(start)
+
+	stats := CollectTrafficStats(t, otg, c, o.Timeout)
+	for _, name := range o.Flows {
+		f, ok := flows[name]
+		fs, sok := stats.Flows[name]
+		if !ok || !sok {
+			t.Errorf("Flow %s is not part of the OTG configuration", name)
+			continue
+		}
+		fc := &FlowConvergence{Flow: name, TxPkts: fs.TxPkts, RxPkts: fs.RxPkts, RatePPS: flowRate(f)}
+		if fc.RatePPS == 0 && sent > 0 {
+			// The rate is not configured in pps, so derive it from what was sent.
+			fc.RatePPS = float64(fs.TxPkts) / sent.Seconds()
+		}
+		var first, last float64
+		var timestamps bool
+		if o.Timestamps != nil {
+			var err error
+			if first, last, timestamps, err = o.Timestamps.FlowTimestamps(name); err != nil {
+				t.Logf("Could not get timestamps for flow %s, using packet loss: %v", name, err)
+				timestamps = false
+			}
+		}
+		fc.Outage, fc.Method = flowOutage(fs.TxPkts, fs.RxPkts, fc.RatePPS, first, last, timestamps)
+		fc.Passed = fs.TxPkts > 0 && fs.RxPkts > 0 && fc.Outage <= o.Threshold
+		res.Flows = append(res.Flows, fc)
+	}
+	t.Logf("Convergence result:\n%s", res)
+	return res
+}
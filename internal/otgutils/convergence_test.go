@@ -0,0 +1,83 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otgutils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOutageFromLoss(t *testing.T) {
+	tests := []struct {
+		desc   string
+		tx, rx uint64
+		pps    float64
+		want   time.Duration
+	}{
+		{"no loss", 1000, 1000, 1000, 0},
+		{"quarter second", 10000, 9750, 1000, 250 * time.Millisecond},
+		{"unknown rate", 1000, 0, 0, 0},
+		{"more received than sent", 1000, 1001, 1000, 0},
+	}
+	for _, tt := range tests {
+		if got := OutageFromLoss(tt.tx, tt.rx, tt.pps); got != tt.want {
+			t.Errorf("%s: OutageFromLoss(%d, %d, %v) got %v, want %v", tt.desc, tt.tx, tt.rx, tt.pps, got, tt.want)
+		}
+	}
+}
+
+func TestOutageFromTimestamps(t *testing.T) {
+	sec := float64(time.Second)
+	tests := []struct {
+		desc        string
+		first, last float64
+		rx          uint64
+		pps         float64
+		want        time.Duration
+	}{
+		{"no gap", 0, 9.999 * sec, 10000, 1000, 0},
+		{"half second gap", 0, 10.499 * sec, 10000, 1000, 500 * time.Millisecond},
+		{"single packet", 0, 0, 1, 1000, 0},
+	}
+	for _, tt := range tests {
+		got := OutageFromTimestamps(tt.first, tt.last, tt.rx, tt.pps)
+		if diff := got - tt.want; diff > time.Microsecond || diff < -time.Microsecond {
+			t.Errorf("%s: OutageFromTimestamps() got %v, want %v", tt.desc, got, tt.want)
+		}
+	}
+}
+
+func TestFlowOutage(t *testing.T) {
+	sec := float64(time.Second)
+	tests := []struct {
+		desc        string
+		tx, rx      uint64
+		first, last float64
+		timestamps  bool
+		want        time.Duration
+		wantMethod  string
+	}{
+		{"no timestamps", 10000, 9500, 0, 0, false, 500 * time.Millisecond, "loss"},
+		{"gap in the receive window", 10000, 9500, 0, 10.499 * sec, true, 1000 * time.Millisecond, "timestamps"},
+		{"tail loss", 10000, 5000, 0, 4.999 * sec, true, 5 * time.Second, "loss"},
+		{"late start", 10000, 7000, 3 * sec, 9.999 * sec, true, 3 * time.Second, "loss"},
+	}
+	for _, tt := range tests {
+		got, method := flowOutage(tt.tx, tt.rx, 1000, tt.first, tt.last, tt.timestamps)
+		if diff := got - tt.want; diff > time.Microsecond || diff < -time.Microsecond || method != tt.wantMethod {
+			t.Errorf("%s: flowOutage() got (%v, %s), want (%v, %s)", tt.desc, got, method, tt.want, tt.wantMethod)
+		}
+	}
+}
@@ -0,0 +1,388 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otgutils
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-traffic-generator/snappi/gosnappi"
+	"github.com/openconfig/featureprofiles/internal/fptest"
+	"github.com/openconfig/ondatra"
+	"github.com/openconfig/ondatra/gnmi"
+	"github.com/openconfig/ondatra/otg"
+	"github.com/openconfig/ygnmi/ygnmi"
+
+	otgtelemetry "github.com/openconfig/ondatra/gnmi/otg"
+)
+
+// FlowExpectation describes the expected outcome of a single flow. The loss
+// of the flow is checked against MaxLossPct when it is set or when
+// MinLossPct is not, so a flow with no loss bounds must not lose anything;
+// the other checks are only evaluated when their fields are set.
+type FlowExpectation struct {
+	Flow string
+	// MaxLossPct is the maximum tolerated loss in percent, 0 when unset. A
+	// flow that did not transmit anything is reported as 100% loss.
+	MaxLossPct float64
+	// MinLossPct is the minimum expected loss in percent, e.g. 100 for a flow
+	// that must be dropped.
+	MinLossPct float64
+	// RxPorts is the set of OTG ports that may receive the flow. Packets of
+	// the flow received on any other port fail the check.
+	RxPorts []string
+	// Distribution maps OTG port names to their expected relative weight of
+	// the received packets of the flow, e.g. for ECMP or WCMP checks.
+	Distribution map[string]float64
+	// PortTag is the name of the metric tag of the flow whose value tells
+	// the port receiving each packet, e.g. the destination MAC address the
+	// DUT writes on each egress interface, and PortTagValues maps its values
+	// to OTG port names. RxPorts and Distribution are evaluated on these
+	// per-flow counters, so that control plane frames and other flows
+	// received on the same ports do not count. Both checks fail when PortTag
+	// is not set.
+	PortTag       string
+	PortTagValues map[string]string
+	// Tags maps an egress tracking or metric tag name to the expected share
+	// of received packets per tag value, e.g. {"dscp": {"0x2e": 1}}. Values
+	// are normalized like Distribution weights.
+	Tags map[string]map[string]float64
+	// Tolerance is the allowed deviation in percentage points for
+	// Distribution and Tags checks. Defaults to 5.
+	Tolerance float64
+}
+
+// FlowCounters holds the counters of a flow.
+type FlowCounters struct {
+	TxPkts uint64
+	RxPkts uint64
+	// Tags maps tag name to tag value to received packets.
+	Tags map[string]map[string]uint64
+}
+
+// LossPct returns the loss of the flow in percent, or 100 if nothing was
+// transmitted.
+func (c *FlowCounters) LossPct() float64 {
+	if c.TxPkts == 0 {
+		return 100
+	}
+	if c.RxPkts >= c.TxPkts {
+		return 0
+	}
+	return float64(c.TxPkts-c.RxPkts) * 100 / float64(c.TxPkts)
+}
+
+// rxPorts returns the received packets of the flow per OTG port, using the
+// port tag of e. Packets with tag values missing from e.PortTagValues are
+// counted under their tag value.
+func (c *FlowCounters) rxPorts(e *FlowExpectation) map[string]uint64 {
+	rx := map[string]uint64{}
+	for value, n := range c.Tags[e.PortTag] {
+		port, ok := e.PortTagValues[value]
+		if !ok {
+			port = e.PortTag + "=" + value
+		}
+		rx[port] += n
+	}
+	return rx
+}
+
+// TrafficStats holds the OTG counters used to evaluate expectations.
+type TrafficStats struct {
+	Flows map[string]*FlowCounters
+}
+
+// CheckResult is a single row of a Verdict.
+type CheckResult struct {
+	Flow   string
+	Check  string
+	Want   string
+	Got    string
+	Passed bool
+}
+
+// Verdict is the result of evaluating a set of flow expectations.
+type Verdict struct {
+	Results []*CheckResult
+}
+
+// Passed reports whether all checks passed.
+func (v *Verdict) Passed() bool {
+	for _, r := range v.Results {
+		if !r.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Failures returns the checks that did not pass.
+func (v *Verdict) Failures() []*CheckResult {
+	var failed []*CheckResult
+	for _, r := range v.Results {
+		if !r.Passed {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+func (v *Verdict) add(flow, check, want, got string, passed bool) {
+	v.Results = append(v.Results, &CheckResult{Flow: flow, Check: check, Want: want, Got: got, Passed: passed})
+}
+
+// String renders the verdict as a table.
+func (v *Verdict) String() string {
+	var out strings.Builder
+	fmt.Fprintln(&out, strings.Repeat("-", 120))
+	fmt.Fprintf(&out, "%-25s%-25s%-30s%-30s%-10s\n", "Flow", "Check", "Want", "Got", "Result")
+	for _, r := range v.Results {
+		result := "PASS"
+		if !r.Passed {
+			result = "FAIL"
+		}
+		fmt.Fprintf(&out, "%-25s%-25s%-30s%-30s%-10s\n", r.Flow, r.Check, r.Want, r.Got, result)
+	}
+	fmt.Fprintln(&out, strings.Repeat("-", 120))
+	return out.String()
+}
+
+// CSV renders the verdict in CSV format.
+func (v *Verdict) CSV() string {
+	var out strings.Builder
+	w := csv.NewWriter(&out)
+	// Writes to a strings.Builder cannot fail.
+	w.Write([]string{"flow", "check", "want", "got", "passed"})
+	for _, r := range v.Results {
+		w.Write([]string{r.Flow, r.Check, r.Want, r.Got, strconv.FormatBool(r.Passed)})
+	}
+	w.Flush()
+	return out.String()
+}
+
+// Report logs the verdict, writes it as a CSV file to the test outputs
+// directory and reports every failed check as a test error.
+func (v *Verdict) Report(t testing.TB) {
+	t.Helper()
+	t.Logf("Traffic verdict:\n%s", v)
+	filename, err := fptest.WriteOutput(t.Name()+" traffic verdict", ".csv", v.CSV())
+	if err != nil {
+		t.Logf("Could not write traffic verdict: %v", err)
+	}
+	if filename != "" {
+		ondatra.Report().AddTestProperty(t, "traffic_verdict", filename)
+	}
+	for _, r := range v.Failures() {
+		t.Errorf("Flow %s %s check failed: got %s, want %s", r.Flow, r.Check, r.Got, r.Want)
+	}
+}
+
+// Evaluate checks the expectations against the collected stats.
+func Evaluate(exps []*FlowExpectation, stats *TrafficStats) *Verdict {
+	v := &Verdict{}
+	for _, e := range exps {
+		fc, ok := stats.Flows[e.Flow]
+		if !ok {
+			v.add(e.Flow, "present", "true", "false", false)
+			continue
+		}
+		tol := e.Tolerance
+		if tol == 0 {
+			tol = 5
+		}
+		loss := fc.LossPct()
+		got := fmt.Sprintf("%.2f%% (tx %d, rx %d)", loss, fc.TxPkts, fc.RxPkts)
+		if e.MinLossPct > 0 {
+			v.add(e.Flow, "min loss", fmt.Sprintf(">= %.2f%%", e.MinLossPct), got, loss >= e.MinLossPct)
+		}
+		if e.MaxLossPct > 0 || e.MinLossPct == 0 {
+			v.add(e.Flow, "max loss", fmt.Sprintf("<= %.2f%%", e.MaxLossPct), got, fc.TxPkts > 0 && loss <= e.MaxLossPct)
+		}
+		if len(e.RxPorts) > 0 || len(e.Distribution) > 0 {
+			checkPorts(v, e, fc, tol)
+		}
+		for _, name := range sortedKeys(e.Tags) {
+			checkShares(v, e.Flow, "tag "+name+"=", e.Tags[name], fc.Tags[name], tol)
+		}
+	}
+	return v
+}
+
+// checkPorts evaluates the RxPorts and Distribution checks of e on the
+// received packets of the flow per port.
+func checkPorts(v *Verdict, e *FlowExpectation, fc *FlowCounters, tol float64) {
+	if e.PortTag == "" {
+		v.add(e.Flow, "port tag", "set", "unset", false)
+		return
+	}
+	rx := fc.rxPorts(e)
+	if len(e.RxPorts) > 0 {
+		allowed := map[string]bool{}
+		for _, p := range e.RxPorts {
+			allowed[p] = true
+		}
+		var unexpected []string
+		for _, p := range sortedCounts(rx) {
+			if !allowed[p] && rx[p] > 0 {
+				unexpected = append(unexpected, fmt.Sprintf("%s=%d", p, rx[p]))
+			}
+		}
+		v.add(e.Flow, "rx ports", strings.Join(e.RxPorts, ","), "unexpected: "+strings.Join(unexpected, ","), len(unexpected) == 0)
+	}
+	if len(e.Distribution) > 0 {
+		checkShares(v, e.Flow, "distribution ", e.Distribution, rx, tol)
+	}
+}
+
+// checkShares compares the share of each key in got against the normalized
+// weights in want. Keys present in got but not in want must have no packets.
+func checkShares(v *Verdict, flow, prefix string, want map[string]float64, got map[string]uint64, tol float64) {
+	var wantSum float64
+	for _, w := range want {
+		wantSum += w
+	}
+	var gotSum uint64
+	for _, n := range got {
+		gotSum += n
+	}
+	keys := map[string]bool{}
+	for k := range want {
+		keys[k] = true
+	}
+	for k, n := range got {
+		if n > 0 {
+			keys[k] = true
+		}
+	}
+	var sorted []string
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+	for _, k := range sorted {
+		var wantPct, gotPct float64
+		if wantSum > 0 {
+			wantPct = want[k] * 100 / wantSum
+		}
+		if gotSum > 0 {
+			gotPct = float64(got[k]) * 100 / float64(gotSum)
+		}
+		v.add(flow, prefix+k,
+			fmt.Sprintf("%.2f%% +/- %.2f", wantPct, tol),
+			fmt.Sprintf("%.2f%% (%d)", gotPct, got[k]),
+			gotSum > 0 && math.Abs(gotPct-wantPct) <= tol)
+	}
+}
+
+func sortedCounts(m map[string]uint64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedKeys(m map[string]map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// tagValue returns the value of a tag as a string, whatever its type.
+func tagValue(v *otgtelemetry.Flow_TaggedMetric_Tags_TagValue) string {
+	switch {
+	case v == nil:
+		return ""
+	case v.ValueAsHex != nil:
+		return v.GetValueAsHex()
+	case v.ValueAsCounter64 != nil:
+		return fmt.Sprint(v.GetValueAsCounter64())
+	case v.ValueAsString != nil:
+		return v.GetValueAsString()
+	case v.ValueAsIpv4 != nil:
+		return v.GetValueAsIpv4()
+	case v.ValueAsIpv6 != nil:
+		return v.GetValueAsIpv6()
+	case v.ValueAsMac != nil:
+		return v.GetValueAsMac()
+	case v.ValueAsBool != nil:
+		return fmt.Sprint(v.GetValueAsBool())
+	}
+	return ""
+}
+
+// CollectTrafficStats waits for the flows of the configuration to stop and
+// returns their counters along with the tagged metrics of flows that have
+// metric tags or egress tracking configured.
+func CollectTrafficStats(t testing.TB, otg *otg.OTG, c gosnappi.Config, timeout time.Duration) *TrafficStats {
+	t.Helper()
+	stats := &TrafficStats{Flows: map[string]*FlowCounters{}}
+	for _, f := range c.Flows().Items() {
+		gnmi.Watch(t, otg, gnmi.OTG().Flow(f.Name()).Transmit().State(), timeout, func(val *ygnmi.Value[bool]) bool {
+			transmitting, ok := val.Val()
+			return ok && !transmitting
+		}).Await(t)
+	}
+	for _, f := range c.Flows().Items() {
+		fm := gnmi.Get(t, otg, gnmi.OTG().Flow(f.Name()).State())
+		fc := &FlowCounters{
+			TxPkts: fm.GetCounters().GetOutPkts(),
+			RxPkts: fm.GetCounters().GetInPkts(),
+			Tags:   map[string]map[string]uint64{},
+		}
+		stats.Flows[f.Name()] = fc
+		for _, val := range gnmi.LookupAll(t, otg, gnmi.OTG().Flow(f.Name()).TaggedMetricAny().State()) {
+			tm, ok := val.Val()
+			if !ok {
+				continue
+			}
+			for _, tag := range tm.Tags {
+				name := tag.GetTagName()
+				if fc.Tags[name] == nil {
+					fc.Tags[name] = map[string]uint64{}
+				}
+				fc.Tags[name][tagValue(tag.GetTagValue())] += tm.GetCounters().GetInPkts()
+			}
+		}
+	}
+	return stats
+}
+
+// VerifyTraffic collects the OTG stats of the configuration, evaluates the
+// expectations and reports the verdict to the test.
+//
+// Usage:
+//
+//	otgutils.VerifyTraffic(t, ate.OTG(), top, time.Minute,
+//	  &otgutils.FlowExpectation{Flow: "v4", MaxLossPct: 1, Distribution: map[string]float64{"port2": 1, "port3": 3},
+//	    PortTag: "dst_mac", PortTagValues: map[string]string{"02:00:02:01:01:01": "port2", "02:00:03:01:01:01": "port3"}},
+//	  &otgutils.FlowExpectation{Flow: "blackhole", MinLossPct: 100},
+//	)
+func VerifyTraffic(t testing.TB, otg *otg.OTG, c gosnappi.Config, timeout time.Duration, exps ...*FlowExpectation) *Verdict {
+	t.Helper()
+	v := Evaluate(exps, CollectTrafficStats(t, otg, c, timeout))
+	v.Report(t)
+	return v
+}
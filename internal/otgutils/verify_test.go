@@ -0,0 +1,94 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otgutils
+
+import (
+	"testing"
+)
+
+func TestLossPct(t *testing.T) {
+	tests := []struct {
+		tx, rx uint64
+		want   float64
+	}{
+		{0, 0, 100},
+		{100, 100, 0},
+		{100, 75, 25},
+		{100, 101, 0},
+	}
+	for _, tt := range tests {
+		if got := (&FlowCounters{TxPkts: tt.tx, RxPkts: tt.rx}).LossPct(); got != tt.want {
+			t.Errorf("LossPct() with tx %d, rx %d got %v, want %v", tt.tx, tt.rx, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	stats := &TrafficStats{
+		Flows: map[string]*FlowCounters{
+			"ecmp": {TxPkts: 1000, RxPkts: 995, Tags: map[string]map[string]uint64{
+				"dst_mac": {"02:00:02:01:01:01": 250, "02:00:03:01:01:01": 745},
+			}},
+			"blackhole": {TxPkts: 1000, RxPkts: 0},
+			"policed":   {TxPkts: 1000, RxPkts: 400},
+			"idle":      {},
+			"dscp": {TxPkts: 100, RxPkts: 100, Tags: map[string]map[string]uint64{
+				"dscp": {"0x2e": 90, "0x00": 10},
+			}},
+		},
+	}
+	macs := map[string]string{"02:00:02:01:01:01": "port2", "02:00:03:01:01:01": "port3"}
+	tests := []struct {
+		desc       string
+		exp        *FlowExpectation
+		wantPassed bool
+	}{
+		{"ecmp within tolerance", &FlowExpectation{Flow: "ecmp", MaxLossPct: 1, RxPorts: []string{"port2", "port3"}, Distribution: map[string]float64{"port2": 1, "port3": 3}, PortTag: "dst_mac", PortTagValues: macs}, true},
+		{"ecmp loss too high", &FlowExpectation{Flow: "ecmp", MaxLossPct: 0.1}, false},
+		{"ecmp default max loss", &FlowExpectation{Flow: "ecmp"}, false},
+		{"ecmp wrong distribution", &FlowExpectation{Flow: "ecmp", MaxLossPct: 1, Distribution: map[string]float64{"port2": 1, "port3": 1}, PortTag: "dst_mac", PortTagValues: macs}, false},
+		{"ecmp unexpected port", &FlowExpectation{Flow: "ecmp", MaxLossPct: 1, RxPorts: []string{"port2"}, PortTag: "dst_mac", PortTagValues: macs}, false},
+		{"ecmp unknown tag value", &FlowExpectation{Flow: "ecmp", MaxLossPct: 1, RxPorts: []string{"port2", "port3"}, PortTag: "dst_mac", PortTagValues: map[string]string{"02:00:02:01:01:01": "port2"}}, false},
+		{"ecmp without port tag", &FlowExpectation{Flow: "ecmp", MaxLossPct: 1, RxPorts: []string{"port2", "port3"}}, false},
+		{"blackhole", &FlowExpectation{Flow: "blackhole", MinLossPct: 100}, true},
+		{"blackhole not dropped", &FlowExpectation{Flow: "ecmp", MinLossPct: 100}, false},
+		{"policed min loss only", &FlowExpectation{Flow: "policed", MinLossPct: 50}, true},
+		{"policed loss too high", &FlowExpectation{Flow: "policed", MinLossPct: 50, MaxLossPct: 55}, false},
+		{"nothing sent", &FlowExpectation{Flow: "idle", MaxLossPct: 100}, false},
+		{"missing flow", &FlowExpectation{Flow: "missing"}, false},
+		{"dscp tags", &FlowExpectation{Flow: "dscp", Tags: map[string]map[string]float64{"dscp": {"0x2e": 9, "0x00": 1}}}, true},
+		{"dscp unexpected value", &FlowExpectation{Flow: "dscp", Tags: map[string]map[string]float64{"dscp": {"0x2e": 1}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			v := Evaluate([]*FlowExpectation{tt.exp}, stats)
+			if got := v.Passed(); got != tt.wantPassed {
+				t.Errorf("Evaluate() got passed %v, want %v, verdict:\n%s", got, tt.wantPassed, v)
+			}
+		})
+	}
+}
+
+func TestVerdictCSV(t *testing.T) {
+	v := &Verdict{}
+	v.add("v4", "min loss", ">= 100.00%", "0.00% (tx 10, rx 10)", false)
+	v.add(`say "hi", flow`, "max loss", "<= 1.00%", "0.00%", true)
+	want := "flow,check,want,got,passed\n" +
+		"v4,min loss,>= 100.00%,\"0.00% (tx 10, rx 10)\",false\n" +
+		"\"say \"\"hi\"\", flow\",max loss,<= 1.00%,0.00%,true\n"
+	if got := v.CSV(); got != want {
+		t.Errorf("CSV() got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,535 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qoscfg
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/open-traffic-generator/snappi/gosnappi"
+	"github.com/openconfig/featureprofiles/internal/attrs"
+	"github.com/openconfig/featureprofiles/internal/deviations"
+	"github.com/openconfig/ondatra"
+	"github.com/openconfig/ondatra/gnmi"
+	"github.com/openconfig/ondatra/gnmi/oc"
+	"github.com/openconfig/ondatra/netutil"
+)
+
+const (
+	// DefaultSchedulerPolicy is the scheduler policy name used when
+	// Profile.SchedulerPolicy is empty.
+	DefaultSchedulerPolicy = "scheduler"
+	// Names of the classifiers rendered from the classes of a profile.
+	IPv4Classifier = "dscp_based_classifier_ipv4"
+	IPv6Classifier = "dscp_based_classifier_ipv6"
+	MPLSClassifier = "exp_based_classifier_mpls"
+
+	bufferAllocationProfile = "ballocprofile"
+	bufferSharedLimit       = 268435456
+	// ecnThresholdGap is the difference between the WRED thresholds required
+	// by devices that cannot use equal thresholds.
+	ecnThresholdGap = 6144
+	// maxLimitedWeight is the highest scheduler weight accepted by devices
+	// with the SchedulerInputWeightLimit deviation.
+	maxLimitedWeight = 100
+	defaultFrameSize = 1000
+)
+
+// TrafficClass is a class of traffic classified into one output queue.
+type TrafficClass struct {
+	// Name identifies the class, e.g. AF3. The forwarding group of the class
+	// is target-group-<Name>.
+	Name string
+	// Queue is the output queue name, usually taken from
+	// netutil.CommonTrafficQueues.
+	Queue string
+	// QueueID is used on devices that require queue IDs. Defaults to the
+	// number of classes minus the index of the class, so that classes
+	// listed first get higher IDs.
+	QueueID uint8
+	// DSCP are the IPv4 and IPv6 DSCP values classified into the class.
+	DSCP []uint8
+	// EXP are the MPLS traffic class values classified into the class.
+	EXP []uint8
+	// Strict schedules the queue with strict priority. Strict classes are
+	// served in the order they are listed.
+	Strict bool
+	// Weight is the WRR weight of the queue. Optional for strict classes.
+	Weight uint64
+	// QueueManagement names the QueueManagementProfile of the output queue,
+	// if any.
+	QueueManagement string
+}
+
+func (c *TrafficClass) forwardingGroup() string {
+	return "target-group-" + c.Name
+}
+
+// QueueManagementProfile is a WRED profile, optionally marking ECN instead of
+// dropping.
+type QueueManagementProfile struct {
+	Name string
+	// MinThreshold and MaxThreshold are in bytes.
+	MinThreshold, MaxThreshold uint64
+	MaxDropProbabilityPercent  uint8
+	ECN                        bool
+	Drop                       bool
+	Weight                     uint32
+}
+
+// Profile is a declarative QoS configuration: traffic classes, their
+// classifiers, queue management and a scheduler policy applied to output
+// interfaces.
+type Profile struct {
+	// Classes are listed from the highest to the lowest priority.
+	Classes         []*TrafficClass
+	QueueManagement []*QueueManagementProfile
+	// SchedulerPolicy defaults to DefaultSchedulerPolicy.
+	SchedulerPolicy string
+	// InputInterfaces get the classifiers of the profile.
+	InputInterfaces []string
+	// OutputInterfaces get the scheduler policy and the output queues.
+	OutputInterfaces []string
+}
+
+// CommonProfile returns the profile used by the QoS traffic tests: NC1 and
+// AF4 are strict priority and AF3, AF2, AF1, BE0 and BE1 share the remaining
+// bandwidth with weights 64, 16, 4, 1 and 1. Interfaces are left to the
+// caller.
+func CommonProfile(t *testing.T, dut *ondatra.DUTDevice) *Profile {
+	t.Helper()
+	queues := netutil.CommonTrafficQueues(t, dut)
+	return &Profile{
+		Classes: []*TrafficClass{
+			{Name: "NC1", Queue: queues.NC1, DSCP: []uint8{48, 49, 50, 51, 52, 53, 54, 55, 56, 57, 58, 59}, EXP: []uint8{6, 7}, Strict: true, Weight: 200},
+			{Name: "AF4", Queue: queues.AF4, DSCP: []uint8{32, 33, 34, 35}, EXP: []uint8{4, 5}, Strict: true, Weight: 100},
+			{Name: "AF3", Queue: queues.AF3, DSCP: []uint8{24, 25, 26, 27}, EXP: []uint8{3}, Weight: 64},
+			{Name: "AF2", Queue: queues.AF2, DSCP: []uint8{16, 17, 18, 19}, EXP: []uint8{2}, Weight: 16},
+			{Name: "AF1", Queue: queues.AF1, DSCP: []uint8{8, 9, 10, 11}, EXP: []uint8{1}, Weight: 4},
+			{Name: "BE0", Queue: queues.BE0, DSCP: []uint8{4, 5, 6, 7}, Weight: 1},
+			{Name: "BE1", Queue: queues.BE1, DSCP: []uint8{0, 1, 2, 3}, EXP: []uint8{0}, Weight: 1},
+		},
+	}
+}
+
+// Class returns the class with the given name, or nil if there is none.
+func (p *Profile) Class(name string) *TrafficClass {
+	for _, c := range p.Classes {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func (p *Profile) schedulerPolicy() string {
+	if p.SchedulerPolicy != "" {
+		return p.SchedulerPolicy
+	}
+	return DefaultSchedulerPolicy
+}
+
+// Validate checks that classes, queues and classifier values are unique and
+// that the queue management profiles referred to are defined.
+func (p *Profile) Validate() error {
+	if len(p.Classes) == 0 {
+		return fmt.Errorf("QoS profile has no traffic classes")
+	}
+	qms := map[string]bool{}
+	for _, qm := range p.QueueManagement {
+		if qm.Name == "" || qms[qm.Name] {
+			return fmt.Errorf("queue management profile name %q is empty or duplicated", qm.Name)
+		}
+		qms[qm.Name] = true
+		if qm.MinThreshold > qm.MaxThreshold {
+			return fmt.Errorf("queue management profile %s has min threshold %d above max threshold %d", qm.Name, qm.MinThreshold, qm.MaxThreshold)
+		}
+		if qm.MaxDropProbabilityPercent > 100 {
+			return fmt.Errorf("queue management profile %s has drop probability %d%%", qm.Name, qm.MaxDropProbabilityPercent)
+		}
+	}
+	names, queues := map[string]bool{}, map[string]bool{}
+	dscps, exps := map[uint8]string{}, map[uint8]string{}
+	for _, c := range p.Classes {
+		if c.Name == "" || names[c.Name] {
+			return fmt.Errorf("traffic class name %q is empty or duplicated", c.Name)
+		}
+		names[c.Name] = true
+		if c.Queue == "" || queues[c.Queue] {
+			return fmt.Errorf("traffic class %s: queue %q is empty or shared with another class", c.Name, c.Queue)
+		}
+		queues[c.Queue] = true
+		if !c.Strict && c.Weight == 0 {
+			return fmt.Errorf("traffic class %s: WRR classes need a weight", c.Name)
+		}
+		if c.QueueManagement != "" && !qms[c.QueueManagement] {
+			return fmt.Errorf("traffic class %s: queue management profile %q is not defined", c.Name, c.QueueManagement)
+		}
+		for _, v := range c.DSCP {
+			if other, ok := dscps[v]; ok || v > 63 {
+				return fmt.Errorf("traffic class %s: DSCP %d is invalid or already used by %q", c.Name, v, other)
+			}
+			dscps[v] = c.Name
+		}
+		for _, v := range c.EXP {
+			if other, ok := exps[v]; ok || v > 7 {
+				return fmt.Errorf("traffic class %s: EXP %d is invalid or already used by %q", c.Name, v, other)
+			}
+			exps[v] = c.Name
+		}
+	}
+	return nil
+}
+
+// renderOptions hold the deviations that affect the rendered configuration.
+type renderOptions struct {
+	queueRequiresID                 bool
+	interfaceRefUnsupported         bool
+	inputSubinterfaceRefUnsupported bool
+	weightLimit                     bool
+	distinctECNThresholds           bool
+	setWeightUnsupported            bool
+	bufferAllocationRequired        bool
+}
+
+func newRenderOptions(dut *ondatra.DUTDevice) *renderOptions {
+	return &renderOptions{
+		queueRequiresID:                 deviations.QOSQueueRequiresID(dut),
+		interfaceRefUnsupported:         deviations.InterfaceRefConfigUnsupported(dut),
+		inputSubinterfaceRefUnsupported: dut.Vendor() == ondatra.CISCO, // As in SetInputClassifier.
+		weightLimit:                     deviations.SchedulerInputWeightLimit(dut),
+		distinctECNThresholds:           deviations.EcnSameMinMaxThresholdUnsupported(dut),
+		setWeightUnsupported:            deviations.QosSetWeightConfigUnsupported(dut),
+		bufferAllocationRequired:        deviations.QOSBufferAllocationConfigRequired(dut),
+	}
+}
+
+// weights returns the scheduler weights of the classes, scaled down so that
+// none exceeds maxLimitedWeight when limit is set. WRR weights keep their
+// ratios as closely as possible and strict priority weights are capped.
+func (p *Profile) weights(limit bool) map[string]uint64 {
+	var maxWRR uint64
+	for _, c := range p.Classes {
+		if !c.Strict && c.Weight > maxWRR {
+			maxWRR = c.Weight
+		}
+	}
+	w := map[string]uint64{}
+	for _, c := range p.Classes {
+		w[c.Name] = c.Weight
+		switch {
+		case !limit:
+		case c.Strict:
+			w[c.Name] = min(c.Weight, maxLimitedWeight)
+		case maxWRR > maxLimitedWeight:
+			w[c.Name] = max(1, uint64(math.Round(float64(c.Weight)*maxLimitedWeight/float64(maxWRR))))
+		}
+	}
+	if limit {
+		// Keep the strict weights distinct, as the test profiles do.
+		next := uint64(maxLimitedWeight)
+		for _, c := range p.Classes {
+			if c.Strict && c.Weight != 0 && w[c.Name] >= next {
+				w[c.Name] = next
+				next--
+			}
+		}
+	}
+	return w
+}
+
+// render builds the QoS configuration of the profile.
+func (p *Profile) render(o *renderOptions) (*oc.Qos, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	q := &oc.Qos{}
+	for _, qm := range p.QueueManagement {
+		prof := q.GetOrCreateQueueManagementProfile(qm.Name)
+		prof.SetName(qm.Name)
+		u := prof.GetOrCreateWred().GetOrCreateUniform()
+		u.SetEnableEcn(qm.ECN)
+		u.SetDrop(qm.Drop)
+		minT, maxT := qm.MinThreshold, qm.MaxThreshold
+		if o.distinctECNThresholds && minT == maxT {
+			maxT = minT + ecnThresholdGap
+		}
+		u.SetMinThreshold(minT)
+		u.SetMaxThreshold(maxT)
+		u.SetMaxDropProbabilityPercent(qm.MaxDropProbabilityPercent)
+		if !o.setWeightUnsupported {
+			u.SetWeight(qm.Weight)
+		}
+	}
+
+	weights := p.weights(o.weightLimit)
+	sp := q.GetOrCreateSchedulerPolicy(p.schedulerPolicy())
+	sp.SetName(p.schedulerPolicy())
+	classifiers := map[oc.E_Input_Classifier_Type]string{}
+	for i, c := range p.Classes {
+		q.GetOrCreateForwardingGroup(c.forwardingGroup()).SetOutputQueue(c.Queue)
+		queue := q.GetOrCreateQueue(c.Queue)
+		queue.SetName(c.Queue)
+		if o.queueRequiresID {
+			id := c.QueueID
+			if id == 0 {
+				id = uint8(len(p.Classes) - i)
+			}
+			queue.SetQueueId(id)
+		}
+
+		term := func(name string, typ oc.E_Qos_Classifier_Type) *oc.Qos_Classifier_Term {
+			cl := q.GetOrCreateClassifier(name)
+			cl.SetName(name)
+			cl.SetType(typ)
+			t := cl.GetOrCreateTerm(strconv.Itoa(i))
+			t.SetId(strconv.Itoa(i))
+			t.GetOrCreateActions().SetTargetGroup(c.forwardingGroup())
+			return t
+		}
+		if len(c.DSCP) > 0 {
+			term(IPv4Classifier, oc.Qos_Classifier_Type_IPV4).GetOrCreateConditions().GetOrCreateIpv4().SetDscpSet(c.DSCP)
+			term(IPv6Classifier, oc.Qos_Classifier_Type_IPV6).GetOrCreateConditions().GetOrCreateIpv6().SetDscpSet(c.DSCP)
+			classifiers[oc.Input_Classifier_Type_IPV4] = IPv4Classifier
+			classifiers[oc.Input_Classifier_Type_IPV6] = IPv6Classifier
+		}
+		// A term matches a single MPLS traffic class, so each EXP value gets
+		// its own term.
+		for _, exp := range c.EXP {
+			cl := q.GetOrCreateClassifier(MPLSClassifier)
+			cl.SetName(MPLSClassifier)
+			cl.SetType(oc.Qos_Classifier_Type_MPLS)
+			id := fmt.Sprintf("%d-%d", i, exp)
+			t := cl.GetOrCreateTerm(id)
+			t.SetId(id)
+			t.GetOrCreateActions().SetTargetGroup(c.forwardingGroup())
+			t.GetOrCreateConditions().GetOrCreateMpls().SetTrafficClass(exp)
+			classifiers[oc.Input_Classifier_Type_MPLS] = MPLSClassifier
+		}
+
+		seq := uint32(1)
+		prio := oc.Scheduler_Priority_UNSET
+		if c.Strict {
+			seq, prio = 0, oc.Scheduler_Priority_STRICT
+		}
+		s := sp.GetOrCreateScheduler(seq)
+		s.SetSequence(seq)
+		s.SetPriority(prio)
+		in := s.GetOrCreateInput(c.Name)
+		in.SetId(c.Name)
+		in.SetInputType(oc.Input_InputType_QUEUE)
+		in.SetQueue(c.Queue)
+		if w := weights[c.Name]; w != 0 {
+			in.SetWeight(w)
+		}
+	}
+
+	for _, name := range p.InputInterfaces {
+		intf := q.GetOrCreateInterface(name)
+		intf.SetInterfaceId(name)
+		intf.GetOrCreateInterfaceRef().SetInterface(name)
+		if !o.inputSubinterfaceRefUnsupported {
+			intf.GetOrCreateInterfaceRef().SetSubinterface(0)
+		}
+		if o.interfaceRefUnsupported {
+			intf.InterfaceRef = nil
+		}
+		for typ, cl := range classifiers {
+			intf.GetOrCreateInput().GetOrCreateClassifier(typ).SetName(cl)
+		}
+	}
+	for _, name := range p.OutputInterfaces {
+		intf := q.GetOrCreateInterface(name)
+		intf.SetInterfaceId(name)
+		intf.GetOrCreateInterfaceRef().SetInterface(name)
+		if o.interfaceRefUnsupported {
+			intf.InterfaceRef = nil
+		}
+		out := intf.GetOrCreateOutput()
+		out.GetOrCreateSchedulerPolicy().SetName(p.schedulerPolicy())
+		for _, c := range p.Classes {
+			queue := out.GetOrCreateQueue(c.Queue)
+			queue.SetName(c.Queue)
+			if c.QueueManagement != "" {
+				queue.SetQueueManagementProfile(c.QueueManagement)
+			}
+		}
+		if o.bufferAllocationRequired {
+			ba := q.GetOrCreateBufferAllocationProfile(bufferAllocationProfile)
+			ba.SetName(bufferAllocationProfile)
+			for _, c := range p.Classes {
+				ba.GetOrCreateQueue(c.Queue).SetStaticSharedBufferLimit(bufferSharedLimit)
+			}
+			out.SetBufferAllocationProfile(bufferAllocationProfile)
+		}
+	}
+	return q, nil
+}
+
+// Render returns the QoS configuration of the profile for dut, with the QoS
+// deviations of dut applied.
+func (p *Profile) Render(t *testing.T, dut *ondatra.DUTDevice) *oc.Qos {
+	t.Helper()
+	q, err := p.render(newRenderOptions(dut))
+	if err != nil {
+		t.Fatalf("Rendering QoS profile: %v", err)
+	}
+	return q
+}
+
+// AddToBatch adds the replacement of the whole QoS tree of dut with the
+// profile to batch.
+func (p *Profile) AddToBatch(t *testing.T, dut *ondatra.DUTDevice, batch *gnmi.SetBatch) {
+	t.Helper()
+	gnmi.BatchReplace(batch, gnmi.OC().Qos().Config(), p.Render(t, dut))
+}
+
+// Push replaces the QoS configuration of dut with the profile in a single
+// gNMI Set.
+func (p *Profile) Push(t *testing.T, dut *ondatra.DUTDevice) {
+	t.Helper()
+	batch := &gnmi.SetBatch{}
+	p.AddToBatch(t, dut, batch)
+	batch.Set(t, dut)
+}
+
+// Offer is traffic offered to a traffic class.
+type Offer struct {
+	// Name is the flow name. Defaults to <Src.Name>-<Class>.
+	Name  string
+	Class string
+	// Src and Dst are the ATE interfaces the traffic is sent from and to.
+	Src, Dst *attrs.Attributes
+	// RatePercent is the rate as a percentage of the source port line rate.
+	// Expectations assume that all ports run at the same speed and that all
+	// offers egress the same interface.
+	RatePercent float32
+	// FrameSize defaults to 1000 bytes.
+	FrameSize uint32
+	IPv6      bool
+}
+
+func (o *Offer) name() string {
+	if o.Name != "" {
+		return o.Name
+	}
+	return o.Src.Name + "-" + o.Class
+}
+
+// AddFlows adds an OTG flow for each offer, marked with the first DSCP value
+// of its class.
+func (p *Profile) AddFlows(top gosnappi.Config, offers ...*Offer) error {
+	for _, o := range offers {
+		c := p.Class(o.Class)
+		if c == nil {
+			return fmt.Errorf("flow %s: traffic class %q is not defined", o.name(), o.Class)
+		}
+		if len(c.DSCP) == 0 {
+			return fmt.Errorf("flow %s: traffic class %s has no DSCP value", o.name(), c.Name)
+		}
+		size := o.FrameSize
+		if size == 0 {
+			size = defaultFrameSize
+		}
+		flow := top.Flows().Add().SetName(o.name())
+		flow.Metrics().SetEnable(true)
+		flow.Packet().Add().Ethernet().Src().SetValue(o.Src.MAC)
+		if o.IPv6 {
+			flow.TxRx().Device().SetTxNames([]string{o.Src.Name + ".IPv6"}).SetRxNames([]string{o.Dst.Name + ".IPv6"})
+			ip := flow.Packet().Add().Ipv6()
+			ip.Src().SetValue(o.Src.IPv6)
+			ip.Dst().SetValue(o.Dst.IPv6)
+			ip.TrafficClass().SetValue(uint32(c.DSCP[0]) << 2)
+		} else {
+			flow.TxRx().Device().SetTxNames([]string{o.Src.Name + ".IPv4"}).SetRxNames([]string{o.Dst.Name + ".IPv4"})
+			ip := flow.Packet().Add().Ipv4()
+			ip.Src().SetValue(o.Src.IPv4)
+			ip.Dst().SetValue(o.Dst.IPv4)
+			ip.Priority().Dscp().Phb().SetValue(uint32(c.DSCP[0]))
+		}
+		flow.Size().SetFixed(size)
+		flow.Rate().SetPercentage(o.RatePercent)
+	}
+	return nil
+}
+
+// ExpectedThroughput returns the percentage of the traffic of each offer
+// that the output queues are expected to forward, keyed by flow name.
+//
+// Strict priority classes are served first, in order, then the remaining
+// bandwidth is shared among the WRR classes in proportion to their weights,
+// with the share unused by a class redistributed to the others. Offers of
+// the same class get the same percentage.
+func (p *Profile) ExpectedThroughput(offers ...*Offer) (map[string]float32, error) {
+	demand := map[string]float64{}
+	for _, o := range offers {
+		if p.Class(o.Class) == nil {
+			return nil, fmt.Errorf("flow %s: traffic class %q is not defined", o.name(), o.Class)
+		}
+		demand[o.Class] += float64(o.RatePercent)
+	}
+	served := p.schedule(demand, 100)
+	want := map[string]float32{}
+	for _, o := range offers {
+		want[o.name()] = float32(100 * served[o.Class] / demand[o.Class])
+	}
+	return want, nil
+}
+
+// schedule returns the rate served to each class given the rate offered to
+// each class and the capacity of the output interface, in the same unit.
+func (p *Profile) schedule(demand map[string]float64, capacity float64) map[string]float64 {
+	served := map[string]float64{}
+	remaining := capacity
+	var wrr []*TrafficClass
+	for _, c := range p.Classes {
+		if demand[c.Name] == 0 {
+			continue
+		}
+		if !c.Strict {
+			wrr = append(wrr, c)
+			continue
+		}
+		served[c.Name] = math.Min(demand[c.Name], remaining)
+		remaining -= served[c.Name]
+	}
+	for len(wrr) > 0 && remaining > 0 {
+		var total float64
+		for _, c := range wrr {
+			total += float64(c.Weight)
+		}
+		var next []*TrafficClass
+		left := remaining
+		for _, c := range wrr {
+			share := remaining * float64(c.Weight) / total
+			if demand[c.Name] <= share {
+				served[c.Name] = demand[c.Name]
+				left -= demand[c.Name]
+				continue
+			}
+			next = append(next, c)
+		}
+		if len(next) == len(wrr) {
+			for _, c := range wrr {
+				served[c.Name] = remaining * float64(c.Weight) / total
+			}
+			break
+		}
+		wrr, remaining = next, left
+	}
+	return served
+}
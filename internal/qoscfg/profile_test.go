@@ -0,0 +1,243 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qoscfg
+
+import (
+	"math"
+	"testing"
+
+	"github.com/open-traffic-generator/snappi/gosnappi"
+	"github.com/openconfig/featureprofiles/internal/attrs"
+	"github.com/openconfig/ondatra/gnmi/oc"
+)
+
+func testProfile() *Profile {
+	return &Profile{
+		Classes: []*TrafficClass{
+			{Name: "NC1", Queue: "NC1", DSCP: []uint8{48}, EXP: []uint8{6, 7}, Strict: true, Weight: 200},
+			{Name: "AF4", Queue: "AF4", DSCP: []uint8{32}, Strict: true, Weight: 100},
+			{Name: "AF3", Queue: "AF3", DSCP: []uint8{24}, Weight: 640, QueueManagement: "ecn"},
+			{Name: "AF2", Queue: "AF2", DSCP: []uint8{16}, Weight: 160},
+			{Name: "BE1", Queue: "BE1", DSCP: []uint8{0}, EXP: []uint8{0}, Weight: 10},
+		},
+		QueueManagement: []*QueueManagementProfile{
+			{Name: "ecn", MinThreshold: 80000, MaxThreshold: 80000, MaxDropProbabilityPercent: 1, ECN: true, Weight: 0},
+		},
+		InputInterfaces:  []string{"port1"},
+		OutputInterfaces: []string{"port2"},
+	}
+}
+
+func TestRender(t *testing.T) {
+	p := testProfile()
+	q, err := p.render(&renderOptions{})
+	if err != nil {
+		t.Fatalf("render() got unexpected error: %v", err)
+	}
+	if got := q.GetForwardingGroup("target-group-AF3").GetOutputQueue(); got != "AF3" {
+		t.Errorf("forwarding group target-group-AF3 got output queue %q, want AF3", got)
+	}
+	if got := q.GetQueue("NC1").QueueId; got != nil {
+		t.Errorf("queue NC1 got queue ID %d, want none", *got)
+	}
+	if got := q.GetClassifier(IPv6Classifier).GetTerm("2").GetConditions().GetIpv6().GetDscpSet(); len(got) != 1 || got[0] != 24 {
+		t.Errorf("IPv6 classifier term 2 got DSCP set %v, want [24]", got)
+	}
+	if got := len(q.GetClassifier(MPLSClassifier).Term); got != 3 {
+		t.Errorf("MPLS classifier got %d terms, want 3", got)
+	}
+	sched := q.GetSchedulerPolicy(DefaultSchedulerPolicy)
+	if got := sched.GetScheduler(0).GetInput("AF4"); got.GetWeight() != 100 || sched.GetScheduler(0).GetPriority() != oc.Scheduler_Priority_STRICT {
+		t.Errorf("strict scheduler got AF4 input %+v, want weight 100 with strict priority", got)
+	}
+	if got := sched.GetScheduler(1).GetInput("AF3").GetWeight(); got != 640 {
+		t.Errorf("WRR scheduler got AF3 weight %d, want 640", got)
+	}
+	u := q.GetQueueManagementProfile("ecn").GetWred().GetUniform()
+	if u.GetMaxThreshold() != 80000 || u.Weight == nil {
+		t.Errorf("queue management profile got %+v, want max threshold 80000 and a weight", u)
+	}
+	in := q.GetInterface("port1")
+	if got := in.GetInterfaceRef().GetSubinterface(); in.GetInterfaceRef() == nil || got != 0 {
+		t.Errorf("input interface got ref %+v, want subinterface 0", in.GetInterfaceRef())
+	}
+	if got := len(in.GetInput().Classifier); got != 3 {
+		t.Errorf("input interface got %d classifiers, want 3", got)
+	}
+	out := q.GetInterface("port2").GetOutput()
+	if got := out.GetSchedulerPolicy().GetName(); got != DefaultSchedulerPolicy {
+		t.Errorf("output interface got scheduler policy %q, want %q", got, DefaultSchedulerPolicy)
+	}
+	if got := out.GetQueue("AF3").GetQueueManagementProfile(); got != "ecn" {
+		t.Errorf("output queue AF3 got queue management profile %q, want ecn", got)
+	}
+	if out.BufferAllocationProfile != nil {
+		t.Errorf("output interface got buffer allocation profile %q, want none", out.GetBufferAllocationProfile())
+	}
+
+	q, err = p.render(&renderOptions{inputSubinterfaceRefUnsupported: true})
+	if err != nil {
+		t.Fatalf("render() without input subinterface ref got unexpected error: %v", err)
+	}
+	if ref := q.GetInterface("port1").GetInterfaceRef(); ref.GetInterface() != "port1" || ref.Subinterface != nil {
+		t.Errorf("input interface without subinterface ref got ref %+v, want interface port1 only", ref)
+	}
+}
+
+func TestRenderDeviations(t *testing.T) {
+	q, err := testProfile().render(&renderOptions{
+		queueRequiresID:                 true,
+		interfaceRefUnsupported:         true,
+		inputSubinterfaceRefUnsupported: true,
+		weightLimit:                     true,
+		distinctECNThresholds:           true,
+		setWeightUnsupported:            true,
+		bufferAllocationRequired:        true,
+	})
+	if err != nil {
+		t.Fatalf("render() got unexpected error: %v", err)
+	}
+	if got := q.GetQueue("NC1").GetQueueId(); got != 5 {
+		t.Errorf("queue NC1 got ID %d, want 5", got)
+	}
+	if got := q.GetQueue("BE1").GetQueueId(); got != 1 {
+		t.Errorf("queue BE1 got ID %d, want 1", got)
+	}
+	sched := q.GetSchedulerPolicy(DefaultSchedulerPolicy)
+	for class, want := range map[string]uint64{"NC1": 100, "AF4": 99, "AF3": 100, "AF2": 25, "BE1": 2} {
+		seq := uint32(1)
+		if class == "NC1" || class == "AF4" {
+			seq = 0
+		}
+		if got := sched.GetScheduler(seq).GetInput(class).GetWeight(); got != want {
+			t.Errorf("class %s got weight %d, want %d", class, got, want)
+		}
+	}
+	u := q.GetQueueManagementProfile("ecn").GetWred().GetUniform()
+	if got, want := u.GetMaxThreshold()-u.GetMinThreshold(), uint64(ecnThresholdGap); got != want {
+		t.Errorf("queue management profile got threshold gap %d, want %d", got, want)
+	}
+	if u.Weight != nil {
+		t.Errorf("queue management profile got weight %d, want none", u.GetWeight())
+	}
+	if ref := q.GetInterface("port1").InterfaceRef; ref != nil {
+		t.Errorf("input interface got ref %+v, want none", ref)
+	}
+	if got := q.GetInterface("port2").GetOutput().GetBufferAllocationProfile(); got != bufferAllocationProfile {
+		t.Errorf("output interface got buffer allocation profile %q, want %q", got, bufferAllocationProfile)
+	}
+	if got := q.GetBufferAllocationProfile(bufferAllocationProfile).GetQueue("AF2").GetStaticSharedBufferLimit(); got != bufferSharedLimit {
+		t.Errorf("buffer allocation profile got AF2 limit %d, want %d", got, bufferSharedLimit)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		desc string
+		mod  func(*Profile)
+	}{
+		{"no classes", func(p *Profile) { p.Classes = nil }},
+		{"duplicate class", func(p *Profile) { p.Classes[1].Name = "NC1" }},
+		{"shared queue", func(p *Profile) { p.Classes[1].Queue = "NC1" }},
+		{"WRR without weight", func(p *Profile) { p.Classes[2].Weight = 0 }},
+		{"duplicate DSCP", func(p *Profile) { p.Classes[2].DSCP = []uint8{48} }},
+		{"DSCP out of range", func(p *Profile) { p.Classes[2].DSCP = []uint8{64} }},
+		{"EXP out of range", func(p *Profile) { p.Classes[2].EXP = []uint8{8} }},
+		{"undefined queue management", func(p *Profile) { p.Classes[3].QueueManagement = "wred" }},
+		{"inverted thresholds", func(p *Profile) { p.QueueManagement[0].MinThreshold = 90000 }},
+	}
+	if err := testProfile().Validate(); err != nil {
+		t.Fatalf("Validate() got unexpected error: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			p := testProfile()
+			tt.mod(p)
+			if err := p.Validate(); err == nil {
+				t.Errorf("Validate() got no error, want error")
+			}
+		})
+	}
+}
+
+func TestExpectedThroughput(t *testing.T) {
+	src1 := &attrs.Attributes{Name: "ate1", MAC: "02:00:01:01:01:01", IPv4: "192.0.2.2", IPv6: "2001:db8::2"}
+	src2 := &attrs.Attributes{Name: "ate2", MAC: "02:00:02:01:01:01", IPv4: "192.0.2.6", IPv6: "2001:db8::6"}
+	dst := &attrs.Attributes{Name: "ate3", MAC: "02:00:03:01:01:01", IPv4: "192.0.2.10", IPv6: "2001:db8::a"}
+	tests := []struct {
+		desc   string
+		offers []*Offer
+		want   map[string]float32
+	}{{
+		desc: "no congestion",
+		offers: []*Offer{
+			{Class: "NC1", Src: src1, Dst: dst, RatePercent: 10},
+			{Class: "AF3", Src: src1, Dst: dst, RatePercent: 40},
+			{Class: "AF2", Src: src2, Dst: dst, RatePercent: 40},
+		},
+		want: map[string]float32{"ate1-NC1": 100, "ate1-AF3": 100, "ate2-AF2": 100},
+	}, {
+		desc: "WRR split",
+		offers: []*Offer{
+			{Class: "AF3", Src: src1, Dst: dst, RatePercent: 80},
+			{Class: "AF2", Src: src2, Dst: dst, RatePercent: 80},
+		},
+		want: map[string]float32{"ate1-AF3": 100, "ate2-AF2": 25},
+	}, {
+		desc: "strict priority starves WRR",
+		offers: []*Offer{
+			{Class: "NC1", Src: src1, Dst: dst, RatePercent: 60},
+			{Class: "AF4", Src: src2, Dst: dst, RatePercent: 60},
+			{Class: "AF3", Src: src1, Dst: dst, RatePercent: 10, IPv6: true},
+		},
+		want: map[string]float32{"ate1-NC1": 100, "ate2-AF4": 200.0 / 3, "ate1-AF3": 0},
+	}, {
+		desc: "unused share is redistributed",
+		offers: []*Offer{
+			{Class: "AF3", Src: src1, Dst: dst, RatePercent: 50},
+			{Class: "AF2", Src: src2, Dst: dst, RatePercent: 50},
+			{Class: "BE1", Name: "be", Src: src2, Dst: dst, RatePercent: 1},
+		},
+		// BE1 and AF3 are under their shares, AF2 gets the remaining 49%.
+		want: map[string]float32{"ate1-AF3": 100, "ate2-AF2": 98, "be": 100},
+	}}
+	p := testProfile()
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := p.ExpectedThroughput(tt.offers...)
+			if err != nil {
+				t.Fatalf("ExpectedThroughput() got unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ExpectedThroughput() got %v, want %v", got, tt.want)
+			}
+			for name, want := range tt.want {
+				if math.Abs(float64(got[name]-want)) > 0.01 {
+					t.Errorf("ExpectedThroughput() got %v for %s, want %v", got[name], name, want)
+				}
+			}
+			top := gosnappi.NewConfig()
+			if err := p.AddFlows(top, tt.offers...); err != nil {
+				t.Fatalf("AddFlows() got unexpected error: %v", err)
+			}
+			if got := len(top.Flows().Items()); got != len(tt.offers) {
+				t.Errorf("AddFlows() got %d flows, want %d", got, len(tt.offers))
+			}
+		})
+	}
+	if _, err := p.ExpectedThroughput(&Offer{Class: "AF9", Src: src1, Dst: dst}); err == nil {
+		t.Errorf("ExpectedThroughput() with an unknown class got no error, want error")
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qoscfg
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/openconfig/featureprofiles/internal/deviations"
+	"github.com/openconfig/ondatra"
+	"github.com/openconfig/ondatra/gnmi"
+)
+
+// QueueExpectation is the expected behavior of the output queue of a class.
+type QueueExpectation struct {
+	Class, Queue string
+	// Offered, Transmitted and Dropped are rates in bits per second.
+	Offered, Transmitted, Dropped float64
+}
+
+// TransmitShare returns the expected percentage of the offered traffic that
+// is transmitted.
+func (e *QueueExpectation) TransmitShare() float64 {
+	if e.Offered == 0 {
+		return 0
+	}
+	return 100 * e.Transmitted / e.Offered
+}
+
+// DropShare returns the expected percentage of the offered traffic that is
+// dropped.
+func (e *QueueExpectation) DropShare() float64 {
+	if e.Offered == 0 {
+		return 0
+	}
+	return 100 * e.Dropped / e.Offered
+}
+
+// OfferedLoad returns the rate offered to each class by offers in bits per
+// second, for sources running at linkRate bits per second.
+func OfferedLoad(linkRate float64, offers ...*Offer) map[string]float64 {
+	load := map[string]float64{}
+	for _, o := range offers {
+		load[o.Class] += linkRate * float64(o.RatePercent) / 100
+	}
+	return load
+}
+
+// Expect returns the expected behavior of the output queues of the classes
+// offered traffic, keyed by class name. offered is the rate offered to each
+// class and linkRate the rate of the output interface, both in bits per
+// second.
+func (p *Profile) Expect(offered map[string]float64, linkRate float64) (map[string]*QueueExpectation, error) {
+	if linkRate <= 0 {
+		return nil, fmt.Errorf("invalid link rate %v", linkRate)
+	}
+	for name := range offered {
+		if p.Class(name) == nil {
+			return nil, fmt.Errorf("traffic class %q is not defined", name)
+		}
+	}
+	served := p.schedule(offered, linkRate)
+	exp := map[string]*QueueExpectation{}
+	for _, c := range p.Classes {
+		if offered[c.Name] == 0 {
+			continue
+		}
+		exp[c.Name] = &QueueExpectation{
+			Class:       c.Name,
+			Queue:       c.Queue,
+			Offered:     offered[c.Name],
+			Transmitted: served[c.Name],
+			Dropped:     offered[c.Name] - served[c.Name],
+		}
+	}
+	return exp, nil
+}
+
+// QueueCounters are the counters of an output queue.
+type QueueCounters struct {
+	TransmitPkts, TransmitOctets, DroppedPkts uint64
+}
+
+// Sub returns the counters accumulated since before. A nil before returns c.
+func (c *QueueCounters) Sub(before *QueueCounters) *QueueCounters {
+	if before == nil {
+		return c
+	}
+	return &QueueCounters{
+		TransmitPkts:   c.TransmitPkts - before.TransmitPkts,
+		TransmitOctets: c.TransmitOctets - before.TransmitOctets,
+		DroppedPkts:    c.DroppedPkts - before.DroppedPkts,
+	}
+}
+
+// GetQueueCounters returns the counters of the output queues of the profile
+// on interface intf of dut, keyed by queue name.
+func (p *Profile) GetQueueCounters(t *testing.T, dut *ondatra.DUTDevice, intf string) map[string]*QueueCounters {
+	t.Helper()
+	counters := map[string]*QueueCounters{}
+	for _, c := range p.Classes {
+		q := gnmi.Get(t, dut, gnmi.OC().Qos().Interface(intf).Output().Queue(c.Queue).State())
+		counters[c.Queue] = &QueueCounters{
+			TransmitPkts:   q.GetTransmitPkts(),
+			TransmitOctets: q.GetTransmitOctets(),
+			DroppedPkts:    q.GetDroppedPkts(),
+		}
+	}
+	return counters
+}
+
+// FlowCounters are the OTG packet counters of a flow.
+type FlowCounters struct {
+	TxPkts, RxPkts uint64
+}
+
+// GetFlowCounters returns the OTG counters of the flows of offers, keyed by
+// flow name.
+func GetFlowCounters(t *testing.T, ate *ondatra.ATEDevice, offers ...*Offer) map[string]*FlowCounters {
+	t.Helper()
+	counters := map[string]*FlowCounters{}
+	for _, o := range offers {
+		fc := gnmi.Get(t, ate.OTG(), gnmi.OTG().Flow(o.name()).Counters().State())
+		counters[o.name()] = &FlowCounters{TxPkts: fc.GetOutPkts(), RxPkts: fc.GetInPkts()}
+	}
+	return counters
+}
+
+// QueueDeviation is an observed share of the traffic of a class that differs
+// from the expected share by more than the tolerance.
+type QueueDeviation struct {
+	Class, Queue string
+	// Source is "flows" for OTG flow statistics or "queue" for the output
+	// queue counters of the DUT.
+	Source string
+	// Metric is "transmitted" or "dropped".
+	Metric string
+	// Got and Want are percentages of the packets sent by the ATE.
+	Got, Want float64
+}
+
+func (d *QueueDeviation) String() string {
+	return fmt.Sprintf("class %s (queue %s): %s %s %.2f%%, want %.2f%%", d.Class, d.Queue, d.Source, d.Metric, d.Got, d.Want)
+}
+
+// CompareQueues compares the observed per-class shares of transmitted and
+// dropped traffic with exp, and returns the ones that differ by more than
+// tolerance percentage points. The flow statistics are mapped to classes
+// through offers. queues holds the queue counter deltas over the traffic run
+// keyed by queue name; it may be nil if the DUT does not report them.
+func CompareQueues(exp map[string]*QueueExpectation, queues map[string]*QueueCounters, flows map[string]*FlowCounters, offers []*Offer, tolerance float64) []*QueueDeviation {
+	return compareQueues(exp, queues, flows, offers, tolerance, true)
+}
+
+// compareQueues implements CompareQueues, only comparing the dropped
+// packets of the queues if drops is set.
+func compareQueues(exp map[string]*QueueExpectation, queues map[string]*QueueCounters, flows map[string]*FlowCounters, offers []*Offer, tolerance float64, drops bool) []*QueueDeviation {
+	tx, rx := map[string]uint64{}, map[string]uint64{}
+	for _, o := range offers {
+		if fc, ok := flows[o.name()]; ok {
+			tx[o.Class] += fc.TxPkts
+			rx[o.Class] += fc.RxPkts
+		}
+	}
+	var devs []*QueueDeviation
+	check := func(e *QueueExpectation, source, metric string, got, want float64) {
+		if math.Abs(got-want) > tolerance {
+			devs = append(devs, &QueueDeviation{Class: e.Class, Queue: e.Queue, Source: source, Metric: metric, Got: got, Want: want})
+		}
+	}
+	seen := map[string]bool{}
+	for _, o := range offers {
+		e := exp[o.Class]
+		if e == nil || seen[o.Class] {
+			continue
+		}
+		seen[o.Class] = true
+		sent := float64(tx[o.Class])
+		if sent == 0 {
+			check(e, "flows", "transmitted", 0, e.TransmitShare())
+			continue
+		}
+		check(e, "flows", "transmitted", 100*float64(rx[o.Class])/sent, e.TransmitShare())
+		if q, ok := queues[e.Queue]; ok {
+			check(e, "queue", "transmitted", 100*float64(q.TransmitPkts)/sent, e.TransmitShare())
+			if drops {
+				check(e, "queue", "dropped", 100*float64(q.DroppedPkts)/sent, e.DropShare())
+			}
+		}
+	}
+	return devs
+}
+
+// VerifyQueues checks the traffic of offers against the expected behavior of
+// the output queues of intf at linkRate bits per second, and reports each
+// share off by more than tolerance percentage points as a test error.
+// before holds the queue counters read before the traffic was started and
+// may be nil if the counters were cleared. The deviations are also returned.
+//
+// The queue counters of the DUT are not compared if the DUT does not support
+// reading QoS state, and their dropped packets are not compared if the DUT
+// does not support the queue drop counters.
+func (p *Profile) VerifyQueues(t *testing.T, dut *ondatra.DUTDevice, ate *ondatra.ATEDevice, intf string, before map[string]*QueueCounters, linkRate, tolerance float64, offers ...*Offer) []*QueueDeviation {
+	t.Helper()
+	exp, err := p.Expect(OfferedLoad(linkRate, offers...), linkRate)
+	if err != nil {
+		t.Fatalf("Computing expected queue behavior: %v", err)
+	}
+	var queues map[string]*QueueCounters
+	if !deviations.QosGetStatePathUnsupported(dut) {
+		queues = map[string]*QueueCounters{}
+		for q, c := range p.GetQueueCounters(t, dut, intf) {
+			queues[q] = c.Sub(before[q])
+		}
+	}
+	drops := !deviations.QOSVoqDropCounterUnsupported(dut)
+	devs := compareQueues(exp, queues, GetFlowCounters(t, ate, offers...), offers, tolerance, drops)
+	for _, d := range devs {
+		t.Errorf("Queue deviation on %s: %v", intf, d)
+	}
+	return devs
+}
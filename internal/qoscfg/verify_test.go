@@ -0,0 +1,99 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qoscfg
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/openconfig/featureprofiles/internal/attrs"
+)
+
+func TestExpect(t *testing.T) {
+	p := testProfile()
+	const linkRate = 10e9
+	got, err := p.Expect(map[string]float64{"NC1": 2e9, "AF3": 8e9, "AF2": 8e9}, linkRate)
+	if err != nil {
+		t.Fatalf("Expect() got unexpected error: %v", err)
+	}
+	want := map[string]*QueueExpectation{
+		"NC1": {Class: "NC1", Queue: "NC1", Offered: 2e9, Transmitted: 2e9},
+		"AF3": {Class: "AF3", Queue: "AF3", Offered: 8e9, Transmitted: 6.4e9, Dropped: 1.6e9},
+		"AF2": {Class: "AF2", Queue: "AF2", Offered: 8e9, Transmitted: 1.6e9, Dropped: 6.4e9},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.EquateApprox(0, 1)); diff != "" {
+		t.Errorf("Expect() got unexpected diff (-want +got):\n%s", diff)
+	}
+	if got, want := got["AF2"].DropShare(), 80.0; got != want {
+		t.Errorf("DropShare() got %v, want %v", got, want)
+	}
+
+	if _, err := p.Expect(map[string]float64{"AF9": 1}, linkRate); err == nil {
+		t.Errorf("Expect() with an unknown class got no error, want error")
+	}
+	if _, err := p.Expect(nil, 0); err == nil {
+		t.Errorf("Expect() with no link rate got no error, want error")
+	}
+}
+
+func TestCompareQueues(t *testing.T) {
+	src := &attrs.Attributes{Name: "ate1"}
+	dst := &attrs.Attributes{Name: "ate2"}
+	offers := []*Offer{
+		{Class: "AF3", Src: src, Dst: dst, RatePercent: 60},
+		{Class: "AF3", Name: "af3-v6", Src: src, Dst: dst, RatePercent: 20, IPv6: true},
+		{Class: "AF2", Src: src, Dst: dst, RatePercent: 80},
+		{Class: "BE1", Src: src, Dst: dst, RatePercent: 1},
+	}
+	p := testProfile()
+	exp, err := p.Expect(OfferedLoad(1e9, offers...), 1e9)
+	if err != nil {
+		t.Fatalf("Expect() got unexpected error: %v", err)
+	}
+	flows := map[string]*FlowCounters{
+		"ate1-AF3": {TxPkts: 6000, RxPkts: 4700},
+		"af3-v6":   {TxPkts: 2000, RxPkts: 1600},
+		"ate1-AF2": {TxPkts: 8000, RxPkts: 1500},
+		// BE1 sent nothing.
+	}
+	queues := map[string]*QueueCounters{
+		"AF3": {TransmitPkts: 6300, DroppedPkts: 1700},
+		"AF2": {TransmitPkts: 1500, DroppedPkts: 6500},
+	}
+	got := CompareQueues(exp, queues, flows, offers, 2)
+	// AF3 and AF2 share 99% of the link 80/20 after BE1 gets its 1%: AF3
+	// transmits 79.2/80 = 99% and AF2 19.8/80 = 24.75%.
+	want := []*QueueDeviation{
+		{Class: "AF3", Queue: "AF3", Source: "flows", Metric: "transmitted", Got: 78.75, Want: 99},
+		{Class: "AF3", Queue: "AF3", Source: "queue", Metric: "transmitted", Got: 78.75, Want: 99},
+		{Class: "AF3", Queue: "AF3", Source: "queue", Metric: "dropped", Got: 21.25, Want: 1},
+		{Class: "AF2", Queue: "AF2", Source: "flows", Metric: "transmitted", Got: 18.75, Want: 24.75},
+		{Class: "AF2", Queue: "AF2", Source: "queue", Metric: "transmitted", Got: 18.75, Want: 24.75},
+		{Class: "AF2", Queue: "AF2", Source: "queue", Metric: "dropped", Got: 81.25, Want: 75.25},
+		{Class: "BE1", Queue: "BE1", Source: "flows", Metric: "transmitted", Got: 0, Want: 100},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("CompareQueues() got unexpected diff (-want +got):\n%s", diff)
+	}
+	if got := CompareQueues(exp, nil, flows, offers, 30); len(got) != 1 {
+		t.Errorf("CompareQueues() with a 30%% tolerance got %v, want only the BE1 deviation", got)
+	}
+	for _, d := range compareQueues(exp, queues, flows, offers, 2, false) {
+		if d.Metric == "dropped" {
+			t.Errorf("compareQueues() without drops got deviation %v, want no dropped deviation", d)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package acctz provides helper APIs to simplify writing gNSI acctz test
+// cases: a collector that buffers the accounting records of a device, and
+// assertions that the operations a test performed were accounted for.
+package acctz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openconfig/featureprofiles/internal/security/gnxi"
+	"github.com/openconfig/ondatra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	acctzpb "github.com/openconfig/gnsi/acctz"
+	tspb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Collector buffers the accounting records streamed by a device.
+type Collector struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu      sync.Mutex
+	records []*acctzpb.RecordResponse
+	err     error
+	// updated is closed and replaced whenever a record is buffered.
+	updated chan struct{}
+}
+
+// timestamp returns the request timestamp for records after since. The zero
+// time requests the whole history of the device.
+func timestamp(since time.Time) *tspb.Timestamp {
+	if since.IsZero() {
+		return &tspb.Timestamp{}
+	}
+	return tspb.New(since)
+}
+
+func newCollector(cancel context.CancelFunc, recv func() (*acctzpb.RecordResponse, error)) *Collector {
+	c := &Collector{
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		updated: make(chan struct{}),
+	}
+	go c.run(recv)
+	return c
+}
+
+func (c *Collector) run(recv func() (*acctzpb.RecordResponse, error)) {
+	defer close(c.done)
+	for {
+		r, err := recv()
+		c.mu.Lock()
+		if err != nil {
+			if !errors.Is(err, io.EOF) && status.Code(err) != codes.Canceled {
+				c.err = err
+			}
+			c.mu.Unlock()
+			return
+		}
+		c.records = append(c.records, r)
+		close(c.updated)
+		c.updated = make(chan struct{})
+		c.mu.Unlock()
+	}
+}
+
+// Subscribe starts collecting the records after since with the Acctz
+// service of client.
+func Subscribe(ctx context.Context, client acctzpb.AcctzClient, since time.Time) (*Collector, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := client.RecordSubscribe(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if err := stream.Send(&acctzpb.RecordRequest{Timestamp: timestamp(since)}); err != nil {
+		cancel()
+		return nil, err
+	}
+	return newCollector(cancel, stream.Recv), nil
+}
+
+// SubscribeStream starts collecting the records after since with the
+// AcctzStream service of client.
+func SubscribeStream(ctx context.Context, client acctzpb.AcctzStreamClient, since time.Time) (*Collector, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := client.RecordSubscribe(ctx, &acctzpb.RecordRequest{Timestamp: timestamp(since)})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return newCollector(cancel, stream.Recv), nil
+}
+
+// Collect starts collecting the records of device dut after since, and
+// stops when the test ends. It fails the test on error.
+func Collect(t testing.TB, dut *ondatra.DUTDevice, since time.Time) *Collector {
+	t.Helper()
+	gnsiC, err := dut.RawAPIs().BindingDUT().DialGNSI(context.Background())
+	if err != nil {
+		t.Fatalf("Could not connect gnsi %v", err)
+	}
+	c, err := Subscribe(context.Background(), gnsiC.Acctz(), since)
+	if err != nil {
+		t.Fatalf("Could not subscribe to accounting records of device %s: %v", dut.Name(), err)
+	}
+	t.Cleanup(func() {
+		if err := c.Stop(); err != nil {
+			t.Errorf("Accounting record subscription of device %s failed: %v", dut.Name(), err)
+		}
+	})
+	return c
+}
+
+// Records returns the records buffered so far.
+func (c *Collector) Records() []*acctzpb.RecordResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*acctzpb.RecordResponse(nil), c.records...)
+}
+
+// Stop ends the subscription and returns the error that ended it early, if
+// any. The buffered records remain available.
+func (c *Collector) Stop() error {
+	c.cancel()
+	<-c.done
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Want describes a record expected for an operation.
+type Want struct {
+	// User is the identity the operation is attributed to.
+	User string
+	// GRPCService and RPC describe a gRPC operation, RPC being the path of
+	// the RPC, such as /gnmi.gNMI/Get.
+	GRPCService acctzpb.GrpcService_GrpcServiceType
+	RPC         string
+	// CmdService and Cmd describe a command operation, Cmd being the command
+	// with its arguments.
+	CmdService acctzpb.CommandService_CmdServiceType
+	Cmd        string
+	// Authz is the authorization status of the operation, any if unset.
+	Authz acctzpb.AuthzDetail_AuthzStatus
+}
+
+// grpcServiceType returns the acctz service type of the gRPC service
+// named service.
+func grpcServiceType(service string) acctzpb.GrpcService_GrpcServiceType {
+	switch strings.SplitN(service, ".", 2)[0] {
+	case "gnmi":
+		return acctzpb.GrpcService_GRPC_SERVICE_TYPE_GNMI
+	case "gnoi":
+		return acctzpb.GrpcService_GRPC_SERVICE_TYPE_GNOI
+	case "gnsi":
+		return acctzpb.GrpcService_GRPC_SERVICE_TYPE_GNSI
+	case "gribi":
+		return acctzpb.GrpcService_GRPC_SERVICE_TYPE_GRIBI
+	case "p4":
+		return acctzpb.GrpcService_GRPC_SERVICE_TYPE_P4RT
+	}
+	return acctzpb.GrpcService_GRPC_SERVICE_TYPE_UNSPECIFIED
+}
+
+// GRPC returns the record expected when user calls rpc.
+func GRPC(user string, rpc *gnxi.RPC, authz acctzpb.AuthzDetail_AuthzStatus) *Want {
+	return &Want{User: user, GRPCService: grpcServiceType(rpc.Service), RPC: rpc.Path, Authz: authz}
+}
+
+// CLI returns the record expected when user runs the CLI command cmd.
+func CLI(user, cmd string, authz acctzpb.AuthzDetail_AuthzStatus) *Want {
+	return &Want{User: user, CmdService: acctzpb.CommandService_CMD_SERVICE_TYPE_CLI, Cmd: cmd, Authz: authz}
+}
+
+func (w *Want) String() string {
+	var op string
+	if w.Cmd != "" {
+		op = fmt.Sprintf("%v command %q", w.CmdService, w.Cmd)
+	} else {
+		op = fmt.Sprintf("%v rpc %s", w.GRPCService, w.RPC)
+	}
+	if w.Authz == acctzpb.AuthzDetail_AUTHZ_STATUS_UNSPECIFIED {
+		return fmt.Sprintf("%s by %s", op, w.User)
+	}
+	return fmt.Sprintf("%s by %s with %v", op, w.User, w.Authz)
+}
+
+// matchCmd reports whether the command of record cs, with its arguments,
+// is cmd. Whitespace is not significant, and truncated commands match on
+// their prefix.
+func matchCmd(cs *acctzpb.CommandService, cmd string) bool {
+	want := strings.Join(strings.Fields(cmd), " ")
+	got := strings.Join(strings.Fields(strings.Join(append([]string{cs.GetCmd()}, cs.GetCmdArgs()...), " ")), " ")
+	if cs.GetCmdIstruncated() || cs.GetCmdArgsIstruncated() {
+		return got != "" && strings.HasPrefix(want, got)
+	}
+	return got == want
+}
+
+// Match reports whether record r is the expected record.
+func (w *Want) Match(r *acctzpb.RecordResponse) bool {
+	if r.GetSessionInfo().GetUser().GetIdentity() != w.User {
+		return false
+	}
+	var authz *acctzpb.AuthzDetail
+	if w.Cmd != "" {
+		cs := r.GetCmdService()
+		if cs == nil || cs.GetServiceType() != w.CmdService || !matchCmd(cs, w.Cmd) {
+			return false
+		}
+		authz = cs.GetAuthz()
+	} else {
+		gs := r.GetGrpcService()
+		if gs == nil || gs.GetServiceType() != w.GRPCService || gs.GetRpcName() != w.RPC {
+			return false
+		}
+		authz = gs.GetAuthz()
+	}
+	return w.Authz == acctzpb.AuthzDetail_AUTHZ_STATUS_UNSPECIFIED || authz.GetStatus() == w.Authz
+}
+
+// assign matches the wanted records to distinct records, in order, and
+// returns the matched records and the wanted records left unmatched.
+func assign(records []*acctzpb.RecordResponse, wants []*Want) ([]*acctzpb.RecordResponse, []*Want) {
+	used := make([]bool, len(records))
+	var matched []*acctzpb.RecordResponse
+	var missing []*Want
+	for _, w := range wants {
+		found := false
+		for i, r := range records {
+			if !used[i] && w.Match(r) {
+				used[i], found = true, true
+				matched = append(matched, r)
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, w)
+		}
+	}
+	return matched, missing
+}
+
+// Wait waits until every wanted record is buffered, each matching a
+// distinct record, and returns the matched records. It returns the wanted
+// records still missing with an error when ctx is done or the subscription
+// ends first.
+func (c *Collector) Wait(ctx context.Context, wants ...*Want) ([]*acctzpb.RecordResponse, []*Want, error) {
+	for {
+		c.mu.Lock()
+		matched, missing := assign(c.records, wants)
+		updated := c.updated
+		c.mu.Unlock()
+		if len(missing) == 0 {
+			return matched, nil, nil
+		}
+		select {
+		case <-updated:
+		case <-c.done:
+			c.mu.Lock()
+			matched, missing = assign(c.records, wants)
+			err := c.err
+			c.mu.Unlock()
+			if len(missing) == 0 {
+				return matched, nil, nil
+			}
+			if err == nil {
+				err = errors.New("accounting record subscription ended")
+			}
+			return matched, missing, err
+		case <-ctx.Done():
+			return matched, missing, ctx.Err()
+		}
+	}
+}
+
+// Expect waits up to timeout for the wanted records and reports an error
+// for every one that is missing. It returns the matched records.
+func (c *Collector) Expect(t testing.TB, timeout time.Duration, wants ...*Want) []*acctzpb.RecordResponse {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	matched, missing, err := c.Wait(ctx, wants...)
+	if len(missing) == 0 {
+		return matched
+	}
+	for _, w := range missing {
+		t.Errorf("No accounting record for %v: %v", w, err)
+	}
+	for _, r := range c.Records() {
+		t.Logf("Accounting record: %v", r)
+	}
+	return matched
+}
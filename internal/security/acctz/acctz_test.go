@@ -0,0 +1,234 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acctz
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/featureprofiles/internal/security/gnxi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	acctzpb "github.com/openconfig/gnsi/acctz"
+	tspb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	permit = acctzpb.AuthzDetail_AUTHZ_STATUS_PERMIT
+	deny   = acctzpb.AuthzDetail_AUTHZ_STATUS_DENY
+)
+
+func grpcRecord(user string, at time.Time, rpc *gnxi.RPC, status acctzpb.AuthzDetail_AuthzStatus) *acctzpb.RecordResponse {
+	return &acctzpb.RecordResponse{
+		SessionInfo: &acctzpb.SessionInfo{User: &acctzpb.UserDetail{Identity: user}},
+		Timestamp:   tspb.New(at),
+		ServiceRequest: &acctzpb.RecordResponse_GrpcService{GrpcService: &acctzpb.GrpcService{
+			ServiceType: grpcServiceType(rpc.Service),
+			RpcName:     rpc.Path,
+			Authz:       &acctzpb.AuthzDetail{Status: status},
+		}},
+	}
+}
+
+func cliRecord(user string, at time.Time, cmd string, args []string, status acctzpb.AuthzDetail_AuthzStatus) *acctzpb.RecordResponse {
+	return &acctzpb.RecordResponse{
+		SessionInfo: &acctzpb.SessionInfo{User: &acctzpb.UserDetail{Identity: user}},
+		Timestamp:   tspb.New(at),
+		ServiceRequest: &acctzpb.RecordResponse_CmdService{CmdService: &acctzpb.CommandService{
+			ServiceType: acctzpb.CommandService_CMD_SERVICE_TYPE_CLI,
+			Cmd:         cmd,
+			CmdArgs:     args,
+			Authz:       &acctzpb.AuthzDetail{Status: status},
+		}},
+	}
+}
+
+func dial(t *testing.T, addr string) *grpc.ClientConn {
+	t.Helper()
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("grpc.Dial(%s) failed: %v", addr, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestCollector(t *testing.T) {
+	since := time.Now()
+	before := cliRecord("alice", since.Add(-time.Minute), "show version", nil, permit)
+	get := grpcRecord("alice", since.Add(time.Second), gnxi.RPCs.GnmiGet, permit)
+	set := grpcRecord("bob", since.Add(2*time.Second), gnxi.RPCs.GnmiSet, deny)
+	cli := cliRecord("alice", since.Add(3*time.Second), "show", []string{"running-config"}, permit)
+
+	subscribers := []struct {
+		desc      string
+		subscribe func(context.Context, *grpc.ClientConn) (*Collector, error)
+	}{{
+		desc: "Acctz",
+		subscribe: func(ctx context.Context, conn *grpc.ClientConn) (*Collector, error) {
+			return Subscribe(ctx, acctzpb.NewAcctzClient(conn), since)
+		},
+	}, {
+		desc: "AcctzStream",
+		subscribe: func(ctx context.Context, conn *grpc.ClientConn) (*Collector, error) {
+			return SubscribeStream(ctx, acctzpb.NewAcctzStreamClient(conn), since)
+		},
+	}}
+	for _, s := range subscribers {
+		t.Run(s.desc, func(t *testing.T) {
+			fake := NewFake()
+			fake.Add(before, get)
+			c, err := s.subscribe(context.Background(), dial(t, fake.Start(t)))
+			if err != nil {
+				t.Fatalf("Subscribe failed: %v", err)
+			}
+			fake.Add(set, cli)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			wants := []*Want{
+				CLI("alice", "show  running-config", permit),
+				GRPC("bob", gnxi.RPCs.GnmiSet, deny),
+				GRPC("alice", gnxi.RPCs.GnmiGet, acctzpb.AuthzDetail_AUTHZ_STATUS_UNSPECIFIED),
+			}
+			matched, missing, err := c.Wait(ctx, wants...)
+			if err != nil || len(missing) != 0 {
+				t.Fatalf("Wait() got missing %v, error %v, want none", missing, err)
+			}
+			want := []*acctzpb.RecordResponse{cli, set, get}
+			if diff := cmp.Diff(want, matched, protocmp.Transform()); diff != "" {
+				t.Errorf("Wait() returned diff (-want +got):\n%s", diff)
+			}
+
+			if err := c.Stop(); err != nil {
+				t.Errorf("Stop() failed: %v", err)
+			}
+			if got := len(c.Records()); got != 3 {
+				t.Errorf("Records after stop: got %d, want 3", got)
+			}
+		})
+	}
+}
+
+func TestWaitMissing(t *testing.T) {
+	since := time.Now()
+	fake := NewFake()
+	fake.Add(grpcRecord("alice", since.Add(time.Second), gnxi.RPCs.GnmiGet, permit))
+	c, err := Subscribe(context.Background(), acctzpb.NewAcctzClient(dial(t, fake.Start(t))), since)
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	defer c.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	wants := []*Want{
+		GRPC("alice", gnxi.RPCs.GnmiGet, permit),
+		// A second operation needs a second record.
+		GRPC("alice", gnxi.RPCs.GnmiGet, permit),
+		GRPC("alice", gnxi.RPCs.GnmiGet, deny),
+	}
+	_, missing, err := c.Wait(ctx, wants...)
+	if err == nil {
+		t.Errorf("Wait() got nil error, want error")
+	}
+	if diff := cmp.Diff(wants[1:], missing); diff != "" {
+		t.Errorf("Wait() returned missing diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	at := time.Now()
+	tests := []struct {
+		desc   string
+		want   *Want
+		record *acctzpb.RecordResponse
+		match  bool
+	}{{
+		desc:   "grpc",
+		want:   GRPC("alice", gnxi.RPCs.GnoiSystemTime, permit),
+		record: grpcRecord("alice", at, gnxi.RPCs.GnoiSystemTime, permit),
+		match:  true,
+	}, {
+		desc:   "other user",
+		want:   GRPC("alice", gnxi.RPCs.GnoiSystemTime, permit),
+		record: grpcRecord("bob", at, gnxi.RPCs.GnoiSystemTime, permit),
+	}, {
+		desc:   "other rpc",
+		want:   GRPC("alice", gnxi.RPCs.GnoiSystemTime, permit),
+		record: grpcRecord("alice", at, gnxi.RPCs.GnoiSystemPing, permit),
+	}, {
+		desc:   "other status",
+		want:   GRPC("alice", gnxi.RPCs.GnoiSystemTime, permit),
+		record: grpcRecord("alice", at, gnxi.RPCs.GnoiSystemTime, deny),
+	}, {
+		desc:   "cli for grpc",
+		want:   GRPC("alice", gnxi.RPCs.GnoiSystemTime, permit),
+		record: cliRecord("alice", at, "show clock", nil, permit),
+	}, {
+		desc:   "cli with args",
+		want:   CLI("alice", "show interfaces Ethernet1", permit),
+		record: cliRecord("alice", at, "show", []string{"interfaces", "Ethernet1"}, permit),
+		match:  true,
+	}, {
+		desc:   "cli in cmd",
+		want:   CLI("alice", "show interfaces Ethernet1", permit),
+		record: cliRecord("alice", at, "show interfaces Ethernet1", nil, permit),
+		match:  true,
+	}, {
+		desc:   "other cli",
+		want:   CLI("alice", "show interfaces Ethernet1", permit),
+		record: cliRecord("alice", at, "show interfaces Ethernet2", nil, permit),
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := tt.want.Match(tt.record); got != tt.match {
+				t.Errorf("Match(): got %t, want %t", got, tt.match)
+			}
+		})
+	}
+}
+
+func TestMatchCmdTruncated(t *testing.T) {
+	cs := &acctzpb.CommandService{Cmd: "show running-config sec", CmdIstruncated: true}
+	if !matchCmd(cs, "show running-config section bgp") {
+		t.Errorf("matchCmd() of truncated command: got false, want true")
+	}
+	if matchCmd(cs, "show version") {
+		t.Errorf("matchCmd() of other command: got true, want false")
+	}
+}
+
+func TestGRPCServiceType(t *testing.T) {
+	tests := []struct {
+		rpc  *gnxi.RPC
+		want acctzpb.GrpcService_GrpcServiceType
+	}{
+		{gnxi.RPCs.GnmiGet, acctzpb.GrpcService_GRPC_SERVICE_TYPE_GNMI},
+		{gnxi.RPCs.GnoiSystemTime, acctzpb.GrpcService_GRPC_SERVICE_TYPE_GNOI},
+		{gnxi.RPCs.GnsiAuthzGet, acctzpb.GrpcService_GRPC_SERVICE_TYPE_GNSI},
+		{gnxi.RPCs.GribiGet, acctzpb.GrpcService_GRPC_SERVICE_TYPE_GRIBI},
+		{gnxi.RPCs.P4P4runtimeRead, acctzpb.GrpcService_GRPC_SERVICE_TYPE_P4RT},
+	}
+	for _, tt := range tests {
+		if got := GRPC("alice", tt.rpc, permit).GRPCService; got != tt.want {
+			t.Errorf("GRPC(%s) service: got %v, want %v", tt.rpc.Path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acctz
+
+import (
+	"net"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	acctzpb "github.com/openconfig/gnsi/acctz"
+)
+
+// Fake is a local acctz server, serving both the Acctz and the AcctzStream
+// services, for unit tests. Like a device, it streams the records of its
+// history after the requested timestamp, then the records added while the
+// subscription lasts.
+type Fake struct {
+	acctzpb.UnimplementedAcctzServer
+
+	mu      sync.Mutex
+	history []*acctzpb.RecordResponse
+	// added is closed and replaced whenever a record is added.
+	added chan struct{}
+}
+
+// NewFake returns a fake server with an empty history.
+func NewFake() *Fake {
+	return &Fake{added: make(chan struct{})}
+}
+
+// Add appends records to the history of the fake and streams them to the
+// subscribers.
+func (f *Fake) Add(records ...*acctzpb.RecordResponse) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.history = append(f.history, records...)
+	close(f.added)
+	f.added = make(chan struct{})
+}
+
+// Start serves the fake on a local port until the test ends, and returns
+// the address of the server.
+func (f *Fake) Start(t testing.TB) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Could not listen: %v", err)
+	}
+	srv := grpc.NewServer()
+	acctzpb.RegisterAcctzServer(srv, f)
+	acctzpb.RegisterAcctzStreamServer(srv, fakeStream{f: f})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+// stream sends the records after the timestamp of req until done is closed
+// or send fails.
+func (f *Fake) stream(req *acctzpb.RecordRequest, done <-chan struct{}, send func(*acctzpb.RecordResponse) error) error {
+	since := req.GetTimestamp().AsTime()
+	next := 0
+	for {
+		f.mu.Lock()
+		records := f.history[next:]
+		next = len(f.history)
+		added := f.added
+		f.mu.Unlock()
+		for _, r := range records {
+			if !r.GetTimestamp().AsTime().After(since) {
+				continue
+			}
+			if err := send(proto.Clone(r).(*acctzpb.RecordResponse)); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-added:
+		case <-done:
+			return nil
+		}
+	}
+}
+
+// RecordSubscribe implements the Acctz service.
+func (f *Fake) RecordSubscribe(stream acctzpb.Acctz_RecordSubscribeServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	return f.stream(req, stream.Context().Done(), stream.Send)
+}
+
+// fakeStream adapts the AcctzStream service to the Fake, whose
+// RecordSubscribe method implements the Acctz service.
+type fakeStream struct {
+	acctzpb.UnimplementedAcctzStreamServer
+	f *Fake
+}
+
+// RecordSubscribe implements the AcctzStream service.
+func (s fakeStream) RecordSubscribe(req *acctzpb.RecordRequest, stream acctzpb.AcctzStream_RecordSubscribeServer) error {
+	return s.f.stream(req, stream.Context().Done(), stream.Send)
+}
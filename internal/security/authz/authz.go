@@ -22,11 +22,9 @@ import (
 
 	"crypto/tls"
 	"encoding/json"
-	"os"
 	"testing"
 	"time"
 
-	"github.com/google/go-cmp/cmp"
 	"github.com/openconfig/featureprofiles/internal/security/gnxi"
 	"github.com/openconfig/ondatra"
 	"google.golang.org/grpc"
@@ -135,8 +133,8 @@ func (p *AuthorizationPolicy) Rotate(t *testing.T, dut *ondatra.DUTDevice, creat
 	}
 	// validate Result
 	_, tempPolicy := Get(t, dut)
-	if !cmp.Equal(p, tempPolicy) {
-		t.Fatalf("Policy after upload (temporary) is not the same as the one upload, diff is: %v", cmp.Diff(p, tempPolicy))
+	if d := Diff(p, tempPolicy); !d.Empty() {
+		t.Fatalf("Policy after upload (temporary) is not the same as the one upload, diff is:\n%v", d)
 	}
 	finalizeRotateReq := &authzpb.RotateAuthzRequest_FinalizeRotation{FinalizeRotation: &authzpb.FinalizeRequest{}}
 	err = rotateStream.Send(&authzpb.RotateAuthzRequest{RotateRequest: finalizeRotateReq})
@@ -145,8 +143,8 @@ func (p *AuthorizationPolicy) Rotate(t *testing.T, dut *ondatra.DUTDevice, creat
 		t.Fatalf("Error while finalizing rotate request  %v", err)
 	}
 	_, finalPolicy := Get(t, dut)
-	if !cmp.Equal(p, finalPolicy) {
-		t.Fatalf("Policy after upload (temporary) is not the same as the one upload, diff is: %v", cmp.Diff(p, finalPolicy))
+	if d := Diff(p, finalPolicy); !d.Empty() {
+		t.Fatalf("Policy after finalize is not the same as the one upload, diff is:\n%v", d)
 	}
 
 }
@@ -260,17 +258,9 @@ func Verify(t testing.TB, dut *ondatra.DUTDevice, spiffe *Spiffe, rpc *gnxi.RPC,
 
 // LoadPolicyFromJSONFile Loads Policy from a JSON File.
 func LoadPolicyFromJSONFile(t *testing.T, filePath string) map[string]AuthorizationPolicy {
-	file, err := os.Open(filePath)
+	policies, err := ReadPolicyFile(filePath)
 	if err != nil {
-		t.Fatalf("Not expecting error while opening policy file %v", err)
-	}
-	defer file.Close()
-
-	decoder := json.NewDecoder(file)
-	var policies []AuthorizationPolicy
-	err1 := decoder.Decode(&policies)
-	if err1 != nil {
-		t.Fatalf("Not expecting error while decoding policy %v", err)
+		t.Fatalf("Not expecting error while loading policy file %v", err)
 	}
 	policyMap := map[string]AuthorizationPolicy{}
 	for _, policy := range policies {
@@ -0,0 +1,190 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authz
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/openconfig/featureprofiles/internal/security/gnxi"
+	"github.com/openconfig/ondatra"
+
+	authzpb "github.com/openconfig/gnsi/authz"
+)
+
+// matchPattern reports whether s matches pattern using the string matching
+// of gRPC authorization policies: "*" matches anything, a leading or
+// trailing "*" matches a suffix or a prefix, anything else matches exactly.
+func matchPattern(pattern, s string) bool {
+	switch {
+	case pattern == "*":
+		return true
+	case strings.HasSuffix(pattern, "*"):
+		return strings.HasPrefix(s, strings.TrimSuffix(pattern, "*"))
+	case strings.HasPrefix(pattern, "*"):
+		return strings.HasSuffix(s, strings.TrimPrefix(pattern, "*"))
+	}
+	return pattern == s
+}
+
+func matchAny(patterns []string, s string) bool {
+	// An empty list in a rule matches everything.
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if matchPattern(p, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether rule r applies to a call of the RPC at path by
+// principal.
+func (r *Rule) Matches(principal, path string) bool {
+	return matchAny(r.Source.Principals, principal) && matchAny(r.Request.Paths, path)
+}
+
+// Evaluate returns the action the policy is expected to take on a call of
+// the RPC at path by principal, with the name of the rule that decided it.
+// Deny rules are evaluated before allow rules, and calls that match no allow
+// rule are denied with an empty rule name.
+func (p *AuthorizationPolicy) Evaluate(principal, path string) (authzpb.ProbeResponse_Action, string) {
+	for _, r := range p.DenyRules {
+		if r.Matches(principal, path) {
+			return authzpb.ProbeResponse_ACTION_DENY, r.Name
+		}
+	}
+	for _, r := range p.AllowRules {
+		if r.Matches(principal, path) {
+			return authzpb.ProbeResponse_ACTION_PERMIT, r.Name
+		}
+	}
+	return authzpb.ProbeResponse_ACTION_DENY, ""
+}
+
+// ConcreteRPCs returns the RPCs of gnxi.RPCMAP that are not wildcards, sorted
+// by path.
+func ConcreteRPCs() []*gnxi.RPC {
+	var rpcs []*gnxi.RPC
+	for _, rpc := range gnxi.RPCMAP {
+		if !strings.HasSuffix(rpc.Path, "*") {
+			rpcs = append(rpcs, rpc)
+		}
+	}
+	sort.Slice(rpcs, func(i, j int) bool { return rpcs[i].Path < rpcs[j].Path })
+	return rpcs
+}
+
+// Matrix holds the action taken on each RPC path for each principal.
+type Matrix map[string]map[string]authzpb.ProbeResponse_Action
+
+func (m Matrix) set(principal, path string, action authzpb.ProbeResponse_Action) {
+	if m[principal] == nil {
+		m[principal] = map[string]authzpb.ProbeResponse_Action{}
+	}
+	m[principal][path] = action
+}
+
+// ExpectedMatrix evaluates the policy for every principal and RPC.
+func (p *AuthorizationPolicy) ExpectedMatrix(principals []string, rpcs []*gnxi.RPC) Matrix {
+	m := Matrix{}
+	for _, principal := range principals {
+		for _, rpc := range rpcs {
+			action, _ := p.Evaluate(principal, rpc.Path)
+			m.set(principal, rpc.Path, action)
+		}
+	}
+	return m
+}
+
+// ProbeMatrix asks dut through Authz.Probe for the action taken on every
+// principal and RPC.
+func ProbeMatrix(t testing.TB, dut *ondatra.DUTDevice, principals []string, rpcs []*gnxi.RPC) Matrix {
+	t.Helper()
+	gnsiC, err := dut.RawAPIs().BindingDUT().DialGNSI(context.Background())
+	if err != nil {
+		t.Fatalf("Could not connect gnsi %v", err)
+	}
+	m := Matrix{}
+	for _, principal := range principals {
+		for _, rpc := range rpcs {
+			req := &authzpb.ProbeRequest{User: principal, Rpc: rpc.Path}
+			resp, err := gnsiC.Authz().Probe(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Prob Request %s failed on dut %s: %v", prettyPrint(req), dut.Name(), err)
+			}
+			m.set(principal, rpc.Path, resp.GetAction())
+		}
+	}
+	return m
+}
+
+// Mismatch is a principal and RPC for which the action taken differs from
+// the expected one.
+type Mismatch struct {
+	Principal, Path string
+	Want, Got       authzpb.ProbeResponse_Action
+	// Rule is the name of the rule that decided the expected action, if any.
+	Rule string
+}
+
+func (m *Mismatch) String() string {
+	rule := "no rule"
+	if m.Rule != "" {
+		rule = "rule " + m.Rule
+	}
+	return fmt.Sprintf("user %s, path %s: got %v, want %v (%s)", m.Principal, m.Path, m.Got, m.Want, rule)
+}
+
+// Compare returns the entries of got that differ from the expectation of
+// the policy, sorted by principal and path.
+func (p *AuthorizationPolicy) Compare(got Matrix) []*Mismatch {
+	var mismatches []*Mismatch
+	for principal, paths := range got {
+		for path, action := range paths {
+			want, rule := p.Evaluate(principal, path)
+			if action != want {
+				mismatches = append(mismatches, &Mismatch{Principal: principal, Path: path, Want: want, Got: action, Rule: rule})
+			}
+		}
+	}
+	sort.Slice(mismatches, func(i, j int) bool {
+		if mismatches[i].Principal != mismatches[j].Principal {
+			return mismatches[i].Principal < mismatches[j].Principal
+		}
+		return mismatches[i].Path < mismatches[j].Path
+	})
+	return mismatches
+}
+
+// VerifyMatrix probes dut for every principal and RPC, by default every
+// concrete RPC, and reports each result that differs from the expectation
+// of policy p as a test error. The mismatches are also returned.
+func (p *AuthorizationPolicy) VerifyMatrix(t testing.TB, dut *ondatra.DUTDevice, principals []string, rpcs ...*gnxi.RPC) []*Mismatch {
+	t.Helper()
+	if len(rpcs) == 0 {
+		rpcs = ConcreteRPCs()
+	}
+	mismatches := p.Compare(ProbeMatrix(t, dut, principals, rpcs))
+	for _, m := range mismatches {
+		t.Errorf("Probe result of policy %s on dut %s is unexpected: %v", p.Name, dut.Name(), m)
+	}
+	return mismatches
+}
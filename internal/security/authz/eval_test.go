@@ -0,0 +1,108 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authz
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/featureprofiles/internal/security/gnxi"
+
+	authzpb "github.com/openconfig/gnsi/authz"
+)
+
+const (
+	permit = authzpb.ProbeResponse_ACTION_PERMIT
+	deny   = authzpb.ProbeResponse_ACTION_DENY
+)
+
+func testPolicy() *AuthorizationPolicy {
+	p := NewAuthorizationPolicy("test")
+	p.AddAllowRules("gnmi-all", []string{"spiffe://test-abc.foo.bar/xyz/admin", "spiffe://test-abc.foo.bar/xyz/read*"}, []*gnxi.RPC{gnxi.RPCs.GnmiAllRPC})
+	p.AddAllowRules("admin-all", []string{"spiffe://test-abc.foo.bar/xyz/admin"}, []*gnxi.RPC{gnxi.RPCs.AllRPC})
+	p.AddDenyRules("no-set", []string{"spiffe://test-abc.foo.bar/xyz/reader"}, []*gnxi.RPC{gnxi.RPCs.GnmiSet})
+	p.AddDenyRules("no-reboot", nil, []*gnxi.RPC{gnxi.RPCs.GnoiSystemReboot})
+	return p
+}
+
+func TestEvaluate(t *testing.T) {
+	p := testPolicy()
+	tests := []struct {
+		principal, path string
+		want            authzpb.ProbeResponse_Action
+		wantRule        string
+	}{
+		{"spiffe://test-abc.foo.bar/xyz/admin", "/gnmi.gNMI/Set", permit, "gnmi-all"},
+		{"spiffe://test-abc.foo.bar/xyz/admin", "/gnoi.file.File/Get", permit, "admin-all"},
+		{"spiffe://test-abc.foo.bar/xyz/admin", "/gnoi.system.System/Reboot", deny, "no-reboot"},
+		{"spiffe://test-abc.foo.bar/xyz/reader", "/gnmi.gNMI/Get", permit, "gnmi-all"},
+		{"spiffe://test-abc.foo.bar/xyz/reader", "/gnmi.gNMI/Set", deny, "no-set"},
+		{"spiffe://test-abc.foo.bar/xyz/readonly", "/gnmi.gNMI/Set", permit, "gnmi-all"},
+		{"spiffe://test-abc.foo.bar/xyz/reader", "/gribi.gRIBI/Get", deny, ""},
+		{"spiffe://test-abc.foo.bar/xyz/other", "/gnmi.gNMI/Get", deny, ""},
+	}
+	for _, tt := range tests {
+		got, rule := p.Evaluate(tt.principal, tt.path)
+		if got != tt.want || rule != tt.wantRule {
+			t.Errorf("Evaluate(%s, %s) got (%v, %q), want (%v, %q)", tt.principal, tt.path, got, rule, tt.want, tt.wantRule)
+		}
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"*", "/gnmi.gNMI/Get", true},
+		{"/gnmi.gNMI/*", "/gnmi.gNMI/Get", true},
+		{"/gnmi.gNMI/*", "/gnoi.file.File/Get", false},
+		{"*/admin", "spiffe://x/admin", true},
+		{"/gnmi.gNMI/Get", "/gnmi.gNMI/GetX", false},
+	}
+	for _, tt := range tests {
+		if got := matchPattern(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("matchPattern(%q, %q) got %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	p := testPolicy()
+	principals := []string{"spiffe://test-abc.foo.bar/xyz/admin", "spiffe://test-abc.foo.bar/xyz/reader"}
+	rpcs := ConcreteRPCs()
+	for _, rpc := range rpcs {
+		if rpc.Path == "*" || rpc.Name == "*" {
+			t.Fatalf("ConcreteRPCs() got wildcard RPC %s", rpc.Path)
+		}
+	}
+	got := p.ExpectedMatrix(principals, rpcs)
+	if n := len(got[principals[1]]); n != len(rpcs) {
+		t.Fatalf("ExpectedMatrix() got %d RPCs for %s, want %d", n, principals[1], len(rpcs))
+	}
+	if m := p.Compare(got); len(m) != 0 {
+		t.Errorf("Compare() of the expected matrix got mismatches %v", m)
+	}
+
+	got[principals[1]]["/gnmi.gNMI/Set"] = permit
+	got[principals[0]]["/gnoi.file.File/Get"] = deny
+	want := []*Mismatch{
+		{Principal: principals[0], Path: "/gnoi.file.File/Get", Want: permit, Got: deny, Rule: "admin-all"},
+		{Principal: principals[1], Path: "/gnmi.gNMI/Set", Want: deny, Got: permit, Rule: "no-set"},
+	}
+	if diff := cmp.Diff(want, p.Compare(got)); diff != "" {
+		t.Errorf("Compare() got unexpected diff (-want +got):\n%s", diff)
+	}
+}
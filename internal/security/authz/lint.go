@@ -0,0 +1,299 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authz
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/featureprofiles/internal/security/gnxi"
+)
+
+// ReadPolicies decodes a JSON list of policies, as found in the policy files
+// of the authz tests.
+func ReadPolicies(r io.Reader) ([]AuthorizationPolicy, error) {
+	var policies []AuthorizationPolicy
+	if err := json.NewDecoder(r).Decode(&policies); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// ReadPolicyFile reads the JSON list of policies in file filePath.
+func ReadPolicyFile(filePath string) ([]AuthorizationPolicy, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	policies, err := ReadPolicies(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode policies of %s: %v", filePath, err)
+	}
+	return policies, nil
+}
+
+// canonical returns a copy of rule r with its principals and paths sorted.
+func (r *Rule) canonical() Rule {
+	c := *r
+	c.Source.Principals = slices.Clone(r.Source.Principals)
+	sort.Strings(c.Source.Principals)
+	c.Request.Paths = slices.Clone(r.Request.Paths)
+	sort.Strings(c.Request.Paths)
+	return c
+}
+
+// Canonical returns a copy of policy p with its rules sorted by name and the
+// principals and paths of each rule sorted. Ordering is not significant in a
+// policy, so two equivalent policies have the same canonical form.
+func (p *AuthorizationPolicy) Canonical() *AuthorizationPolicy {
+	c := &AuthorizationPolicy{Name: p.Name}
+	canonicalRules := func(rules []Rule) []Rule {
+		var out []Rule
+		for i := range rules {
+			out = append(out, rules[i].canonical())
+		}
+		sort.SliceStable(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+		return out
+	}
+	c.AllowRules = canonicalRules(p.AllowRules)
+	c.DenyRules = canonicalRules(p.DenyRules)
+	return c
+}
+
+// RuleDiff is a rule added, removed or changed between two policies.
+type RuleDiff struct {
+	// Deny is set for a deny rule, unset for an allow rule.
+	Deny bool
+	Name string
+	// Old and New are the rule in the old and new policies, nil if the rule
+	// was added or removed.
+	Old, New *Rule
+}
+
+func (d *RuleDiff) String() string {
+	kind := "allow"
+	if d.Deny {
+		kind = "deny"
+	}
+	switch {
+	case d.Old == nil:
+		return fmt.Sprintf("+ %s rule %q: principals %v, paths %v", kind, d.Name, d.New.Source.Principals, d.New.Request.Paths)
+	case d.New == nil:
+		return fmt.Sprintf("- %s rule %q: principals %v, paths %v", kind, d.Name, d.Old.Source.Principals, d.Old.Request.Paths)
+	}
+	var changes []string
+	if !slices.Equal(d.Old.Source.Principals, d.New.Source.Principals) {
+		changes = append(changes, fmt.Sprintf("principals %v -> %v", d.Old.Source.Principals, d.New.Source.Principals))
+	}
+	if !slices.Equal(d.Old.Request.Paths, d.New.Request.Paths) {
+		changes = append(changes, fmt.Sprintf("paths %v -> %v", d.Old.Request.Paths, d.New.Request.Paths))
+	}
+	return fmt.Sprintf("~ %s rule %q: %s", kind, d.Name, strings.Join(changes, ", "))
+}
+
+// PolicyDiff holds the semantic differences between two policies.
+type PolicyDiff struct {
+	OldName, NewName string
+	// Rules are the rules added, removed or changed, deny rules first, each
+	// kind sorted by name.
+	Rules []*RuleDiff
+}
+
+// Empty reports whether the policies are equivalent.
+func (d *PolicyDiff) Empty() bool {
+	return d.OldName == d.NewName && len(d.Rules) == 0
+}
+
+func (d *PolicyDiff) String() string {
+	var lines []string
+	if d.OldName != d.NewName {
+		lines = append(lines, fmt.Sprintf("~ policy name %q -> %q", d.OldName, d.NewName))
+	}
+	for _, r := range d.Rules {
+		lines = append(lines, r.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Diff returns the differences from policy old to policy new. Rules are
+// matched by name, and the order of rules, principals and paths is ignored.
+func Diff(old, new *AuthorizationPolicy) *PolicyDiff {
+	d := &PolicyDiff{OldName: old.Name, NewName: new.Name}
+	oldC, newC := old.Canonical(), new.Canonical()
+	diffRules := func(deny bool, oldRules, newRules []Rule) {
+		byName := map[string]*Rule{}
+		for i := range oldRules {
+			byName[oldRules[i].Name] = &oldRules[i]
+		}
+		var diffs []*RuleDiff
+		for i := range newRules {
+			n := &newRules[i]
+			o, ok := byName[n.Name]
+			delete(byName, n.Name)
+			switch {
+			case !ok:
+				diffs = append(diffs, &RuleDiff{Deny: deny, Name: n.Name, New: n})
+			case !slices.Equal(o.Source.Principals, n.Source.Principals) || !slices.Equal(o.Request.Paths, n.Request.Paths):
+				diffs = append(diffs, &RuleDiff{Deny: deny, Name: n.Name, Old: o, New: n})
+			}
+		}
+		for name, o := range byName {
+			diffs = append(diffs, &RuleDiff{Deny: deny, Name: name, Old: o})
+		}
+		sort.SliceStable(diffs, func(i, j int) bool { return diffs[i].Name < diffs[j].Name })
+		d.Rules = append(d.Rules, diffs...)
+	}
+	diffRules(true, oldC.DenyRules, newC.DenyRules)
+	diffRules(false, oldC.AllowRules, newC.AllowRules)
+	return d
+}
+
+// LintIssue is a likely mistake found in a policy.
+type LintIssue struct {
+	Policy string
+	// Rule is the name of the rule at fault, empty for the whole policy.
+	Rule    string
+	Message string
+}
+
+func (i *LintIssue) String() string {
+	if i.Rule == "" {
+		return fmt.Sprintf("policy %s: %s", i.Policy, i.Message)
+	}
+	return fmt.Sprintf("policy %s, rule %s: %s", i.Policy, i.Rule, i.Message)
+}
+
+// covers reports whether every string matched by pattern q is matched by
+// pattern p.
+func covers(p, q string) bool {
+	switch {
+	case p == "*":
+		return true
+	case q == "*":
+		return false
+	case strings.HasSuffix(p, "*"):
+		prefix := strings.TrimSuffix(p, "*")
+		return !strings.HasPrefix(q, "*") && strings.HasPrefix(strings.TrimSuffix(q, "*"), prefix)
+	case strings.HasPrefix(p, "*"):
+		suffix := strings.TrimPrefix(p, "*")
+		return !strings.HasSuffix(q, "*") && strings.HasSuffix(strings.TrimPrefix(q, "*"), suffix)
+	}
+	return p == q
+}
+
+// coversAny reports whether the patterns of a rule field cover pattern q.
+// An empty list matches everything.
+func coversAny(patterns []string, q string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if covers(p, q) {
+			return true
+		}
+	}
+	return false
+}
+
+// shadowed reports whether every principal and path of rule r is denied by
+// one of the deny rules.
+func shadowed(r *Rule, deny []Rule) bool {
+	if len(deny) == 0 {
+		return false
+	}
+	principals, paths := r.Source.Principals, r.Request.Paths
+	if len(principals) == 0 {
+		principals = []string{"*"}
+	}
+	if len(paths) == 0 {
+		paths = []string{"*"}
+	}
+	for _, principal := range principals {
+		for _, path := range paths {
+			if !slices.ContainsFunc(deny, func(d Rule) bool {
+				return coversAny(d.Source.Principals, principal) && coversAny(d.Request.Paths, path)
+			}) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// knownPath reports whether path, which may be a pattern, matches an RPC of
+// gnxi.RPCMAP.
+func knownPath(path string) bool {
+	if _, ok := gnxi.RPCMAP[path]; ok {
+		return true
+	}
+	for known := range gnxi.RPCMAP {
+		if matchPattern(path, known) {
+			return true
+		}
+	}
+	return false
+}
+
+// Lint returns the likely mistakes in policy p: allow rules that are
+// unreachable because deny rules cover them, paths that match no RPC of
+// gnxi.RPCMAP, rules without principals, which apply to everyone, rules
+// without or with duplicate names, and policies without allow rules, which
+// deny every RPC.
+func (p *AuthorizationPolicy) Lint() []*LintIssue {
+	var issues []*LintIssue
+	add := func(rule, format string, args ...any) {
+		issues = append(issues, &LintIssue{Policy: p.Name, Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+	if p.Name == "" {
+		add("", "policy has no name")
+	}
+	if len(p.AllowRules) == 0 {
+		add("", "policy has no allow rules and denies every RPC")
+	}
+	names := map[string]bool{}
+	check := func(kind string, r *Rule) {
+		if r.Name == "" {
+			add("", "%s rule with principals %v and paths %v has no name", kind, r.Source.Principals, r.Request.Paths)
+		} else if names[r.Name] {
+			add(r.Name, "duplicate rule name")
+		}
+		names[r.Name] = true
+		if len(r.Source.Principals) == 0 {
+			add(r.Name, "%s rule has no principals and applies to everyone", kind)
+		}
+		for _, path := range r.Request.Paths {
+			if !knownPath(path) {
+				add(r.Name, "path %s matches no known RPC", path)
+			}
+		}
+	}
+	for i := range p.DenyRules {
+		check("deny", &p.DenyRules[i])
+	}
+	for i := range p.AllowRules {
+		r := &p.AllowRules[i]
+		check("allow", r)
+		if shadowed(r, p.DenyRules) {
+			add(r.Name, "allow rule is unreachable, as deny rules cover all its principals and paths")
+		}
+	}
+	return issues
+}
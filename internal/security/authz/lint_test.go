@@ -0,0 +1,98 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authz
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/featureprofiles/internal/security/gnxi"
+)
+
+func TestDiff(t *testing.T) {
+	old := testPolicy()
+	reordered := NewAuthorizationPolicy("test")
+	reordered.AddAllowRules("admin-all", []string{"spiffe://test-abc.foo.bar/xyz/admin"}, []*gnxi.RPC{gnxi.RPCs.AllRPC})
+	reordered.AddAllowRules("gnmi-all", []string{"spiffe://test-abc.foo.bar/xyz/read*", "spiffe://test-abc.foo.bar/xyz/admin"}, []*gnxi.RPC{gnxi.RPCs.GnmiAllRPC})
+	reordered.AddDenyRules("no-reboot", nil, []*gnxi.RPC{gnxi.RPCs.GnoiSystemReboot})
+	reordered.AddDenyRules("no-set", []string{"spiffe://test-abc.foo.bar/xyz/reader"}, []*gnxi.RPC{gnxi.RPCs.GnmiSet})
+	if d := Diff(old, reordered); !d.Empty() {
+		t.Errorf("Diff() of reordered policies got\n%s\nwant no difference", d)
+	}
+
+	changed := testPolicy()
+	changed.DenyRules = changed.DenyRules[:1]
+	changed.AllowRules[1].Request.Paths = []string{"/gribi.gRIBI/*"}
+	changed.AddAllowRules("gribi-get", nil, []*gnxi.RPC{gnxi.RPCs.GribiGet})
+	var got []string
+	for _, r := range Diff(old, changed).Rules {
+		got = append(got, r.String())
+	}
+	want := []string{
+		`- deny rule "no-reboot": principals [], paths [/gnoi.system.System/Reboot]`,
+		`~ allow rule "admin-all": paths [*] -> [/gribi.gRIBI/*]`,
+		`+ allow rule "gribi-get": principals [], paths [/gribi.gRIBI/Get]`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Diff() got unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestLint(t *testing.T) {
+	p := NewAuthorizationPolicy("lint")
+	p.AddDenyRules("no-gnmi-for-readers", []string{"spiffe://x/read*"}, []*gnxi.RPC{gnxi.RPCs.GnmiAllRPC})
+	p.AddDenyRules("no-reboot", []string{"*"}, []*gnxi.RPC{gnxi.RPCs.GnoiSystemReboot})
+	p.AddAllowRules("reader-get", []string{"spiffe://x/reader"}, []*gnxi.RPC{gnxi.RPCs.GnmiGet, gnxi.RPCs.GnmiSet})
+	p.AddAllowRules("reader-gribi", []string{"spiffe://x/reader"}, []*gnxi.RPC{gnxi.RPCs.GnmiGet, gnxi.RPCs.GribiGet})
+	p.AddAllowRules("everyone", nil, []*gnxi.RPC{gnxi.RPCs.GnoiSystemTime})
+	p.AllowRules = append(p.AllowRules, Rule{Name: "typo"})
+	p.AllowRules[3].Source.Principals = []string{"spiffe://x/admin"}
+	p.AllowRules[3].Request.Paths = []string{"/gnmi.gNMI/Gett", "/gnoi.system.*"}
+	p.AllowRules = append(p.AllowRules, p.AllowRules[2])
+
+	var got []string
+	for _, i := range p.Lint() {
+		got = append(got, i.String())
+	}
+	want := []string{
+		"policy lint, rule reader-get: allow rule is unreachable, as deny rules cover all its principals and paths",
+		"policy lint, rule everyone: allow rule has no principals and applies to everyone",
+		"policy lint, rule typo: path /gnmi.gNMI/Gett matches no known RPC",
+		"policy lint, rule everyone: duplicate rule name",
+		"policy lint, rule everyone: allow rule has no principals and applies to everyone",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Lint() got unexpected diff (-want +got):\n%s", diff)
+	}
+
+	empty := NewAuthorizationPolicy("empty")
+	if issues := empty.Lint(); len(issues) != 1 || !strings.Contains(issues[0].Message, "no allow rules") {
+		t.Errorf("Lint() of an empty policy got %v, want a single issue about the missing allow rules", issues)
+	}
+}
+
+func TestReadPolicies(t *testing.T) {
+	policies, err := ReadPolicies(strings.NewReader(`[{"name": "p", "allow_rules": [{"name": "r", "source": {"principals": ["*"]}, "request": {"paths": ["/gnmi.gNMI/Get"]}}]}]`))
+	if err != nil {
+		t.Fatalf("ReadPolicies() got unexpected error: %v", err)
+	}
+	if len(policies) != 1 || policies[0].AllowRules[0].Request.Paths[0] != "/gnmi.gNMI/Get" {
+		t.Errorf("ReadPolicies() got %+v, want one policy allowing gNMI Get", policies)
+	}
+	if _, err := ReadPolicies(strings.NewReader(`{}`)); err == nil {
+		t.Errorf("ReadPolicies() of an object got no error, want error")
+	}
+}
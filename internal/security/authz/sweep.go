@@ -0,0 +1,332 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authz
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openconfig/featureprofiles/internal/fptest"
+	"github.com/openconfig/featureprofiles/internal/security/gnxi"
+	"github.com/openconfig/featureprofiles/internal/security/svid"
+	"github.com/openconfig/ondatra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	authzpb "github.com/openconfig/gnsi/authz"
+)
+
+// GenSpiffes generates an SVID signed by the CA for each SPIFFE ID, and
+// returns the identities with a TLS configuration trusting roots.
+func GenSpiffes(ids []string, caCert *x509.Certificate, caKey any, roots *x509.CertPool, keyAlgo x509.PublicKeyAlgorithm) ([]*Spiffe, error) {
+	var spiffes []*Spiffe
+	for _, id := range ids {
+		cert, err := svid.GenSVID("", id, 300, caCert, caKey, keyAlgo)
+		if err != nil {
+			return nil, fmt.Errorf("could not generate svid for %s: %v", id, err)
+		}
+		spiffes = append(spiffes, &Spiffe{
+			ID: id,
+			TLSConf: &tls.Config{
+				Certificates: []tls.Certificate{*cert},
+				RootCAs:      roots,
+			},
+		})
+	}
+	return spiffes, nil
+}
+
+// SweepOptions configure a conformance sweep.
+type SweepOptions struct {
+	// RPCs are the RPCs swept. Defaults to ConcreteRPCs().
+	RPCs []*gnxi.RPC
+	// HardVerify also executes every RPC with the SVID of every identity.
+	HardVerify bool
+	// Concurrency is the number of requests in flight. Defaults to 8.
+	Concurrency int
+	// Rate is the maximum number of requests started per second, counting
+	// probes and executions, at most one per nanosecond. Zero means no
+	// limit.
+	Rate float64
+	// ExecTimeout bounds each RPC execution. Defaults to 30 seconds.
+	ExecTimeout time.Duration
+	// Report is the name of the CSV and HTML reports written to
+	// --outputs_dir. No report is written if empty.
+	Report string
+}
+
+// SweepResult is the outcome of one RPC for one identity.
+type SweepResult struct {
+	Principal, Path string
+	// Expected is the action predicted from the policy, decided by Rule.
+	Expected authzpb.ProbeResponse_Action
+	Rule     string
+	// Probe is the action returned by Authz.Probe, unless ProbeErr is set.
+	Probe    authzpb.ProbeResponse_Action
+	ProbeErr error
+	// Executed is set when the RPC was executed.
+	Executed bool
+	ExecErr  error
+}
+
+// Unimplemented reports whether the execution of an RPC permitted by the
+// policy failed with Unimplemented. The DUT authorized the RPC, but as the
+// RPC has no implementation, on the DUT or in gnxi, the execution does not
+// verify the permission. Denied RPCs failing with Unimplemented are
+// mismatches instead, as the DUT did not deny them.
+func (r *SweepResult) Unimplemented() bool {
+	return r.Executed && status.Code(r.ExecErr) == codes.Unimplemented && r.Expected != authzpb.ProbeResponse_ACTION_DENY
+}
+
+// ProbeMismatch reports whether the probe failed or disagrees with the
+// policy.
+func (r *SweepResult) ProbeMismatch() bool {
+	return r.ProbeErr != nil || r.Probe != r.Expected
+}
+
+// ExecMismatch reports whether the execution of the RPC disagrees with the
+// policy. Denied RPCs must fail with PermissionDenied; permitted RPCs may
+// fail for other reasons, as their parameters are not always valid for the
+// DUT.
+func (r *SweepResult) ExecMismatch() bool {
+	if !r.Executed {
+		return false
+	}
+	denied := status.Code(r.ExecErr) == codes.PermissionDenied
+	return denied != (r.Expected == authzpb.ProbeResponse_ACTION_DENY)
+}
+
+// Mismatch reports whether the probe or the execution disagree with the
+// policy.
+func (r *SweepResult) Mismatch() bool {
+	return r.ProbeMismatch() || r.ExecMismatch()
+}
+
+// ProbeString returns the probe result, or its error, as shown in reports.
+func (r *SweepResult) ProbeString() string {
+	if r.ProbeErr != nil {
+		return "error: " + r.ProbeErr.Error()
+	}
+	return r.Probe.String()
+}
+
+// ExecString returns the status code of the execution as shown in reports,
+// or "-" if the RPC was not executed.
+func (r *SweepResult) ExecString() string {
+	if !r.Executed {
+		return "-"
+	}
+	return status.Code(r.ExecErr).String()
+}
+
+// SweepReport holds the results of a sweep, ordered by identity then RPC.
+type SweepReport struct {
+	Policy  string
+	Results []*SweepResult
+}
+
+// Unimplemented returns the results of permitted RPCs whose execution failed
+// with Unimplemented, and which are therefore only verified by the probe.
+func (r *SweepReport) Unimplemented() []*SweepResult {
+	var u []*SweepResult
+	for _, res := range r.Results {
+		if res.Unimplemented() {
+			u = append(u, res)
+		}
+	}
+	return u
+}
+
+// Mismatches returns the results that disagree with the policy.
+func (r *SweepReport) Mismatches() []*SweepResult {
+	var m []*SweepResult
+	for _, res := range r.Results {
+		if res.Mismatch() {
+			m = append(m, res)
+		}
+	}
+	return m
+}
+
+// CSV renders the report as CSV with one row per identity and RPC.
+func (r *SweepReport) CSV() (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"principal", "path", "expected", "rule", "probe", "exec", "mismatch"})
+	for _, res := range r.Results {
+		w.Write([]string{res.Principal, res.Path, res.Expected.String(), res.Rule, res.ProbeString(), res.ExecString(), fmt.Sprint(res.Mismatch())})
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+var reportTmpl = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Authz sweep of policy {{.Policy}}</title>
+<style>td, th { padding: 2px 8px; } tr.mismatch { background: #fcc; } tr.unimplemented { background: #ffc; }</style>
+</head>
+<body>
+<h1>Authz sweep of policy {{.Policy}}</h1>
+<p>{{len .Mismatches}} mismatches in {{len .Results}} results, {{len .Unimplemented}} permitted RPCs not verified by execution as they are unimplemented.</p>
+<table>
+<tr><th>Principal</th><th>Path</th><th>Expected</th><th>Rule</th><th>Probe</th><th>Exec</th></tr>
+{{range .Results}}<tr{{if .Mismatch}} class="mismatch"{{else if .Unimplemented}} class="unimplemented"{{end}}><td>{{.Principal}}</td><td>{{.Path}}</td><td>{{.Expected}}</td><td>{{.Rule}}</td><td>{{.ProbeString}}</td><td>{{.ExecString}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// HTML renders the report as an HTML table with mismatches highlighted.
+func (r *SweepReport) HTML() (string, error) {
+	var buf bytes.Buffer
+	if err := reportTmpl.Execute(&buf, r); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// probeFunc returns the action of the DUT for principal calling path.
+type probeFunc func(ctx context.Context, principal, path string) (authzpb.ProbeResponse_Action, error)
+
+// execFunc executes rpc with the credentials of spiffe.
+type execFunc func(ctx context.Context, spiffe *Spiffe, rpc *gnxi.RPC) error
+
+// sweep evaluates, probes and optionally executes every RPC for every
+// identity using up to opts.Concurrency workers, limited to opts.Rate
+// requests per second.
+func (p *AuthorizationPolicy) sweep(ctx context.Context, identities []*Spiffe, opts *SweepOptions, probe probeFunc, exec execFunc) (*SweepReport, error) {
+	if opts.Rate < 0 || opts.Rate > float64(time.Second) {
+		return nil, fmt.Errorf("sweep rate %v is not between 0 and %d requests per second", opts.Rate, time.Second)
+	}
+	rpcs := opts.RPCs
+	if len(rpcs) == 0 {
+		rpcs = ConcreteRPCs()
+	}
+	workers := opts.Concurrency
+	if workers <= 0 {
+		workers = 8
+	}
+	execTimeout := opts.ExecTimeout
+	if execTimeout == 0 {
+		execTimeout = 30 * time.Second
+	}
+	wait := func() {}
+	if opts.Rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.Rate))
+		defer ticker.Stop()
+		wait = func() { <-ticker.C }
+	}
+
+	report := &SweepReport{Policy: p.Name}
+	type job struct {
+		spiffe *Spiffe
+		rpc    *gnxi.RPC
+		res    *SweepResult
+	}
+	jobs := make(chan *job)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				wait()
+				j.res.Probe, j.res.ProbeErr = probe(ctx, j.spiffe.ID, j.rpc.Path)
+				if !opts.HardVerify {
+					continue
+				}
+				wait()
+				ectx, cancel := context.WithTimeout(ctx, execTimeout)
+				j.res.ExecErr = exec(ectx, j.spiffe, j.rpc)
+				j.res.Executed = true
+				cancel()
+			}
+		}()
+	}
+	for _, s := range identities {
+		for _, rpc := range rpcs {
+			res := &SweepResult{Principal: s.ID, Path: rpc.Path}
+			res.Expected, res.Rule = p.Evaluate(s.ID, rpc.Path)
+			report.Results = append(report.Results, res)
+			jobs <- &job{spiffe: s, rpc: rpc, res: res}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return report, nil
+}
+
+// Sweep probes, and with opts.HardVerify executes, every RPC for every
+// identity against dut, compares the results with the policy, and reports
+// each mismatch as a test error. The policy is expected to be already
+// installed on dut. Permitted RPCs that are unimplemented are logged
+// separately. The report is returned and, if opts.Report is set, written to
+// --outputs_dir as CSV and HTML.
+func (p *AuthorizationPolicy) Sweep(t testing.TB, dut *ondatra.DUTDevice, identities []*Spiffe, opts *SweepOptions) *SweepReport {
+	t.Helper()
+	if opts == nil {
+		opts = &SweepOptions{}
+	}
+	gnsiC, err := dut.RawAPIs().BindingDUT().DialGNSI(context.Background())
+	if err != nil {
+		t.Fatalf("Could not connect gnsi %v", err)
+	}
+	probe := func(ctx context.Context, principal, path string) (authzpb.ProbeResponse_Action, error) {
+		resp, err := gnsiC.Authz().Probe(ctx, &authzpb.ProbeRequest{User: principal, Rpc: path})
+		return resp.GetAction(), err
+	}
+	exec := func(ctx context.Context, spiffe *Spiffe, rpc *gnxi.RPC) error {
+		return rpc.Exec(ctx, dut, []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(spiffe.TLSConf))})
+	}
+	report, err := p.sweep(context.Background(), identities, opts, probe, exec)
+	if err != nil {
+		t.Fatalf("Authz sweep of policy %s on dut %s: %v", p.Name, dut.Name(), err)
+	}
+
+	if opts.Report != "" {
+		for suffix, render := range map[string]func() (string, error){".csv": report.CSV, ".html": report.HTML} {
+			content, err := render()
+			if err != nil {
+				t.Errorf("Could not render %s sweep report: %v", suffix, err)
+				continue
+			}
+			name, err := fptest.WriteOutput(opts.Report, suffix, content)
+			if err != nil {
+				t.Errorf("Could not write sweep report: %v", err)
+				continue
+			}
+			t.Logf("Wrote authz sweep report %s", name)
+		}
+	}
+	for _, u := range report.Unimplemented() {
+		t.Logf("Authz sweep of policy %s on dut %s: user %s, path %s: permitted RPC is unimplemented, only verified by probe %s",
+			p.Name, dut.Name(), u.Principal, u.Path, u.ProbeString())
+	}
+	for _, m := range report.Mismatches() {
+		t.Errorf("Authz sweep of policy %s on dut %s: user %s, path %s: want %v (%s), probe %s, exec %s",
+			p.Name, dut.Name(), m.Principal, m.Path, m.Expected, m.Rule, m.ProbeString(), m.ExecString())
+	}
+	return report
+}
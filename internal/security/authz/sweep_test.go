@@ -0,0 +1,147 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authz
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/openconfig/featureprofiles/internal/security/gnxi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	authzpb "github.com/openconfig/gnsi/authz"
+)
+
+func TestSweep(t *testing.T) {
+	p := testPolicy()
+	admin := &Spiffe{ID: "spiffe://test-abc.foo.bar/xyz/admin"}
+	reader := &Spiffe{ID: "spiffe://test-abc.foo.bar/xyz/reader"}
+	rpcs := []*gnxi.RPC{gnxi.RPCs.GnmiGet, gnxi.RPCs.GnmiSet, gnxi.RPCs.GnoiSystemReboot, gnxi.RPCs.GribiGet}
+
+	// The fake DUT permits Set for the reader, fails probes of gRIBI and
+	// has no implementation of reboot or gNMI Get.
+	var calls atomic.Int32
+	probe := func(_ context.Context, principal, path string) (authzpb.ProbeResponse_Action, error) {
+		calls.Add(1)
+		switch {
+		case path == gnxi.RPCs.GribiGet.Path:
+			return authzpb.ProbeResponse_ACTION_UNSPECIFIED, errors.New("probe failed")
+		case principal == reader.ID && path == gnxi.RPCs.GnmiSet.Path:
+			return permit, nil
+		}
+		action, _ := p.Evaluate(principal, path)
+		return action, nil
+	}
+	exec := func(_ context.Context, s *Spiffe, rpc *gnxi.RPC) error {
+		calls.Add(1)
+		if rpc == gnxi.RPCs.GnoiSystemReboot || rpc == gnxi.RPCs.GnmiGet {
+			return status.Error(codes.Unimplemented, "not implemented")
+		}
+		if action, _ := p.Evaluate(s.ID, rpc.Path); action == deny {
+			return status.Error(codes.PermissionDenied, "denied")
+		}
+		return nil
+	}
+
+	report, err := p.sweep(context.Background(), []*Spiffe{admin, reader}, &SweepOptions{RPCs: rpcs, HardVerify: true, Concurrency: 3, Rate: 1000}, probe, exec)
+	if err != nil {
+		t.Fatalf("sweep() got unexpected error: %v", err)
+	}
+	if got, want := calls.Load(), int32(16); got != want {
+		t.Errorf("sweep() made %d calls, want %d", got, want)
+	}
+	if got := len(report.Results); got != 8 {
+		t.Fatalf("sweep() got %d results, want 8", got)
+	}
+	if r := report.Results[5]; r.Principal != reader.ID || r.Path != gnxi.RPCs.GnmiSet.Path {
+		t.Errorf("sweep() result 5 is for %s %s, want results ordered by identity then RPC", r.Principal, r.Path)
+	}
+	var got []string
+	for _, m := range report.Mismatches() {
+		got = append(got, m.Principal+" "+m.Path)
+	}
+	want := []string{
+		admin.ID + " /gnoi.system.System/Reboot",
+		admin.ID + " /gribi.gRIBI/Get",
+		reader.ID + " /gnmi.gNMI/Set",
+		reader.ID + " /gnoi.system.System/Reboot",
+		reader.ID + " /gribi.gRIBI/Get",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Mismatches() got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	got = nil
+	for _, u := range report.Unimplemented() {
+		got = append(got, u.Principal+" "+u.Path)
+	}
+	want = []string{
+		admin.ID + " /gnmi.gNMI/Get",
+		reader.ID + " /gnmi.gNMI/Get",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unimplemented() got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if r := report.Results[2]; !r.Executed || r.ExecString() != "Unimplemented" {
+		t.Errorf("unimplemented reboot got result %+v, want executed with Unimplemented", r)
+	}
+
+	csv, err := report.CSV()
+	if err != nil {
+		t.Fatalf("CSV() got unexpected error: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(csv), "\n"); len(lines) != 9 || !strings.HasSuffix(lines[6], "ACTION_DENY,no-set,ACTION_PERMIT,PermissionDenied,true") {
+		t.Errorf("CSV() got\n%s\nwant a header, 8 rows and the reader Set mismatch", csv)
+	}
+	html, err := report.HTML()
+	if err != nil {
+		t.Fatalf("HTML() got unexpected error: %v", err)
+	}
+	if got := strings.Count(html, `class="mismatch"`); got != 5 {
+		t.Errorf("HTML() got %d mismatch rows, want 5", got)
+	}
+	if got := strings.Count(html, `class="unimplemented"`); got != 2 {
+		t.Errorf("HTML() got %d unimplemented rows, want 2", got)
+	}
+}
+
+func TestSweepRate(t *testing.T) {
+	p := testPolicy()
+	probe := func(context.Context, string, string) (authzpb.ProbeResponse_Action, error) {
+		return permit, nil
+	}
+	tests := []struct {
+		desc    string
+		rate    float64
+		wantErr bool
+	}{
+		{"unlimited", 0, false},
+		{"one per nanosecond", 1e9, false},
+		{"negative", -1, true},
+		{"above one per nanosecond", 2e9, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			opts := &SweepOptions{RPCs: []*gnxi.RPC{gnxi.RPCs.GnmiGet}, Concurrency: 1, Rate: tt.rate}
+			_, err := p.sweep(context.Background(), []*Spiffe{{ID: "spiffe://test-abc.foo.bar/xyz/admin"}}, opts, probe, nil)
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Errorf("sweep() with rate %v got error %v, want error: %v", tt.rate, err, tt.wantErr)
+			}
+		})
+	}
+}
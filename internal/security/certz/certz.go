@@ -0,0 +1,415 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package certz provides helpers to rotate certificates on a DUT with
+// gNSI Certz.Rotate, and to verify the result by TLS handshakes with the
+// gRPC services of the DUT.
+package certz
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/openconfig/featureprofiles/internal/security/svid"
+	"github.com/openconfig/ondatra"
+	"github.com/openconfig/ondatra/binding/introspect"
+	"google.golang.org/protobuf/encoding/prototext"
+
+	certzpb "github.com/openconfig/gnsi/certz"
+)
+
+const (
+	// DefaultProfile is the SSL profile used by the gRPC services of the DUT.
+	DefaultProfile = "system_default_profile"
+	// handshakeTimeout bounds each TLS connection to the DUT.
+	handshakeTimeout = 10 * time.Second
+	// rollbackTimeout bounds the wait for the DUT to restore its certificate
+	// after an abandoned rotation.
+	rollbackTimeout = time.Minute
+	// http2Preface is sent after the TLS handshake, so that the rejection of
+	// a client certificate, which TLS 1.3 reports after the handshake, is
+	// received.
+	http2Preface = "PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n"
+)
+
+// DefaultServices are the services of the DUT verified when none is given.
+var DefaultServices = []introspect.Service{introspect.GNMI, introspect.GRIBI}
+
+// Bundle is the material uploaded by a rotation. Unset entities are not
+// rotated.
+type Bundle struct {
+	Version string
+	// Server is the certificate, private key and chain presented by the DUT.
+	Server *svid.Cert
+	// TrustBundle are the CAs trusted by the DUT to authenticate clients.
+	TrustBundle []*svid.CA
+	// CRLs are DER encoded revocation lists checked by the DUT.
+	CRLs [][]byte
+}
+
+func x509PEM(c *x509.Certificate) *certzpb.Certificate {
+	return &certzpb.Certificate{
+		Type:        certzpb.CertificateType_CERTIFICATE_TYPE_X509,
+		Encoding:    certzpb.CertificateEncoding_CERTIFICATE_ENCODING_PEM,
+		Certificate: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Raw}),
+	}
+}
+
+// chain links certs into a CertificateChain, the first certificate being
+// the leaf.
+func chain(certs []*x509.Certificate) *certzpb.CertificateChain {
+	var c *certzpb.CertificateChain
+	for i := len(certs) - 1; i >= 0; i-- {
+		c = &certzpb.CertificateChain{
+			Certificate: x509PEM(certs[i]),
+			Parent:      c,
+		}
+	}
+	return c
+}
+
+// entities returns the entities uploaded for b, created at createdOn.
+func (b *Bundle) entities(createdOn uint64) ([]*certzpb.Entity, error) {
+	var entities []*certzpb.Entity
+	if b.Server != nil {
+		key, err := b.Server.KeyPEM()
+		if err != nil {
+			return nil, err
+		}
+		c := chain(append([]*x509.Certificate{b.Server.Cert}, b.Server.Chain...))
+		c.Certificate.PrivateKey = key
+		entities = append(entities, &certzpb.Entity{
+			Version:   b.Version,
+			CreatedOn: createdOn,
+			Entity:    &certzpb.Entity_CertificateChain{CertificateChain: c},
+		})
+	}
+	if len(b.TrustBundle) > 0 {
+		var certs []*x509.Certificate
+		for _, ca := range b.TrustBundle {
+			for c := ca; c != nil; c = c.Parent {
+				certs = append(certs, c.Cert.Cert)
+			}
+		}
+		entities = append(entities, &certzpb.Entity{
+			Version:   b.Version,
+			CreatedOn: createdOn,
+			Entity:    &certzpb.Entity_TrustBundle{TrustBundle: chain(certs)},
+		})
+	}
+	if len(b.CRLs) > 0 {
+		bundle := &certzpb.CertificateRevocationListBundle{}
+		for i, crl := range b.CRLs {
+			bundle.CertificateRevocationLists = append(bundle.CertificateRevocationLists, &certzpb.CertificateRevocationList{
+				Type:                      certzpb.CertificateType_CERTIFICATE_TYPE_X509,
+				Encoding:                  certzpb.CertificateEncoding_CERTIFICATE_ENCODING_DER,
+				CertificateRevocationList: crl,
+				Id:                        fmt.Sprintf("%s-crl%d", b.Version, i),
+			})
+		}
+		entities = append(entities, &certzpb.Entity{
+			Version:   b.Version,
+			CreatedOn: createdOn,
+			Entity:    &certzpb.Entity_CertificateRevocationListBundle{CertificateRevocationListBundle: bundle},
+		})
+	}
+	if len(entities) == 0 {
+		return nil, fmt.Errorf("bundle %q has nothing to rotate", b.Version)
+	}
+	return entities, nil
+}
+
+// Rotation is a Certz.Rotate stream whose upload has been accepted by the
+// DUT, but not finalized yet.
+type Rotation struct {
+	dut     *ondatra.DUTDevice
+	profile string
+	stream  certzpb.Certz_RotateClient
+	cancel  context.CancelFunc
+}
+
+// Rotate uploads b to the SSL profile of dut through a new Certz.Rotate
+// stream and waits for the DUT to accept it. The returned rotation must be
+// finalized or abandoned.
+func Rotate(t testing.TB, dut *ondatra.DUTDevice, profile string, b *Bundle) *Rotation {
+	t.Helper()
+	entities, err := b.entities(uint64(time.Now().Unix()))
+	if err != nil {
+		t.Fatalf("Could not build certz upload request: %v", err)
+	}
+	gnsiC, err := dut.RawAPIs().BindingDUT().DialGNSI(context.Background())
+	if err != nil {
+		t.Fatalf("Could not connect gnsi %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := gnsiC.Certz().Rotate(ctx)
+	if err != nil {
+		cancel()
+		t.Fatalf("Could not start a certz rotate stream on dut %s: %v", dut.Name(), err)
+	}
+	req := &certzpb.RotateCertificateRequest{
+		SslProfileId: profile,
+		RotateRequest: &certzpb.RotateCertificateRequest_Certificates{
+			Certificates: &certzpb.UploadRequest{Entities: entities},
+		},
+	}
+	t.Logf("Sending Certz.Rotate upload of version %s to profile %s on dut %s", b.Version, profile, dut.Name())
+	if err := stream.Send(req); err != nil {
+		cancel()
+		t.Fatalf("Error while uploading certz rotate request on dut %s: %v", dut.Name(), err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		cancel()
+		t.Fatalf("Error while receiving certz rotate reply on dut %s: %v", dut.Name(), err)
+	}
+	t.Logf("Certz.Rotate upload response: %s", prototext.Format(resp))
+	return &Rotation{dut: dut, profile: profile, stream: stream, cancel: cancel}
+}
+
+// Finalize finalizes the rotation, making the uploaded material permanent.
+func (r *Rotation) Finalize(t testing.TB) {
+	t.Helper()
+	defer r.cancel()
+	req := &certzpb.RotateCertificateRequest{
+		SslProfileId:  r.profile,
+		RotateRequest: &certzpb.RotateCertificateRequest_FinalizeRotation{FinalizeRotation: &certzpb.FinalizeRequest{}},
+	}
+	if err := r.stream.Send(req); err != nil {
+		t.Fatalf("Error while finalizing certz rotate request on dut %s: %v", r.dut.Name(), err)
+	}
+	if err := r.stream.CloseSend(); err != nil {
+		t.Fatalf("Error while closing certz rotate stream on dut %s: %v", r.dut.Name(), err)
+	}
+	// The DUT closes the stream once the rotation is finalized.
+	if _, err := r.stream.Recv(); err != nil && err != io.EOF {
+		t.Fatalf("Certz.Rotate finalize failed on dut %s: %v", r.dut.Name(), err)
+	}
+}
+
+// Abandon cancels the stream without finalizing the rotation, which the
+// DUT must roll back.
+func (r *Rotation) Abandon(t testing.TB) {
+	t.Helper()
+	t.Logf("Abandoning Certz.Rotate stream of profile %s on dut %s", r.profile, r.dut.Name())
+	r.cancel()
+}
+
+// Client is the identity used to connect to the DUT.
+type Client struct {
+	// Cert is the client certificate presented to the DUT, if any.
+	Cert *svid.Cert
+	// Roots are the CAs trusted to issue the certificate of the DUT. The
+	// certificate of the DUT is not verified if empty.
+	Roots []*svid.CA
+}
+
+// TLSConfig returns the TLS configuration of the client. The name of the
+// DUT is not verified, as the DUT is dialed by address.
+func (c *Client) TLSConfig() *tls.Config {
+	conf := &tls.Config{
+		InsecureSkipVerify: true,
+		NextProtos:         []string{"h2"},
+	}
+	if c.Cert != nil {
+		conf.Certificates = []tls.Certificate{c.Cert.TLSCertificate()}
+	}
+	if len(c.Roots) == 0 {
+		return conf
+	}
+	roots := x509.NewCertPool()
+	for _, ca := range c.Roots {
+		roots.AddCert(ca.Root().Cert.Cert)
+	}
+	conf.VerifyConnection = func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return fmt.Errorf("server presented no certificate")
+		}
+		intermediates := x509.NewCertPool()
+		for _, c := range cs.PeerCertificates[1:] {
+			intermediates.AddCert(c)
+		}
+		_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		})
+		return err
+	}
+	return conf
+}
+
+// handshake connects to target with conf, exchanges the HTTP/2 preface, and
+// returns the certificate presented by the server.
+func handshake(ctx context.Context, target string, conf *tls.Config) (*x509.Certificate, error) {
+	ctx, cancel := context.WithTimeout(ctx, handshakeTimeout)
+	defer cancel()
+	d := &tls.Dialer{NetDialer: &net.Dialer{}, Config: conf}
+	conn, err := d.DialContext(ctx, "tcp", target)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	peer := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(peer) == 0 {
+		return nil, fmt.Errorf("server presented no certificate")
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if _, err := io.WriteString(conn, http2Preface); err != nil {
+		return peer[0], err
+	}
+	// The server answers the preface with its SETTINGS frame.
+	if _, err := conn.Read(make([]byte, 1)); err != nil {
+		return peer[0], err
+	}
+	return peer[0], nil
+}
+
+// Handshake connects to the service svc of dut as client, and returns the
+// certificate presented by the DUT, or the error of the connection.
+func Handshake(t testing.TB, dut *ondatra.DUTDevice, svc introspect.Service, client *Client) (*x509.Certificate, error) {
+	t.Helper()
+	dialer := introspect.DUTDialer(t, dut, svc)
+	return handshake(context.Background(), dialer.DialTarget, client.TLSConfig())
+}
+
+// Presented returns the certificate presented by the service svc of dut,
+// without verifying it.
+func Presented(t testing.TB, dut *ondatra.DUTDevice, svc introspect.Service) *x509.Certificate {
+	t.Helper()
+	dialer := introspect.DUTDialer(t, dut, svc)
+	conf := &tls.Config{InsecureSkipVerify: true, NextProtos: []string{"h2"}}
+	d := &tls.Dialer{NetDialer: &net.Dialer{Timeout: handshakeTimeout}, Config: conf}
+	conn, err := d.DialContext(context.Background(), "tcp", dialer.DialTarget)
+	if err != nil {
+		t.Fatalf("Could not connect to %s of dut %s: %v", svc, dut.Name(), err)
+	}
+	defer conn.Close()
+	return conn.(*tls.Conn).ConnectionState().PeerCertificates[0]
+}
+
+// VerifyAccepted connects to each service of dut, by default
+// DefaultServices, as client, and reports a test error unless the
+// connection succeeds with the DUT presenting want. It returns whether every
+// connection succeeded.
+func VerifyAccepted(t testing.TB, dut *ondatra.DUTDevice, client *Client, want *x509.Certificate, svcs ...introspect.Service) bool {
+	t.Helper()
+	if len(svcs) == 0 {
+		svcs = DefaultServices
+	}
+	ok := true
+	for _, svc := range svcs {
+		got, err := Handshake(t, dut, svc, client)
+		switch {
+		case err != nil:
+			t.Errorf("Connection to %s of dut %s failed: %v", svc, dut.Name(), err)
+			ok = false
+		case !got.Equal(want):
+			t.Errorf("Connection to %s of dut %s got certificate %q (serial %v), want %q (serial %v)", svc, dut.Name(), got.Subject, got.SerialNumber, want.Subject, want.SerialNumber)
+			ok = false
+		default:
+			t.Logf("Connection to %s of dut %s succeeded with certificate %q", svc, dut.Name(), got.Subject)
+		}
+	}
+	return ok
+}
+
+// VerifyRejected connects to each service of dut, by default
+// DefaultServices, as client, and reports a test error if the connection
+// succeeds. It returns whether every connection was rejected.
+func VerifyRejected(t testing.TB, dut *ondatra.DUTDevice, client *Client, svcs ...introspect.Service) bool {
+	t.Helper()
+	if len(svcs) == 0 {
+		svcs = DefaultServices
+	}
+	ok := true
+	for _, svc := range svcs {
+		if got, err := Handshake(t, dut, svc, client); err == nil {
+			t.Errorf("Connection to %s of dut %s got success with certificate %q, want failure", svc, dut.Name(), got.Subject)
+			ok = false
+		} else {
+			t.Logf("Connection to %s of dut %s failed as expected: %v", svc, dut.Name(), err)
+		}
+	}
+	return ok
+}
+
+// RotateAndFinalize rotates b onto the profile of dut, verifies that new
+// connections of newClient succeed with the new server certificate while
+// those of oldClient, if not nil, are rejected, and finalizes the rotation.
+// The rotation is abandoned if the verification fails.
+func RotateAndFinalize(t testing.TB, dut *ondatra.DUTDevice, profile string, b *Bundle, newClient, oldClient *Client, svcs ...introspect.Service) {
+	t.Helper()
+	if b.Server == nil {
+		t.Fatalf("Certz rotation of version %s has no server certificate to verify", b.Version)
+	}
+	r := Rotate(t, dut, profile, b)
+	want := b.Server.Cert
+	ok := VerifyAccepted(t, dut, newClient, want, svcs...)
+	if oldClient != nil && !VerifyRejected(t, dut, oldClient, svcs...) {
+		ok = false
+	}
+	if !ok {
+		r.Abandon(t)
+		t.Fatalf("Certz rotation of version %s on dut %s is not finalized as it failed verification", b.Version, dut.Name())
+	}
+	r.Finalize(t)
+	VerifyAccepted(t, dut, newClient, want, svcs...)
+}
+
+// RotateAndRollback rotates b onto the profile of dut, verifies that new
+// connections of newClient succeed with the new server certificate, then
+// abandons the rotation and verifies that the DUT restores the certificate
+// it presented before.
+func RotateAndRollback(t testing.TB, dut *ondatra.DUTDevice, profile string, b *Bundle, newClient *Client, svcs ...introspect.Service) {
+	t.Helper()
+	if b.Server == nil {
+		t.Fatalf("Certz rotation of version %s has no server certificate to verify", b.Version)
+	}
+	if len(svcs) == 0 {
+		svcs = DefaultServices
+	}
+	before := map[introspect.Service]*x509.Certificate{}
+	for _, svc := range svcs {
+		before[svc] = Presented(t, dut, svc)
+	}
+	r := Rotate(t, dut, profile, b)
+	VerifyAccepted(t, dut, newClient, b.Server.Cert, svcs...)
+	r.Abandon(t)
+
+	for _, svc := range svcs {
+		deadline := time.Now().Add(rollbackTimeout)
+		for {
+			got := Presented(t, dut, svc)
+			if got.Equal(before[svc]) {
+				t.Logf("Dut %s rolled back the certificate of %s to %q", dut.Name(), svc, got.Subject)
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Errorf("Dut %s got certificate %q for %s %v after abandoning the rotation, want %q", dut.Name(), got.Subject, svc, rollbackTimeout, before[svc].Subject)
+				break
+			}
+			time.Sleep(time.Second)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certz
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/openconfig/featureprofiles/internal/security/svid"
+)
+
+func mustCA(t *testing.T, name string) *svid.CA {
+	t.Helper()
+	ca, err := svid.NewRootCA(svid.CertOptions{CommonName: name})
+	if err != nil {
+		t.Fatalf("NewRootCA() got unexpected error: %v", err)
+	}
+	return ca
+}
+
+func TestEntities(t *testing.T) {
+	root := mustCA(t, "root")
+	inter, err := root.NewIntermediateCA(svid.CertOptions{CommonName: "intermediate"})
+	if err != nil {
+		t.Fatalf("NewIntermediateCA() got unexpected error: %v", err)
+	}
+	server, err := inter.IssueServer(svid.CertOptions{CommonName: "dut", DNSNames: []string{"dut"}})
+	if err != nil {
+		t.Fatalf("IssueServer() got unexpected error: %v", err)
+	}
+	crl, err := root.CRL(time.Hour)
+	if err != nil {
+		t.Fatalf("CRL() got unexpected error: %v", err)
+	}
+	b := &Bundle{Version: "v1", Server: server, TrustBundle: []*svid.CA{inter}, CRLs: [][]byte{crl}}
+	entities, err := b.entities(42)
+	if err != nil {
+		t.Fatalf("entities() got unexpected error: %v", err)
+	}
+	if len(entities) != 3 {
+		t.Fatalf("entities() got %d entities, want 3", len(entities))
+	}
+	cc := entities[0].GetCertificateChain()
+	if cc.GetCertificate().GetPrivateKey() == nil || cc.GetParent().GetCertificate() == nil || cc.GetParent().GetParent() != nil {
+		t.Errorf("entities() got certificate chain %v, want the server certificate with its key and the intermediate CA", cc)
+	}
+	if tb := entities[1].GetTrustBundle(); tb.GetParent().GetCertificate() == nil || tb.GetCertificate().GetPrivateKey() != nil {
+		t.Errorf("entities() got trust bundle %v, want the intermediate and root CAs without key", tb)
+	}
+	if got := entities[2].GetCertificateRevocationListBundle().GetCertificateRevocationLists()[0].GetId(); got != "v1-crl0" {
+		t.Errorf("entities() got CRL ID %q, want v1-crl0", got)
+	}
+	for _, e := range entities {
+		if e.GetVersion() != "v1" || e.GetCreatedOn() != 42 {
+			t.Errorf("entities() got version %q created on %d, want v1 created on 42", e.GetVersion(), e.GetCreatedOn())
+		}
+	}
+	if _, err := (&Bundle{Version: "empty"}).entities(42); err == nil {
+		t.Errorf("entities() of an empty bundle got no error, want error")
+	}
+}
+
+// serve accepts TLS connections on a local port, and answers the HTTP/2
+// preface like a gRPC server.
+func serve(t *testing.T, conf *tls.Config) string {
+	t.Helper()
+	l, err := tls.Listen("tcp", "127.0.0.1:0", conf)
+	if err != nil {
+		t.Fatalf("Listen() got unexpected error: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				buf := make([]byte, len(http2Preface))
+				if _, err := io.ReadFull(conn, buf); err == nil {
+					conn.Write([]byte{0})
+				}
+			}()
+		}
+	}()
+	return l.Addr().String()
+}
+
+func TestHandshake(t *testing.T) {
+	serverCA, clientCA, otherCA := mustCA(t, "server-ca"), mustCA(t, "client-ca"), mustCA(t, "other-ca")
+	server, err := serverCA.IssueServer(svid.CertOptions{CommonName: "dut", IPAddresses: []net.IP{net.ParseIP("127.0.0.1")}})
+	if err != nil {
+		t.Fatalf("IssueServer() got unexpected error: %v", err)
+	}
+	client, err := clientCA.IssueSVID("spiffe://test-abc.foo.bar/xyz/admin", svid.CertOptions{})
+	if err != nil {
+		t.Fatalf("IssueSVID() got unexpected error: %v", err)
+	}
+	rogue, err := otherCA.IssueSVID("spiffe://test-abc.foo.bar/xyz/admin", svid.CertOptions{})
+	if err != nil {
+		t.Fatalf("IssueSVID() got unexpected error: %v", err)
+	}
+	target := serve(t, svid.ServerTLSConfig(server, clientCA))
+
+	tests := []struct {
+		desc    string
+		client  *Client
+		wantErr bool
+	}{
+		{"trusted", &Client{Cert: client, Roots: []*svid.CA{serverCA}}, false},
+		{"server not verified", &Client{Cert: client}, false},
+		{"untrusted server", &Client{Cert: client, Roots: []*svid.CA{otherCA}}, true},
+		{"untrusted client", &Client{Cert: rogue, Roots: []*svid.CA{serverCA}}, true},
+		{"no client certificate", &Client{Roots: []*svid.CA{serverCA}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := handshake(context.Background(), target, tt.client.TLSConfig())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("handshake() got error %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && !got.Equal(server.Cert) {
+				t.Errorf("handshake() got certificate %v, want %v", got.Subject, server.Cert.Subject)
+			}
+		})
+	}
+}
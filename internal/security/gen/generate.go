@@ -13,13 +13,27 @@
 // limitations under the License.
 
 // package main generate  data structure and skeleton function for all rpc related to fp.
+//
+// The services are discovered from the proto descriptors registered by the
+// packages imported in protos.go, filtered by --proto_packages. The exec
+// functions already written for the RPCs are preserved: a skeleton is only
+// generated in rpcexec_stubs.go for RPCs that have none. With --check, the
+// generator only reports the RPCs added upstream or removed since rpcs.go was
+// last generated.
 package main
 
 import (
-	"bufio"
+	"bytes"
 	"flag"
 	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -27,143 +41,275 @@ import (
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 	"google.golang.org/protobuf/reflect/protoreflect"
-
-	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
-	accpb "github.com/openconfig/gnsi/acctz"
-	authzpb "github.com/openconfig/gnsi/authz"
-	certzpb "github.com/openconfig/gnsi/certz"
-	credpb "github.com/openconfig/gnsi/credentialz"
-	pathzpb "github.com/openconfig/gnsi/pathz"
-	gribipb "github.com/openconfig/gribi/v1/proto/service"
-
-	bpb "github.com/openconfig/gnoi/bgp"
-	cpb "github.com/openconfig/gnoi/cert"
-	dpb "github.com/openconfig/gnoi/diag"
-	frpb "github.com/openconfig/gnoi/factory_reset"
-	fpb "github.com/openconfig/gnoi/file"
-	hpb "github.com/openconfig/gnoi/healthz"
-	lpb "github.com/openconfig/gnoi/layer2"
-	mpb "github.com/openconfig/gnoi/mpls"
-	ospb "github.com/openconfig/gnoi/os"
-	otpb "github.com/openconfig/gnoi/otdr"
-	plqpb "github.com/openconfig/gnoi/packet_link_qualification"
-	spb "github.com/openconfig/gnoi/system"
-	wpb "github.com/openconfig/gnoi/wavelength_router"
-	p4rtpb "github.com/p4lang/p4runtime/go/p4/v1"
+	"google.golang.org/protobuf/reflect/protoregistry"
 
 	log "github.com/golang/glog"
 )
 
-var (
-	srcFolder   = flag.String("src_folder", ".", "The directory where the generated source code will be saved")
-	genExecFunc = flag.Bool("gen_exec_func", true, "if set to true, the skeleton for exec function with be generated")
-	pkgName     = flag.String("pkg_name", "gnxi", "The name of the package for the generated source code")
+const (
+	rpcsFile  = "rpcs.go"
+	stubsFile = "rpcexec_stubs.go"
 )
 
+// defaultProtoPackages are the proto packages whose services are related to
+// FP testing.
+var defaultProtoPackages = []string{
+	"gnmi",
+	"gribi",
+	"gnoi.bgp",
+	"gnoi.certificate",
+	"gnoi.diag",
+	"gnoi.factory_reset",
+	"gnoi.file",
+	"gnoi.healthz",
+	"gnoi.layer2",
+	"gnoi.mpls",
+	"gnoi.optical",
+	"gnoi.os",
+	"gnoi.packet_link_qualification",
+	"gnoi.system",
+	"gnsi.acctz.v1",
+	"gnsi.authz.v1",
+	"gnsi.certz.v1",
+	"gnsi.credentialz.v1",
+	"gnsi.pathz.v1",
+	"p4.v1",
+}
+
 var (
-	services = map[string]protoreflect.ServiceDescriptors{
-		"gnmi":                    gnmipb.File_proto_gnmi_gnmi_proto.Services(),
-		"gribi":                   gribipb.File_v1_proto_service_gribi_proto.Services(),
-		"gnsi.authz":              authzpb.File_github_com_openconfig_gnsi_authz_authz_proto.Services(),
-		"gnsi.certz":              certzpb.File_github_com_openconfig_gnsi_certz_certz_proto.Services(),
-		"gnsi.pathz":              pathzpb.File_github_com_openconfig_gnsi_pathz_pathz_proto.Services(),
-		"gnsi.cred":               credpb.File_github_com_openconfig_gnsi_credentialz_credentialz_proto.Services(),
-		"gnsi.acc":                accpb.File_github_com_openconfig_gnsi_acctz_acctz_proto.Services(),
-		"gnoi.bgp":                bpb.File_bgp_bgp_proto.Services(),
-		"gnoi.cert":               cpb.File_cert_cert_proto.Services(),
-		"gnoi.diag":               dpb.File_diag_diag_proto.Services(),
-		"gnoi.factory_reset":      frpb.File_factory_reset_factory_reset_proto.Services(),
-		"gnoi.file":               fpb.File_file_file_proto.Services(),
-		"gnoi.healthz":            hpb.File_healthz_healthz_proto.Services(),
-		"gnoi.layer2":             lpb.File_layer2_layer2_proto.Services(),
-		"gnoi.mppls":              mpb.File_mpls_mpls_proto.Services(),
-		"gnoi.os":                 ospb.File_os_os_proto.Services(),
-		"gnoi.otdr":               otpb.File_otdr_otdr_proto.Services(),
-		"gnoi.link_qualification": plqpb.File_packet_link_qualification_packet_link_qualification_proto.Services(),
-		"gnoi.system":             spb.File_system_system_proto.Services(),
-		"gnoi.wpb":                wpb.File_wavelength_router_wavelength_router_proto.Services(),
-		"p4rt":                    p4rtpb.File_p4_v1_p4runtime_proto.Services(),
-	}
+	srcFolder     = flag.String("src_folder", ".", "The directory where the generated source code will be saved")
+	genExecFunc   = flag.Bool("gen_exec_func", true, "if set to true, the skeleton for exec function will be generated for the rpcs that have none")
+	pkgName       = flag.String("pkg_name", "gnxi", "The name of the package for the generated source code")
+	protoPackages = flag.String("proto_packages", strings.Join(defaultProtoPackages, ","), "Comma separated list of proto packages whose services are included, a package also includes its sub-packages")
+	check         = flag.Bool("check", false, "if set to true, only report the rpcs added upstream or removed since the last generation, and exit with status 1 if there are any")
 )
 
 func main() {
+	flag.Parse()
 
-	rpcMap := make(map[string]*gnxi.RPC)
-	p4rtpb.File_p4_v1_p4data_proto.Services()
-	// add * that represent all grpc rpcs
-	rpcMap["ALL"] = &gnxi.RPC{
-		Name:    "*",
-		Service: "*",
-		FQN:     "*",
-		Path:    "*",
+	rpcs, err := discoverRPCs(protoregistry.GlobalFiles, strings.Split(*protoPackages, ","))
+	if err != nil {
+		log.Exit(err)
 	}
-	for serviceName, service := range services {
-		if service.Len() == 0 {
-			log.Warningf("service %s has no rpc methods\n", serviceName)
+
+	if *check {
+		existing, err := existingPaths(filepath.Join(*srcFolder, rpcsFile))
+		if err != nil {
+			log.Exit(err)
 		}
-		for i := 0; i < service.Len(); i++ {
-			log.Infof("Service %s RPCs are: \n", serviceName)
-			for j := 0; j < service.Get(i).Methods().Len(); j++ {
-				rpcName := fmt.Sprintf("%v", service.Get(i).Methods().Get(j).Name())
-				enumName := fmt.Sprintf("%s_%s", strings.ToUpper(serviceName), strings.ToUpper(rpcName))
-				rpcFQN := fmt.Sprintf("%v", service.Get(i).Methods().Get(j).FullName())
-				path := ""
-				lastPointIdx := strings.LastIndex(rpcFQN, ".")
-				if lastPointIdx > 0 {
-					path = "/" + rpcFQN[:lastPointIdx] + "/" + rpcFQN[lastPointIdx+1:]
-				}
-				serviceName = rpcFQN[:lastPointIdx]
-				rpcMap[enumName] = &gnxi.RPC{
-					Name:    rpcName,
-					Service: serviceName,
-					FQN:     rpcFQN,
-					Path:    path,
-				}
-				log.Infof("\t RPC is %v\n", rpcMap[enumName])
-				log.Infof("\t %s: %s \n", service.Get(i).Methods().Get(j).Name(), service.Get(i).Methods().Get(j).FullName())
+		added, removed := diffPaths(existing, rpcs)
+		for _, p := range added {
+			fmt.Printf("+ %s\n", p)
+		}
+		for _, p := range removed {
+			fmt.Printf("- %s\n", p)
+		}
+		if len(added)+len(removed) > 0 {
+			fmt.Printf("%d rpcs added and %d rpcs removed since %s was generated\n", len(added), len(removed), rpcsFile)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := writeSource(filepath.Join(*srcFolder, rpcsFile), authzTemplateRPCs, rpcs); err != nil {
+		log.Exit(err)
+	}
+	if !*genExecFunc {
+		return
+	}
+	funcs, err := existingFuncs(*srcFolder)
+	if err != nil {
+		log.Exit(err)
+	}
+	var stubs []*gnxi.RPC
+	for _, rpc := range rpcs {
+		if !funcs[funcName(rpc.Service, rpc.Name)] {
+			stubs = append(stubs, rpc)
+		}
+	}
+	stubsPath := filepath.Join(*srcFolder, stubsFile)
+	if len(stubs) == 0 {
+		if err := os.Remove(stubsPath); err != nil && !os.IsNotExist(err) {
+			log.Exit(err)
+		}
+		return
+	}
+	log.Infof("Generating exec function skeletons for %d rpcs in %s", len(stubs), stubsPath)
+	if err := writeSource(stubsPath, authzTemplateRPCExec, stubs); err != nil {
+		log.Exit(err)
+	}
+}
 
+// inPackages reports whether the proto package pkg is one of pkgs or one of
+// their sub-packages.
+func inPackages(pkg protoreflect.FullName, pkgs []string) bool {
+	for _, p := range pkgs {
+		p = strings.TrimSpace(p)
+		if string(pkg) == p || strings.HasPrefix(string(pkg), p+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// discoverRPCs returns the RPCs of the services registered in files whose
+// proto package is in pkgs, with a wildcard RPC for each service and for all
+// services, sorted by path. Every package must provide at least one service.
+func discoverRPCs(files *protoregistry.Files, pkgs []string) ([]*gnxi.RPC, error) {
+	// add * that represent all grpc rpcs
+	rpcs := []*gnxi.RPC{{Name: "*", Service: "*", FQN: "*", Path: "*"}}
+	found := map[string]bool{}
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		if !inPackages(fd.Package(), pkgs) {
+			return true
+		}
+		for _, p := range pkgs {
+			if fd.Services().Len() > 0 && inPackages(fd.Package(), []string{p}) {
+				found[strings.TrimSpace(p)] = true
 			}
+		}
+		for i := 0; i < fd.Services().Len(); i++ {
+			service := fd.Services().Get(i)
+			serviceName := string(service.FullName())
 			// add service/* for each service that represents all RPC for the service
-			rpcMap[strings.ToUpper(serviceName)+"_ALL"] = &gnxi.RPC{
+			rpcs = append(rpcs, &gnxi.RPC{
 				Name:    "*",
 				Service: serviceName,
 				FQN:     serviceName + ".*",
 				Path:    "/" + serviceName + "/*",
+			})
+			for j := 0; j < service.Methods().Len(); j++ {
+				method := service.Methods().Get(j)
+				rpcs = append(rpcs, &gnxi.RPC{
+					Name:    string(method.Name()),
+					Service: serviceName,
+					FQN:     string(method.FullName()),
+					Path:    "/" + serviceName + "/" + string(method.Name()),
+				})
 			}
 		}
-
+		return true
+	})
+	for _, p := range pkgs {
+		if !found[strings.TrimSpace(p)] {
+			return nil, fmt.Errorf("no service found in proto package %q, is its go package imported in protos.go?", p)
+		}
 	}
+	sort.Slice(rpcs, func(i, j int) bool { return rpcs[i].Path < rpcs[j].Path })
+	return rpcs, nil
+}
 
-	rpcsCodeFile, err := os.Create(*srcFolder + "/rpcs.go")
+// existingPaths returns the paths of the RPCs in a previously generated
+// rpcs.go.
+func existingPaths(fileName string) ([]string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), fileName, nil, 0)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	defer rpcsCodeFile.Close()
-	writer := bufio.NewWriter(rpcsCodeFile)
-	codeGenForEnums, err := template.New("codeGenForEnums").Funcs(funcMap).Parse(authzTemplateRPCs)
-	if err == nil {
-		codeGenForEnums.Execute(writer, rpcMap)
-	} else {
-		panic(fmt.Sprintf("Code generation for RPC enums failed: %v", err))
+	var paths []string
+	ast.Inspect(f, func(n ast.Node) bool {
+		kv, ok := n.(*ast.KeyValueExpr)
+		if !ok {
+			return true
+		}
+		if key, ok := kv.Key.(*ast.Ident); !ok || key.Name != "Path" {
+			return true
+		}
+		if lit, ok := kv.Value.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			if p, err := strconv.Unquote(lit.Value); err == nil {
+				paths = append(paths, p)
+			}
+		}
+		return false
+	})
+	return paths, nil
+}
+
+// diffPaths returns the sorted paths of the rpcs that are not in existing,
+// and the sorted paths in existing that are not in rpcs.
+func diffPaths(existing []string, rpcs []*gnxi.RPC) (added, removed []string) {
+	old := map[string]bool{}
+	for _, p := range existing {
+		old[p] = true
 	}
-	writer.Flush()
+	current := map[string]bool{}
+	for _, rpc := range rpcs {
+		current[rpc.Path] = true
+		if !old[rpc.Path] {
+			added = append(added, rpc.Path)
+		}
+	}
+	for p := range old {
+		if !current[p] {
+			removed = append(removed, p)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
 
-	if *genExecFunc {
-		rpcExecCodeFile, err := os.Create(*srcFolder + "/rpcexec.go")
-		if err != nil {
-			panic(err)
+// existingFuncs returns the names of the functions declared in the package in
+// dir, except in the generated skeleton file.
+func existingFuncs(dir string) (map[string]bool, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return fi.Name() != stubsFile && !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	funcs := map[string]bool{}
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			for _, decl := range f.Decls {
+				if fd, ok := decl.(*ast.FuncDecl); ok && fd.Recv == nil {
+					funcs[fd.Name.Name] = true
+				}
+			}
 		}
-		defer rpcExecCodeFile.Close()
-		writer := bufio.NewWriter(rpcExecCodeFile)
-		codeGenForExecFunction, err := template.New("codeGenForExecFunction").Funcs(funcMap).Parse(authzTemplateRPCExec)
-		if err == nil {
-			codeGenForExecFunction.Execute(writer, rpcMap)
-		} else {
-			panic(fmt.Sprintf("Code generation for RPC exec functions failed: %v", err))
+	}
+	return funcs, nil
+}
+
+// funcName returns the name of the exec function, and of the field in RPCs,
+// of the RPC name of service.
+func funcName(service, name string) string {
+	caser := cases.Title(language.English)
+	funcName := ""
+	if service != "*" {
+		parts := strings.Split(service, ".")
+		if len(parts) >= 1 {
+			funcName += caser.String(parts[0])
+			if len(parts) > 2 {
+				funcName += caser.String(parts[len(parts)-1])
+			}
 		}
-		writer.Flush()
 	}
+	if name != "*" {
+		funcName += name
+	} else {
+		funcName += "AllRPC"
+	}
+	return funcName
+}
 
+// writeSource executes the template tmpl for rpcs and writes the formatted
+// result to fileName.
+func writeSource(fileName, tmpl string, rpcs []*gnxi.RPC) error {
+	t, err := template.New(filepath.Base(fileName)).Funcs(funcMap).Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("code generation for %s failed: %v", fileName, err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, rpcs); err != nil {
+		return fmt.Errorf("code generation for %s failed: %v", fileName, err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("formatting of %s failed: %v", fileName, err)
+	}
+	return os.WriteFile(fileName, src, 0644)
 }
 
 var (
@@ -190,51 +336,26 @@ var (
 			}
 			return varName
 		},
-		"funcName": func(service, name string) string {
+		"funcName": funcName,
+		"execFunc": func(funcName string) string {
 			if !*genExecFunc {
 				return "nil"
 			}
-			caser := cases.Title(language.English)
-			funcName := ""
-			if service != "*" {
-				parts := strings.Split(service, ".")
-				if len(parts) >= 1 {
-					funcName += caser.String(parts[0])
-					if len(parts) > 2 {
-						funcName += caser.String(parts[len(parts)-1])
-					}
-				}
-			}
-			if name != "*" {
-				funcName += name
-			} else {
-				funcName += "AllRPC"
-			}
 			return funcName
 		},
-		"enumName": func(service, name string) string {
-			varName := ""
-			if service != "*" {
-				parts := strings.Split(service, ".")
-				prevPart := ""
-				for _, part := range parts {
-					if !strings.EqualFold(part, prevPart) {
-						varName += part + "_"
-						prevPart = part
-					}
-				}
-			}
-			if name != "*" {
-				varName += name
-			} else {
-				varName += "ALL"
+		"lintComment": func(varName string) string {
+			if strings.Contains(varName, "_") {
+				return " //revive:disable-line the name of the rpc includes _"
 			}
-			return strings.ToTitle(varName)
+			return ""
 		},
 	}
 
 	authzTemplateRPCExec = `package {{pkgName}}
 
+// The below code is generated using ../gen/generate.go for the rpcs that have
+// no exec function. Move a function to rpcexec.go when implementing it.
+
 import (
 	"context"
 
@@ -244,51 +365,48 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-
 {{- range .}}
-// function {{funcName .Service .Name}} implements a sample request for service {{.Path}} to validate if authz works as expected.
-func {{ funcName .Service .Name}}(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, params ...any)  error  {
+// {{funcName .Service .Name}} implements a sample request for service {{.Path}} to validate if authz works as expected.
+func {{funcName .Service .Name}}(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
 	return status.Errorf(codes.Unimplemented, "exec function for RPC {{.Path}} is not implemented")
 }
 {{ end }}
 `
-	authzTemplateRPCs = `
-// This following provide a list of all RPCs related to FP testing.
+	authzTemplateRPCs = `// Package {{pkgName}} populates a list of all RPCs related for featuresprofile tests.
 // The below code is generated using ../gen/generate.go. Please do not modify.
 package {{pkgName}}
-	type rpcs struct{
-	{{- range .}}
-		{{ funcName .Service .Name}}   *RPC
-	{{- end }}
-	}
-
 
-	var (
-		// ALL defines all FP related RPCs
+type rpcs struct {
+{{- range .}}
+	{{funcName .Service .Name}} *RPC
+{{- end}}
+}
 
-		{{- range .}}
-		{{ varName .Service .Name}} = &RPC{
-			Name: "{{.Name}}",
-			Service: "{{.Service}}",
-			FQN: "{{.FQN}}",
-			Path: "{{.Path}}",
-			Exec: {{ funcName .Service .Name}},
-		}
-		{{- end }}
+var (
+	// ALL defines all FP related RPCs
+{{- range .}}
+	{{varName .Service .Name}} = &RPC{ {{- lintComment (varName .Service .Name)}}
+		Name:    "{{.Name}}",
+		Service: "{{.Service}}",
+		FQN:     "{{.FQN}}",
+		Path:    "{{.Path}}",
+		Exec:    {{execFunc (funcName .Service .Name)}},
+	}
+{{- end}}
 
-		// RPCs is a list of all FP related RPCs
-		RPCs=rpcs{
-		{{- range .}}
-			{{ funcName .Service .Name}} : {{ varName .Service .Name}},
-		{{- end }}
-		}
+	// RPCs is a list of all FP related RPCs
+	RPCs = rpcs{
+{{- range .}}
+		{{funcName .Service .Name}}: {{varName .Service .Name}},
+{{- end}}
+	}
 
-		// RPCMAP is a helper that  maps path to RPCs data that may be needed in tests.
-		RPCMAP=map[string]*RPC{
-			{{- range .}}
-				"{{.Path}}" : {{ varName .Service .Name}},
-			{{- end }}
-		}
-	)
+	// RPCMAP is a helper that  maps path to RPCs data that may be needed in tests.
+	RPCMAP = map[string]*RPC{
+{{- range .}}
+		"{{.Path}}": {{varName .Service .Name}},
+{{- end}}
+	}
+)
 `
 )
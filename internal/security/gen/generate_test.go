@@ -0,0 +1,68 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+func TestDiscoverRPCs(t *testing.T) {
+	rpcs, err := discoverRPCs(protoregistry.GlobalFiles, []string{"gnsi.authz.v1", " gribi"})
+	if err != nil {
+		t.Fatalf("discoverRPCs() got unexpected error: %v", err)
+	}
+	var got []string
+	for _, rpc := range rpcs {
+		got = append(got, rpc.Path)
+	}
+	want := []string{
+		"*",
+		"/gnsi.authz.v1.Authz/*",
+		"/gnsi.authz.v1.Authz/Get",
+		"/gnsi.authz.v1.Authz/Probe",
+		"/gnsi.authz.v1.Authz/Rotate",
+		"/gribi.gRIBI/*",
+		"/gribi.gRIBI/Flush",
+		"/gribi.gRIBI/Get",
+		"/gribi.gRIBI/Modify",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("discoverRPCs() got unexpected diff (-want +got):\n%s", diff)
+	}
+	if got, want := funcName(rpcs[3].Service, rpcs[3].Name), "GnsiAuthzProbe"; got != want {
+		t.Errorf("funcName() got %s, want %s", got, want)
+	}
+
+	if _, err := discoverRPCs(protoregistry.GlobalFiles, []string{"gnoi.containerz"}); err == nil {
+		t.Errorf("discoverRPCs() of a package that is not imported got no error, want error")
+	}
+}
+
+func TestDiffPaths(t *testing.T) {
+	rpcs, err := discoverRPCs(protoregistry.GlobalFiles, []string{"gribi"})
+	if err != nil {
+		t.Fatalf("discoverRPCs() got unexpected error: %v", err)
+	}
+	added, removed := diffPaths([]string{"*", "/gribi.gRIBI/*", "/gribi.gRIBI/Get", "/gribi.gRIBI/Modify", "/gribi.gRIBI/Old"}, rpcs)
+	if diff := cmp.Diff([]string{"/gribi.gRIBI/Flush"}, added); diff != "" {
+		t.Errorf("diffPaths() got unexpected added diff (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"/gribi.gRIBI/Old"}, removed); diff != "" {
+		t.Errorf("diffPaths() got unexpected removed diff (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+// The packages below register the proto descriptors of the services that can
+// be selected with --proto_packages. Import the go package of a new service
+// here to make it available to the generator.
+import (
+	_ "github.com/openconfig/gnmi/proto/gnmi"
+	_ "github.com/openconfig/gnoi/bgp"
+	_ "github.com/openconfig/gnoi/cert"
+	_ "github.com/openconfig/gnoi/diag"
+	_ "github.com/openconfig/gnoi/factory_reset"
+	_ "github.com/openconfig/gnoi/file"
+	_ "github.com/openconfig/gnoi/healthz"
+	_ "github.com/openconfig/gnoi/layer2"
+	_ "github.com/openconfig/gnoi/mpls"
+	_ "github.com/openconfig/gnoi/os"
+	_ "github.com/openconfig/gnoi/otdr"
+	_ "github.com/openconfig/gnoi/packet_link_qualification"
+	_ "github.com/openconfig/gnoi/system"
+	_ "github.com/openconfig/gnoi/wavelength_router"
+	_ "github.com/openconfig/gnsi/acctz"
+	_ "github.com/openconfig/gnsi/authz"
+	_ "github.com/openconfig/gnsi/certz"
+	_ "github.com/openconfig/gnsi/credentialz"
+	_ "github.com/openconfig/gnsi/pathz"
+	_ "github.com/openconfig/gribi/v1/proto/service"
+	_ "github.com/p4lang/p4runtime/go/p4/v1"
+)
@@ -3,6 +3,7 @@ package gnxi
 import (
 	"context"
 	"io"
+	"math"
 	"strings"
 	"time"
 
@@ -14,19 +15,103 @@ import (
 	"google.golang.org/grpc/status"
 
 	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	bpb "github.com/openconfig/gnoi/bgp"
+	cmpb "github.com/openconfig/gnoi/cert"
+	dpb "github.com/openconfig/gnoi/diag"
+	fpb "github.com/openconfig/gnoi/file"
+	hpb "github.com/openconfig/gnoi/healthz"
+	lpb "github.com/openconfig/gnoi/layer2"
+	mpb "github.com/openconfig/gnoi/mpls"
+	ospb "github.com/openconfig/gnoi/os"
+	otpb "github.com/openconfig/gnoi/otdr"
+	plqpb "github.com/openconfig/gnoi/packet_link_qualification"
 	spb "github.com/openconfig/gnoi/system"
+	tpb "github.com/openconfig/gnoi/types"
+	wrpb "github.com/openconfig/gnoi/wavelength_router"
+	acctzpb "github.com/openconfig/gnsi/acctz"
 	authzpb "github.com/openconfig/gnsi/authz"
+	certzpb "github.com/openconfig/gnsi/certz"
+	credzpb "github.com/openconfig/gnsi/credentialz"
+	pathzpb "github.com/openconfig/gnsi/pathz"
 	grpb "github.com/openconfig/gribi/v1/proto/service"
+	p4pb "github.com/p4lang/p4runtime/go/p4/v1"
+	tspb "google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// The exec functions below are safe to run against a production DUT. RPCs
+// that only read state are called with valid arguments and must succeed
+// when permitted. RPCs that change state are called with arguments that
+// name nonexistent entities or are otherwise invalid, so that the request
+// reaches the authz layer but is rejected by the service afterwards:
+//
+//   - a permitted call returns nil; the InvalidArgument, NotFound,
+//     FailedPrecondition, OutOfRange, AlreadyExists and Aborted codes
+//     returned by the service are mapped to nil by authorized;
+//   - a denied call returns PermissionDenied;
+//   - other errors, such as Unauthenticated or Unavailable, are returned
+//     as is and indicate a problem with the DUT or the connection;
+//   - Unimplemented is returned for RPCs that are deliberately not executed
+//     because no request is safe, such as FactoryReset.Start.
+
+const (
+	// nonexistent names an entity that does not exist on the DUT.
+	nonexistent = "authz-exec-nonexistent"
+	// invalidRebootMethod is the reboot method gNOI defines as invalid, so
+	// the reboot request is rejected by the service.
+	invalidRebootMethod = spb.RebootMethod_UNKNOWN
+	// p4rtDeviceID is the P4RT device ID used by read-only requests, and
+	// unknownP4RTDeviceID a device ID that no DUT uses. Devices check the
+	// device ID before the election ID of write requests.
+	p4rtDeviceID        = 1
+	unknownP4RTDeviceID = math.MaxUint64
+	// acctzTimeout bounds the wait for the first accounting record.
+	acctzTimeout = 5 * time.Second
+)
+
+var (
+	nonexistentComponent = &tpb.Path{
+		Origin: "openconfig",
+		Elem: []*tpb.PathElem{
+			{Name: "components"},
+			{Name: "component", Key: map[string]string{"name": nonexistent}},
+		},
+	}
+	nonexistentInterface = &tpb.Path{
+		Origin: "openconfig",
+		Elem: []*tpb.PathElem{
+			{Name: "interfaces"},
+			{Name: "interface", Key: map[string]string{"name": nonexistent}},
+		},
+	}
+)
+
+// authorized maps the errors returned by a service for a request that
+// passed the authz layer, but whose arguments were rejected, to nil.
+func authorized(err error) error {
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.NotFound, codes.FailedPrecondition, codes.OutOfRange, codes.AlreadyExists, codes.Aborted:
+		return nil
+	}
+	return err
+}
+
+// recvErr receives one message from a stream and returns the error, if
+// any. The end of the stream is not an error.
+func recvErr[T any](recv func() (T, error)) error {
+	if _, err := recv(); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
 // AllRPC implements a sample request for service * to validate if authz works as expected.
-func AllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC * is not implemented")
+func AllRPC(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	return GnmiCapabilities(ctx, dut, opts)
 }
 
 // GnmiAllRPC implements a sample request for service /gnmi.gNMI/* to validate if authz works as expected.
-func GnmiAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnmi.gNMI/* is not implemented")
+func GnmiAllRPC(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	return GnmiCapabilities(ctx, dut, opts)
 }
 
 // GnmiGet implements a sample request for service /gnmi.gNMI/Get to validate if authz works as expected.
@@ -93,313 +178,610 @@ func GnmiCapabilities(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.D
 }
 
 // GnoiBgpAllRPC implements a sample request for service /gnoi.bgp.BGP/* to validate if authz works as expected.
-func GnoiBgpAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.bgp.BGP/* is not implemented")
+func GnoiBgpAllRPC(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	return GnoiBgpClearBGPNeighbor(ctx, dut, opts)
 }
 
 // GnoiBgpClearBGPNeighbor implements a sample request for service /gnoi.bgp.BGP/ClearBGPNeighbor to validate if authz works as expected.
-func GnoiBgpClearBGPNeighbor(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.bgp.BGP/ClearBGPNeighbor is not implemented")
+func GnoiBgpClearBGPNeighbor(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.BGP().ClearBGPNeighbor(ctx, &bpb.ClearBGPNeighborRequest{Address: "192.0.2.1", RoutingInstance: nonexistent, Mode: bpb.ClearBGPNeighborRequest_SOFT})
+	return authorized(err)
 }
 
 // GnoiCertificatemanagementAllRPC implements a sample request for service /gnoi.certificate.CertificateManagement/* to validate if authz works as expected.
-func GnoiCertificatemanagementAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.certificate.CertificateManagement/* is not implemented")
+func GnoiCertificatemanagementAllRPC(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	return GnoiCertificatemanagementGetCertificates(ctx, dut, opts)
 }
 
 // GnoiCertificatemanagementCanGenerateCSR implements a sample request for service /gnoi.certificate.CertificateManagement/CanGenerateCSR to validate if authz works as expected.
-func GnoiCertificatemanagementCanGenerateCSR(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.certificate.CertificateManagement/CanGenerateCSR is not implemented")
+func GnoiCertificatemanagementCanGenerateCSR(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.CertificateManagement().CanGenerateCSR(ctx, &cmpb.CanGenerateCSRRequest{KeyType: cmpb.KeyType_KT_RSA, CertificateType: cmpb.CertificateType_CT_X509, KeySize: 2048})
+	return err
 }
 
 // GnoiCertificatemanagementGenerateCSR implements a sample request for service /gnoi.certificate.CertificateManagement/GenerateCSR to validate if authz works as expected.
-func GnoiCertificatemanagementGenerateCSR(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.certificate.CertificateManagement/GenerateCSR is not implemented")
+func GnoiCertificatemanagementGenerateCSR(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.CertificateManagement().GenerateCSR(ctx, &cmpb.GenerateCSRRequest{CertificateId: nonexistent})
+	return authorized(err)
 }
 
 // GnoiCertificatemanagementGetCertificates implements a sample request for service /gnoi.certificate.CertificateManagement/GetCertificates to validate if authz works as expected.
-func GnoiCertificatemanagementGetCertificates(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.certificate.CertificateManagement/GetCertificates is not implemented")
+func GnoiCertificatemanagementGetCertificates(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.CertificateManagement().GetCertificates(ctx, &cmpb.GetCertificatesRequest{})
+	return err
 }
 
 // GnoiCertificatemanagementInstall implements a sample request for service /gnoi.certificate.CertificateManagement/Install to validate if authz works as expected.
-func GnoiCertificatemanagementInstall(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.certificate.CertificateManagement/Install is not implemented")
+func GnoiCertificatemanagementInstall(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	stream, err := gnoiC.CertificateManagement().Install(ctx)
+	if err != nil {
+		return authorized(err)
+	}
+	defer stream.CloseSend()
+	if err := stream.Send(&cmpb.InstallCertificateRequest{InstallRequest: &cmpb.InstallCertificateRequest_GenerateCsr{GenerateCsr: &cmpb.GenerateCSRRequest{CertificateId: nonexistent}}}); err != nil && err != io.EOF {
+		return authorized(err)
+	}
+	return authorized(recvErr(stream.Recv))
 }
 
 // GnoiCertificatemanagementLoadCertificate implements a sample request for service /gnoi.certificate.CertificateManagement/LoadCertificate to validate if authz works as expected.
-func GnoiCertificatemanagementLoadCertificate(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.certificate.CertificateManagement/LoadCertificate is not implemented")
+func GnoiCertificatemanagementLoadCertificate(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.CertificateManagement().LoadCertificate(ctx, &cmpb.LoadCertificateRequest{})
+	return authorized(err)
 }
 
 // GnoiCertificatemanagementLoadCertificateAuthorityBundle implements a sample request for service /gnoi.certificate.CertificateManagement/LoadCertificateAuthorityBundle to validate if authz works as expected.
-func GnoiCertificatemanagementLoadCertificateAuthorityBundle(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.certificate.CertificateManagement/LoadCertificateAuthorityBundle is not implemented")
+func GnoiCertificatemanagementLoadCertificateAuthorityBundle(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.CertificateManagement().LoadCertificateAuthorityBundle(ctx, &cmpb.LoadCertificateAuthorityBundleRequest{CaCertificates: []*cmpb.Certificate{{Type: cmpb.CertificateType_CT_X509, Certificate: []byte(nonexistent)}}})
+	return authorized(err)
 }
 
 // GnoiCertificatemanagementRevokeCertificates implements a sample request for service /gnoi.certificate.CertificateManagement/RevokeCertificates to validate if authz works as expected.
-func GnoiCertificatemanagementRevokeCertificates(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.certificate.CertificateManagement/RevokeCertificates is not implemented")
+func GnoiCertificatemanagementRevokeCertificates(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.CertificateManagement().RevokeCertificates(ctx, &cmpb.RevokeCertificatesRequest{CertificateId: []string{nonexistent}})
+	return authorized(err)
 }
 
 // GnoiCertificatemanagementRotate implements a sample request for service /gnoi.certificate.CertificateManagement/Rotate to validate if authz works as expected.
-func GnoiCertificatemanagementRotate(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.certificate.CertificateManagement/Rotate is not implemented")
+func GnoiCertificatemanagementRotate(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	stream, err := gnoiC.CertificateManagement().Rotate(ctx)
+	if err != nil {
+		return authorized(err)
+	}
+	defer stream.CloseSend()
+	if err := stream.Send(&cmpb.RotateCertificateRequest{RotateRequest: &cmpb.RotateCertificateRequest_FinalizeRotation{FinalizeRotation: &cmpb.FinalizeRequest{}}}); err != nil && err != io.EOF {
+		return authorized(err)
+	}
+	return authorized(recvErr(stream.Recv))
 }
 
 // GnoiDiagAllRPC implements a sample request for service /gnoi.diag.Diag/* to validate if authz works as expected.
-func GnoiDiagAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.diag.Diag/* is not implemented")
+func GnoiDiagAllRPC(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	return GnoiDiagGetBERTResult(ctx, dut, opts)
 }
 
 // GnoiDiagGetBERTResult implements a sample request for service /gnoi.diag.Diag/GetBERTResult to validate if authz works as expected.
-func GnoiDiagGetBERTResult(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.diag.Diag/GetBERTResult is not implemented")
+func GnoiDiagGetBERTResult(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.Diag().GetBERTResult(ctx, &dpb.GetBERTResultRequest{BertOperationId: nonexistent})
+	return authorized(err)
 }
 
 // GnoiDiagStopBERT implements a sample request for service /gnoi.diag.Diag/StopBERT to validate if authz works as expected.
-func GnoiDiagStopBERT(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.diag.Diag/StopBERT is not implemented")
+func GnoiDiagStopBERT(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.Diag().StopBERT(ctx, &dpb.StopBERTRequest{BertOperationId: nonexistent})
+	return authorized(err)
 }
 
 // GnoiDiagStartBERT implements a sample request for service /gnoi.diag.Diag/StartBERT to validate if authz works as expected.
-func GnoiDiagStartBERT(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.diag.Diag/StartBERT is not implemented")
+func GnoiDiagStartBERT(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.Diag().StartBERT(ctx, &dpb.StartBERTRequest{BertOperationId: nonexistent})
+	return authorized(err)
 }
 
 // GnoiFactoryresetAllRPC implements a sample request for service /gnoi.factory_reset.FactoryReset/* to validate if authz works as expected.
+//
+// It is not executed: gNOI FactoryReset has no RPC that is safe to execute.
 func GnoiFactoryresetAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.factory_reset.FactoryReset/* is not implemented")
+	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.factory_reset.FactoryReset/* is not implemented: gNOI FactoryReset has no RPC that is safe to execute")
 }
 
 // GnoiFactoryresetStart implements a sample request for service /gnoi.factory_reset.FactoryReset/Start to validate if authz works as expected.
+//
+// It is not executed: every StartRequest is a valid request to factory reset the device, so the RPC is deliberately not executed.
 func GnoiFactoryresetStart(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.factory_reset.FactoryReset/Start is not implemented")
+	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.factory_reset.FactoryReset/Start is not implemented: every StartRequest is a valid request to factory reset the device, so the RPC is deliberately not executed")
 }
 
 // GnoiFileAllRPC implements a sample request for service /gnoi.file.File/* to validate if authz works as expected.
-func GnoiFileAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.file.File/* is not implemented")
+func GnoiFileAllRPC(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	return GnoiFileStat(ctx, dut, opts)
 }
 
 // GnoiFilePut implements a sample request for service /gnoi.file.File/Put to validate if authz works as expected.
-func GnoiFilePut(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.file.File/Put is not implemented")
+func GnoiFilePut(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	stream, err := gnoiC.File().Put(ctx)
+	if err != nil {
+		return authorized(err)
+	}
+	if err := stream.Send(&fpb.PutRequest{Request: &fpb.PutRequest_Open{Open: &fpb.PutRequest_Details{}}}); err != nil && err != io.EOF {
+		return authorized(err)
+	}
+	_, err = stream.CloseAndRecv()
+	return authorized(err)
 }
 
 // GnoiFileRemove implements a sample request for service /gnoi.file.File/Remove to validate if authz works as expected.
-func GnoiFileRemove(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.file.File/Remove is not implemented")
+func GnoiFileRemove(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.File().Remove(ctx, &fpb.RemoveRequest{RemoteFile: "/" + nonexistent})
+	return authorized(err)
 }
 
 // GnoiFileStat implements a sample request for service /gnoi.file.File/Stat to validate if authz works as expected.
-func GnoiFileStat(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.file.File/Stat is not implemented")
+func GnoiFileStat(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.File().Stat(ctx, &fpb.StatRequest{Path: "/"})
+	return authorized(err)
 }
 
 // GnoiFileTransferToRemote implements a sample request for service /gnoi.file.File/TransferToRemote to validate if authz works as expected.
-func GnoiFileTransferToRemote(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.file.File/TransferToRemote is not implemented")
+func GnoiFileTransferToRemote(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.File().TransferToRemote(ctx, &fpb.TransferToRemoteRequest{LocalPath: "/" + nonexistent})
+	return authorized(err)
 }
 
 // GnoiFileGet implements a sample request for service /gnoi.file.File/Get to validate if authz works as expected.
-func GnoiFileGet(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.file.File/Get is not implemented")
+func GnoiFileGet(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	stream, err := gnoiC.File().Get(ctx, &fpb.GetRequest{RemoteFile: "/" + nonexistent})
+	if err != nil {
+		return authorized(err)
+	}
+	return authorized(recvErr(stream.Recv))
 }
 
 // GnoiHealthzAcknowledge implements a sample request for service /gnoi.healthz.Healthz/Acknowledge to validate if authz works as expected.
-func GnoiHealthzAcknowledge(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.healthz.Healthz/Acknowledge is not implemented")
+func GnoiHealthzAcknowledge(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.Healthz().Acknowledge(ctx, &hpb.AcknowledgeRequest{Path: nonexistentComponent, Id: nonexistent})
+	return authorized(err)
 }
 
 // GnoiHealthzAllRPC implements a sample request for service /gnoi.healthz.Healthz/* to validate if authz works as expected.
-func GnoiHealthzAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.healthz.Healthz/* is not implemented")
+func GnoiHealthzAllRPC(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	return GnoiHealthzList(ctx, dut, opts)
 }
 
 // GnoiHealthzArtifact implements a sample request for service /gnoi.healthz.Healthz/Artifact to validate if authz works as expected.
-func GnoiHealthzArtifact(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.healthz.Healthz/Artifact is not implemented")
+func GnoiHealthzArtifact(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	stream, err := gnoiC.Healthz().Artifact(ctx, &hpb.ArtifactRequest{Id: nonexistent})
+	if err != nil {
+		return authorized(err)
+	}
+	return authorized(recvErr(stream.Recv))
 }
 
 // GnoiHealthzCheck implements a sample request for service /gnoi.healthz.Healthz/Check to validate if authz works as expected.
-func GnoiHealthzCheck(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.healthz.Healthz/Check is not implemented")
+func GnoiHealthzCheck(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.Healthz().Check(ctx, &hpb.CheckRequest{Path: nonexistentComponent})
+	return authorized(err)
 }
 
 // GnoiHealthzList implements a sample request for service /gnoi.healthz.Healthz/List to validate if authz works as expected.
-func GnoiHealthzList(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.healthz.Healthz/List is not implemented")
+func GnoiHealthzList(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.Healthz().List(ctx, &hpb.ListRequest{Path: nonexistentComponent})
+	return authorized(err)
 }
 
 // GnoiHealthzGet implements a sample request for service /gnoi.healthz.Healthz/Get to validate if authz works as expected.
-func GnoiHealthzGet(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.healthz.Healthz/Get is not implemented")
+func GnoiHealthzGet(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.Healthz().Get(ctx, &hpb.GetRequest{Path: nonexistentComponent})
+	return authorized(err)
 }
 
 // GnoiLayer2AllRPC implements a sample request for service /gnoi.layer2.Layer2/* to validate if authz works as expected.
-func GnoiLayer2AllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.layer2.Layer2/* is not implemented")
+func GnoiLayer2AllRPC(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	return GnoiLayer2ClearLLDPInterface(ctx, dut, opts)
 }
 
 // GnoiLayer2ClearLLDPInterface implements a sample request for service /gnoi.layer2.Layer2/ClearLLDPInterface to validate if authz works as expected.
-func GnoiLayer2ClearLLDPInterface(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.layer2.Layer2/ClearLLDPInterface is not implemented")
+func GnoiLayer2ClearLLDPInterface(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.Layer2().ClearLLDPInterface(ctx, &lpb.ClearLLDPInterfaceRequest{Interface: nonexistentInterface})
+	return authorized(err)
 }
 
 // GnoiLayer2ClearSpanningTree implements a sample request for service /gnoi.layer2.Layer2/ClearSpanningTree to validate if authz works as expected.
-func GnoiLayer2ClearSpanningTree(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.layer2.Layer2/ClearSpanningTree is not implemented")
+func GnoiLayer2ClearSpanningTree(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.Layer2().ClearSpanningTree(ctx, &lpb.ClearSpanningTreeRequest{Interface: nonexistentInterface})
+	return authorized(err)
 }
 
 // GnoiLayer2PerformBERT implements a sample request for service /gnoi.layer2.Layer2/PerformBERT to validate if authz works as expected.
-func GnoiLayer2PerformBERT(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.layer2.Layer2/PerformBERT is not implemented")
+func GnoiLayer2PerformBERT(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	stream, err := gnoiC.Layer2().PerformBERT(ctx, &lpb.PerformBERTRequest{Id: nonexistent, Interface: nonexistentInterface})
+	if err != nil {
+		return authorized(err)
+	}
+	return authorized(recvErr(stream.Recv))
 }
 
 // GnoiLayer2SendWakeOnLAN implements a sample request for service /gnoi.layer2.Layer2/SendWakeOnLAN to validate if authz works as expected.
-func GnoiLayer2SendWakeOnLAN(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.layer2.Layer2/SendWakeOnLAN is not implemented")
+func GnoiLayer2SendWakeOnLAN(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.Layer2().SendWakeOnLAN(ctx, &lpb.SendWakeOnLANRequest{Interface: nonexistentInterface})
+	return authorized(err)
 }
 
 // GnoiLayer2ClearNeighborDiscovery implements a sample request for service /gnoi.layer2.Layer2/ClearNeighborDiscovery to validate if authz works as expected.
-func GnoiLayer2ClearNeighborDiscovery(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.layer2.Layer2/ClearNeighborDiscovery is not implemented")
+func GnoiLayer2ClearNeighborDiscovery(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.Layer2().ClearNeighborDiscovery(ctx, &lpb.ClearNeighborDiscoveryRequest{Protocol: tpb.L3Protocol_IPV6, Address: "2001:db8::dead"})
+	return authorized(err)
 }
 
 // GnoiLinkqualificationCreate implements a sample request for service /gnoi.packet_link_qualification.LinkQualification/Create to validate if authz works as expected.
-func GnoiLinkqualificationCreate(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.packet_link_qualification.LinkQualification/Create is not implemented")
+func GnoiLinkqualificationCreate(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.LinkQualification().Create(ctx, &plqpb.CreateRequest{Interfaces: []*plqpb.QualificationConfiguration{{Id: nonexistent, InterfaceName: nonexistent}}})
+	return authorized(err)
 }
 
 // GnoiMplsAllRPC implements a sample request for service /gnoi.mpls.MPLS/* to validate if authz works as expected.
-func GnoiMplsAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.mpls.MPLS/* is not implemented")
+func GnoiMplsAllRPC(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	return GnoiMplsClearLSPCounters(ctx, dut, opts)
 }
 
 // GnoiMplsClearLSPCounters implements a sample request for service /gnoi.mpls.MPLS/ClearLSPCounters to validate if authz works as expected.
-func GnoiMplsClearLSPCounters(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.mpls.MPLS/ClearLSPCounters is not implemented")
+func GnoiMplsClearLSPCounters(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.MPLS().ClearLSPCounters(ctx, &mpb.ClearLSPCountersRequest{Name: nonexistent})
+	return authorized(err)
 }
 
 // GnoiMplsMPLSPing implements a sample request for service /gnoi.mpls.MPLS/MPLSPing to validate if authz works as expected.
-func GnoiMplsMPLSPing(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.mpls.MPLS/MPLSPing is not implemented")
+func GnoiMplsMPLSPing(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	stream, err := gnoiC.MPLS().MPLSPing(ctx, &mpb.MPLSPingRequest{})
+	if err != nil {
+		return authorized(err)
+	}
+	return authorized(recvErr(stream.Recv))
 }
 
 // GnoiMplsClearLSP implements a sample request for service /gnoi.mpls.MPLS/ClearLSP to validate if authz works as expected.
-func GnoiMplsClearLSP(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.mpls.MPLS/ClearLSP is not implemented")
+func GnoiMplsClearLSP(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.MPLS().ClearLSP(ctx, &mpb.ClearLSPRequest{Name: nonexistent})
+	return authorized(err)
 }
 
 // GnoiOtdrAllRPC implements a sample request for service /gnoi.optical.OTDR/* to validate if authz works as expected.
-func GnoiOtdrAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.optical.OTDR/* is not implemented")
+func GnoiOtdrAllRPC(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	return GnoiOtdrInitiate(ctx, dut, opts)
 }
 
 // GnoiWavelengthrouterAdjustSpectrum implements a sample request for service /gnoi.optical.WavelengthRouter/AdjustSpectrum to validate if authz works as expected.
-func GnoiWavelengthrouterAdjustSpectrum(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.optical.WavelengthRouter/AdjustSpectrum is not implemented")
+func GnoiWavelengthrouterAdjustSpectrum(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	stream, err := gnoiC.WavelengthRouter().AdjustSpectrum(ctx, &wrpb.AdjustSpectrumRequest{Component: nonexistentComponent})
+	if err != nil {
+		return authorized(err)
+	}
+	return authorized(recvErr(stream.Recv))
 }
 
 // GnoiWavelengthrouterAllRPC implements a sample request for service /gnoi.optical.WavelengthRouter/* to validate if authz works as expected.
-func GnoiWavelengthrouterAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.optical.WavelengthRouter/* is not implemented")
+func GnoiWavelengthrouterAllRPC(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	return GnoiWavelengthrouterCancelAdjustPSD(ctx, dut, opts)
 }
 
 // GnoiWavelengthrouterCancelAdjustPSD implements a sample request for service /gnoi.optical.WavelengthRouter/CancelAdjustPSD to validate if authz works as expected.
-func GnoiWavelengthrouterCancelAdjustPSD(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.optical.WavelengthRouter/CancelAdjustPSD is not implemented")
+func GnoiWavelengthrouterCancelAdjustPSD(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.WavelengthRouter().CancelAdjustPSD(ctx, &wrpb.AdjustPSDRequest{Component: nonexistentComponent})
+	return authorized(err)
 }
 
 // GnoiWavelengthrouterCancelAdjustSpectrum implements a sample request for service /gnoi.optical.WavelengthRouter/CancelAdjustSpectrum to validate if authz works as expected.
-func GnoiWavelengthrouterCancelAdjustSpectrum(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.optical.WavelengthRouter/CancelAdjustSpectrum is not implemented")
+func GnoiWavelengthrouterCancelAdjustSpectrum(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.WavelengthRouter().CancelAdjustSpectrum(ctx, &wrpb.AdjustSpectrumRequest{Component: nonexistentComponent})
+	return authorized(err)
 }
 
 // GnoiOsActivate implements a sample request for service /gnoi.os.OS/Activate to validate if authz works as expected.
-func GnoiOsActivate(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.os.OS/Activate is not implemented")
+func GnoiOsActivate(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.OS().Activate(ctx, &ospb.ActivateRequest{Version: nonexistent, NoReboot: true})
+	return authorized(err)
 }
 
 // GnoiOsAllRPC implements a sample request for service /gnoi.os.OS/* to validate if authz works as expected.
-func GnoiOsAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.os.OS/* is not implemented")
+func GnoiOsAllRPC(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	return GnoiOsVerify(ctx, dut, opts)
 }
 
 // GnoiOsVerify implements a sample request for service /gnoi.os.OS/Verify to validate if authz works as expected.
-func GnoiOsVerify(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.os.OS/Verify is not implemented")
+func GnoiOsVerify(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.OS().Verify(ctx, &ospb.VerifyRequest{})
+	return err
 }
 
 // GnoiOsInstall implements a sample request for service /gnoi.os.OS/Install to validate if authz works as expected.
-func GnoiOsInstall(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.os.OS/Install is not implemented")
+func GnoiOsInstall(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	stream, err := gnoiC.OS().Install(ctx)
+	if err != nil {
+		return authorized(err)
+	}
+	defer stream.CloseSend()
+	if err := stream.Send(&ospb.InstallRequest{Request: &ospb.InstallRequest_TransferEnd{TransferEnd: &ospb.TransferEnd{}}}); err != nil && err != io.EOF {
+		return authorized(err)
+	}
+	return authorized(recvErr(stream.Recv))
 }
 
 // GnoiOtdrInitiate implements a sample request for service /gnoi.optical.OTDR/Initiate to validate if authz works as expected.
-func GnoiOtdrInitiate(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.optical.OTDR/Initiate is not implemented")
+func GnoiOtdrInitiate(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	stream, err := gnoiC.OTDR().Initiate(ctx, &otpb.InitiateRequest{Component: nonexistentComponent})
+	if err != nil {
+		return authorized(err)
+	}
+	return authorized(recvErr(stream.Recv))
 }
 
 // GnoiLinkqualificationAllRPC implements a sample request for service /gnoi.packet_link_qualification.LinkQualification/* to validate if authz works as expected.
-func GnoiLinkqualificationAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.packet_link_qualification.LinkQualification/* is not implemented")
+func GnoiLinkqualificationAllRPC(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	return GnoiLinkqualificationCapabilities(ctx, dut, opts)
 }
 
 // GnoiLinkqualificationCapabilities implements a sample request for service /gnoi.packet_link_qualification.LinkQualification/Capabilities to validate if authz works as expected.
-func GnoiLinkqualificationCapabilities(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.packet_link_qualification.LinkQualification/Capabilities is not implemented")
+func GnoiLinkqualificationCapabilities(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.LinkQualification().Capabilities(ctx, &plqpb.CapabilitiesRequest{})
+	return err
 }
 
 // GnoiLinkqualificationDelete implements a sample request for service /gnoi.packet_link_qualification.LinkQualification/Delete to validate if authz works as expected.
-func GnoiLinkqualificationDelete(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.packet_link_qualification.LinkQualification/Delete is not implemented")
+func GnoiLinkqualificationDelete(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.LinkQualification().Delete(ctx, &plqpb.DeleteRequest{Ids: []string{nonexistent}})
+	return authorized(err)
 }
 
 // GnoiLinkqualificationGet implements a sample request for service /gnoi.packet_link_qualification.LinkQualification/Get to validate if authz works as expected.
-func GnoiLinkqualificationGet(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.packet_link_qualification.LinkQualification/Get is not implemented")
+func GnoiLinkqualificationGet(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.LinkQualification().Get(ctx, &plqpb.GetRequest{Ids: []string{nonexistent}})
+	return authorized(err)
 }
 
 // GnoiLinkqualificationList implements a sample request for service /gnoi.packet_link_qualification.LinkQualification/List to validate if authz works as expected.
-func GnoiLinkqualificationList(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.packet_link_qualification.LinkQualification/List is not implemented")
+func GnoiLinkqualificationList(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.LinkQualification().List(ctx, &plqpb.ListRequest{})
+	return err
 }
 
 // GnoiSystemAllRPC implements a sample request for service /gnoi.system.System/* to validate if authz works as expected.
-func GnoiSystemAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.system.System/* is not implemented")
+func GnoiSystemAllRPC(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	return GnoiSystemTime(ctx, dut, opts)
 }
 
 // GnoiSystemCancelReboot implements a sample request for service /gnoi.system.System/CancelReboot to validate if authz works as expected.
-func GnoiSystemCancelReboot(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.system.System/CancelReboot is not implemented")
+func GnoiSystemCancelReboot(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.System().CancelReboot(ctx, &spb.CancelRebootRequest{Subcomponents: []*tpb.Path{nonexistentComponent}})
+	return authorized(err)
 }
 
 // GnoiSystemKillProcess implements a sample request for service /gnoi.system.System/KillProcess to validate if authz works as expected.
-func GnoiSystemKillProcess(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.system.System/KillProcess is not implemented")
+func GnoiSystemKillProcess(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.System().KillProcess(ctx, &spb.KillProcessRequest{Name: nonexistent, Signal: spb.KillProcessRequest_SIGNAL_TERM})
+	return authorized(err)
 }
 
 // GnoiSystemReboot implements a sample request for service /gnoi.system.System/Reboot to validate if authz works as expected.
-func GnoiSystemReboot(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.system.System/Reboot is not implemented")
+func GnoiSystemReboot(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.System().Reboot(ctx, &spb.RebootRequest{Method: invalidRebootMethod, Subcomponents: []*tpb.Path{nonexistentComponent}, Message: nonexistent})
+	return authorized(err)
 }
 
 // GnoiSystemRebootStatus implements a sample request for service /gnoi.system.System/RebootStatus to validate if authz works as expected.
-func GnoiSystemRebootStatus(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.system.System/RebootStatus is not implemented")
+func GnoiSystemRebootStatus(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.System().RebootStatus(ctx, &spb.RebootStatusRequest{})
+	return err
 }
 
 // GnoiSystemSetPackage implements a sample request for service /gnoi.system.System/SetPackage to validate if authz works as expected.
-func GnoiSystemSetPackage(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.system.System/SetPackage is not implemented")
+func GnoiSystemSetPackage(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	stream, err := gnoiC.System().SetPackage(ctx)
+	if err != nil {
+		return authorized(err)
+	}
+	if err := stream.Send(&spb.SetPackageRequest{Request: &spb.SetPackageRequest_Package{Package: &spb.Package{}}}); err != nil && err != io.EOF {
+		return authorized(err)
+	}
+	_, err = stream.CloseAndRecv()
+	return authorized(err)
 }
 
 // GnoiSystemSwitchControlProcessor implements a sample request for service /gnoi.system.System/SwitchControlProcessor to validate if authz works as expected.
-func GnoiSystemSwitchControlProcessor(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.system.System/SwitchControlProcessor is not implemented")
+func GnoiSystemSwitchControlProcessor(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnoiC.System().SwitchControlProcessor(ctx, &spb.SwitchControlProcessorRequest{ControlProcessor: nonexistentComponent})
+	return authorized(err)
 }
 
 // GnoiSystemTime implements a sample request for service /gnoi.system.System/Time to validate if authz works as expected.
@@ -413,8 +795,16 @@ func GnoiSystemTime(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.Dia
 }
 
 // GnoiSystemTraceroute implements a sample request for service /gnoi.system.System/Traceroute to validate if authz works as expected.
-func GnoiSystemTraceroute(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.system.System/Traceroute is not implemented")
+func GnoiSystemTraceroute(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	stream, err := gnoiC.System().Traceroute(ctx, &spb.TracerouteRequest{Destination: "192.0.2.1", MaxTtl: 1})
+	if err != nil {
+		return authorized(err)
+	}
+	return authorized(recvErr(stream.Recv))
 }
 
 // GnoiSystemPing implements a sample request for service /gnoi.system.System/Ping to validate if authz works as expected.
@@ -433,33 +823,21 @@ func GnoiSystemPing(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.Dia
 }
 
 // GnoiWavelengthrouterAdjustPSD implements a sample request for service /gnoi.optical.WavelengthRouter/AdjustPSD to validate if authz works as expected.
-func GnoiWavelengthrouterAdjustPSD(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnoi.optical.WavelengthRouter/AdjustPSD is not implemented")
-}
-
-// GnsiAccountingpullAllRPC implements a sample request for service /gnsi.accounting.v1.AccountingPull/* to validate if authz works as expected.
-func GnsiAccountingpullAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.accounting.v1.AccountingPull/* is not implemented")
-}
-
-// GnsiAccountingpushRecordStream implements a sample request for service /gnsi.accounting.v1.AccountingPush/RecordStream to validate if authz works as expected.
-func GnsiAccountingpushRecordStream(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.accounting.v1.AccountingPush/RecordStream is not implemented")
-}
-
-// GnsiAccountingpushAllRPC implements a sample request for service /gnsi.accounting.v1.AccountingPush/* to validate if authz works as expected.
-func GnsiAccountingpushAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.accounting.v1.AccountingPush/* is not implemented")
-}
-
-// GnsiAccountingpullRecordStream implements a sample request for service /gnsi.accounting.v1.AccountingPull/RecordStream to validate if authz works as expected.
-func GnsiAccountingpullRecordStream(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.accounting.v1.AccountingPull/RecordStream is not implemented")
+func GnoiWavelengthrouterAdjustPSD(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnoiC, err := dut.RawAPIs().BindingDUT().DialGNOI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	stream, err := gnoiC.WavelengthRouter().AdjustPSD(ctx, &wrpb.AdjustPSDRequest{Component: nonexistentComponent})
+	if err != nil {
+		return authorized(err)
+	}
+	return authorized(recvErr(stream.Recv))
 }
 
 // GnsiAuthzAllRPC implements a sample request for service /gnsi.authz.v1.Authz/* to validate if authz works as expected.
-func GnsiAuthzAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.authz.v1.Authz/* is not implemented")
+func GnsiAuthzAllRPC(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	return GnsiAuthzGet(ctx, dut, opts)
 }
 
 // GnsiAuthzGet implements a sample request for service /gnsi.authz.v1.Authz/Get to validate if authz works as expected.
@@ -514,83 +892,161 @@ func GnsiAuthzRotate(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.Di
 }
 
 // GnsiCertzAddProfile implements a sample request for service /gnsi.certz.v1.Certz/AddProfile to validate if authz works as expected.
-func GnsiCertzAddProfile(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.certz.v1.Certz/AddProfile is not implemented")
+func GnsiCertzAddProfile(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnsiC, err := dut.RawAPIs().BindingDUT().DialGNSI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnsiC.Certz().AddProfile(ctx, &certzpb.AddProfileRequest{})
+	return authorized(err)
 }
 
 // GnsiCertzAllRPC implements a sample request for service /gnsi.certz.v1.Certz/* to validate if authz works as expected.
-func GnsiCertzAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.certz.v1.Certz/* is not implemented")
+func GnsiCertzAllRPC(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	return GnsiCertzGetProfileList(ctx, dut, opts)
 }
 
 // GnsiCertzCanGenerateCSR implements a sample request for service /gnsi.certz.v1.Certz/CanGenerateCSR to validate if authz works as expected.
-func GnsiCertzCanGenerateCSR(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.certz.v1.Certz/CanGenerateCSR is not implemented")
+func GnsiCertzCanGenerateCSR(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnsiC, err := dut.RawAPIs().BindingDUT().DialGNSI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnsiC.Certz().CanGenerateCSR(ctx, &certzpb.CanGenerateCSRRequest{Params: &certzpb.CSRParams{CsrSuite: certzpb.CSRSuite_CSRSUITE_X509_KEY_TYPE_RSA_2048_SIGNATURE_ALGORITHM_SHA_2_256, CommonName: nonexistent}})
+	return err
 }
 
 // GnsiCertzDeleteProfile implements a sample request for service /gnsi.certz.v1.Certz/DeleteProfile to validate if authz works as expected.
-func GnsiCertzDeleteProfile(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.certz.v1.Certz/DeleteProfile is not implemented")
+func GnsiCertzDeleteProfile(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnsiC, err := dut.RawAPIs().BindingDUT().DialGNSI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnsiC.Certz().DeleteProfile(ctx, &certzpb.DeleteProfileRequest{SslProfileId: nonexistent})
+	return authorized(err)
 }
 
 // GnsiCertzGetProfileList implements a sample request for service /gnsi.certz.v1.Certz/GetProfileList to validate if authz works as expected.
-func GnsiCertzGetProfileList(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.certz.v1.Certz/GetProfileList is not implemented")
+func GnsiCertzGetProfileList(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnsiC, err := dut.RawAPIs().BindingDUT().DialGNSI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnsiC.Certz().GetProfileList(ctx, &certzpb.GetProfileListRequest{})
+	return err
 }
 
 // GnsiCertzRotate implements a sample request for service /gnsi.certz.v1.Certz/Rotate to validate if authz works as expected.
-func GnsiCertzRotate(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.certz.v1.Certz/Rotate is not implemented")
+func GnsiCertzRotate(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnsiC, err := dut.RawAPIs().BindingDUT().DialGNSI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	stream, err := gnsiC.Certz().Rotate(ctx)
+	if err != nil {
+		return authorized(err)
+	}
+	defer stream.CloseSend()
+	if err := stream.Send(&certzpb.RotateCertificateRequest{SslProfileId: nonexistent, RotateRequest: &certzpb.RotateCertificateRequest_FinalizeRotation{FinalizeRotation: &certzpb.FinalizeRequest{}}}); err != nil && err != io.EOF {
+		return authorized(err)
+	}
+	return authorized(recvErr(stream.Recv))
 }
 
 // GnsiCredentialzAllRPC implements a sample request for service /gnsi.credentialz.v1.Credentialz/* to validate if authz works as expected.
-func GnsiCredentialzAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.credentialz.v1.Credentialz/* is not implemented")
+func GnsiCredentialzAllRPC(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	return GnsiCredentialzGetPublicKeys(ctx, dut, opts)
 }
 
 // GnsiCredentialzCanGenerateKey implements a sample request for service /gnsi.credentialz.v1.Credentialz/CanGenerateKey to validate if authz works as expected.
-func GnsiCredentialzCanGenerateKey(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.credentialz.v1.Credentialz/CanGenerateKey is not implemented")
+func GnsiCredentialzCanGenerateKey(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnsiC, err := dut.RawAPIs().BindingDUT().DialGNSI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnsiC.Credentialz().CanGenerateKey(ctx, &credzpb.CanGenerateKeyRequest{KeyParams: credzpb.KeyGen_KEY_GEN_SSH_KEY_TYPE_RSA_2048})
+	return err
 }
 
 // GnsiCredentialzGetPublicKeys implements a sample request for service /gnsi.credentialz.v1.Credentialz/GetPublicKeys to validate if authz works as expected.
-func GnsiCredentialzGetPublicKeys(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.credentialz.v1.Credentialz/GetPublicKeys is not implemented")
+func GnsiCredentialzGetPublicKeys(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnsiC, err := dut.RawAPIs().BindingDUT().DialGNSI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnsiC.Credentialz().GetPublicKeys(ctx, &credzpb.GetPublicKeysRequest{})
+	return err
 }
 
 // GnsiCredentialzRotateHostCredentials implements a sample request for service /gnsi.credentialz.v1.Credentialz/RotateHostCredentials to validate if authz works as expected.
+//
+// It is not executed: RotateHostCredentials is not part of the gNSI version used by featureprofiles.
 func GnsiCredentialzRotateHostCredentials(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.credentialz.v1.Credentialz/RotateHostCredentials is not implemented")
+	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.credentialz.v1.Credentialz/RotateHostCredentials is not implemented: RotateHostCredentials is not part of the gNSI version used by featureprofiles")
 }
 
 // GnsiCredentialzRotateAccountCredentials implements a sample request for service /gnsi.credentialz.v1.Credentialz/RotateAccountCredentials to validate if authz works as expected.
-func GnsiCredentialzRotateAccountCredentials(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.credentialz.v1.Credentialz/RotateAccountCredentials is not implemented")
+func GnsiCredentialzRotateAccountCredentials(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnsiC, err := dut.RawAPIs().BindingDUT().DialGNSI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	stream, err := gnsiC.Credentialz().RotateAccountCredentials(ctx)
+	if err != nil {
+		return authorized(err)
+	}
+	defer stream.CloseSend()
+	if err := stream.Send(&credzpb.RotateAccountCredentialsRequest{Request: &credzpb.RotateAccountCredentialsRequest_Finalize{Finalize: &credzpb.FinalizeRequest{}}}); err != nil && err != io.EOF {
+		return authorized(err)
+	}
+	return authorized(recvErr(stream.Recv))
 }
 
 // GnsiPathzAllRPC implements a sample request for service /gnsi.pathz.v1.Pathz/* to validate if authz works as expected.
-func GnsiPathzAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.pathz.v1.Pathz/* is not implemented")
+func GnsiPathzAllRPC(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	return GnsiPathzGet(ctx, dut, opts)
 }
 
 // GnsiPathzGet implements a sample request for service /gnsi.pathz.v1.Pathz/Get to validate if authz works as expected.
-func GnsiPathzGet(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.pathz.v1.Pathz/Get is not implemented")
+func GnsiPathzGet(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnsiC, err := dut.RawAPIs().BindingDUT().DialGNSI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnsiC.Pathz().Get(ctx, &pathzpb.GetRequest{PolicyInstance: pathzpb.PolicyInstance_POLICY_INSTANCE_ACTIVE})
+	return err
 }
 
 // GnsiPathzProbe implements a sample request for service /gnsi.pathz.v1.Pathz/Probe to validate if authz works as expected.
-func GnsiPathzProbe(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.pathz.v1.Pathz/Probe is not implemented")
+func GnsiPathzProbe(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnsiC, err := dut.RawAPIs().BindingDUT().DialGNSI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = gnsiC.Pathz().Probe(ctx, &pathzpb.ProbeRequest{User: "dummy", Path: &gpb.Path{Origin: "openconfig"}, Mode: pathzpb.Mode_MODE_READ, PolicyInstance: pathzpb.PolicyInstance_POLICY_INSTANCE_ACTIVE})
+	return authorized(err)
 }
 
 // GnsiPathzRotate implements a sample request for service /gnsi.pathz.v1.Pathz/Rotate to validate if authz works as expected.
-func GnsiPathzRotate(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.pathz.v1.Pathz/Rotate is not implemented")
+func GnsiPathzRotate(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnsiC, err := dut.RawAPIs().BindingDUT().DialGNSI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	stream, err := gnsiC.Pathz().Rotate(ctx)
+	if err != nil {
+		return authorized(err)
+	}
+	defer stream.CloseSend()
+	if err := stream.Send(&pathzpb.RotateRequest{RotateRequest: &pathzpb.RotateRequest_FinalizeRotation{FinalizeRotation: &pathzpb.FinalizeRequest{}}}); err != nil && err != io.EOF {
+		return authorized(err)
+	}
+	return authorized(recvErr(stream.Recv))
 }
 
 // GribiAllRPC implements a sample request for service /gribi.gRIBI/* to validate if authz works as expected.
-func GribiAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gribi.gRIBI/* is not implemented")
+func GribiAllRPC(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	return GribiGet(ctx, dut, opts)
 }
 
 // GribiFlush implements a sample request for service /gribi.gRIBI/Flush to validate if authz works as expected.
@@ -646,51 +1102,122 @@ func GribiModify(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOp
 }
 
 // P4P4runtimeAllRPC implements a sample request for service /p4.v1.P4Runtime/* to validate if authz works as expected.
-func P4P4runtimeAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /p4.v1.P4Runtime/* is not implemented")
+func P4P4runtimeAllRPC(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	return P4P4runtimeCapabilities(ctx, dut, opts)
 }
 
 // P4P4runtimeCapabilities implements a sample request for service /p4.v1.P4Runtime/Capabilities to validate if authz works as expected.
-func P4P4runtimeCapabilities(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /p4.v1.P4Runtime/Capabilities is not implemented")
+func P4P4runtimeCapabilities(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	p4rtC, err := dut.RawAPIs().BindingDUT().DialP4RT(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = p4rtC.Capabilities(ctx, &p4pb.CapabilitiesRequest{})
+	return err
 }
 
 // P4P4runtimeGetForwardingPipelineConfig implements a sample request for service /p4.v1.P4Runtime/GetForwardingPipelineConfig to validate if authz works as expected.
-func P4P4runtimeGetForwardingPipelineConfig(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /p4.v1.P4Runtime/GetForwardingPipelineConfig is not implemented")
+func P4P4runtimeGetForwardingPipelineConfig(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	p4rtC, err := dut.RawAPIs().BindingDUT().DialP4RT(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = p4rtC.GetForwardingPipelineConfig(ctx, &p4pb.GetForwardingPipelineConfigRequest{DeviceId: p4rtDeviceID, ResponseType: p4pb.GetForwardingPipelineConfigRequest_COOKIE_ONLY})
+	return authorized(err)
 }
 
 // P4P4runtimeRead implements a sample request for service /p4.v1.P4Runtime/Read to validate if authz works as expected.
-func P4P4runtimeRead(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /p4.v1.P4Runtime/Read is not implemented")
+func P4P4runtimeRead(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	p4rtC, err := dut.RawAPIs().BindingDUT().DialP4RT(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	stream, err := p4rtC.Read(ctx, &p4pb.ReadRequest{DeviceId: p4rtDeviceID, Entities: []*p4pb.Entity{{Entity: &p4pb.Entity_TableEntry{TableEntry: &p4pb.TableEntry{}}}}})
+	if err != nil {
+		return authorized(err)
+	}
+	return authorized(recvErr(stream.Recv))
 }
 
 // P4P4runtimeSetForwardingPipelineConfig implements a sample request for service /p4.v1.P4Runtime/SetForwardingPipelineConfig to validate if authz works as expected.
-func P4P4runtimeSetForwardingPipelineConfig(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /p4.v1.P4Runtime/SetForwardingPipelineConfig is not implemented")
+func P4P4runtimeSetForwardingPipelineConfig(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	p4rtC, err := dut.RawAPIs().BindingDUT().DialP4RT(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = p4rtC.SetForwardingPipelineConfig(ctx, &p4pb.SetForwardingPipelineConfigRequest{DeviceId: unknownP4RTDeviceID, Action: p4pb.SetForwardingPipelineConfigRequest_VERIFY})
+	return authorized(err)
 }
 
 // P4P4runtimeStreamChannel implements a sample request for service /p4.v1.P4Runtime/StreamChannel to validate if authz works as expected.
-func P4P4runtimeStreamChannel(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /p4.v1.P4Runtime/StreamChannel is not implemented")
+func P4P4runtimeStreamChannel(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	p4rtC, err := dut.RawAPIs().BindingDUT().DialP4RT(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	stream, err := p4rtC.StreamChannel(ctx)
+	if err != nil {
+		return authorized(err)
+	}
+	defer stream.CloseSend()
+	if err := stream.Send(&p4pb.StreamMessageRequest{Update: &p4pb.StreamMessageRequest_Arbitration{Arbitration: &p4pb.MasterArbitrationUpdate{DeviceId: p4rtDeviceID}}}); err != nil && err != io.EOF {
+		return authorized(err)
+	}
+	return authorized(recvErr(stream.Recv))
 }
 
 // P4P4runtimeWrite implements a sample request for service /p4.v1.P4Runtime/Write to validate if authz works as expected.
-func P4P4runtimeWrite(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /p4.v1.P4Runtime/Write is not implemented")
+func P4P4runtimeWrite(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	p4rtC, err := dut.RawAPIs().BindingDUT().DialP4RT(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = p4rtC.Write(ctx, &p4pb.WriteRequest{DeviceId: unknownP4RTDeviceID})
+	return authorized(err)
 }
 
 // GnsiAcctzAllRPC implements a sample request for service /gnsi.acctz.v1.Acctz/* to validate if authz works as expected.
-func GnsiAcctzAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.acctz.v1.Acctz/* is not implemented")
+func GnsiAcctzAllRPC(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	return GnsiAcctzRecordSubscribe(ctx, dut, opts)
 }
 
 // GnsiAcctzRecordSubscribe implements a sample request for service /gnsi.acctz.v1.Acctz/RecordSubscribe to validate if authz works as expected.
-func GnsiAcctzRecordSubscribe(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.acctz.v1.Acctz/RecordSubscribe is not implemented")
+func GnsiAcctzRecordSubscribe(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnsiC, err := dut.RawAPIs().BindingDUT().DialGNSI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	// The stream stays open waiting for new records, so an authorized call
+	// either returns a record or times out.
+	ctx, cancel := context.WithTimeout(ctx, acctzTimeout)
+	defer cancel()
+	stream, err := gnsiC.Acctz().RecordSubscribe(ctx)
+	if err != nil {
+		return authorized(err)
+	}
+	if err := stream.Send(&acctzpb.RecordRequest{Timestamp: tspb.Now()}); err != nil && err != io.EOF {
+		return authorized(err)
+	}
+	err = recvErr(stream.Recv)
+	if status.Code(err) == codes.DeadlineExceeded {
+		return nil
+	}
+	return authorized(err)
 }
 
-// GnsiCredentialzRotateHostParameters implements a sample request for service /gnsi.acctz.v1.Acctz/RecordSubscribe to validate if authz works as expected.
-func GnsiCredentialzRotateHostParameters(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
-	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.acctz.v1.Acctz/RecordSubscribe is not implemented")
+// GnsiCredentialzRotateHostParameters implements a sample request for service /gnsi.credentialz.v1.Credentialz/RotateHostParameters to validate if authz works as expected.
+func GnsiCredentialzRotateHostParameters(ctx context.Context, dut *ondatra.DUTDevice, opts []grpc.DialOption, _ ...any) error {
+	gnsiC, err := dut.RawAPIs().BindingDUT().DialGNSI(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	stream, err := gnsiC.Credentialz().RotateHostParameters(ctx)
+	if err != nil {
+		return authorized(err)
+	}
+	defer stream.CloseSend()
+	if err := stream.Send(&credzpb.RotateHostParametersRequest{Request: &credzpb.RotateHostParametersRequest_Finalize{Finalize: &credzpb.FinalizeRequest{}}}); err != nil && err != io.EOF {
+		return authorized(err)
+	}
+	return authorized(recvErr(stream.Recv))
 }
@@ -0,0 +1,23 @@
+package gnxi
+
+// The below code is generated using ../gen/generate.go for the rpcs that have
+// no exec function. Move a function to rpcexec.go when implementing it.
+
+import (
+	"context"
+
+	"github.com/openconfig/ondatra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GnsiAcctzstreamAllRPC implements a sample request for service /gnsi.acctz.v1.AcctzStream/* to validate if authz works as expected.
+func GnsiAcctzstreamAllRPC(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
+	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.acctz.v1.AcctzStream/* is not implemented")
+}
+
+// GnsiAcctzstreamRecordSubscribe implements a sample request for service /gnsi.acctz.v1.AcctzStream/RecordSubscribe to validate if authz works as expected.
+func GnsiAcctzstreamRecordSubscribe(_ context.Context, _ *ondatra.DUTDevice, _ []grpc.DialOption, _ ...any) error {
+	return status.Errorf(codes.Unimplemented, "exec function for RPC /gnsi.acctz.v1.AcctzStream/RecordSubscribe is not implemented")
+}
@@ -5,10 +5,10 @@ package gnxi
 type rpcs struct {
 	AllRPC                                                  *RPC
 	GnmiAllRPC                                              *RPC
+	GnmiCapabilities                                        *RPC
 	GnmiGet                                                 *RPC
 	GnmiSet                                                 *RPC
 	GnmiSubscribe                                           *RPC
-	GnmiCapabilities                                        *RPC
 	GnoiBgpAllRPC                                           *RPC
 	GnoiBgpClearBGPNeighbor                                 *RPC
 	GnoiCertificatemanagementAllRPC                         *RPC
@@ -22,67 +22,69 @@ type rpcs struct {
 	GnoiCertificatemanagementRotate                         *RPC
 	GnoiDiagAllRPC                                          *RPC
 	GnoiDiagGetBERTResult                                   *RPC
-	GnoiDiagStopBERT                                        *RPC
 	GnoiDiagStartBERT                                       *RPC
+	GnoiDiagStopBERT                                        *RPC
 	GnoiFactoryresetAllRPC                                  *RPC
 	GnoiFactoryresetStart                                   *RPC
 	GnoiFileAllRPC                                          *RPC
+	GnoiFileGet                                             *RPC
 	GnoiFilePut                                             *RPC
 	GnoiFileRemove                                          *RPC
 	GnoiFileStat                                            *RPC
 	GnoiFileTransferToRemote                                *RPC
-	GnoiFileGet                                             *RPC
-	GnoiHealthzAcknowledge                                  *RPC
 	GnoiHealthzAllRPC                                       *RPC
+	GnoiHealthzAcknowledge                                  *RPC
 	GnoiHealthzArtifact                                     *RPC
 	GnoiHealthzCheck                                        *RPC
-	GnoiHealthzList                                         *RPC
 	GnoiHealthzGet                                          *RPC
+	GnoiHealthzList                                         *RPC
 	GnoiLayer2AllRPC                                        *RPC
 	GnoiLayer2ClearLLDPInterface                            *RPC
+	GnoiLayer2ClearNeighborDiscovery                        *RPC
 	GnoiLayer2ClearSpanningTree                             *RPC
 	GnoiLayer2PerformBERT                                   *RPC
 	GnoiLayer2SendWakeOnLAN                                 *RPC
-	GnoiLayer2ClearNeighborDiscovery                        *RPC
-	GnoiLinkqualificationCreate                             *RPC
 	GnoiMplsAllRPC                                          *RPC
+	GnoiMplsClearLSP                                        *RPC
 	GnoiMplsClearLSPCounters                                *RPC
 	GnoiMplsMPLSPing                                        *RPC
-	GnoiMplsClearLSP                                        *RPC
 	GnoiOtdrAllRPC                                          *RPC
-	GnoiWavelengthrouterAdjustSpectrum                      *RPC
+	GnoiOtdrInitiate                                        *RPC
 	GnoiWavelengthrouterAllRPC                              *RPC
+	GnoiWavelengthrouterAdjustPSD                           *RPC
+	GnoiWavelengthrouterAdjustSpectrum                      *RPC
 	GnoiWavelengthrouterCancelAdjustPSD                     *RPC
 	GnoiWavelengthrouterCancelAdjustSpectrum                *RPC
-	GnoiOsActivate                                          *RPC
 	GnoiOsAllRPC                                            *RPC
-	GnoiOsVerify                                            *RPC
+	GnoiOsActivate                                          *RPC
 	GnoiOsInstall                                           *RPC
-	GnoiOtdrInitiate                                        *RPC
+	GnoiOsVerify                                            *RPC
 	GnoiLinkqualificationAllRPC                             *RPC
 	GnoiLinkqualificationCapabilities                       *RPC
+	GnoiLinkqualificationCreate                             *RPC
 	GnoiLinkqualificationDelete                             *RPC
 	GnoiLinkqualificationGet                                *RPC
 	GnoiLinkqualificationList                               *RPC
 	GnoiSystemAllRPC                                        *RPC
 	GnoiSystemCancelReboot                                  *RPC
 	GnoiSystemKillProcess                                   *RPC
+	GnoiSystemPing                                          *RPC
 	GnoiSystemReboot                                        *RPC
 	GnoiSystemRebootStatus                                  *RPC
 	GnoiSystemSetPackage                                    *RPC
 	GnoiSystemSwitchControlProcessor                        *RPC
 	GnoiSystemTime                                          *RPC
 	GnoiSystemTraceroute                                    *RPC
-	GnoiSystemPing                                          *RPC
-	GnoiWavelengthrouterAdjustPSD                           *RPC
 	GnsiAcctzAllRPC                                         *RPC
 	GnsiAcctzRecordSubscribe                                *RPC
+	GnsiAcctzstreamAllRPC                                   *RPC
+	GnsiAcctzstreamRecordSubscribe                          *RPC
 	GnsiAuthzAllRPC                                         *RPC
 	GnsiAuthzGet                                            *RPC
 	GnsiAuthzProbe                                          *RPC
 	GnsiAuthzRotate                                         *RPC
-	GnsiCertzAddProfile                                     *RPC
 	GnsiCertzAllRPC                                         *RPC
+	GnsiCertzAddProfile                                     *RPC
 	GnsiCertzCanGenerateCSR                                 *RPC
 	GnsiCertzDeleteProfile                                  *RPC
 	GnsiCertzGetProfileList                                 *RPC
@@ -90,8 +92,8 @@ type rpcs struct {
 	GnsiCredentialzAllRPC                                   *RPC
 	GnsiCredentialzCanGenerateKey                           *RPC
 	GnsiCredentialzGetPublicKeys                            *RPC
-	GnsiCredentialzRotateHostParameters                     *RPC
 	GnsiCredentialzRotateAccountCredentials                 *RPC
+	GnsiCredentialzRotateHostParameters                     *RPC
 	GnsiPathzAllRPC                                         *RPC
 	GnsiPathzGet                                            *RPC
 	GnsiPathzProbe                                          *RPC
@@ -125,6 +127,13 @@ var (
 		Path:    "/gnmi.gNMI/*",
 		Exec:    GnmiAllRPC,
 	}
+	gnmiCapabilities = &RPC{
+		Name:    "Capabilities",
+		Service: "gnmi.gNMI",
+		FQN:     "gnmi.gNMI.Capabilities",
+		Path:    "/gnmi.gNMI/Capabilities",
+		Exec:    GnmiCapabilities,
+	}
 	gnmiGet = &RPC{
 		Name:    "Get",
 		Service: "gnmi.gNMI",
@@ -146,13 +155,6 @@ var (
 		Path:    "/gnmi.gNMI/Subscribe",
 		Exec:    GnmiSubscribe,
 	}
-	gnmiCapabilities = &RPC{
-		Name:    "Capabilities",
-		Service: "gnmi.gNMI",
-		FQN:     "gnmi.gNMI.Capabilities",
-		Path:    "/gnmi.gNMI/Capabilities",
-		Exec:    GnmiCapabilities,
-	}
 	gnoibgpALL = &RPC{
 		Name:    "*",
 		Service: "gnoi.bgp.BGP",
@@ -244,13 +246,6 @@ var (
 		Path:    "/gnoi.diag.Diag/GetBERTResult",
 		Exec:    GnoiDiagGetBERTResult,
 	}
-	gnoidiagStopBERT = &RPC{
-		Name:    "StopBERT",
-		Service: "gnoi.diag.Diag",
-		FQN:     "gnoi.diag.Diag.StopBERT",
-		Path:    "/gnoi.diag.Diag/StopBERT",
-		Exec:    GnoiDiagStopBERT,
-	}
 	gnoidiagStartBERT = &RPC{
 		Name:    "StartBERT",
 		Service: "gnoi.diag.Diag",
@@ -258,6 +253,13 @@ var (
 		Path:    "/gnoi.diag.Diag/StartBERT",
 		Exec:    GnoiDiagStartBERT,
 	}
+	gnoidiagStopBERT = &RPC{
+		Name:    "StopBERT",
+		Service: "gnoi.diag.Diag",
+		FQN:     "gnoi.diag.Diag.StopBERT",
+		Path:    "/gnoi.diag.Diag/StopBERT",
+		Exec:    GnoiDiagStopBERT,
+	}
 	gnoifactory_resetFactoryResetALL = &RPC{ //revive:disable-line the name of the rpc includes _
 		Name:    "*",
 		Service: "gnoi.factory_reset.FactoryReset",
@@ -279,6 +281,13 @@ var (
 		Path:    "/gnoi.file.File/*",
 		Exec:    GnoiFileAllRPC,
 	}
+	gnoifileGet = &RPC{
+		Name:    "Get",
+		Service: "gnoi.file.File",
+		FQN:     "gnoi.file.File.Get",
+		Path:    "/gnoi.file.File/Get",
+		Exec:    GnoiFileGet,
+	}
 	gnoifilePut = &RPC{
 		Name:    "Put",
 		Service: "gnoi.file.File",
@@ -307,12 +316,12 @@ var (
 		Path:    "/gnoi.file.File/TransferToRemote",
 		Exec:    GnoiFileTransferToRemote,
 	}
-	gnoifileGet = &RPC{
-		Name:    "Get",
-		Service: "gnoi.file.File",
-		FQN:     "gnoi.file.File.Get",
-		Path:    "/gnoi.file.File/Get",
-		Exec:    GnoiFileGet,
+	gnoihealthzALL = &RPC{
+		Name:    "*",
+		Service: "gnoi.healthz.Healthz",
+		FQN:     "gnoi.healthz.Healthz.*",
+		Path:    "/gnoi.healthz.Healthz/*",
+		Exec:    GnoiHealthzAllRPC,
 	}
 	gnoihealthzAcknowledge = &RPC{
 		Name:    "Acknowledge",
@@ -321,13 +330,6 @@ var (
 		Path:    "/gnoi.healthz.Healthz/Acknowledge",
 		Exec:    GnoiHealthzAcknowledge,
 	}
-	gnoihealthzALL = &RPC{
-		Name:    "*",
-		Service: "gnoi.healthz.Healthz",
-		FQN:     "gnoi.healthz.Healthz.*",
-		Path:    "/gnoi.healthz.Healthz/*",
-		Exec:    GnoiHealthzAllRPC,
-	}
 	gnoihealthzArtifact = &RPC{
 		Name:    "Artifact",
 		Service: "gnoi.healthz.Healthz",
@@ -342,13 +344,6 @@ var (
 		Path:    "/gnoi.healthz.Healthz/Check",
 		Exec:    GnoiHealthzCheck,
 	}
-	gnoihealthzList = &RPC{
-		Name:    "List",
-		Service: "gnoi.healthz.Healthz",
-		FQN:     "gnoi.healthz.Healthz.List",
-		Path:    "/gnoi.healthz.Healthz/List",
-		Exec:    GnoiHealthzList,
-	}
 	gnoihealthzGet = &RPC{
 		Name:    "Get",
 		Service: "gnoi.healthz.Healthz",
@@ -356,6 +351,13 @@ var (
 		Path:    "/gnoi.healthz.Healthz/Get",
 		Exec:    GnoiHealthzGet,
 	}
+	gnoihealthzList = &RPC{
+		Name:    "List",
+		Service: "gnoi.healthz.Healthz",
+		FQN:     "gnoi.healthz.Healthz.List",
+		Path:    "/gnoi.healthz.Healthz/List",
+		Exec:    GnoiHealthzList,
+	}
 	gnoilayer2ALL = &RPC{
 		Name:    "*",
 		Service: "gnoi.layer2.Layer2",
@@ -370,6 +372,13 @@ var (
 		Path:    "/gnoi.layer2.Layer2/ClearLLDPInterface",
 		Exec:    GnoiLayer2ClearLLDPInterface,
 	}
+	gnoilayer2ClearNeighborDiscovery = &RPC{
+		Name:    "ClearNeighborDiscovery",
+		Service: "gnoi.layer2.Layer2",
+		FQN:     "gnoi.layer2.Layer2.ClearNeighborDiscovery",
+		Path:    "/gnoi.layer2.Layer2/ClearNeighborDiscovery",
+		Exec:    GnoiLayer2ClearNeighborDiscovery,
+	}
 	gnoilayer2ClearSpanningTree = &RPC{
 		Name:    "ClearSpanningTree",
 		Service: "gnoi.layer2.Layer2",
@@ -391,20 +400,6 @@ var (
 		Path:    "/gnoi.layer2.Layer2/SendWakeOnLAN",
 		Exec:    GnoiLayer2SendWakeOnLAN,
 	}
-	gnoilayer2ClearNeighborDiscovery = &RPC{
-		Name:    "ClearNeighborDiscovery",
-		Service: "gnoi.layer2.Layer2",
-		FQN:     "gnoi.layer2.Layer2.ClearNeighborDiscovery",
-		Path:    "/gnoi.layer2.Layer2/ClearNeighborDiscovery",
-		Exec:    GnoiLayer2ClearNeighborDiscovery,
-	}
-	gnoipacket_link_qualificationLinkQualificationCreate = &RPC{ //revive:disable-line the name of the rpc includes _
-		Name:    "Create",
-		Service: "gnoi.packet_link_qualification.LinkQualification",
-		FQN:     "gnoi.packet_link_qualification.LinkQualification.Create",
-		Path:    "/gnoi.packet_link_qualification.LinkQualification/Create",
-		Exec:    GnoiLinkqualificationCreate,
-	}
 	gnoimplsALL = &RPC{
 		Name:    "*",
 		Service: "gnoi.mpls.MPLS",
@@ -412,6 +407,13 @@ var (
 		Path:    "/gnoi.mpls.MPLS/*",
 		Exec:    GnoiMplsAllRPC,
 	}
+	gnoimplsClearLSP = &RPC{
+		Name:    "ClearLSP",
+		Service: "gnoi.mpls.MPLS",
+		FQN:     "gnoi.mpls.MPLS.ClearLSP",
+		Path:    "/gnoi.mpls.MPLS/ClearLSP",
+		Exec:    GnoiMplsClearLSP,
+	}
 	gnoimplsClearLSPCounters = &RPC{
 		Name:    "ClearLSPCounters",
 		Service: "gnoi.mpls.MPLS",
@@ -426,13 +428,6 @@ var (
 		Path:    "/gnoi.mpls.MPLS/MPLSPing",
 		Exec:    GnoiMplsMPLSPing,
 	}
-	gnoimplsClearLSP = &RPC{
-		Name:    "ClearLSP",
-		Service: "gnoi.mpls.MPLS",
-		FQN:     "gnoi.mpls.MPLS.ClearLSP",
-		Path:    "/gnoi.mpls.MPLS/ClearLSP",
-		Exec:    GnoiMplsClearLSP,
-	}
 	gnoiopticalOTDRALL = &RPC{
 		Name:    "*",
 		Service: "gnoi.optical.OTDR",
@@ -440,12 +435,12 @@ var (
 		Path:    "/gnoi.optical.OTDR/*",
 		Exec:    GnoiOtdrAllRPC,
 	}
-	gnoiopticalWavelengthRouterAdjustSpectrum = &RPC{
-		Name:    "AdjustSpectrum",
-		Service: "gnoi.optical.WavelengthRouter",
-		FQN:     "gnoi.optical.WavelengthRouter.AdjustSpectrum",
-		Path:    "/gnoi.optical.WavelengthRouter/AdjustSpectrum",
-		Exec:    GnoiWavelengthrouterAdjustSpectrum,
+	gnoiopticalOTDRInitiate = &RPC{
+		Name:    "Initiate",
+		Service: "gnoi.optical.OTDR",
+		FQN:     "gnoi.optical.OTDR.Initiate",
+		Path:    "/gnoi.optical.OTDR/Initiate",
+		Exec:    GnoiOtdrInitiate,
 	}
 	gnoiopticalWavelengthRouterALL = &RPC{
 		Name:    "*",
@@ -454,6 +449,20 @@ var (
 		Path:    "/gnoi.optical.WavelengthRouter/*",
 		Exec:    GnoiWavelengthrouterAllRPC,
 	}
+	gnoiopticalWavelengthRouterAdjustPSD = &RPC{
+		Name:    "AdjustPSD",
+		Service: "gnoi.optical.WavelengthRouter",
+		FQN:     "gnoi.optical.WavelengthRouter.AdjustPSD",
+		Path:    "/gnoi.optical.WavelengthRouter/AdjustPSD",
+		Exec:    GnoiWavelengthrouterAdjustPSD,
+	}
+	gnoiopticalWavelengthRouterAdjustSpectrum = &RPC{
+		Name:    "AdjustSpectrum",
+		Service: "gnoi.optical.WavelengthRouter",
+		FQN:     "gnoi.optical.WavelengthRouter.AdjustSpectrum",
+		Path:    "/gnoi.optical.WavelengthRouter/AdjustSpectrum",
+		Exec:    GnoiWavelengthrouterAdjustSpectrum,
+	}
 	gnoiopticalWavelengthRouterCancelAdjustPSD = &RPC{
 		Name:    "CancelAdjustPSD",
 		Service: "gnoi.optical.WavelengthRouter",
@@ -468,13 +477,6 @@ var (
 		Path:    "/gnoi.optical.WavelengthRouter/CancelAdjustSpectrum",
 		Exec:    GnoiWavelengthrouterCancelAdjustSpectrum,
 	}
-	gnoiosActivate = &RPC{
-		Name:    "Activate",
-		Service: "gnoi.os.OS",
-		FQN:     "gnoi.os.OS.Activate",
-		Path:    "/gnoi.os.OS/Activate",
-		Exec:    GnoiOsActivate,
-	}
 	gnoiosALL = &RPC{
 		Name:    "*",
 		Service: "gnoi.os.OS",
@@ -482,12 +484,12 @@ var (
 		Path:    "/gnoi.os.OS/*",
 		Exec:    GnoiOsAllRPC,
 	}
-	gnoiosVerify = &RPC{
-		Name:    "Verify",
+	gnoiosActivate = &RPC{
+		Name:    "Activate",
 		Service: "gnoi.os.OS",
-		FQN:     "gnoi.os.OS.Verify",
-		Path:    "/gnoi.os.OS/Verify",
-		Exec:    GnoiOsVerify,
+		FQN:     "gnoi.os.OS.Activate",
+		Path:    "/gnoi.os.OS/Activate",
+		Exec:    GnoiOsActivate,
 	}
 	gnoiosInstall = &RPC{
 		Name:    "Install",
@@ -496,29 +498,35 @@ var (
 		Path:    "/gnoi.os.OS/Install",
 		Exec:    GnoiOsInstall,
 	}
-	gnoiopticalOTDRInitiate = &RPC{
-		Name:    "Initiate",
-		Service: "gnoi.optical.OTDR",
-		FQN:     "gnoi.optical.OTDR.Initiate",
-		Path:    "/gnoi.optical.OTDR/Initiate",
-		Exec:    GnoiOtdrInitiate,
+	gnoiosVerify = &RPC{
+		Name:    "Verify",
+		Service: "gnoi.os.OS",
+		FQN:     "gnoi.os.OS.Verify",
+		Path:    "/gnoi.os.OS/Verify",
+		Exec:    GnoiOsVerify,
 	}
 	gnoipacket_link_qualificationLinkQualificationALL = &RPC{ //revive:disable-line the name of the rpc includes _
+		Name:    "*",
 		Service: "gnoi.packet_link_qualification.LinkQualification",
 		FQN:     "gnoi.packet_link_qualification.LinkQualification.*",
 		Path:    "/gnoi.packet_link_qualification.LinkQualification/*",
 		Exec:    GnoiLinkqualificationAllRPC,
 	}
 	gnoipacket_link_qualificationLinkQualificationCapabilities = &RPC{ //revive:disable-line the name of the rpc includes _
-
 		Name:    "Capabilities",
 		Service: "gnoi.packet_link_qualification.LinkQualification",
 		FQN:     "gnoi.packet_link_qualification.LinkQualification.Capabilities",
 		Path:    "/gnoi.packet_link_qualification.LinkQualification/Capabilities",
 		Exec:    GnoiLinkqualificationCapabilities,
 	}
+	gnoipacket_link_qualificationLinkQualificationCreate = &RPC{ //revive:disable-line the name of the rpc includes _
+		Name:    "Create",
+		Service: "gnoi.packet_link_qualification.LinkQualification",
+		FQN:     "gnoi.packet_link_qualification.LinkQualification.Create",
+		Path:    "/gnoi.packet_link_qualification.LinkQualification/Create",
+		Exec:    GnoiLinkqualificationCreate,
+	}
 	gnoipacket_link_qualificationLinkQualificationDelete = &RPC{ //revive:disable-line the name of the rpc includes _
-
 		Name:    "Delete",
 		Service: "gnoi.packet_link_qualification.LinkQualification",
 		FQN:     "gnoi.packet_link_qualification.LinkQualification.Delete",
@@ -560,6 +568,13 @@ var (
 		Path:    "/gnoi.system.System/KillProcess",
 		Exec:    GnoiSystemKillProcess,
 	}
+	gnoisystemPing = &RPC{
+		Name:    "Ping",
+		Service: "gnoi.system.System",
+		FQN:     "gnoi.system.System.Ping",
+		Path:    "/gnoi.system.System/Ping",
+		Exec:    GnoiSystemPing,
+	}
 	gnoisystemReboot = &RPC{
 		Name:    "Reboot",
 		Service: "gnoi.system.System",
@@ -602,20 +617,6 @@ var (
 		Path:    "/gnoi.system.System/Traceroute",
 		Exec:    GnoiSystemTraceroute,
 	}
-	gnoisystemPing = &RPC{
-		Name:    "Ping",
-		Service: "gnoi.system.System",
-		FQN:     "gnoi.system.System.Ping",
-		Path:    "/gnoi.system.System/Ping",
-		Exec:    GnoiSystemPing,
-	}
-	gnoiopticalWavelengthRouterAdjustPSD = &RPC{
-		Name:    "AdjustPSD",
-		Service: "gnoi.optical.WavelengthRouter",
-		FQN:     "gnoi.optical.WavelengthRouter.AdjustPSD",
-		Path:    "/gnoi.optical.WavelengthRouter/AdjustPSD",
-		Exec:    GnoiWavelengthrouterAdjustPSD,
-	}
 	gnsiacctzv1AcctzALL = &RPC{
 		Name:    "*",
 		Service: "gnsi.acctz.v1.Acctz",
@@ -630,6 +631,20 @@ var (
 		Path:    "/gnsi.acctz.v1.Acctz/RecordSubscribe",
 		Exec:    GnsiAcctzRecordSubscribe,
 	}
+	gnsiacctzv1AcctzStreamALL = &RPC{
+		Name:    "*",
+		Service: "gnsi.acctz.v1.AcctzStream",
+		FQN:     "gnsi.acctz.v1.AcctzStream.*",
+		Path:    "/gnsi.acctz.v1.AcctzStream/*",
+		Exec:    GnsiAcctzstreamAllRPC,
+	}
+	gnsiacctzv1AcctzStreamRecordSubscribe = &RPC{
+		Name:    "RecordSubscribe",
+		Service: "gnsi.acctz.v1.AcctzStream",
+		FQN:     "gnsi.acctz.v1.AcctzStream.RecordSubscribe",
+		Path:    "/gnsi.acctz.v1.AcctzStream/RecordSubscribe",
+		Exec:    GnsiAcctzstreamRecordSubscribe,
+	}
 	gnsiauthzv1AuthzALL = &RPC{
 		Name:    "*",
 		Service: "gnsi.authz.v1.Authz",
@@ -658,13 +673,6 @@ var (
 		Path:    "/gnsi.authz.v1.Authz/Rotate",
 		Exec:    GnsiAuthzRotate,
 	}
-	gnsicertzv1CertzAddProfile = &RPC{
-		Name:    "AddProfile",
-		Service: "gnsi.certz.v1.Certz",
-		FQN:     "gnsi.certz.v1.Certz.AddProfile",
-		Path:    "/gnsi.certz.v1.Certz/AddProfile",
-		Exec:    GnsiCertzAddProfile,
-	}
 	gnsicertzv1CertzALL = &RPC{
 		Name:    "*",
 		Service: "gnsi.certz.v1.Certz",
@@ -672,6 +680,13 @@ var (
 		Path:    "/gnsi.certz.v1.Certz/*",
 		Exec:    GnsiCertzAllRPC,
 	}
+	gnsicertzv1CertzAddProfile = &RPC{
+		Name:    "AddProfile",
+		Service: "gnsi.certz.v1.Certz",
+		FQN:     "gnsi.certz.v1.Certz.AddProfile",
+		Path:    "/gnsi.certz.v1.Certz/AddProfile",
+		Exec:    GnsiCertzAddProfile,
+	}
 	gnsicertzv1CertzCanGenerateCSR = &RPC{
 		Name:    "CanGenerateCSR",
 		Service: "gnsi.certz.v1.Certz",
@@ -721,13 +736,6 @@ var (
 		Path:    "/gnsi.credentialz.v1.Credentialz/GetPublicKeys",
 		Exec:    GnsiCredentialzGetPublicKeys,
 	}
-	gnsicredentialzv1CredentialzRotateHostParameters = &RPC{
-		Name:    "RotateHostParameters",
-		Service: "gnsi.credentialz.v1.Credentialz",
-		FQN:     "gnsi.credentialz.v1.Credentialz.RotateHostParameters",
-		Path:    "/gnsi.credentialz.v1.Credentialz/RotateHostParameters",
-		Exec:    GnsiCredentialzRotateHostParameters,
-	}
 	gnsicredentialzv1CredentialzRotateAccountCredentials = &RPC{
 		Name:    "RotateAccountCredentials",
 		Service: "gnsi.credentialz.v1.Credentialz",
@@ -735,6 +743,13 @@ var (
 		Path:    "/gnsi.credentialz.v1.Credentialz/RotateAccountCredentials",
 		Exec:    GnsiCredentialzRotateAccountCredentials,
 	}
+	gnsicredentialzv1CredentialzRotateHostParameters = &RPC{
+		Name:    "RotateHostParameters",
+		Service: "gnsi.credentialz.v1.Credentialz",
+		FQN:     "gnsi.credentialz.v1.Credentialz.RotateHostParameters",
+		Path:    "/gnsi.credentialz.v1.Credentialz/RotateHostParameters",
+		Exec:    GnsiCredentialzRotateHostParameters,
+	}
 	gnsipathzv1PathzALL = &RPC{
 		Name:    "*",
 		Service: "gnsi.pathz.v1.Pathz",
@@ -845,10 +860,10 @@ var (
 	RPCs = rpcs{
 		AllRPC:                                   ALL,
 		GnmiAllRPC:                               gnmiALL,
+		GnmiCapabilities:                         gnmiCapabilities,
 		GnmiGet:                                  gnmiGet,
 		GnmiSet:                                  gnmiSet,
 		GnmiSubscribe:                            gnmiSubscribe,
-		GnmiCapabilities:                         gnmiCapabilities,
 		GnoiBgpAllRPC:                            gnoibgpALL,
 		GnoiBgpClearBGPNeighbor:                  gnoibgpClearBGPNeighbor,
 		GnoiCertificatemanagementAllRPC:          gnoicertificateCertificateManagementALL,
@@ -862,67 +877,69 @@ var (
 		GnoiCertificatemanagementRotate:                         gnoicertificateCertificateManagementRotate,
 		GnoiDiagAllRPC:                                          gnoidiagALL,
 		GnoiDiagGetBERTResult:                                   gnoidiagGetBERTResult,
-		GnoiDiagStopBERT:                                        gnoidiagStopBERT,
 		GnoiDiagStartBERT:                                       gnoidiagStartBERT,
+		GnoiDiagStopBERT:                                        gnoidiagStopBERT,
 		GnoiFactoryresetAllRPC:                                  gnoifactory_resetFactoryResetALL,
 		GnoiFactoryresetStart:                                   gnoifactory_resetFactoryResetStart,
 		GnoiFileAllRPC:                                          gnoifileALL,
+		GnoiFileGet:                                             gnoifileGet,
 		GnoiFilePut:                                             gnoifilePut,
 		GnoiFileRemove:                                          gnoifileRemove,
 		GnoiFileStat:                                            gnoifileStat,
 		GnoiFileTransferToRemote:                                gnoifileTransferToRemote,
-		GnoiFileGet:                                             gnoifileGet,
-		GnoiHealthzAcknowledge:                                  gnoihealthzAcknowledge,
 		GnoiHealthzAllRPC:                                       gnoihealthzALL,
+		GnoiHealthzAcknowledge:                                  gnoihealthzAcknowledge,
 		GnoiHealthzArtifact:                                     gnoihealthzArtifact,
 		GnoiHealthzCheck:                                        gnoihealthzCheck,
-		GnoiHealthzList:                                         gnoihealthzList,
 		GnoiHealthzGet:                                          gnoihealthzGet,
+		GnoiHealthzList:                                         gnoihealthzList,
 		GnoiLayer2AllRPC:                                        gnoilayer2ALL,
 		GnoiLayer2ClearLLDPInterface:                            gnoilayer2ClearLLDPInterface,
+		GnoiLayer2ClearNeighborDiscovery:                        gnoilayer2ClearNeighborDiscovery,
 		GnoiLayer2ClearSpanningTree:                             gnoilayer2ClearSpanningTree,
 		GnoiLayer2PerformBERT:                                   gnoilayer2PerformBERT,
 		GnoiLayer2SendWakeOnLAN:                                 gnoilayer2SendWakeOnLAN,
-		GnoiLayer2ClearNeighborDiscovery:                        gnoilayer2ClearNeighborDiscovery,
-		GnoiLinkqualificationCreate:                             gnoipacket_link_qualificationLinkQualificationCreate,
 		GnoiMplsAllRPC:                                          gnoimplsALL,
+		GnoiMplsClearLSP:                                        gnoimplsClearLSP,
 		GnoiMplsClearLSPCounters:                                gnoimplsClearLSPCounters,
 		GnoiMplsMPLSPing:                                        gnoimplsMPLSPing,
-		GnoiMplsClearLSP:                                        gnoimplsClearLSP,
 		GnoiOtdrAllRPC:                                          gnoiopticalOTDRALL,
-		GnoiWavelengthrouterAdjustSpectrum:                      gnoiopticalWavelengthRouterAdjustSpectrum,
+		GnoiOtdrInitiate:                                        gnoiopticalOTDRInitiate,
 		GnoiWavelengthrouterAllRPC:                              gnoiopticalWavelengthRouterALL,
+		GnoiWavelengthrouterAdjustPSD:                           gnoiopticalWavelengthRouterAdjustPSD,
+		GnoiWavelengthrouterAdjustSpectrum:                      gnoiopticalWavelengthRouterAdjustSpectrum,
 		GnoiWavelengthrouterCancelAdjustPSD:                     gnoiopticalWavelengthRouterCancelAdjustPSD,
 		GnoiWavelengthrouterCancelAdjustSpectrum:                gnoiopticalWavelengthRouterCancelAdjustSpectrum,
-		GnoiOsActivate:                                          gnoiosActivate,
 		GnoiOsAllRPC:                                            gnoiosALL,
-		GnoiOsVerify:                                            gnoiosVerify,
+		GnoiOsActivate:                                          gnoiosActivate,
 		GnoiOsInstall:                                           gnoiosInstall,
-		GnoiOtdrInitiate:                                        gnoiopticalOTDRInitiate,
+		GnoiOsVerify:                                            gnoiosVerify,
 		GnoiLinkqualificationAllRPC:                             gnoipacket_link_qualificationLinkQualificationALL,
 		GnoiLinkqualificationCapabilities:                       gnoipacket_link_qualificationLinkQualificationCapabilities,
+		GnoiLinkqualificationCreate:                             gnoipacket_link_qualificationLinkQualificationCreate,
 		GnoiLinkqualificationDelete:                             gnoipacket_link_qualificationLinkQualificationDelete,
 		GnoiLinkqualificationGet:                                gnoipacket_link_qualificationLinkQualificationGet,
 		GnoiLinkqualificationList:                               gnoipacket_link_qualificationLinkQualificationList,
 		GnoiSystemAllRPC:                                        gnoisystemALL,
 		GnoiSystemCancelReboot:                                  gnoisystemCancelReboot,
 		GnoiSystemKillProcess:                                   gnoisystemKillProcess,
+		GnoiSystemPing:                                          gnoisystemPing,
 		GnoiSystemReboot:                                        gnoisystemReboot,
 		GnoiSystemRebootStatus:                                  gnoisystemRebootStatus,
 		GnoiSystemSetPackage:                                    gnoisystemSetPackage,
 		GnoiSystemSwitchControlProcessor:                        gnoisystemSwitchControlProcessor,
 		GnoiSystemTime:                                          gnoisystemTime,
 		GnoiSystemTraceroute:                                    gnoisystemTraceroute,
-		GnoiSystemPing:                                          gnoisystemPing,
-		GnoiWavelengthrouterAdjustPSD:                           gnoiopticalWavelengthRouterAdjustPSD,
 		GnsiAcctzAllRPC:                                         gnsiacctzv1AcctzALL,
 		GnsiAcctzRecordSubscribe:                                gnsiacctzv1AcctzRecordSubscribe,
+		GnsiAcctzstreamAllRPC:                                   gnsiacctzv1AcctzStreamALL,
+		GnsiAcctzstreamRecordSubscribe:                          gnsiacctzv1AcctzStreamRecordSubscribe,
 		GnsiAuthzAllRPC:                                         gnsiauthzv1AuthzALL,
 		GnsiAuthzGet:                                            gnsiauthzv1AuthzGet,
 		GnsiAuthzProbe:                                          gnsiauthzv1AuthzProbe,
 		GnsiAuthzRotate:                                         gnsiauthzv1AuthzRotate,
-		GnsiCertzAddProfile:                                     gnsicertzv1CertzAddProfile,
 		GnsiCertzAllRPC:                                         gnsicertzv1CertzALL,
+		GnsiCertzAddProfile:                                     gnsicertzv1CertzAddProfile,
 		GnsiCertzCanGenerateCSR:                                 gnsicertzv1CertzCanGenerateCSR,
 		GnsiCertzDeleteProfile:                                  gnsicertzv1CertzDeleteProfile,
 		GnsiCertzGetProfileList:                                 gnsicertzv1CertzGetProfileList,
@@ -930,8 +947,8 @@ var (
 		GnsiCredentialzAllRPC:                                   gnsicredentialzv1CredentialzALL,
 		GnsiCredentialzCanGenerateKey:                           gnsicredentialzv1CredentialzCanGenerateKey,
 		GnsiCredentialzGetPublicKeys:                            gnsicredentialzv1CredentialzGetPublicKeys,
-		GnsiCredentialzRotateHostParameters:                     gnsicredentialzv1CredentialzRotateHostParameters,
 		GnsiCredentialzRotateAccountCredentials:                 gnsicredentialzv1CredentialzRotateAccountCredentials,
+		GnsiCredentialzRotateHostParameters:                     gnsicredentialzv1CredentialzRotateHostParameters,
 		GnsiPathzAllRPC:                                         gnsipathzv1PathzALL,
 		GnsiPathzGet:                                            gnsipathzv1PathzGet,
 		GnsiPathzProbe:                                          gnsipathzv1PathzProbe,
@@ -953,10 +970,10 @@ var (
 	RPCMAP = map[string]*RPC{
 		"*":                              ALL,
 		"/gnmi.gNMI/*":                   gnmiALL,
+		"/gnmi.gNMI/Capabilities":        gnmiCapabilities,
 		"/gnmi.gNMI/Get":                 gnmiGet,
 		"/gnmi.gNMI/Set":                 gnmiSet,
 		"/gnmi.gNMI/Subscribe":           gnmiSubscribe,
-		"/gnmi.gNMI/Capabilities":        gnmiCapabilities,
 		"/gnoi.bgp.BGP/*":                gnoibgpALL,
 		"/gnoi.bgp.BGP/ClearBGPNeighbor": gnoibgpClearBGPNeighbor,
 		"/gnoi.certificate.CertificateManagement/*":                              gnoicertificateCertificateManagementALL,
@@ -970,67 +987,69 @@ var (
 		"/gnoi.certificate.CertificateManagement/Rotate":                         gnoicertificateCertificateManagementRotate,
 		"/gnoi.diag.Diag/*":                                              gnoidiagALL,
 		"/gnoi.diag.Diag/GetBERTResult":                                  gnoidiagGetBERTResult,
-		"/gnoi.diag.Diag/StopBERT":                                       gnoidiagStopBERT,
 		"/gnoi.diag.Diag/StartBERT":                                      gnoidiagStartBERT,
+		"/gnoi.diag.Diag/StopBERT":                                       gnoidiagStopBERT,
 		"/gnoi.factory_reset.FactoryReset/*":                             gnoifactory_resetFactoryResetALL,
 		"/gnoi.factory_reset.FactoryReset/Start":                         gnoifactory_resetFactoryResetStart,
 		"/gnoi.file.File/*":                                              gnoifileALL,
+		"/gnoi.file.File/Get":                                            gnoifileGet,
 		"/gnoi.file.File/Put":                                            gnoifilePut,
 		"/gnoi.file.File/Remove":                                         gnoifileRemove,
 		"/gnoi.file.File/Stat":                                           gnoifileStat,
 		"/gnoi.file.File/TransferToRemote":                               gnoifileTransferToRemote,
-		"/gnoi.file.File/Get":                                            gnoifileGet,
-		"/gnoi.healthz.Healthz/Acknowledge":                              gnoihealthzAcknowledge,
 		"/gnoi.healthz.Healthz/*":                                        gnoihealthzALL,
+		"/gnoi.healthz.Healthz/Acknowledge":                              gnoihealthzAcknowledge,
 		"/gnoi.healthz.Healthz/Artifact":                                 gnoihealthzArtifact,
 		"/gnoi.healthz.Healthz/Check":                                    gnoihealthzCheck,
-		"/gnoi.healthz.Healthz/List":                                     gnoihealthzList,
 		"/gnoi.healthz.Healthz/Get":                                      gnoihealthzGet,
+		"/gnoi.healthz.Healthz/List":                                     gnoihealthzList,
 		"/gnoi.layer2.Layer2/*":                                          gnoilayer2ALL,
 		"/gnoi.layer2.Layer2/ClearLLDPInterface":                         gnoilayer2ClearLLDPInterface,
+		"/gnoi.layer2.Layer2/ClearNeighborDiscovery":                     gnoilayer2ClearNeighborDiscovery,
 		"/gnoi.layer2.Layer2/ClearSpanningTree":                          gnoilayer2ClearSpanningTree,
 		"/gnoi.layer2.Layer2/PerformBERT":                                gnoilayer2PerformBERT,
 		"/gnoi.layer2.Layer2/SendWakeOnLAN":                              gnoilayer2SendWakeOnLAN,
-		"/gnoi.layer2.Layer2/ClearNeighborDiscovery":                     gnoilayer2ClearNeighborDiscovery,
-		"/gnoi.packet_link_qualification.LinkQualification/Create":       gnoipacket_link_qualificationLinkQualificationCreate,
 		"/gnoi.mpls.MPLS/*":                                              gnoimplsALL,
+		"/gnoi.mpls.MPLS/ClearLSP":                                       gnoimplsClearLSP,
 		"/gnoi.mpls.MPLS/ClearLSPCounters":                               gnoimplsClearLSPCounters,
 		"/gnoi.mpls.MPLS/MPLSPing":                                       gnoimplsMPLSPing,
-		"/gnoi.mpls.MPLS/ClearLSP":                                       gnoimplsClearLSP,
 		"/gnoi.optical.OTDR/*":                                           gnoiopticalOTDRALL,
-		"/gnoi.optical.WavelengthRouter/AdjustSpectrum":                  gnoiopticalWavelengthRouterAdjustSpectrum,
+		"/gnoi.optical.OTDR/Initiate":                                    gnoiopticalOTDRInitiate,
 		"/gnoi.optical.WavelengthRouter/*":                               gnoiopticalWavelengthRouterALL,
+		"/gnoi.optical.WavelengthRouter/AdjustPSD":                       gnoiopticalWavelengthRouterAdjustPSD,
+		"/gnoi.optical.WavelengthRouter/AdjustSpectrum":                  gnoiopticalWavelengthRouterAdjustSpectrum,
 		"/gnoi.optical.WavelengthRouter/CancelAdjustPSD":                 gnoiopticalWavelengthRouterCancelAdjustPSD,
 		"/gnoi.optical.WavelengthRouter/CancelAdjustSpectrum":            gnoiopticalWavelengthRouterCancelAdjustSpectrum,
-		"/gnoi.os.OS/Activate":                                           gnoiosActivate,
 		"/gnoi.os.OS/*":                                                  gnoiosALL,
-		"/gnoi.os.OS/Verify":                                             gnoiosVerify,
+		"/gnoi.os.OS/Activate":                                           gnoiosActivate,
 		"/gnoi.os.OS/Install":                                            gnoiosInstall,
-		"/gnoi.optical.OTDR/Initiate":                                    gnoiopticalOTDRInitiate,
+		"/gnoi.os.OS/Verify":                                             gnoiosVerify,
 		"/gnoi.packet_link_qualification.LinkQualification/*":            gnoipacket_link_qualificationLinkQualificationALL,
 		"/gnoi.packet_link_qualification.LinkQualification/Capabilities": gnoipacket_link_qualificationLinkQualificationCapabilities,
+		"/gnoi.packet_link_qualification.LinkQualification/Create":       gnoipacket_link_qualificationLinkQualificationCreate,
 		"/gnoi.packet_link_qualification.LinkQualification/Delete":       gnoipacket_link_qualificationLinkQualificationDelete,
 		"/gnoi.packet_link_qualification.LinkQualification/Get":          gnoipacket_link_qualificationLinkQualificationGet,
 		"/gnoi.packet_link_qualification.LinkQualification/List":         gnoipacket_link_qualificationLinkQualificationList,
 		"/gnoi.system.System/*":                                          gnoisystemALL,
 		"/gnoi.system.System/CancelReboot":                               gnoisystemCancelReboot,
 		"/gnoi.system.System/KillProcess":                                gnoisystemKillProcess,
+		"/gnoi.system.System/Ping":                                       gnoisystemPing,
 		"/gnoi.system.System/Reboot":                                     gnoisystemReboot,
 		"/gnoi.system.System/RebootStatus":                               gnoisystemRebootStatus,
 		"/gnoi.system.System/SetPackage":                                 gnoisystemSetPackage,
 		"/gnoi.system.System/SwitchControlProcessor":                     gnoisystemSwitchControlProcessor,
 		"/gnoi.system.System/Time":                                       gnoisystemTime,
 		"/gnoi.system.System/Traceroute":                                 gnoisystemTraceroute,
-		"/gnoi.system.System/Ping":                                       gnoisystemPing,
-		"/gnoi.optical.WavelengthRouter/AdjustPSD":                       gnoiopticalWavelengthRouterAdjustPSD,
 		"/gnsi.acctz.v1.Acctz/*":                                         gnsiacctzv1AcctzALL,
 		"/gnsi.acctz.v1.Acctz/RecordSubscribe":                           gnsiacctzv1AcctzRecordSubscribe,
+		"/gnsi.acctz.v1.AcctzStream/*":                                   gnsiacctzv1AcctzStreamALL,
+		"/gnsi.acctz.v1.AcctzStream/RecordSubscribe":                     gnsiacctzv1AcctzStreamRecordSubscribe,
 		"/gnsi.authz.v1.Authz/*":                                         gnsiauthzv1AuthzALL,
 		"/gnsi.authz.v1.Authz/Get":                                       gnsiauthzv1AuthzGet,
 		"/gnsi.authz.v1.Authz/Probe":                                     gnsiauthzv1AuthzProbe,
 		"/gnsi.authz.v1.Authz/Rotate":                                    gnsiauthzv1AuthzRotate,
-		"/gnsi.certz.v1.Certz/AddProfile":                                gnsicertzv1CertzAddProfile,
 		"/gnsi.certz.v1.Certz/*":                                         gnsicertzv1CertzALL,
+		"/gnsi.certz.v1.Certz/AddProfile":                                gnsicertzv1CertzAddProfile,
 		"/gnsi.certz.v1.Certz/CanGenerateCSR":                            gnsicertzv1CertzCanGenerateCSR,
 		"/gnsi.certz.v1.Certz/DeleteProfile":                             gnsicertzv1CertzDeleteProfile,
 		"/gnsi.certz.v1.Certz/GetProfileList":                            gnsicertzv1CertzGetProfileList,
@@ -1038,8 +1057,8 @@ var (
 		"/gnsi.credentialz.v1.Credentialz/*":                             gnsicredentialzv1CredentialzALL,
 		"/gnsi.credentialz.v1.Credentialz/CanGenerateKey":                gnsicredentialzv1CredentialzCanGenerateKey,
 		"/gnsi.credentialz.v1.Credentialz/GetPublicKeys":                 gnsicredentialzv1CredentialzGetPublicKeys,
-		"/gnsi.credentialz.v1.Credentialz/RotateHostParameters":          gnsicredentialzv1CredentialzRotateHostParameters,
 		"/gnsi.credentialz.v1.Credentialz/RotateAccountCredentials":      gnsicredentialzv1CredentialzRotateAccountCredentials,
+		"/gnsi.credentialz.v1.Credentialz/RotateHostParameters":          gnsicredentialzv1CredentialzRotateHostParameters,
 		"/gnsi.pathz.v1.Pathz/*":                                         gnsipathzv1PathzALL,
 		"/gnsi.pathz.v1.Pathz/Get":                                       gnsipathzv1PathzGet,
 		"/gnsi.pathz.v1.Pathz/Probe":                                     gnsipathzv1PathzProbe,
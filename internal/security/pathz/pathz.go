@@ -0,0 +1,144 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ondatra"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	pathzpb "github.com/openconfig/gnsi/pathz"
+)
+
+// Diff returns a human readable diff of policies got and want, or an empty
+// string if they are equivalent. The order of rules and groups is ignored.
+func Diff(want, got *pathzpb.AuthorizationPolicy) string {
+	return cmp.Diff(want, got,
+		protocmp.Transform(),
+		protocmp.SortRepeated(func(a, b *pathzpb.AuthorizationRule) bool { return a.GetId() < b.GetId() }),
+		protocmp.SortRepeated(func(a, b *pathzpb.Group) bool { return a.GetName() < b.GetName() }),
+	)
+}
+
+// Get reads the policy instance of device dut. It fails the test on error.
+func Get(t testing.TB, dut *ondatra.DUTDevice, instance pathzpb.PolicyInstance) *pathzpb.GetResponse {
+	t.Helper()
+	gnsiC, err := dut.RawAPIs().BindingDUT().DialGNSI(context.Background())
+	if err != nil {
+		t.Fatalf("Could not connect gnsi %v", err)
+	}
+	resp, err := gnsiC.Pathz().Get(context.Background(), &pathzpb.GetRequest{PolicyInstance: instance})
+	if err != nil {
+		t.Fatalf("Pathz.Get request of %v is failed on device %s: %v", instance, dut.Name(), err)
+	}
+	return resp
+}
+
+// Rotate uploads policy p to device dut, checks that the sandbox holds it,
+// finalizes the rotation and checks that the active instance holds it. It
+// fails the test on error.
+func (p *Policy) Rotate(t testing.TB, dut *ondatra.DUTDevice, createdOn uint64, version string, forceOverwrite bool) {
+	t.Helper()
+	t.Logf("Performing Pathz.Rotate request on device %s", dut.Name())
+	gnsiC, err := dut.RawAPIs().BindingDUT().DialGNSI(context.Background())
+	if err != nil {
+		t.Fatalf("Could not connect gnsi %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rotateStream, err := gnsiC.Pathz().Rotate(ctx)
+	if err != nil {
+		t.Fatalf("Could not start a rotate stream %v", err)
+	}
+	policy := p.Proto()
+	err = rotateStream.Send(&pathzpb.RotateRequest{
+		ForceOverwrite: forceOverwrite,
+		RotateRequest: &pathzpb.RotateRequest_UploadRequest{
+			UploadRequest: &pathzpb.UploadRequest{
+				Version:   version,
+				CreatedOn: createdOn,
+				Policy:    policy,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Error while uploading pathz policy %v", err)
+	}
+	if _, err := rotateStream.Recv(); err != nil {
+		t.Fatalf("Error while receiving rotate request reply %v", err)
+	}
+	sandbox := Get(t, dut, pathzpb.PolicyInstance_POLICY_INSTANCE_SANDBOX)
+	if d := Diff(policy, sandbox.GetPolicy()); d != "" {
+		t.Fatalf("Policy after upload (sandbox) is not the same as the one uploaded, diff (-want +got):\n%s", d)
+	}
+	err = rotateStream.Send(&pathzpb.RotateRequest{
+		RotateRequest: &pathzpb.RotateRequest_FinalizeRotation{FinalizeRotation: &pathzpb.FinalizeRequest{}},
+	})
+	if err != nil {
+		t.Fatalf("Error while finalizing rotate request %v", err)
+	}
+	// The device closes the stream once the policy is active.
+	rotateStream.CloseSend()
+	rotateStream.Recv()
+	active := Get(t, dut, pathzpb.PolicyInstance_POLICY_INSTANCE_ACTIVE)
+	if d := Diff(policy, active.GetPolicy()); d != "" {
+		t.Fatalf("Policy after finalize is not the same as the one uploaded, diff (-want +got):\n%s", d)
+	}
+	if active.GetVersion() != version {
+		t.Fatalf("Active pathz policy version: got %q, want %q", active.GetVersion(), version)
+	}
+}
+
+// Probe returns the action device dut takes on an access of user to path
+// with mode, as decided by the active policy. It fails the test on error.
+func Probe(t testing.TB, dut *ondatra.DUTDevice, user string, path *gpb.Path, mode pathzpb.Mode) pathzpb.Action {
+	t.Helper()
+	gnsiC, err := dut.RawAPIs().BindingDUT().DialGNSI(context.Background())
+	if err != nil {
+		t.Fatalf("Could not connect gnsi %v", err)
+	}
+	req := &pathzpb.ProbeRequest{
+		User:           user,
+		Path:           proto.Clone(path).(*gpb.Path),
+		Mode:           mode,
+		PolicyInstance: pathzpb.PolicyInstance_POLICY_INSTANCE_ACTIVE,
+	}
+	resp, err := gnsiC.Pathz().Probe(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Pathz.Probe of user %s, path %s and mode %v is failed on device %s: %v", user, pathString(path), mode, dut.Name(), err)
+	}
+	return resp.GetAction()
+}
+
+// VerifyProbe checks that device dut decides every access of the users to
+// the paths with the modes as policy p does locally.
+func (p *Policy) VerifyProbe(t testing.TB, dut *ondatra.DUTDevice, users []string, modes []pathzpb.Mode, paths ...*gpb.Path) {
+	t.Helper()
+	for _, user := range users {
+		for _, mode := range modes {
+			for _, path := range paths {
+				want, rule := p.Evaluate(user, path, mode)
+				if got := Probe(t, dut, user, path, mode); got != want {
+					t.Errorf("Pathz.Probe of user %s, path %s and mode %v: got %v, want %v (rule %q)", user, pathString(path), mode, got, want, rule)
+				}
+			}
+		}
+	}
+}
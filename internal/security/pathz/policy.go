@@ -0,0 +1,250 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pathz provides helper APIs to simplify writing gNSI pathz test
+// cases: building policies from ygnmi path structs, rotating them on a DUT,
+// evaluating them locally, and verifying the access of SPIFFE identities
+// with gNMI Get, Subscribe and Set.
+package pathz
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/openconfig/ygnmi/ygnmi"
+	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	pathzpb "github.com/openconfig/gnsi/pathz"
+)
+
+const (
+	permit = pathzpb.Action_ACTION_PERMIT
+	deny   = pathzpb.Action_ACTION_DENY
+	read   = pathzpb.Mode_MODE_READ
+	write  = pathzpb.Mode_MODE_WRITE
+)
+
+// Principal is the user or the group a rule applies to.
+type Principal struct {
+	User, Group string
+}
+
+// User returns the principal for user name.
+func User(name string) Principal { return Principal{User: name} }
+
+// Group returns the principal for the group name of the policy.
+func Group(name string) Principal { return Principal{Group: name} }
+
+// Path resolves a ygnmi path struct, such as the PathStruct of a query, to
+// a gNMI path with the openconfig origin.
+func Path(ps ygnmi.PathStruct) (*gpb.Path, error) {
+	path, opts, err := ygnmi.ResolvePath(ps)
+	if err != nil {
+		return nil, err
+	}
+	if origin, ok := opts[ygnmi.OriginOverride]; ok {
+		path.Origin = origin.(string)
+	}
+	if path.Origin == "" {
+		path.Origin = "openconfig"
+	}
+	return path, nil
+}
+
+// Policy is a pathz authorization policy.
+type Policy struct {
+	Rules  []*pathzpb.AuthorizationRule
+	Groups []*pathzpb.Group
+}
+
+// NewPolicy returns an empty policy, which denies every path.
+func NewPolicy() *Policy {
+	return &Policy{}
+}
+
+// FromProto returns the policy of the proto p.
+func FromProto(p *pathzpb.AuthorizationPolicy) *Policy {
+	return &Policy{Rules: p.GetRules(), Groups: p.GetGroups()}
+}
+
+// Proto returns the policy as a proto.
+func (p *Policy) Proto() *pathzpb.AuthorizationPolicy {
+	return &pathzpb.AuthorizationPolicy{Rules: p.Rules, Groups: p.Groups}
+}
+
+// AddGroup adds a group of users to the policy.
+func (p *Policy) AddGroup(name string, users ...string) {
+	g := &pathzpb.Group{Name: name}
+	for _, u := range users {
+		g.Users = append(g.Users, &pathzpb.User{Name: u})
+	}
+	p.Groups = append(p.Groups, g)
+}
+
+// AddRule adds a rule with action for principal accessing path with mode.
+func (p *Policy) AddRule(id string, principal Principal, action pathzpb.Action, mode pathzpb.Mode, path *gpb.Path) error {
+	r := &pathzpb.AuthorizationRule{Id: id, Path: path, Action: action, Mode: mode}
+	switch {
+	case principal.User != "" && principal.Group != "":
+		return fmt.Errorf("rule %s has both user %s and group %s", id, principal.User, principal.Group)
+	case principal.User != "":
+		r.Principal = &pathzpb.AuthorizationRule_User{User: principal.User}
+	case principal.Group != "":
+		if !slices.ContainsFunc(p.Groups, func(g *pathzpb.Group) bool { return g.GetName() == principal.Group }) {
+			return fmt.Errorf("rule %s refers to unknown group %s", id, principal.Group)
+		}
+		r.Principal = &pathzpb.AuthorizationRule_Group{Group: principal.Group}
+	default:
+		return fmt.Errorf("rule %s has no principal", id)
+	}
+	for _, e := range path.GetElem() {
+		if e.GetName() == "*" || e.GetName() == "..." {
+			return fmt.Errorf("rule %s has wildcard element %s, only keys may be wildcards", id, e.GetName())
+		}
+	}
+	if slices.ContainsFunc(p.Rules, func(o *pathzpb.AuthorizationRule) bool { return o.GetId() == id }) {
+		return fmt.Errorf("duplicate rule id %s", id)
+	}
+	p.Rules = append(p.Rules, r)
+	return nil
+}
+
+func (p *Policy) addRule(t testing.TB, id string, principal Principal, action pathzpb.Action, mode pathzpb.Mode, ps ygnmi.PathStruct) {
+	t.Helper()
+	path, err := Path(ps)
+	if err != nil {
+		t.Fatalf("Could not resolve path of pathz rule %s: %v", id, err)
+	}
+	if err := p.AddRule(id, principal, action, mode, path); err != nil {
+		t.Fatalf("Could not add pathz rule: %v", err)
+	}
+}
+
+// Permit adds a rule permitting principal to access the path of ps with
+// mode. It fails the test if the rule is invalid.
+func (p *Policy) Permit(t testing.TB, id string, principal Principal, mode pathzpb.Mode, ps ygnmi.PathStruct) {
+	t.Helper()
+	p.addRule(t, id, principal, permit, mode, ps)
+}
+
+// Deny adds a rule denying principal to access the path of ps with mode. It
+// fails the test if the rule is invalid.
+func (p *Policy) Deny(t testing.TB, id string, principal Principal, mode pathzpb.Mode, ps ygnmi.PathStruct) {
+	t.Helper()
+	p.addRule(t, id, principal, deny, mode, ps)
+}
+
+// inGroup reports whether user is a member of group.
+func (p *Policy) inGroup(user, group string) bool {
+	for _, g := range p.Groups {
+		if g.GetName() != group {
+			continue
+		}
+		for _, u := range g.GetUsers() {
+			if u.GetName() == user {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sameOrigin(a, b string) bool {
+	if a == "" {
+		a = "openconfig"
+	}
+	if b == "" {
+		b = "openconfig"
+	}
+	return a == b
+}
+
+// match reports whether the path of a rule matches path, and returns the
+// number of definite keys of the rule. A rule matches the paths it is a
+// prefix of, by element. A key missing from the rule, or with value "*",
+// matches any value.
+func match(rule, path *gpb.Path) (bool, int) {
+	if !sameOrigin(rule.GetOrigin(), path.GetOrigin()) || len(rule.GetElem()) > len(path.GetElem()) {
+		return false, 0
+	}
+	definite := 0
+	for i, re := range rule.GetElem() {
+		pe := path.GetElem()[i]
+		if re.GetName() != pe.GetName() {
+			return false, 0
+		}
+		for k, v := range re.GetKey() {
+			if v == "*" {
+				continue
+			}
+			if pe.GetKey()[k] != v {
+				return false, 0
+			}
+			definite++
+		}
+	}
+	return true, definite
+}
+
+// Evaluate returns the action the policy is expected to take on an access
+// of user to path with mode, with the id of the rule that decided it. Among
+// the matching rules, the best match has the longest path, then the most
+// definite keys, then applies to the user rather than to a group, then
+// denies. Accesses that match no rule are denied with an empty rule id.
+func (p *Policy) Evaluate(user string, path *gpb.Path, mode pathzpb.Mode) (pathzpb.Action, string) {
+	var best *pathzpb.AuthorizationRule
+	var bestRank [4]int
+	for _, r := range p.Rules {
+		if r.GetMode() != mode {
+			continue
+		}
+		isUser := 0
+		switch {
+		case r.GetUser() != "":
+			if r.GetUser() != user {
+				continue
+			}
+			isUser = 1
+		case !p.inGroup(user, r.GetGroup()):
+			continue
+		}
+		ok, definite := match(r.GetPath(), path)
+		if !ok {
+			continue
+		}
+		isDeny := 0
+		if r.GetAction() == deny {
+			isDeny = 1
+		}
+		rank := [4]int{len(r.GetPath().GetElem()), definite, isUser, isDeny}
+		if best == nil || slices.Compare(rank[:], bestRank[:]) > 0 {
+			best, bestRank = r, rank
+		}
+	}
+	if best == nil {
+		return deny, ""
+	}
+	return best.GetAction(), best.GetId()
+}
+
+// pathString returns the string form of path for messages.
+func pathString(path *gpb.Path) string {
+	s, err := ygot.PathToString(path)
+	if err != nil {
+		return path.String()
+	}
+	return s
+}
@@ -0,0 +1,217 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathz
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ondatra/gnmi/oc/ocpath"
+	"github.com/openconfig/ygot/ygot"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	pathzpb "github.com/openconfig/gnsi/pathz"
+)
+
+func mustPath(t *testing.T, s string) *gpb.Path {
+	t.Helper()
+	p, err := ygot.StringToStructuredPath(s)
+	if err != nil {
+		t.Fatalf("StringToStructuredPath(%q) failed: %v", s, err)
+	}
+	p.Origin = "openconfig"
+	return p
+}
+
+func TestPath(t *testing.T) {
+	tests := []struct {
+		desc string
+		got  func() (*gpb.Path, error)
+		want string
+	}{{
+		desc: "container",
+		got:  func() (*gpb.Path, error) { return Path(ocpath.Root().Interface("Ethernet1")) },
+		want: "/interfaces/interface[name=Ethernet1]",
+	}, {
+		desc: "wildcard",
+		got:  func() (*gpb.Path, error) { return Path(ocpath.Root().InterfaceAny()) },
+		want: "/interfaces/interface[name=*]",
+	}, {
+		desc: "config leaf",
+		got: func() (*gpb.Path, error) {
+			return Path(ocpath.Root().Interface("Ethernet1").Description().Config().PathStruct())
+		},
+		want: "/interfaces/interface[name=Ethernet1]/config/description",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := tt.got()
+			if err != nil {
+				t.Fatalf("Path() failed: %v", err)
+			}
+			if diff := cmp.Diff(mustPath(t, tt.want), got, protocmp.Transform()); diff != "" {
+				t.Errorf("Path() returned diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAddRuleErrors(t *testing.T) {
+	p := NewPolicy()
+	p.AddGroup("admins", "alice")
+	path := mustPath(t, "/interfaces")
+	if err := p.AddRule("r1", User("alice"), permit, read, path); err != nil {
+		t.Fatalf("AddRule() failed: %v", err)
+	}
+	tests := []struct {
+		desc      string
+		id        string
+		principal Principal
+		path      *gpb.Path
+	}{
+		{desc: "duplicate id", id: "r1", principal: User("bob"), path: path},
+		{desc: "no principal", id: "r2", path: path},
+		{desc: "user and group", id: "r3", principal: Principal{User: "bob", Group: "admins"}, path: path},
+		{desc: "unknown group", id: "r4", principal: Group("ops"), path: path},
+		{desc: "wildcard element", id: "r5", principal: User("bob"), path: mustPath(t, "/interfaces/*")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := p.AddRule(tt.id, tt.principal, permit, read, tt.path); err == nil {
+				t.Errorf("AddRule() got nil error, want error")
+			}
+		})
+	}
+	if got := len(p.Rules); got != 1 {
+		t.Errorf("Rules after invalid additions: got %d, want 1", got)
+	}
+}
+
+func TestPermitDeny(t *testing.T) {
+	p := NewPolicy()
+	p.Permit(t, "permit", User("alice"), write, ocpath.Root().InterfaceAny())
+	p.Deny(t, "deny", User("alice"), write, ocpath.Root().Interface("Management0"))
+	want := &pathzpb.AuthorizationPolicy{Rules: []*pathzpb.AuthorizationRule{{
+		Id:        "permit",
+		Principal: &pathzpb.AuthorizationRule_User{User: "alice"},
+		Path:      mustPath(t, "/interfaces/interface[name=*]"),
+		Action:    permit,
+		Mode:      write,
+	}, {
+		Id:        "deny",
+		Principal: &pathzpb.AuthorizationRule_User{User: "alice"},
+		Path:      mustPath(t, "/interfaces/interface[name=Management0]"),
+		Action:    deny,
+		Mode:      write,
+	}}}
+	if diff := Diff(want, p.Proto()); diff != "" {
+		t.Errorf("Proto() returned diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	p := NewPolicy()
+	p.AddGroup("admins", "alice", "bob")
+	rules := []struct {
+		id        string
+		principal Principal
+		action    pathzpb.Action
+		mode      pathzpb.Mode
+		path      string
+	}{
+		{"admins-read", Group("admins"), permit, read, "/interfaces"},
+		{"admins-write", Group("admins"), permit, write, "/interfaces/interface[name=*]/config"},
+		{"alice-write-eth1", User("alice"), deny, write, "/interfaces/interface[name=Ethernet1]/config"},
+		{"bob-write", User("bob"), permit, write, "/interfaces/interface[name=*]/config"},
+		{"bob-write-deny", User("bob"), deny, write, "/interfaces/interface[name=*]/config"},
+		{"admins-desc", Group("admins"), deny, read, "/interfaces/interface/state/description"},
+		{"carol-system", User("carol"), permit, read, "/system"},
+	}
+	for _, r := range rules {
+		if err := p.AddRule(r.id, r.principal, r.action, r.mode, mustPath(t, r.path)); err != nil {
+			t.Fatalf("AddRule(%s) failed: %v", r.id, err)
+		}
+	}
+	tests := []struct {
+		desc     string
+		user     string
+		path     string
+		mode     pathzpb.Mode
+		want     pathzpb.Action
+		wantRule string
+	}{{
+		desc:     "group prefix",
+		user:     "alice",
+		path:     "/interfaces/interface[name=Ethernet1]/state/counters",
+		mode:     read,
+		want:     permit,
+		wantRule: "admins-read",
+	}, {
+		desc:     "longest match",
+		user:     "alice",
+		path:     "/interfaces/interface[name=Ethernet1]/state/description",
+		mode:     read,
+		want:     deny,
+		wantRule: "admins-desc",
+	}, {
+		desc:     "definite key",
+		user:     "alice",
+		path:     "/interfaces/interface[name=Ethernet1]/config/mtu",
+		mode:     write,
+		want:     deny,
+		wantRule: "alice-write-eth1",
+	}, {
+		desc:     "wildcard key",
+		user:     "alice",
+		path:     "/interfaces/interface[name=Ethernet2]/config/mtu",
+		mode:     write,
+		want:     permit,
+		wantRule: "admins-write",
+	}, {
+		desc:     "deny precedence",
+		user:     "bob",
+		path:     "/interfaces/interface[name=Ethernet2]/config/mtu",
+		mode:     write,
+		want:     deny,
+		wantRule: "bob-write-deny",
+	}, {
+		desc: "mode",
+		user: "carol",
+		path: "/system/config/hostname",
+		mode: write,
+		want: deny,
+	}, {
+		desc: "no match",
+		user: "carol",
+		path: "/interfaces",
+		mode: read,
+		want: deny,
+	}, {
+		desc: "shorter path",
+		user: "alice",
+		path: "/interfaces/interface[name=Ethernet1]",
+		mode: write,
+		want: deny,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, gotRule := p.Evaluate(tt.user, mustPath(t, tt.path), tt.mode)
+			if got != tt.want || gotRule != tt.wantRule {
+				t.Errorf("Evaluate(%s, %s, %v): got %v (rule %q), want %v (rule %q)", tt.user, tt.path, tt.mode, got, gotRule, tt.want, tt.wantRule)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/openconfig/featureprofiles/internal/security/authz"
+	"github.com/openconfig/ondatra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	pathzpb "github.com/openconfig/gnsi/pathz"
+)
+
+const opTimeout = 30 * time.Second
+
+// Op is a gNMI operation used to verify the access to a path.
+type Op int
+
+const (
+	// OpGet reads the path with gNMI Get.
+	OpGet Op = iota
+	// OpSubscribe reads the path with a gNMI ONCE subscription.
+	OpSubscribe
+	// OpSet writes the current values of the path back with gNMI Set. The
+	// path must hold a value.
+	OpSet
+)
+
+func (o Op) String() string {
+	switch o {
+	case OpGet:
+		return "Get"
+	case OpSubscribe:
+		return "Subscribe"
+	case OpSet:
+		return "Set"
+	}
+	return fmt.Sprintf("Op(%d)", int(o))
+}
+
+// Mode returns the pathz mode checked for the operation.
+func (o Op) Mode() pathzpb.Mode {
+	if o == OpSet {
+		return write
+	}
+	return read
+}
+
+// Result is the outcome of an operation of an identity on a path.
+type Result struct {
+	User string
+	Op   Op
+	Path *gpb.Path
+	// Want is the action expected by the local evaluation of the policy, and
+	// Rule the id of the rule that decided it.
+	Want pathzpb.Action
+	Rule string
+	// Got is the action observed on the device, unset if Err is set.
+	Got pathzpb.Action
+	Err error
+}
+
+// Mismatch reports whether the device did not behave as expected.
+func (r *Result) Mismatch() bool {
+	return r.Err != nil || r.Got != r.Want
+}
+
+func (r *Result) String() string {
+	if r.Err != nil {
+		return fmt.Sprintf("%s of %s by %s: %v", r.Op, pathString(r.Path), r.User, r.Err)
+	}
+	return fmt.Sprintf("%s of %s by %s: got %v, want %v (rule %q)", r.Op, pathString(r.Path), r.User, r.Got, r.Want, r.Rule)
+}
+
+// readAction returns the action observed for a read that returned n
+// updates or err. Pathz filters the data a user may not read, so a read
+// without data is taken as denied: the paths verified must hold data.
+func readAction(n int, err error) (pathzpb.Action, error) {
+	switch status.Code(err) {
+	case codes.OK:
+		if n == 0 {
+			return deny, nil
+		}
+		return permit, nil
+	case codes.PermissionDenied, codes.NotFound:
+		return deny, nil
+	}
+	return 0, err
+}
+
+// writeAction returns the action observed for a write that returned err.
+func writeAction(err error) (pathzpb.Action, error) {
+	switch status.Code(err) {
+	case codes.OK:
+		return permit, nil
+	case codes.PermissionDenied:
+		return deny, nil
+	}
+	return 0, err
+}
+
+// get reads path with gNMI Get and returns the updates, with their paths
+// joined to the prefix of their notification.
+func get(ctx context.Context, c gpb.GNMIClient, path *gpb.Path) ([]*gpb.Update, error) {
+	resp, err := c.Get(ctx, &gpb.GetRequest{Path: []*gpb.Path{path}, Encoding: gpb.Encoding_JSON_IETF})
+	if err != nil {
+		return nil, err
+	}
+	var updates []*gpb.Update
+	for _, n := range resp.GetNotification() {
+		for _, u := range n.GetUpdate() {
+			full := &gpb.Path{
+				Origin: n.GetPrefix().GetOrigin(),
+				Elem:   append(append([]*gpb.PathElem(nil), n.GetPrefix().GetElem()...), u.GetPath().GetElem()...),
+			}
+			if full.Origin == "" {
+				full.Origin = u.GetPath().GetOrigin()
+			}
+			updates = append(updates, &gpb.Update{Path: full, Val: u.GetVal()})
+		}
+	}
+	return updates, nil
+}
+
+// subscribe reads path with a gNMI ONCE subscription and returns the number
+// of updates received before the sync.
+func subscribe(ctx context.Context, c gpb.GNMIClient, path *gpb.Path) (int, error) {
+	sub, err := c.Subscribe(ctx)
+	if err != nil {
+		return 0, err
+	}
+	err = sub.Send(&gpb.SubscribeRequest{
+		Request: &gpb.SubscribeRequest_Subscribe{
+			Subscribe: &gpb.SubscriptionList{
+				Mode:         gpb.SubscriptionList_ONCE,
+				Encoding:     gpb.Encoding_PROTO,
+				Subscription: []*gpb.Subscription{{Path: path}},
+			},
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for {
+		resp, err := sub.Recv()
+		switch {
+		case errors.Is(err, io.EOF):
+			return n, nil
+		case err != nil:
+			return n, err
+		case resp.GetSyncResponse():
+			return n, nil
+		}
+		n += len(resp.GetUpdate().GetUpdate())
+	}
+}
+
+// errNoValue is returned by set for paths without a value to write back.
+var errNoValue = errors.New("Set requires a path holding a value")
+
+// setRequest returns the request writing back the updates read by admin,
+// which leaves the configuration of the device unchanged.
+func setRequest(updates []*gpb.Update) (*gpb.SetRequest, error) {
+	if len(updates) == 0 {
+		return nil, errNoValue
+	}
+	return &gpb.SetRequest{Update: updates}, nil
+}
+
+// set writes back the values of path read by admin with client c. It never
+// deletes, so a permitted identity cannot change the configuration.
+func set(ctx context.Context, admin, c gpb.GNMIClient, path *gpb.Path) error {
+	updates, err := get(ctx, admin, path)
+	if err != nil && status.Code(err) != codes.NotFound {
+		return fmt.Errorf("could not read current value: %w", err)
+	}
+	req, err := setRequest(updates)
+	if err != nil {
+		return fmt.Errorf("%s: %w", pathString(path), err)
+	}
+	_, err = c.Set(ctx, req)
+	return err
+}
+
+// execute runs operation op on path with client c and returns the observed
+// action.
+func execute(ctx context.Context, op Op, admin, c gpb.GNMIClient, path *gpb.Path) (pathzpb.Action, error) {
+	ctx, cancel := context.WithTimeout(ctx, opTimeout)
+	defer cancel()
+	switch op {
+	case OpGet:
+		updates, err := get(ctx, c, path)
+		return readAction(len(updates), err)
+	case OpSubscribe:
+		n, err := subscribe(ctx, c, path)
+		return readAction(n, err)
+	case OpSet:
+		return writeAction(set(ctx, admin, c, path))
+	}
+	return 0, fmt.Errorf("unknown operation %v", op)
+}
+
+// Check runs every operation on every path as every identity on device dut
+// and returns the results, with the action expected by policy p. Set
+// operations must use configuration paths holding values, which are written
+// back unchanged.
+func (p *Policy) Check(t testing.TB, dut *ondatra.DUTDevice, identities []*authz.Spiffe, ops []Op, paths ...*gpb.Path) []*Result {
+	t.Helper()
+	ctx := context.Background()
+	admin, err := dut.RawAPIs().BindingDUT().DialGNMI(ctx)
+	if err != nil {
+		t.Fatalf("Could not connect gnmi %v", err)
+	}
+	var results []*Result
+	for _, spiffe := range identities {
+		c, err := dut.RawAPIs().BindingDUT().DialGNMI(ctx, grpc.WithTransportCredentials(credentials.NewTLS(spiffe.TLSConf)))
+		if err != nil {
+			t.Fatalf("Could not connect gnmi as %s: %v", spiffe.ID, err)
+		}
+		for _, op := range ops {
+			for _, path := range paths {
+				r := &Result{User: spiffe.ID, Op: op, Path: path}
+				r.Want, r.Rule = p.Evaluate(spiffe.ID, path, op.Mode())
+				r.Got, r.Err = execute(ctx, op, admin, c, path)
+				results = append(results, r)
+			}
+		}
+	}
+	return results
+}
+
+// Verify runs Check and reports an error for every result that does not
+// match policy p.
+func (p *Policy) Verify(t testing.TB, dut *ondatra.DUTDevice, identities []*authz.Spiffe, ops []Op, paths ...*gpb.Path) {
+	t.Helper()
+	for _, r := range p.Check(t, dut, identities, ops, paths...) {
+		if r.Mismatch() {
+			t.Errorf("Pathz mismatch on device %s: %v", dut.Name(), r)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathz
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	pathzpb "github.com/openconfig/gnsi/pathz"
+)
+
+func TestReadAction(t *testing.T) {
+	tests := []struct {
+		desc    string
+		n       int
+		err     error
+		want    pathzpb.Action
+		wantErr bool
+	}{
+		{desc: "data", n: 2, want: permit},
+		{desc: "filtered", want: deny},
+		{desc: "permission denied", err: status.Error(codes.PermissionDenied, ""), want: deny},
+		{desc: "not found", err: status.Error(codes.NotFound, ""), want: deny},
+		{desc: "unavailable", err: status.Error(codes.Unavailable, ""), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := readAction(tt.n, tt.err)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readAction() got error %v, want error %t", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("readAction(): got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteAction(t *testing.T) {
+	tests := []struct {
+		desc    string
+		err     error
+		want    pathzpb.Action
+		wantErr bool
+	}{
+		{desc: "ok", want: permit},
+		{desc: "permission denied", err: status.Error(codes.PermissionDenied, ""), want: deny},
+		{desc: "invalid", err: status.Error(codes.InvalidArgument, ""), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := writeAction(tt.err)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("writeAction() got error %v, want error %t", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("writeAction(): got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetRequest(t *testing.T) {
+	if _, err := setRequest(nil); !errors.Is(err, errNoValue) {
+		t.Errorf("setRequest() without updates: got error %v, want %v", err, errNoValue)
+	}
+	updates := []*gpb.Update{
+		{Path: mustPath(t, "/system/config/hostname"), Val: &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "dut"}}},
+		{Path: mustPath(t, "/system/config/domain-name"), Val: &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "lab"}}},
+	}
+	req, err := setRequest(updates)
+	if err != nil {
+		t.Fatalf("setRequest() failed: %v", err)
+	}
+	if len(req.GetDelete()) != 0 || len(req.GetReplace()) != 0 {
+		t.Errorf("setRequest() got deletes %v and replaces %v, want none", req.GetDelete(), req.GetReplace())
+	}
+	if got := len(req.GetUpdate()); got != len(updates) {
+		t.Errorf("setRequest() updates: got %d, want %d", got, len(updates))
+	}
+}
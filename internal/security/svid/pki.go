@@ -0,0 +1,399 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svid
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// rsaKeySize is the size of the RSA keys generated by the PKI toolkit.
+	rsaKeySize = 2048
+	// clockSkew is subtracted from the start of the validity of the
+	// certificates, so that they are valid on a DUT whose clock is late.
+	clockSkew = time.Hour
+	// defaultCAValidity and defaultValidity are the validity periods of CA and
+	// leaf certificates when not set in CertOptions.
+	defaultCAValidity = 10 * 365 * 24 * time.Hour
+	defaultValidity   = 365 * 24 * time.Hour
+)
+
+// GenerateKey generates a private key for x509.RSA, x509.ECDSA (P-256) or
+// x509.Ed25519.
+func GenerateKey(keyAlgo x509.PublicKeyAlgorithm) (crypto.Signer, error) {
+	switch keyAlgo {
+	case x509.RSA:
+		return rsa.GenerateKey(rand.Reader, rsaKeySize)
+	case x509.ECDSA:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case x509.Ed25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	}
+	return nil, fmt.Errorf("key algorithm %v is not supported", keyAlgo)
+}
+
+// CertOptions are the parameters of a generated certificate.
+type CertOptions struct {
+	CommonName string
+	// KeyAlgo is the algorithm of the key of the certificate. Defaults to
+	// x509.ECDSA.
+	KeyAlgo x509.PublicKeyAlgorithm
+	// DNSNames, IPAddresses and URIs are the subject alternative names.
+	DNSNames    []string
+	IPAddresses []net.IP
+	URIs        []string
+	// NotBefore and NotAfter bound the validity of the certificate. They
+	// default to one hour ago and to ValidFor from now.
+	NotBefore, NotAfter time.Time
+	// ValidFor is the validity period used when NotAfter is not set. Defaults
+	// to 10 years for CAs and to one year for other certificates.
+	ValidFor time.Duration
+}
+
+// Expired returns a copy of opts for a certificate that expired a day ago.
+func Expired(opts CertOptions) CertOptions {
+	now := time.Now()
+	opts.NotBefore, opts.NotAfter = now.AddDate(0, 0, -2), now.AddDate(0, 0, -1)
+	return opts
+}
+
+// NotYetValid returns a copy of opts for a certificate that becomes valid in
+// a day.
+func NotYetValid(opts CertOptions) CertOptions {
+	now := time.Now()
+	opts.NotBefore, opts.NotAfter = now.AddDate(0, 0, 1), now.AddDate(0, 0, 2)
+	return opts
+}
+
+// template returns the certificate template for opts, with its private key.
+func (opts *CertOptions) template(isCA bool) (*x509.Certificate, crypto.Signer, error) {
+	keyAlgo := opts.KeyAlgo
+	if keyAlgo == x509.UnknownPublicKeyAlgorithm {
+		keyAlgo = x509.ECDSA
+	}
+	key, err := GenerateKey(keyAlgo)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		return nil, nil, err
+	}
+	var uris []*url.URL
+	for _, u := range opts.URIs {
+		uri, err := url.Parse(u)
+		if err != nil {
+			return nil, nil, err
+		}
+		uris = append(uris, uri)
+	}
+	validFor := opts.ValidFor
+	if validFor == 0 {
+		validFor = defaultValidity
+		if isCA {
+			validFor = defaultCAValidity
+		}
+	}
+	notBefore, notAfter := opts.NotBefore, opts.NotAfter
+	if notBefore.IsZero() {
+		notBefore = time.Now().Add(-clockSkew)
+	}
+	if notAfter.IsZero() {
+		notAfter = time.Now().Add(validFor)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   opts.CommonName,
+			Organization: []string{"OpenconfigFeatureProfiles"},
+			Country:      []string{"US"},
+		},
+		DNSNames:    opts.DNSNames,
+		IPAddresses: opts.IPAddresses,
+		URIs:        uris,
+		NotBefore:   notBefore,
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+	}
+	if isCA {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage |= x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	} else if keyAlgo == x509.RSA {
+		tmpl.KeyUsage |= x509.KeyUsageKeyEncipherment
+	}
+	return tmpl, key, nil
+}
+
+// Cert is a generated certificate with its private key.
+type Cert struct {
+	Cert *x509.Certificate
+	Key  crypto.Signer
+	// Chain are the certificates of the intermediate CAs that issued Cert,
+	// from its issuer up to, excluding, the root CA.
+	Chain []*x509.Certificate
+}
+
+// TLSCertificate returns the certificate and its chain for use in a
+// tls.Config.
+func (c *Cert) TLSCertificate() tls.Certificate {
+	tc := tls.Certificate{
+		Certificate: [][]byte{c.Cert.Raw},
+		PrivateKey:  c.Key,
+		Leaf:        c.Cert,
+	}
+	for _, ic := range c.Chain {
+		tc.Certificate = append(tc.Certificate, ic.Raw)
+	}
+	return tc
+}
+
+// CertPEM returns the PEM encoding of the certificate.
+func (c *Cert) CertPEM() []byte {
+	return certsPEM(c.Cert)
+}
+
+// ChainPEM returns the PEM encoding of the certificate followed by its chain.
+func (c *Cert) ChainPEM() []byte {
+	return certsPEM(append([]*x509.Certificate{c.Cert}, c.Chain...)...)
+}
+
+// KeyPEM returns the PKCS #8 PEM encoding of the private key.
+func (c *Cert) KeyPEM() ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(c.Key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// WriteFiles writes the certificate with its chain to <name>-cert.pem and
+// the private key to <name>-key.pem in dir, and returns their paths.
+func (c *Cert) WriteFiles(dir, name string) (certPath, keyPath string, err error) {
+	keyPEM, err := c.KeyPEM()
+	if err != nil {
+		return "", "", err
+	}
+	certPath = filepath.Join(dir, name+"-cert.pem")
+	keyPath = filepath.Join(dir, name+"-key.pem")
+	if err := os.WriteFile(certPath, c.ChainPEM(), 0644); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(keyPath, keyPEM, 0600); err != nil {
+		return "", "", err
+	}
+	return certPath, keyPath, nil
+}
+
+func certsPEM(certs ...*x509.Certificate) []byte {
+	var buf bytes.Buffer
+	for _, c := range certs {
+		pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: c.Raw})
+	}
+	return buf.Bytes()
+}
+
+// CA is a root or intermediate certificate authority that issues
+// certificates and revocation lists.
+type CA struct {
+	Cert
+	// Parent is the CA that issued an intermediate CA, nil for a root CA.
+	Parent *CA
+
+	mu        sync.Mutex
+	revoked   []x509.RevocationListEntry
+	crlNumber int64
+}
+
+// NewRootCA generates a self-signed root CA.
+func NewRootCA(opts CertOptions) (*CA, error) {
+	tmpl, key, err := opts.template(true)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := sign(tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+	return &CA{Cert: Cert{Cert: cert, Key: key}}, nil
+}
+
+// NewIntermediateCA generates an intermediate CA issued by ca.
+func (ca *CA) NewIntermediateCA(opts CertOptions) (*CA, error) {
+	c, err := ca.issue(opts, true)
+	if err != nil {
+		return nil, err
+	}
+	return &CA{Cert: *c, Parent: ca}, nil
+}
+
+// Root returns the root CA of ca.
+func (ca *CA) Root() *CA {
+	for ca.Parent != nil {
+		ca = ca.Parent
+	}
+	return ca
+}
+
+// Pool returns a certificate pool holding the root CA of ca.
+func (ca *CA) Pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Root().Cert.Cert)
+	return pool
+}
+
+// BundlePEM returns the PEM encoding of the certificates of ca and of its
+// parents up to the root CA, as expected in a trust bundle.
+func (ca *CA) BundlePEM() []byte {
+	var certs []*x509.Certificate
+	for c := ca; c != nil; c = c.Parent {
+		certs = append(certs, c.Cert.Cert)
+	}
+	return certsPEM(certs...)
+}
+
+// BundlePEM returns the PEM encoding of the trust bundles of all cas.
+func BundlePEM(cas ...*CA) []byte {
+	var buf bytes.Buffer
+	for _, ca := range cas {
+		buf.Write(ca.BundlePEM())
+	}
+	return buf.Bytes()
+}
+
+// IssueServer issues a certificate for a server, such as a DUT. At least one
+// DNS name or IP address is required.
+func (ca *CA) IssueServer(opts CertOptions) (*Cert, error) {
+	if len(opts.DNSNames) == 0 && len(opts.IPAddresses) == 0 {
+		return nil, fmt.Errorf("server certificate %q has no DNS name or IP address", opts.CommonName)
+	}
+	return ca.issue(opts, false, x509.ExtKeyUsageServerAuth)
+}
+
+// IssueClient issues a certificate for a client.
+func (ca *CA) IssueClient(opts CertOptions) (*Cert, error) {
+	return ca.issue(opts, false, x509.ExtKeyUsageClientAuth)
+}
+
+// IssueSVID issues an SVID for spiffeID, usable by clients and servers.
+func (ca *CA) IssueSVID(spiffeID string, opts CertOptions) (*Cert, error) {
+	opts.URIs = []string{spiffeID}
+	return ca.issue(opts, false, x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth)
+}
+
+func (ca *CA) issue(opts CertOptions, isCA bool, usages ...x509.ExtKeyUsage) (*Cert, error) {
+	tmpl, key, err := opts.template(isCA)
+	if err != nil {
+		return nil, err
+	}
+	tmpl.ExtKeyUsage = usages
+	cert, err := sign(tmpl, ca.Cert.Cert, key.Public(), ca.Key)
+	if err != nil {
+		return nil, err
+	}
+	var chain []*x509.Certificate
+	for c := ca; c.Parent != nil; c = c.Parent {
+		chain = append(chain, c.Cert.Cert)
+	}
+	return &Cert{Cert: cert, Key: key, Chain: chain}, nil
+}
+
+func sign(tmpl, parent *x509.Certificate, pub crypto.PublicKey, key crypto.Signer) (*x509.Certificate, error) {
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, pub, key)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
+
+// Revoke adds c to the certificates revoked by ca. c must be issued by ca.
+func (ca *CA) Revoke(c *Cert) error {
+	if err := c.Cert.CheckSignatureFrom(ca.Cert.Cert); err != nil {
+		return fmt.Errorf("certificate %q is not issued by CA %q: %v", c.Cert.Subject.CommonName, ca.Cert.Cert.Subject.CommonName, err)
+	}
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+	ca.revoked = append(ca.revoked, x509.RevocationListEntry{SerialNumber: c.Cert.SerialNumber, RevocationTime: time.Now()})
+	return nil
+}
+
+// CRL returns a new DER encoded revocation list of ca, valid for validFor.
+func (ca *CA) CRL(validFor time.Duration) ([]byte, error) {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+	ca.crlNumber++
+	now := time.Now()
+	return x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(ca.crlNumber),
+		ThisUpdate:                now.Add(-clockSkew),
+		NextUpdate:                now.Add(validFor),
+		RevokedCertificateEntries: append([]x509.RevocationListEntry(nil), ca.revoked...),
+	}, ca.Cert.Cert, ca.Key)
+}
+
+// CRLPEM returns a new PEM encoded revocation list of ca, valid for validFor.
+func (ca *CA) CRLPEM(validFor time.Duration) ([]byte, error) {
+	der, err := ca.CRL(validFor)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}), nil
+}
+
+// ServerTLSConfig returns a TLS configuration presenting c, that requires
+// client certificates issued by one of clientCAs if any.
+func ServerTLSConfig(c *Cert, clientCAs ...*CA) *tls.Config {
+	conf := &tls.Config{Certificates: []tls.Certificate{c.TLSCertificate()}}
+	if len(clientCAs) > 0 {
+		conf.ClientAuth = tls.RequireAndVerifyClientCert
+		conf.ClientCAs = x509.NewCertPool()
+		for _, ca := range clientCAs {
+			conf.ClientCAs.AddCert(ca.Root().Cert.Cert)
+		}
+	}
+	return conf
+}
+
+// ClientTLSConfig returns a TLS configuration trusting the servers issued by
+// one of roots, presenting c if not nil.
+func ClientTLSConfig(c *Cert, roots ...*CA) *tls.Config {
+	conf := &tls.Config{RootCAs: x509.NewCertPool()}
+	for _, ca := range roots {
+		conf.RootCAs.AddCert(ca.Root().Cert.Cert)
+	}
+	if c != nil {
+		conf.Certificates = []tls.Certificate{c.TLSCertificate()}
+	}
+	return conf
+}
@@ -0,0 +1,155 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svid
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestPKI(t *testing.T) {
+	for _, algo := range []x509.PublicKeyAlgorithm{x509.RSA, x509.ECDSA, x509.Ed25519} {
+		t.Run(algo.String(), func(t *testing.T) {
+			root, err := NewRootCA(CertOptions{CommonName: "root", KeyAlgo: algo})
+			if err != nil {
+				t.Fatalf("NewRootCA() got unexpected error: %v", err)
+			}
+			inter, err := root.NewIntermediateCA(CertOptions{CommonName: "intermediate", KeyAlgo: algo})
+			if err != nil {
+				t.Fatalf("NewIntermediateCA() got unexpected error: %v", err)
+			}
+			server, err := inter.IssueServer(CertOptions{CommonName: "dut", KeyAlgo: algo, DNSNames: []string{"dut.example.com"}, IPAddresses: []net.IP{net.ParseIP("192.0.2.1")}})
+			if err != nil {
+				t.Fatalf("IssueServer() got unexpected error: %v", err)
+			}
+			if got := server.Cert.PublicKeyAlgorithm; got != algo {
+				t.Errorf("IssueServer() got key algorithm %v, want %v", got, algo)
+			}
+			intermediates := x509.NewCertPool()
+			for _, c := range server.Chain {
+				intermediates.AddCert(c)
+			}
+			opts := x509.VerifyOptions{DNSName: "dut.example.com", Roots: root.Pool(), Intermediates: intermediates}
+			if _, err := server.Cert.Verify(opts); err != nil {
+				t.Errorf("Verify() of server certificate got unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidity(t *testing.T) {
+	root, err := NewRootCA(CertOptions{CommonName: "root"})
+	if err != nil {
+		t.Fatalf("NewRootCA() got unexpected error: %v", err)
+	}
+	opts := CertOptions{CommonName: "dut", DNSNames: []string{"dut"}}
+	for desc, o := range map[string]CertOptions{"expired": Expired(opts), "not yet valid": NotYetValid(opts)} {
+		c, err := root.IssueServer(o)
+		if err != nil {
+			t.Fatalf("IssueServer() of %s certificate got unexpected error: %v", desc, err)
+		}
+		if _, err := c.Cert.Verify(x509.VerifyOptions{DNSName: "dut", Roots: root.Pool()}); err == nil {
+			t.Errorf("Verify() of %s certificate got no error, want error", desc)
+		}
+	}
+	if _, err := root.IssueServer(CertOptions{CommonName: "dut"}); err == nil {
+		t.Errorf("IssueServer() without SAN got no error, want error")
+	}
+}
+
+func TestCRL(t *testing.T) {
+	root, err := NewRootCA(CertOptions{CommonName: "root"})
+	if err != nil {
+		t.Fatalf("NewRootCA() got unexpected error: %v", err)
+	}
+	revoked, err := root.IssueClient(CertOptions{CommonName: "revoked"})
+	if err != nil {
+		t.Fatalf("IssueClient() got unexpected error: %v", err)
+	}
+	if err := root.Revoke(revoked); err != nil {
+		t.Fatalf("Revoke() got unexpected error: %v", err)
+	}
+	other, err := NewRootCA(CertOptions{CommonName: "other"})
+	if err != nil {
+		t.Fatalf("NewRootCA() got unexpected error: %v", err)
+	}
+	if err := other.Revoke(revoked); err == nil {
+		t.Errorf("Revoke() of a certificate issued by another CA got no error, want error")
+	}
+
+	crlPEM, err := root.CRLPEM(time.Hour)
+	if err != nil {
+		t.Fatalf("CRLPEM() got unexpected error: %v", err)
+	}
+	block, _ := pem.Decode(crlPEM)
+	if block == nil || block.Type != "X509 CRL" {
+		t.Fatalf("CRLPEM() got %q, want an X509 CRL PEM block", crlPEM)
+	}
+	crl, err := x509.ParseRevocationList(block.Bytes)
+	if err != nil {
+		t.Fatalf("ParseRevocationList() got unexpected error: %v", err)
+	}
+	if err := crl.CheckSignatureFrom(root.Cert.Cert); err != nil {
+		t.Errorf("CheckSignatureFrom() of CRL got unexpected error: %v", err)
+	}
+	if len(crl.RevokedCertificateEntries) != 1 || crl.RevokedCertificateEntries[0].SerialNumber.Cmp(revoked.Cert.SerialNumber) != 0 {
+		t.Errorf("CRL got revoked entries %v, want serial %v", crl.RevokedCertificateEntries, revoked.Cert.SerialNumber)
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	root, err := NewRootCA(CertOptions{CommonName: "root"})
+	if err != nil {
+		t.Fatalf("NewRootCA() got unexpected error: %v", err)
+	}
+	inter, err := root.NewIntermediateCA(CertOptions{CommonName: "intermediate"})
+	if err != nil {
+		t.Fatalf("NewIntermediateCA() got unexpected error: %v", err)
+	}
+	server, err := inter.IssueServer(CertOptions{CommonName: "dut", DNSNames: []string{"dut"}})
+	if err != nil {
+		t.Fatalf("IssueServer() got unexpected error: %v", err)
+	}
+	client, err := inter.IssueSVID("spiffe://test-abc.foo.bar/xyz/admin", CertOptions{})
+	if err != nil {
+		t.Fatalf("IssueSVID() got unexpected error: %v", err)
+	}
+	if got := string(inter.BundlePEM()); got != string(inter.Cert.CertPEM())+string(root.Cert.CertPEM()) {
+		t.Errorf("BundlePEM() got %q, want the intermediate then the root certificate", got)
+	}
+
+	sConn, cConn := net.Pipe()
+	defer sConn.Close()
+	defer cConn.Close()
+	clientConf := ClientTLSConfig(client, root)
+	clientConf.ServerName = "dut"
+	srv := tls.Server(sConn, ServerTLSConfig(server, root))
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Handshake() }()
+	if err := tls.Client(cConn, clientConf).Handshake(); err != nil {
+		t.Fatalf("client Handshake() got unexpected error: %v", err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("server Handshake() got unexpected error: %v", err)
+	}
+	peer := srv.ConnectionState().PeerCertificates
+	if len(peer) == 0 || len(peer[0].URIs) != 1 || peer[0].URIs[0].String() != "spiffe://test-abc.foo.bar/xyz/admin" {
+		t.Errorf("server got peer certificates %v, want the client SVID", peer)
+	}
+}
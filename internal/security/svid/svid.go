@@ -2,6 +2,10 @@
 // for more info related to SVID refer to:
 //
 //	https://github.com/spiffe/spiffe/blob/main/standards/X509-SVID.md
+//
+// The package also provides an in-process PKI for mTLS and certz testing:
+// root and intermediate CAs, server and client certificates, SVIDs,
+// revocation lists and trust bundles, usable as tls.Config or as PEM.
 package svid
 
 import (
@@ -0,0 +1,459 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrfpolicy
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"testing"
+
+	"github.com/open-traffic-generator/snappi/gosnappi"
+	"github.com/openconfig/featureprofiles/internal/attrs"
+	"github.com/openconfig/featureprofiles/internal/deviations"
+	"github.com/openconfig/ondatra"
+	"github.com/openconfig/ondatra/gnmi"
+	"github.com/openconfig/ondatra/gnmi/oc"
+	"github.com/openconfig/ygot/ygot"
+)
+
+// IP protocol numbers commonly matched by VRF selection rules.
+const (
+	ProtocolIPinIP   uint8 = 4
+	ProtocolIPv6inIP uint8 = 41
+)
+
+// Match selects the packets a rule applies to. Empty fields match any value
+// and a rule with an empty Match matches all packets, while a Match with only
+// IPv6 set matches all IPv6 packets.
+type Match struct {
+	// IPv6 matches IPv6 packets instead of IPv4 packets.
+	IPv6 bool
+	// Protocol is the IP protocol, or the IPv6 next header, to match.
+	Protocol  uint8
+	DSCPSet   []uint8
+	SrcPrefix string
+	DstPrefix string
+}
+
+func (m *Match) empty() bool {
+	return !m.IPv6 && m.Protocol == 0 && len(m.DSCPSet) == 0 && m.SrcPrefix == "" && m.DstPrefix == ""
+}
+
+// Action is what a rule does with the packets it matches. Either NI or
+// DecapNI must be set.
+type Action struct {
+	// NI is the network instance the packets are forwarded in.
+	NI string
+	// DecapNI is the network instance the outer destination is looked up in
+	// to decide whether to decapsulate.
+	DecapNI string
+	// PostDecapNI is the network instance decapsulated packets are
+	// forwarded in.
+	PostDecapNI string
+	// DecapFallbackNI is the network instance packets are forwarded in when
+	// the lookup in DecapNI misses.
+	DecapFallbackNI string
+}
+
+// Rule is a policy forwarding rule.
+type Rule struct {
+	SeqID  uint32
+	Match  Match
+	Action Action
+}
+
+// ExpectedNI returns the network instance that traffic matching r is
+// forwarded in when the decapsulation lookup, if any, succeeds.
+func (r *Rule) ExpectedNI() string {
+	if r.Action.DecapNI != "" {
+		return r.Action.PostDecapNI
+	}
+	return r.Action.NI
+}
+
+// Policy is a policy forwarding policy, by default a VRF selection policy.
+type Policy struct {
+	Name string
+	// Type defaults to VRF_SELECTION_POLICY.
+	Type oc.E_Policy_Type
+	// Rules are evaluated in order of sequence ID.
+	Rules []*Rule
+}
+
+// Validate checks that sequence IDs are unique, that each rule has an
+// action and that prefixes and DSCP values are valid.
+func (p *Policy) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("policy has no name")
+	}
+	seqs := map[uint32]bool{}
+	for _, r := range p.Rules {
+		if seqs[r.SeqID] {
+			return fmt.Errorf("policy %s: duplicate sequence ID %d", p.Name, r.SeqID)
+		}
+		seqs[r.SeqID] = true
+		if (r.Action.NI == "") == (r.Action.DecapNI == "") {
+			return fmt.Errorf("policy %s rule %d: exactly one of NI and DecapNI must be set", p.Name, r.SeqID)
+		}
+		if r.Action.DecapNI == "" && (r.Action.PostDecapNI != "" || r.Action.DecapFallbackNI != "") {
+			return fmt.Errorf("policy %s rule %d: post-decap and fallback NIs need a DecapNI", p.Name, r.SeqID)
+		}
+		for _, d := range r.Match.DSCPSet {
+			if d > 63 {
+				return fmt.Errorf("policy %s rule %d: invalid DSCP %d", p.Name, r.SeqID, d)
+			}
+		}
+		for _, s := range []string{r.Match.SrcPrefix, r.Match.DstPrefix} {
+			if s == "" {
+				continue
+			}
+			pfx, err := netip.ParsePrefix(s)
+			if err != nil {
+				return fmt.Errorf("policy %s rule %d: %v", p.Name, r.SeqID, err)
+			}
+			if pfx.Addr().Is6() != r.Match.IPv6 {
+				return fmt.Errorf("policy %s rule %d: prefix %s does not match the rule address family", p.Name, r.SeqID, s)
+			}
+		}
+	}
+	return nil
+}
+
+// sortedRules returns the rules of p in evaluation order.
+func (p *Policy) sortedRules() []*Rule {
+	rules := slices.Clone(p.Rules)
+	slices.SortStableFunc(rules, func(a, b *Rule) int { return int(a.SeqID) - int(b.SeqID) })
+	return rules
+}
+
+// NetworkInstances returns the network instances referred to by the
+// actions of p, in order of first reference.
+func (p *Policy) NetworkInstances() []string {
+	var nis []string
+	for _, r := range p.sortedRules() {
+		for _, ni := range []string{r.Action.NI, r.Action.DecapNI, r.Action.PostDecapNI, r.Action.DecapFallbackNI} {
+			if ni != "" && !slices.Contains(nis, ni) {
+				nis = append(nis, ni)
+			}
+		}
+	}
+	return nis
+}
+
+// AddTo adds the policy to the policy forwarding configuration pf.
+func (p *Policy) AddTo(pf *oc.NetworkInstance_PolicyForwarding) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	pol := pf.GetOrCreatePolicy(p.Name)
+	pol.SetType(oc.Policy_Type_VRF_SELECTION_POLICY)
+	if p.Type != oc.Policy_Type_UNSET {
+		pol.SetType(p.Type)
+	}
+	for _, r := range p.sortedRules() {
+		rule := pol.GetOrCreateRule(r.SeqID)
+		if m := r.Match; !m.empty() {
+			if m.IPv6 {
+				ip := rule.GetOrCreateIpv6()
+				if m.Protocol != 0 {
+					ip.Protocol = oc.UnionUint8(m.Protocol)
+				}
+				ip.DscpSet = m.DSCPSet
+				if m.SrcPrefix != "" {
+					ip.SourceAddress = ygot.String(m.SrcPrefix)
+				}
+				if m.DstPrefix != "" {
+					ip.DestinationAddress = ygot.String(m.DstPrefix)
+				}
+			} else {
+				ip := rule.GetOrCreateIpv4()
+				if m.Protocol != 0 {
+					ip.Protocol = oc.UnionUint8(m.Protocol)
+				}
+				ip.DscpSet = m.DSCPSet
+				if m.SrcPrefix != "" {
+					ip.SourceAddress = ygot.String(m.SrcPrefix)
+				}
+				if m.DstPrefix != "" {
+					ip.DestinationAddress = ygot.String(m.DstPrefix)
+				}
+			}
+		}
+		a := rule.GetOrCreateAction()
+		if r.Action.NI != "" {
+			a.NetworkInstance = ygot.String(r.Action.NI)
+		}
+		if r.Action.DecapNI != "" {
+			a.DecapNetworkInstance = ygot.String(r.Action.DecapNI)
+		}
+		if r.Action.PostDecapNI != "" {
+			a.PostDecapNetworkInstance = ygot.String(r.Action.PostDecapNI)
+		}
+		if r.Action.DecapFallbackNI != "" {
+			a.DecapFallbackNetworkInstance = ygot.String(r.Action.DecapFallbackNI)
+		}
+	}
+	return nil
+}
+
+// Build returns the policy forwarding configuration of a network instance
+// holding only p.
+func (p *Policy) Build(t testing.TB) *oc.NetworkInstance_PolicyForwarding {
+	t.Helper()
+	pf := &oc.NetworkInstance_PolicyForwarding{}
+	if err := p.AddTo(pf); err != nil {
+		t.Fatalf("Building policy forwarding: %v", err)
+	}
+	return pf
+}
+
+// ConfigureNetworkInstances configures the given network instances as L3VRFs.
+func ConfigureNetworkInstances(t *testing.T, dut *ondatra.DUTDevice, names ...string) {
+	t.Helper()
+	c := &oc.Root{}
+	for _, vrf := range names {
+		ni := c.GetOrCreateNetworkInstance(vrf)
+		ni.Type = oc.NetworkInstanceTypes_NETWORK_INSTANCE_TYPE_L3VRF
+		gnmi.Replace(t, dut, gnmi.OC().NetworkInstance(vrf).Config(), ni)
+	}
+}
+
+// interfaceID returns the policy forwarding interface ID of port.
+func interfaceID(dut *ondatra.DUTDevice, port *ondatra.Port) string {
+	if deviations.InterfaceRefInterfaceIDFormat(dut) {
+		return port.Name() + ".0"
+	}
+	return port.Name()
+}
+
+// Configure replaces the policy forwarding configuration of the default
+// network instance of dut with p and applies p to ports. The non-default
+// network instances referred to by p are created first.
+func (p *Policy) Configure(t *testing.T, dut *ondatra.DUTDevice, ports ...*ondatra.Port) {
+	t.Helper()
+	defaultNI := deviations.DefaultNetworkInstance(dut)
+	var vrfs []string
+	for _, ni := range p.NetworkInstances() {
+		if ni != defaultNI && ni != niDefault {
+			vrfs = append(vrfs, ni)
+		}
+	}
+	ConfigureNetworkInstances(t, dut, vrfs...)
+	p.configure(t, dut, p.Build(t), ports...)
+}
+
+// configure replaces the policy forwarding configuration of the default
+// network instance with pf, then applies p to ports.
+func (p *Policy) configure(t *testing.T, dut *ondatra.DUTDevice, pf *oc.NetworkInstance_PolicyForwarding, ports ...*ondatra.Port) {
+	t.Helper()
+	pfPath := gnmi.OC().NetworkInstance(deviations.DefaultNetworkInstance(dut)).PolicyForwarding()
+	gnmi.Delete(t, dut, pfPath.Config())
+	gnmi.Replace(t, dut, pfPath.Config(), pf)
+	for _, port := range ports {
+		id := interfaceID(dut, port)
+		intf := pf.GetOrCreateInterface(id)
+		intf.ApplyVrfSelectionPolicy = ygot.String(p.Name)
+		intf.GetOrCreateInterfaceRef().Interface = ygot.String(port.Name())
+		intf.GetOrCreateInterfaceRef().Subinterface = ygot.Uint32(0)
+		if deviations.InterfaceRefConfigUnsupported(dut) {
+			intf.InterfaceRef = nil
+		}
+		gnmi.Replace(t, dut, pfPath.Interface(id).Config(), intf)
+	}
+}
+
+// Packet holds the outer header fields a policy matches on.
+type Packet struct {
+	IPv6     bool
+	Protocol uint8
+	DSCP     uint8
+	Src, Dst netip.Addr
+}
+
+func prefixContains(prefix string, a netip.Addr) bool {
+	if prefix == "" {
+		return true
+	}
+	pfx, err := netip.ParsePrefix(prefix)
+	return err == nil && pfx.Contains(a)
+}
+
+func (m *Match) matches(pkt *Packet) bool {
+	if m.empty() {
+		return true
+	}
+	return m.IPv6 == pkt.IPv6 &&
+		(m.Protocol == 0 || m.Protocol == pkt.Protocol) &&
+		(len(m.DSCPSet) == 0 || slices.Contains(m.DSCPSet, pkt.DSCP)) &&
+		prefixContains(m.SrcPrefix, pkt.Src) &&
+		prefixContains(m.DstPrefix, pkt.Dst)
+}
+
+// Lookup returns the first rule of p matching pkt, or nil if none does.
+func (p *Policy) Lookup(pkt *Packet) *Rule {
+	for _, r := range p.sortedRules() {
+		if r.Match.matches(pkt) {
+			return r
+		}
+	}
+	return nil
+}
+
+// FlowParams are the parameters of the flows generated for a policy.
+type FlowParams struct {
+	// Src is the ATE interface sending the flows. Its address is the outer
+	// source of rules not matching on the source.
+	Src *attrs.Attributes
+	// Dst are the ATE interfaces the flows may be received on.
+	Dst []*attrs.Attributes
+	// OuterDstIPv4 and OuterDstIPv6 are the outer destinations of rules not
+	// matching on the destination.
+	OuterDstIPv4, OuterDstIPv6 string
+	// InnerDstIPv4 and InnerDstIPv6 are the destinations of the inner
+	// packets of IP-in-IP flows.
+	InnerDstIPv4, InnerDstIPv6 string
+	// Protocol is used for rules not matching on the protocol. Defaults to
+	// ProtocolIPinIP.
+	Protocol uint8
+	// FrameSize defaults to 512 bytes and PPS to 100.
+	FrameSize uint32
+	PPS       uint64
+}
+
+// RuleFlow is a flow generated to exercise one rule of a policy.
+type RuleFlow struct {
+	Rule   *Rule
+	Packet *Packet
+	Flow   gosnappi.Flow
+}
+
+// ExpectedNI returns the network instance the traffic of the flow is
+// expected to be forwarded in.
+func (rf *RuleFlow) ExpectedNI() string {
+	return rf.Rule.ExpectedNI()
+}
+
+// packetFor returns a packet matched by r and by no rule evaluated before
+// it, or an error if r is shadowed by earlier rules.
+func (p *Policy) packetFor(r *Rule, fp *FlowParams) (*Packet, error) {
+	firstAddr := func(prefix, def string) (netip.Addr, error) {
+		if prefix != "" {
+			pfx, err := netip.ParsePrefix(prefix)
+			if err != nil {
+				return netip.Addr{}, err
+			}
+			return pfx.Masked().Addr(), nil
+		}
+		return netip.ParseAddr(def)
+	}
+	srcDef, dstDef := fp.Src.IPv4, fp.OuterDstIPv4
+	if r.Match.IPv6 {
+		srcDef, dstDef = fp.Src.IPv6, fp.OuterDstIPv6
+	}
+	src, err := firstAddr(r.Match.SrcPrefix, srcDef)
+	if err != nil {
+		return nil, fmt.Errorf("rule %d: outer source: %v", r.SeqID, err)
+	}
+	dst, err := firstAddr(r.Match.DstPrefix, dstDef)
+	if err != nil {
+		return nil, fmt.Errorf("rule %d: outer destination: %v", r.SeqID, err)
+	}
+	proto := r.Match.Protocol
+	if proto == 0 {
+		proto = fp.Protocol
+		if proto == 0 {
+			proto = ProtocolIPinIP
+		}
+	}
+	dscps := r.Match.DSCPSet
+	if len(dscps) == 0 {
+		for d := uint8(0); d < 64; d++ {
+			dscps = append(dscps, d)
+		}
+	}
+	for _, d := range dscps {
+		pkt := &Packet{IPv6: r.Match.IPv6, Protocol: proto, DSCP: d, Src: src, Dst: dst}
+		if p.Lookup(pkt) == r {
+			return pkt, nil
+		}
+	}
+	return nil, fmt.Errorf("rule %d is shadowed by earlier rules", r.SeqID)
+}
+
+// AddFlows adds to top one flow per rule of p, named <policy>-rule<seq>,
+// sending packets that match the rule and no rule before it.
+func (p *Policy) AddFlows(top gosnappi.Config, fp *FlowParams) ([]*RuleFlow, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	size, pps := fp.FrameSize, fp.PPS
+	if size == 0 {
+		size = 512
+	}
+	if pps == 0 {
+		pps = 100
+	}
+	var flows []*RuleFlow
+	for _, r := range p.sortedRules() {
+		pkt, err := p.packetFor(r, fp)
+		if err != nil {
+			return nil, fmt.Errorf("policy %s: %v", p.Name, err)
+		}
+		family := ".IPv4"
+		if pkt.IPv6 {
+			family = ".IPv6"
+		}
+		var rx []string
+		for _, d := range fp.Dst {
+			rx = append(rx, d.Name+family)
+		}
+		flow := top.Flows().Add().SetName(fmt.Sprintf("%s-rule%d", p.Name, r.SeqID))
+		flow.Metrics().SetEnable(true)
+		flow.TxRx().Device().SetTxNames([]string{fp.Src.Name + family}).SetRxNames(rx)
+		flow.Packet().Add().Ethernet().Src().SetValue(fp.Src.MAC)
+		if pkt.IPv6 {
+			outer := flow.Packet().Add().Ipv6()
+			outer.Src().SetValue(pkt.Src.String())
+			outer.Dst().SetValue(pkt.Dst.String())
+			outer.TrafficClass().SetValue(uint32(pkt.DSCP) << 2)
+			if pkt.Protocol != ProtocolIPinIP && pkt.Protocol != ProtocolIPv6inIP {
+				outer.NextHeader().SetValue(uint32(pkt.Protocol))
+			}
+		} else {
+			outer := flow.Packet().Add().Ipv4()
+			outer.Src().SetValue(pkt.Src.String())
+			outer.Dst().SetValue(pkt.Dst.String())
+			outer.Priority().Dscp().Phb().SetValue(uint32(pkt.DSCP))
+			if pkt.Protocol != ProtocolIPinIP && pkt.Protocol != ProtocolIPv6inIP {
+				outer.Protocol().SetValue(uint32(pkt.Protocol))
+			}
+		}
+		switch pkt.Protocol {
+		case ProtocolIPinIP:
+			inner := flow.Packet().Add().Ipv4()
+			inner.Src().SetValue(fp.Src.IPv4)
+			inner.Dst().SetValue(fp.InnerDstIPv4)
+		case ProtocolIPv6inIP:
+			inner := flow.Packet().Add().Ipv6()
+			inner.Src().SetValue(fp.Src.IPv6)
+			inner.Dst().SetValue(fp.InnerDstIPv6)
+		}
+		flow.Size().SetFixed(size)
+		flow.Rate().SetPps(pps)
+		flows = append(flows, &RuleFlow{Rule: r, Packet: pkt, Flow: flow})
+	}
+	return flows, nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrfpolicy
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/open-traffic-generator/snappi/gosnappi"
+	"github.com/openconfig/featureprofiles/internal/attrs"
+	"github.com/openconfig/ondatra/gnmi/oc"
+	"github.com/openconfig/ygot/ygot"
+)
+
+func TestPolicyW(t *testing.T) {
+	pf := PolicyW().Build(t)
+	pol := pf.GetPolicy(vrfPolW)
+	if got := pol.GetType(); got != oc.Policy_Type_VRF_SELECTION_POLICY {
+		t.Errorf("policy W got type %v, want VRF_SELECTION_POLICY", got)
+	}
+	if got := len(pol.Rule); got != 13 {
+		t.Fatalf("policy W got %d rules, want 13", got)
+	}
+	want := &oc.NetworkInstance_PolicyForwarding_Policy_Rule{
+		SequenceId: ygot.Uint32(6),
+		Ipv4: &oc.NetworkInstance_PolicyForwarding_Policy_Rule_Ipv4{
+			Protocol:      oc.UnionUint8(41),
+			DscpSet:       []uint8{dscpEncapB1, dscpEncapB2},
+			SourceAddress: ygot.String(ipv4OuterSrc222WithMask),
+		},
+		Action: &oc.NetworkInstance_PolicyForwarding_Policy_Rule_Action{
+			DecapNetworkInstance:         ygot.String(niDecapTeVrf),
+			PostDecapNetworkInstance:     ygot.String(niEncapTeVrfB),
+			DecapFallbackNetworkInstance: ygot.String(niTeVrf222),
+		},
+	}
+	if diff := cmp.Diff(want, pol.GetRule(6)); diff != "" {
+		t.Errorf("policy W rule 6 got unexpected diff (-want +got):\n%s", diff)
+	}
+	if r := pol.GetRule(11); r.GetIpv4().DscpSet != nil || r.GetIpv4().GetSourceAddress() != ipv4OuterSrc111WithMask || r.GetAction().GetPostDecapNetworkInstance() != niDefault {
+		t.Errorf("policy W rule 11 got %+v, want no DSCP set, source %s and post-decap NI %s", r, ipv4OuterSrc111WithMask, niDefault)
+	}
+	if r := pol.GetRule(13); r.Ipv4 != nil || r.GetAction().GetNetworkInstance() != niDefault {
+		t.Errorf("policy W rule 13 got %+v, want a catch-all to %s", r, niDefault)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		desc string
+		rule *Rule
+	}{
+		{"duplicate sequence", &Rule{SeqID: 1, Action: Action{NI: "A"}}},
+		{"no action", &Rule{SeqID: 2}},
+		{"both actions", &Rule{SeqID: 2, Action: Action{NI: "A", DecapNI: "B"}}},
+		{"fallback without decap", &Rule{SeqID: 2, Action: Action{NI: "A", DecapFallbackNI: "B"}}},
+		{"bad DSCP", &Rule{SeqID: 2, Match: Match{DSCPSet: []uint8{64}}, Action: Action{NI: "A"}}},
+		{"bad prefix", &Rule{SeqID: 2, Match: Match{SrcPrefix: "198.51.100.1"}, Action: Action{NI: "A"}}},
+		{"family mismatch", &Rule{SeqID: 2, Match: Match{IPv6: true, DstPrefix: "198.51.100.0/24"}, Action: Action{NI: "A"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			p := &Policy{Name: "p", Rules: []*Rule{{SeqID: 1, Action: Action{NI: "A"}}, tt.rule}}
+			if err := p.Validate(); err == nil {
+				t.Errorf("Validate() got no error, want error")
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	p := PolicyW()
+	src111 := netip.MustParseAddr("198.51.100.111")
+	tests := []struct {
+		desc string
+		pkt  *Packet
+		want uint32
+	}{
+		{"encap A IPv4", &Packet{Protocol: 4, DSCP: dscpEncapA2, Src: src111}, 3},
+		{"encap B IPv6 in IPv4", &Packet{Protocol: 41, DSCP: dscpEncapB1, Src: src111}, 8},
+		{"no DSCP match", &Packet{Protocol: 4, DSCP: dscpEncapNoMatch, Src: src111}, 11},
+		{"other source", &Packet{Protocol: 4, DSCP: dscpEncapA1, Src: netip.MustParseAddr("198.51.100.1")}, 13},
+		{"IPv6", &Packet{IPv6: true, Protocol: 4, Src: netip.MustParseAddr("2001:db8::1")}, 13},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := p.Lookup(tt.pkt); got.SeqID != tt.want {
+				t.Errorf("Lookup() got rule %d, want %d", got.SeqID, tt.want)
+			}
+		})
+	}
+}
+
+func TestIPv6OnlyRule(t *testing.T) {
+	p := &Policy{Name: "p", Rules: []*Rule{
+		{SeqID: 1, Match: Match{IPv6: true}, Action: Action{NI: "V6"}},
+		{SeqID: 2, Action: Action{NI: "DEFAULT"}},
+	}}
+	pf := p.Build(t)
+	if r := pf.GetPolicy("p").GetRule(1); r.Ipv6 == nil || r.Ipv4 != nil {
+		t.Errorf("IPv6 only rule got %+v, want an IPv6 match", r)
+	}
+	if got := p.Lookup(&Packet{Src: netip.MustParseAddr("198.51.100.1")}); got.SeqID != 2 {
+		t.Errorf("Lookup() of an IPv4 packet got rule %d, want 2", got.SeqID)
+	}
+	if got := p.Lookup(&Packet{IPv6: true, Src: netip.MustParseAddr("2001:db8::1")}); got.SeqID != 1 {
+		t.Errorf("Lookup() of an IPv6 packet got rule %d, want 1", got.SeqID)
+	}
+}
+
+func TestAddFlows(t *testing.T) {
+	fp := &FlowParams{
+		Src:          &attrs.Attributes{Name: "port1", MAC: "02:00:01:01:01:01", IPv4: "192.0.2.2", IPv6: "2001:db8::2"},
+		Dst:          []*attrs.Attributes{{Name: "port2"}},
+		OuterDstIPv4: "203.0.113.1",
+		InnerDstIPv4: "198.18.0.1",
+		InnerDstIPv6: "2001:db8:1::1",
+	}
+	top := gosnappi.NewConfig()
+	flows, err := PolicyW().AddFlows(top, fp)
+	if err != nil {
+		t.Fatalf("AddFlows() got unexpected error: %v", err)
+	}
+	if got := len(top.Flows().Items()); got != 13 {
+		t.Fatalf("AddFlows() got %d flows, want 13", got)
+	}
+	rf := flows[9]
+	if got, want := rf.Flow.Name(), "vrf_selection_policy_w-rule10"; got != want {
+		t.Errorf("AddFlows() got flow %s, want %s", got, want)
+	}
+	if rf.Packet.Src.String() != "198.51.100.222" || rf.Packet.Protocol != 41 || rf.Packet.DSCP != 0 {
+		t.Errorf("AddFlows() got packet %+v for rule 10, want source 198.51.100.222, protocol 41 and DSCP 0", rf.Packet)
+	}
+	if got := rf.ExpectedNI(); got != niDefault {
+		t.Errorf("ExpectedNI() got %s, want %s", got, niDefault)
+	}
+	if got := len(rf.Flow.Packet().Items()); got != 3 {
+		t.Errorf("rule 10 flow got %d headers, want Ethernet, outer IPv4 and inner IPv6", got)
+	}
+	if got := flows[12].Packet.Src.String(); got != fp.Src.IPv4 {
+		t.Errorf("catch-all flow got source %s, want %s", got, fp.Src.IPv4)
+	}
+
+	shadowed := &Policy{Name: "p", Rules: []*Rule{
+		{SeqID: 1, Match: Match{Protocol: 4}, Action: Action{NI: "A"}},
+		{SeqID: 2, Match: Match{Protocol: 4, DSCPSet: []uint8{10}}, Action: Action{NI: "B"}},
+	}}
+	if _, err := shadowed.AddFlows(gosnappi.NewConfig(), fp); err == nil {
+		t.Errorf("AddFlows() with a shadowed rule got no error, want error")
+	}
+}
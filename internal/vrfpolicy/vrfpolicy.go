@@ -12,7 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package vrfpolicy contains functions to build specific vrf policies
+// Package vrfpolicy contains functions to build vrf selection policies and
+// the traffic that exercises their rules.
 package vrfpolicy
 
 import (
@@ -20,9 +21,7 @@ import (
 
 	"github.com/openconfig/featureprofiles/internal/deviations"
 	"github.com/openconfig/ondatra"
-	"github.com/openconfig/ondatra/gnmi"
 	"github.com/openconfig/ondatra/gnmi/oc"
-	"github.com/openconfig/ygot/ygot"
 )
 
 const (
@@ -45,35 +44,38 @@ const (
 	decapFlowSrc            = "198.51.100.111"
 )
 
-type ipv4 struct {
-	protocol   oc.UnionUint8
-	dscpSet    []uint8
-	sourceAddr string
-}
-
-type action struct {
-	decapNI         string
-	postDecapNI     string
-	decapFallbackNI string
-}
-
-type policyFwRule struct {
-	seqID  uint32
-	ipv4   ipv4
-	action action
-}
-
-// configureNetworkInstance configures vrfs DECAP_TE_VRF, ENCAP_TE_VRF_A, ENCAP_TE_VRF_B,
+// configNonDefaultNetworkInstance configures vrfs DECAP_TE_VRF, ENCAP_TE_VRF_A, ENCAP_TE_VRF_B,
 // ENCAP_TE_VRF_C, ENCAP_TE_VRF_D, TE_VRF_111, TE_VRF_222
 func configNonDefaultNetworkInstance(t *testing.T, dut *ondatra.DUTDevice) {
 	t.Helper()
-	c := &oc.Root{}
-	vrfs := []string{niDecapTeVrf, niEncapTeVrfA, niEncapTeVrfB, niEncapTeVrfC, niEncapTeVrfD, niTeVrf111, niTeVrf222}
-	for _, vrf := range vrfs {
-		ni := c.GetOrCreateNetworkInstance(vrf)
-		ni.Type = oc.NetworkInstanceTypes_NETWORK_INSTANCE_TYPE_L3VRF
-		gnmi.Replace(t, dut, gnmi.OC().NetworkInstance(vrf).Config(), ni)
+	ConfigureNetworkInstances(t, dut, niDecapTeVrf, niEncapTeVrfA, niEncapTeVrfB, niEncapTeVrfC, niEncapTeVrfD, niTeVrf111, niTeVrf222)
+}
+
+// PolicyW returns vrf selection policy W.
+// Reference: https://github.com/openconfig/featureprofiles/blob/main/feature/gribi/vrf_policy_driven_te/README.md?plain=1#L252
+func PolicyW() *Policy {
+	p := &Policy{Name: vrfPolW}
+	seq := uint32(0)
+	add := func(dscpSet []uint8, postDecapNI string) {
+		for _, src := range []struct{ prefix, fallback string }{
+			{ipv4OuterSrc222WithMask, niTeVrf222},
+			{ipv4OuterSrc111WithMask, niTeVrf111},
+		} {
+			for _, proto := range []uint8{ProtocolIPinIP, ProtocolIPv6inIP} {
+				seq++
+				p.Rules = append(p.Rules, &Rule{
+					SeqID:  seq,
+					Match:  Match{Protocol: proto, DSCPSet: dscpSet, SrcPrefix: src.prefix},
+					Action: Action{DecapNI: niDecapTeVrf, PostDecapNI: postDecapNI, DecapFallbackNI: src.fallback},
+				})
+			}
+		}
 	}
+	add([]uint8{dscpEncapA1, dscpEncapA2}, niEncapTeVrfA)
+	add([]uint8{dscpEncapB1, dscpEncapB2}, niEncapTeVrfB)
+	add(nil, niDefault)
+	p.Rules = append(p.Rules, &Rule{SeqID: seq + 1, Action: Action{NI: niDefault}})
+	return p
 }
 
 // BuildVRFSelectionPolicyW vrf selection policy rule
@@ -82,97 +84,10 @@ func BuildVRFSelectionPolicyW(t *testing.T, dut *ondatra.DUTDevice, niName strin
 	d := &oc.Root{}
 	configNonDefaultNetworkInstance(t, dut)
 
-	pfRule1 := &policyFwRule{
-		seqID:  1,
-		ipv4:   ipv4{protocol: 4, dscpSet: []uint8{dscpEncapA1, dscpEncapA2}, sourceAddr: ipv4OuterSrc222WithMask},
-		action: action{decapNI: niDecapTeVrf, postDecapNI: niEncapTeVrfA, decapFallbackNI: niTeVrf222},
-	}
-	pfRule2 := &policyFwRule{
-		seqID:  2,
-		ipv4:   ipv4{protocol: 41, dscpSet: []uint8{dscpEncapA1, dscpEncapA2}, sourceAddr: ipv4OuterSrc222WithMask},
-		action: action{decapNI: niDecapTeVrf, postDecapNI: niEncapTeVrfA, decapFallbackNI: niTeVrf222},
-	}
-	pfRule3 := &policyFwRule{
-		seqID:  3,
-		ipv4:   ipv4{protocol: 4, dscpSet: []uint8{dscpEncapA1, dscpEncapA2}, sourceAddr: ipv4OuterSrc111WithMask},
-		action: action{decapNI: niDecapTeVrf, postDecapNI: niEncapTeVrfA, decapFallbackNI: niTeVrf111},
-	}
-	pfRule4 := &policyFwRule{
-		seqID:  4,
-		ipv4:   ipv4{protocol: 41, dscpSet: []uint8{dscpEncapA1, dscpEncapA2}, sourceAddr: ipv4OuterSrc111WithMask},
-		action: action{decapNI: niDecapTeVrf, postDecapNI: niEncapTeVrfA, decapFallbackNI: niTeVrf111},
-	}
-
-	pfRule5 := &policyFwRule{
-		seqID:  5,
-		ipv4:   ipv4{protocol: 4, dscpSet: []uint8{dscpEncapB1, dscpEncapB2}, sourceAddr: ipv4OuterSrc222WithMask},
-		action: action{decapNI: niDecapTeVrf, postDecapNI: niEncapTeVrfB, decapFallbackNI: niTeVrf222},
-	}
-	pfRule6 := &policyFwRule{
-		seqID:  6,
-		ipv4:   ipv4{protocol: 41, dscpSet: []uint8{dscpEncapB1, dscpEncapB2}, sourceAddr: ipv4OuterSrc222WithMask},
-		action: action{decapNI: niDecapTeVrf, postDecapNI: niEncapTeVrfB, decapFallbackNI: niTeVrf222},
-	}
-	pfRule7 := &policyFwRule{
-		seqID:  7,
-		ipv4:   ipv4{protocol: 4, dscpSet: []uint8{dscpEncapB1, dscpEncapB2}, sourceAddr: ipv4OuterSrc111WithMask},
-		action: action{decapNI: niDecapTeVrf, postDecapNI: niEncapTeVrfB, decapFallbackNI: niTeVrf111},
-	}
-	pfRule8 := &policyFwRule{
-		seqID:  8,
-		ipv4:   ipv4{protocol: 41, dscpSet: []uint8{dscpEncapB1, dscpEncapB2}, sourceAddr: ipv4OuterSrc111WithMask},
-		action: action{decapNI: niDecapTeVrf, postDecapNI: niEncapTeVrfB, decapFallbackNI: niTeVrf111},
-	}
-
-	pfRule9 := &policyFwRule{
-		seqID:  9,
-		ipv4:   ipv4{protocol: 4, sourceAddr: ipv4OuterSrc222WithMask},
-		action: action{decapNI: niDecapTeVrf, postDecapNI: niDefault, decapFallbackNI: niTeVrf222},
-	}
-	pfRule10 := &policyFwRule{
-		seqID:  10,
-		ipv4:   ipv4{protocol: 41, sourceAddr: ipv4OuterSrc222WithMask},
-		action: action{decapNI: niDecapTeVrf, postDecapNI: niDefault, decapFallbackNI: niTeVrf222},
-	}
-	pfRule11 := &policyFwRule{
-		seqID:  11,
-		ipv4:   ipv4{protocol: 4, sourceAddr: ipv4OuterSrc111WithMask},
-		action: action{decapNI: niDecapTeVrf, postDecapNI: niDefault, decapFallbackNI: niTeVrf111},
-	}
-	pfRule12 := &policyFwRule{
-		seqID:  12,
-		ipv4:   ipv4{protocol: 41, sourceAddr: ipv4OuterSrc111WithMask},
-		action: action{decapNI: niDecapTeVrf, postDecapNI: niDefault, decapFallbackNI: niTeVrf111},
-	}
-
-	pfRuleList := []*policyFwRule{
-		pfRule1, pfRule2, pfRule3, pfRule4, pfRule5, pfRule6,
-		pfRule7, pfRule8, pfRule9, pfRule10, pfRule11, pfRule12,
-	}
-
-	ni := d.GetOrCreateNetworkInstance(niName)
-	niP := ni.GetOrCreatePolicyForwarding()
-	niPf := niP.GetOrCreatePolicy(vrfPolW)
-	niPf.SetType(oc.Policy_Type_VRF_SELECTION_POLICY)
-
-	for _, pfRule := range pfRuleList {
-		pfR := niPf.GetOrCreateRule(pfRule.seqID)
-		pfRProtoIPv4 := pfR.GetOrCreateIpv4()
-		pfRProtoIPv4.Protocol = oc.UnionUint8(pfRule.ipv4.protocol)
-		if pfRule.ipv4.dscpSet != nil {
-			pfRProtoIPv4.DscpSet = pfRule.ipv4.dscpSet
-		}
-		pfRProtoIPv4.SourceAddress = ygot.String(pfRule.ipv4.sourceAddr)
-		pfRAction := pfR.GetOrCreateAction()
-		pfRAction.DecapNetworkInstance = ygot.String(pfRule.action.decapNI)
-		pfRAction.PostDecapNetworkInstance = ygot.String(pfRule.action.postDecapNI)
-		pfRAction.DecapFallbackNetworkInstance = ygot.String(pfRule.action.decapFallbackNI)
+	niP := d.GetOrCreateNetworkInstance(niName).GetOrCreatePolicyForwarding()
+	if err := PolicyW().AddTo(niP); err != nil {
+		t.Fatalf("Building vrf selection policy W: %v", err)
 	}
-
-	pfR := niPf.GetOrCreateRule(13)
-	pfRAction := pfR.GetOrCreateAction()
-	pfRAction.NetworkInstance = ygot.String(niDefault)
-
 	return niP
 }
 
@@ -181,24 +96,6 @@ func ConfigureVRFSelectionPolicyW(t *testing.T, dut *ondatra.DUTDevice) {
 	t.Helper()
 
 	t.Log("Delete existing vrf selection policy and Apply vrf selectioin policy W")
-	gnmi.Delete(t, dut, gnmi.OC().NetworkInstance(deviations.DefaultNetworkInstance(dut)).PolicyForwarding().Config())
-
-	port1 := dut.Port(t, "port1")
-	interfaceID := port1.Name()
-	if deviations.InterfaceRefInterfaceIDFormat(dut) {
-		interfaceID = interfaceID + ".0"
-	}
-
 	niForwarding := BuildVRFSelectionPolicyW(t, dut, deviations.DefaultNetworkInstance(dut))
-	dutForwardingPath := gnmi.OC().NetworkInstance(deviations.DefaultNetworkInstance(dut)).PolicyForwarding()
-	gnmi.Replace(t, dut, dutForwardingPath.Config(), niForwarding)
-
-	interface1 := niForwarding.GetOrCreateInterface(interfaceID)
-	interface1.ApplyVrfSelectionPolicy = ygot.String(vrfPolW)
-	interface1.GetOrCreateInterfaceRef().Interface = ygot.String(port1.Name())
-	interface1.GetOrCreateInterfaceRef().Subinterface = ygot.Uint32(0)
-	if deviations.InterfaceRefConfigUnsupported(dut) {
-		interface1.InterfaceRef = nil
-	}
-	gnmi.Replace(t, dut, dutForwardingPath.Interface(interfaceID).Config(), interface1)
+	PolicyW().configure(t, dut, niForwarding, dut.Port(t, "port1"))
 }
@@ -0,0 +1,95 @@
+// Copyright 2024 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// The authzpolicy command lints the JSON authz policy files loaded by the
+// authz tests, and optionally diffs or pretty-prints their policies.
+//
+// Usage:
+//
+//	go run ./tools/authzpolicy [-diff_against old.json] [-print] policy.json...
+//
+// It exits with status 1 if any policy has lint issues or differs from the
+// policy of the same name in -diff_against.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/openconfig/featureprofiles/internal/security/authz"
+)
+
+var (
+	diffAgainst = flag.String("diff_against", "", "JSON policy file whose policies are diffed with the policies of the same name")
+	printPolicy = flag.Bool("print", false, "if set to true, print the canonical form of each policy")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] policy.json...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	old := map[string]*authz.AuthorizationPolicy{}
+	if *diffAgainst != "" {
+		policies, err := authz.ReadPolicyFile(*diffAgainst)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		for i := range policies {
+			old[policies[i].Name] = &policies[i]
+		}
+	}
+
+	failed := false
+	for _, file := range flag.Args() {
+		policies, err := authz.ReadPolicyFile(file)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		for i := range policies {
+			p := &policies[i]
+			for _, issue := range p.Lint() {
+				fmt.Printf("%s: %v\n", file, issue)
+				failed = true
+			}
+			if o, ok := old[p.Name]; ok {
+				if d := authz.Diff(o, p); !d.Empty() {
+					fmt.Printf("%s: policy %s differs from %s:\n%v\n", file, p.Name, *diffAgainst, d)
+					failed = true
+				}
+			}
+			if *printPolicy {
+				out, err := json.MarshalIndent(p.Canonical(), "", "  ")
+				if err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(2)
+				}
+				fmt.Printf("%s\n", out)
+			}
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}